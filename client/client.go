@@ -42,6 +42,9 @@
 //
 // - rate limit client calls based on IP addresses
 //
+// - partition or route data points per client identity verified by mutual TLS,
+// available in the client.TLSInfo and under the tls.* metadata keys
+//
 // Processors and exporters relying on the existence of data from the
 // client.Info, especially client.AuthData, should clearly document this as part
 // of the component's README file. The expected pattern for consuming data is to
@@ -99,6 +102,11 @@ type Info struct {
 
 	// Metadata is the request metadata from the client connecting to this connector.
 	Metadata Metadata
+
+	// TLS is the identity of the client verified by mutual TLS, as set by confighttp.ToServer and
+	// configgrpc.ToServer when the server requires client certificates. It is nil if the client
+	// did not present a verified certificate.
+	TLS *TLSInfo
 }
 
 // AuthData represents the authentication data as seen by authenticators tied to
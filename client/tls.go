@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package client // import "go.opentelemetry.io/collector/client"
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"strings"
+)
+
+// The metadata keys of the identity of the clients verified by mutual TLS. The servers of confighttp and
+// configgrpc replace these keys of the request metadata, they cannot be set by the clients.
+const (
+	// MetadataTLSSubject is the distinguished name of the subject of the client certificate.
+	MetadataTLSSubject = "tls.subject"
+	// MetadataTLSCommonName is the common name of the subject of the client certificate.
+	MetadataTLSCommonName = "tls.common_name"
+	// MetadataTLSSPIFFEID is the SPIFFE ID of the client certificate.
+	MetadataTLSSPIFFEID = "tls.spiffe_id"
+	// MetadataTLSDNSNames are the DNS names of the client certificate.
+	MetadataTLSDNSNames = "tls.dns_names"
+	// MetadataTLSURIs are the URIs of the client certificate.
+	MetadataTLSURIs = "tls.uris"
+)
+
+var tlsMetadataKeys = []string{
+	MetadataTLSSubject,
+	MetadataTLSCommonName,
+	MetadataTLSSPIFFEID,
+	MetadataTLSDNSNames,
+	MetadataTLSURIs,
+}
+
+// TLSInfo is the identity of a client, taken from the certificate it presented during a mutual TLS handshake,
+// once verified by the server against its client CA.
+type TLSInfo struct {
+	// Subject is the distinguished name of the subject of the certificate, in the RFC 2253 format.
+	Subject string
+
+	// CommonName is the common name of the subject of the certificate.
+	CommonName string
+
+	// SPIFFEID is the first URI subject alternative name with the spiffe scheme, empty if there is none.
+	SPIFFEID string
+
+	// DNSNames are the DNS subject alternative names of the certificate.
+	DNSNames []string
+
+	// URIs are the URI subject alternative names of the certificate.
+	URIs []string
+
+	// EmailAddresses are the email subject alternative names of the certificate.
+	EmailAddresses []string
+}
+
+// NewTLSInfo returns the identity of the client verified by the TLS handshake. It returns nil if the
+// connection is not using TLS or if the client did not present a certificate verified by the server.
+func NewTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return newTLSInfo(state.VerifiedChains[0][0])
+}
+
+// NewPeerTLSInfo returns the identity of the client from the certificate it presented, for the servers verifying
+// the client certificates themselves with tls.RequireAnyClientCert and tls.Config.VerifyConnection, as done for
+// SPIFFE: crypto/tls leaves the verified chains empty, but the handshake fails unless the server accepted the
+// certificate. It returns nil if the connection is not using TLS or if the client did not present a certificate.
+func NewPeerTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil || !state.HandshakeComplete || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	return newTLSInfo(state.PeerCertificates[0])
+}
+
+func newTLSInfo(cert *x509.Certificate) *TLSInfo {
+	info := &TLSInfo{
+		Subject:        cert.Subject.String(),
+		CommonName:     cert.Subject.CommonName,
+		DNSNames:       cert.DNSNames,
+		EmailAddresses: cert.EmailAddresses,
+	}
+	for _, uri := range cert.URIs {
+		info.URIs = append(info.URIs, uri.String())
+		if info.SPIFFEID == "" && strings.EqualFold(uri.Scheme, "spiffe") {
+			info.SPIFFEID = uri.String()
+		}
+	}
+	return info
+}
+
+// SetMetadata replaces the TLS identity keys of the metadata by the values of the identity.
+// The keys are removed from the metadata if the identity is nil.
+func (t *TLSInfo) SetMetadata(md map[string][]string) {
+	for k := range md {
+		for _, tlsKey := range tlsMetadataKeys {
+			if strings.EqualFold(k, tlsKey) {
+				delete(md, k)
+			}
+		}
+	}
+	if t == nil {
+		return
+	}
+	setMetadataValues(md, MetadataTLSSubject, t.Subject)
+	setMetadataValues(md, MetadataTLSCommonName, t.CommonName)
+	setMetadataValues(md, MetadataTLSSPIFFEID, t.SPIFFEID)
+	setMetadataValues(md, MetadataTLSDNSNames, t.DNSNames...)
+	setMetadataValues(md, MetadataTLSURIs, t.URIs...)
+}
+
+func setMetadataValues(md map[string][]string, key string, values ...string) {
+	var vals []string
+	for _, v := range values {
+		if v != "" {
+			vals = append(vals, v)
+		}
+	}
+	if len(vals) > 0 {
+		md[key] = vals
+	}
+}
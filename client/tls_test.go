@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTLSInfo(t *testing.T) {
+	spiffeID, _ := url.Parse("spiffe://example.org/ns/observability/sa/agent")
+	other, _ := url.Parse("https://agent.example.org")
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "agent", Organization: []string{"Example"}},
+		DNSNames:       []string{"agent.example.org"},
+		EmailAddresses: []string{"agent@example.org"},
+		URIs:           []*url.URL{other, spiffeID},
+	}
+
+	assert.Nil(t, NewTLSInfo(nil))
+	assert.Nil(t, NewTLSInfo(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}))
+	assert.Equal(t, &TLSInfo{
+		Subject:        "CN=agent,O=Example",
+		CommonName:     "agent",
+		SPIFFEID:       "spiffe://example.org/ns/observability/sa/agent",
+		DNSNames:       []string{"agent.example.org"},
+		URIs:           []string{"https://agent.example.org", "spiffe://example.org/ns/observability/sa/agent"},
+		EmailAddresses: []string{"agent@example.org"},
+	}, NewTLSInfo(&tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}))
+}
+
+func TestNewPeerTLSInfo(t *testing.T) {
+	spiffeID, _ := url.Parse("spiffe://example.org/ns/observability/sa/agent")
+	cert := &x509.Certificate{URIs: []*url.URL{spiffeID}}
+
+	assert.Nil(t, NewPeerTLSInfo(nil))
+	assert.Nil(t, NewPeerTLSInfo(&tls.ConnectionState{HandshakeComplete: true}))
+	assert.Nil(t, NewPeerTLSInfo(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}))
+	assert.Equal(t, &TLSInfo{
+		SPIFFEID: "spiffe://example.org/ns/observability/sa/agent",
+		URIs:     []string{"spiffe://example.org/ns/observability/sa/agent"},
+	}, NewPeerTLSInfo(&tls.ConnectionState{HandshakeComplete: true, PeerCertificates: []*x509.Certificate{cert}}))
+}
+
+func TestTLSInfoSetMetadata(t *testing.T) {
+	info := &TLSInfo{
+		Subject:    "CN=agent",
+		CommonName: "agent",
+		DNSNames:   []string{"agent.example.org", "agent"},
+	}
+	md := map[string][]string{
+		"Tls.spiffe_id": {"spiffe://forged"},
+		"tls.subject":   {"CN=forged"},
+		"x-tenant":      {"a"},
+	}
+	info.SetMetadata(md)
+	assert.Equal(t, map[string][]string{
+		MetadataTLSSubject:    {"CN=agent"},
+		MetadataTLSCommonName: {"agent"},
+		MetadataTLSDNSNames:   {"agent.example.org", "agent"},
+		"x-tenant":            {"a"},
+	}, md)
+
+	var noInfo *TLSInfo
+	noInfo.SetMetadata(md)
+	assert.Equal(t, map[string][]string{"x-tenant": {"a"}}, md)
+}
@@ -19,6 +19,71 @@ const (
 	typeEmpty   Type = ""
 )
 
+// Level is the compression level of a compression type, its range depends on the compression type.
+type Level int
+
+// DefaultCompressionLevel selects the default level of the compression type.
+const DefaultCompressionLevel Level = 0
+
+func (l Level) validateRange(typ Type, minLevel, maxLevel Level) error {
+	if l < minLevel || l > maxLevel {
+		return fmt.Errorf("unsupported level %d for compression type %q, the level must be between %d and %d", l, typ, minLevel, maxLevel)
+	}
+	return nil
+}
+
+// CompressionParams are the settings of the compression type.
+type CompressionParams struct {
+	// Level is the compression level, the default level of the compression type is used if not set.
+	// The levels of gzip, zlib, deflate and lz4 range from 1 to 9, the levels of zstd from 1 to 22.
+	Level Level `mapstructure:"level"`
+
+	// Zstd are the settings specific to the zstd compression type.
+	Zstd ZstdParams `mapstructure:"zstd"`
+}
+
+// DecompressionParams are the settings of the decompression of the payloads received by a server.
+type DecompressionParams struct {
+	// Zstd are the settings of the zstd decoders.
+	Zstd ZstdParams `mapstructure:"zstd"`
+}
+
+// Validate checks if the decompression parameters are valid.
+func (p *DecompressionParams) Validate() error {
+	return p.Zstd.Validate()
+}
+
+const (
+	zstdMinWindowSize = 1 << 10
+	zstdMaxWindowSize = 1 << 29
+)
+
+// ZstdParams are the settings of the zstd encoders and decoders.
+type ZstdParams struct {
+	// WindowSize is the window size in bytes of the encoders, and the largest window size accepted by the decoders.
+	// It must be a power of two between 1KiB and 512MiB. The default of the encoders depends on the level,
+	// the default of the decoders depends on the maximum size of the decompressed payloads.
+	WindowSize int `mapstructure:"window_size"`
+
+	// Concurrency is the number of goroutines compressing or decompressing a payload. Default: 1.
+	Concurrency int `mapstructure:"concurrency"`
+
+	// DictionaryFile is the path to a zstd dictionary, as created by `zstd --train`. The encoders compress the
+	// payloads with the dictionary, the decoders accept the payloads compressed with it.
+	DictionaryFile string `mapstructure:"dictionary_file"`
+}
+
+// Validate checks if the zstd parameters are valid.
+func (p *ZstdParams) Validate() error {
+	if p.WindowSize != 0 && (p.WindowSize < zstdMinWindowSize || p.WindowSize > zstdMaxWindowSize || p.WindowSize&(p.WindowSize-1) != 0) {
+		return fmt.Errorf("invalid zstd window size %d, the window size must be a power of two between %d and %d", p.WindowSize, zstdMinWindowSize, zstdMaxWindowSize)
+	}
+	if p.Concurrency < 0 {
+		return fmt.Errorf("invalid zstd concurrency %d, the concurrency must be positive", p.Concurrency)
+	}
+	return nil
+}
+
 // IsCompressed returns false if CompressionType is nil, none, or empty.
 // Otherwise, returns true.
 func (ct *Type) IsCompressed() bool {
@@ -27,17 +92,52 @@ func (ct *Type) IsCompressed() bool {
 
 func (ct *Type) UnmarshalText(in []byte) error {
 	typ := Type(in)
-	if typ == TypeGzip ||
-		typ == TypeZlib ||
-		typ == TypeDeflate ||
-		typ == TypeSnappy ||
-		typ == TypeZstd ||
-		typ == TypeLz4 ||
+	if isBuiltIn(typ) ||
 		typ == typeNone ||
 		typ == typeEmpty {
 		*ct = typ
 		return nil
 	}
+	if _, ok := Lookup(typ); ok {
+		*ct = typ
+		return nil
+	}
 	return fmt.Errorf("unsupported compression type %q", typ)
+}
 
+// ValidateParams returns an error if the compression parameters are not supported by the compression type.
+func (ct *Type) ValidateParams(params CompressionParams) error {
+	if params.Zstd != (ZstdParams{}) {
+		if *ct != TypeZstd {
+			return fmt.Errorf("compression type %q does not support the zstd parameters", *ct)
+		}
+		if err := params.Zstd.Validate(); err != nil {
+			return err
+		}
+	}
+	if params.Level == DefaultCompressionLevel {
+		return nil
+	}
+	switch *ct {
+	case TypeGzip, TypeZlib, TypeDeflate, TypeLz4:
+		return params.Level.validateRange(*ct, 1, 9)
+	case TypeZstd:
+		return params.Level.validateRange(*ct, 1, 22)
+	case TypeSnappy, typeNone, typeEmpty:
+		return fmt.Errorf("compression type %q does not support setting a level", *ct)
+	}
+	if codec, ok := Lookup(*ct); ok {
+		return codec.ValidateParams(params)
+	}
+	return fmt.Errorf("unsupported compression type %q", *ct)
+}
+
+// isBuiltIn returns true if the compression type is implemented by the collector.
+func isBuiltIn(typ Type) bool {
+	return typ == TypeGzip ||
+		typ == TypeZlib ||
+		typ == TypeDeflate ||
+		typ == TypeSnappy ||
+		typ == TypeZstd ||
+		typ == TypeLz4
 }
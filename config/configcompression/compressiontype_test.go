@@ -84,3 +84,91 @@ func TestUnmarshalText(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		typ         Type
+		level       Level
+		expectedErr string
+	}{
+		{typ: TypeGzip, level: DefaultCompressionLevel},
+		{typ: TypeGzip, level: 9},
+		{typ: TypeGzip, level: 10, expectedErr: `unsupported level 10 for compression type "gzip", the level must be between 1 and 9`},
+		{typ: TypeZlib, level: 1},
+		{typ: TypeDeflate, level: -1, expectedErr: `unsupported level -1 for compression type "deflate", the level must be between 1 and 9`},
+		{typ: TypeLz4, level: 5},
+		{typ: TypeZstd, level: 22},
+		{typ: TypeZstd, level: 23, expectedErr: `unsupported level 23 for compression type "zstd", the level must be between 1 and 22`},
+		{typ: TypeSnappy, level: DefaultCompressionLevel},
+		{typ: TypeSnappy, level: 1, expectedErr: `compression type "snappy" does not support setting a level`},
+		{typ: typeNone, level: 1, expectedErr: `compression type "none" does not support setting a level`},
+		{typ: "ggip", level: 1, expectedErr: `unsupported compression type "ggip"`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.typ), func(t *testing.T) {
+			err := tt.typ.ValidateParams(CompressionParams{Level: tt.level})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateZstdParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		typ         Type
+		params      ZstdParams
+		expectedErr string
+	}{
+		{
+			name:   "Valid",
+			typ:    TypeZstd,
+			params: ZstdParams{WindowSize: 1 << 20, Concurrency: 2, DictionaryFile: "dict"},
+		},
+		{
+			name:        "NotZstd",
+			typ:         TypeGzip,
+			params:      ZstdParams{Concurrency: 2},
+			expectedErr: `compression type "gzip" does not support the zstd parameters`,
+		},
+		{
+			name:        "WindowSizeNotPowerOfTwo",
+			typ:         TypeZstd,
+			params:      ZstdParams{WindowSize: 3 << 20},
+			expectedErr: "invalid zstd window size 3145728, the window size must be a power of two between 1024 and 536870912",
+		},
+		{
+			name:        "WindowSizeTooSmall",
+			typ:         TypeZstd,
+			params:      ZstdParams{WindowSize: 512},
+			expectedErr: "invalid zstd window size 512, the window size must be a power of two between 1024 and 536870912",
+		},
+		{
+			name:        "WindowSizeTooLarge",
+			typ:         TypeZstd,
+			params:      ZstdParams{WindowSize: 1 << 30},
+			expectedErr: "invalid zstd window size 1073741824, the window size must be a power of two between 1024 and 536870912",
+		},
+		{
+			name:        "NegativeConcurrency",
+			typ:         TypeZstd,
+			params:      ZstdParams{Concurrency: -1},
+			expectedErr: "invalid zstd concurrency -1, the concurrency must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.typ.ValidateParams(CompressionParams{Zstd: tt.params})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			dp := DecompressionParams{Zstd: tt.params}
+			assert.NoError(t, dp.Validate())
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configcompression // import "go.opentelemetry.io/collector/config/configcompression"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Codec is a compression algorithm registered to extend the built-in compression types.
+type Codec interface {
+	// NewWriter returns a writer compressing the data written to w with the given parameters.
+	// The compressed data is complete once the writer is closed.
+	NewWriter(w io.Writer, params CompressionParams) (io.WriteCloser, error)
+
+	// NewReader returns a reader decompressing the data read from r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+
+	// ValidateParams returns an error if the parameters are not supported by the codec.
+	ValidateParams(params CompressionParams) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[Type]Codec{}
+	hooks      []func(Type, Codec)
+)
+
+// Register makes the codec available with the given compression type, in the configuration of the
+// HTTP and gRPC clients and servers. The type is also the content coding advertised to the peers.
+//
+// Register is meant to be called during the initialization of a distribution, for example in an
+// init function, before the collector starts: the hooks added with OnRegister, like the registration
+// of the gRPC compressors by configgrpc, may only be allowed during the initialization.
+// It returns an error if the type is one of the built-in compression types or is already registered.
+func Register(typ Type, codec Codec) error {
+	if typ == typeEmpty || typ == typeNone {
+		return errors.New("a codec cannot be registered without a compression type")
+	}
+	if codec == nil {
+		return fmt.Errorf("codec for compression type %q is nil", typ)
+	}
+	if isBuiltIn(typ) {
+		return fmt.Errorf("compression type %q is built-in and cannot be registered", typ)
+	}
+
+	registryMu.Lock()
+	if _, ok := registry[typ]; ok {
+		registryMu.Unlock()
+		return fmt.Errorf("compression type %q is already registered", typ)
+	}
+	registry[typ] = codec
+	registeredHooks := hooks
+	registryMu.Unlock()
+
+	for _, hook := range registeredHooks {
+		hook(typ, codec)
+	}
+	return nil
+}
+
+// OnRegister adds a hook called with the codecs registered with Register, and right away with the codecs
+// already registered. It is meant to be called in an init function by the packages making the codecs
+// available to other libraries, like configgrpc registering them in the gRPC compressors.
+func OnRegister(hook func(typ Type, codec Codec)) {
+	registryMu.Lock()
+	hooks = append(hooks, hook)
+	registered := make(map[Type]Codec, len(registry))
+	for typ, codec := range registry {
+		registered[typ] = codec
+	}
+	registryMu.Unlock()
+
+	for typ, codec := range registered {
+		hook(typ, codec)
+	}
+}
+
+// Lookup returns the codec registered with the given compression type.
+// The built-in compression types are not returned.
+func Lookup(typ Type) (Codec, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	codec, ok := registry[typ]
+	return codec, ok
+}
+
+// RegisteredTypes returns the compression types registered with Register.
+func RegisteredTypes() []Type {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	types := make([]Type, 0, len(registry))
+	for typ := range registry {
+		types = append(types, typ)
+	}
+	return types
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configcompression
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upperCodec is a test codec "compressing" the data by upper-casing it.
+type upperCodec struct{}
+
+func (upperCodec) NewWriter(w io.Writer, _ CompressionParams) (io.WriteCloser, error) {
+	return upperWriter{w}, nil
+}
+
+func (upperCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+func (upperCodec) ValidateParams(params CompressionParams) error {
+	if params.Level > 3 {
+		return errors.New("level too high")
+	}
+	return nil
+}
+
+type upperWriter struct {
+	io.Writer
+}
+
+func (w upperWriter) Write(p []byte) (int, error) {
+	return w.Writer.Write(bytes.ToUpper(p))
+}
+
+func (upperWriter) Close() error {
+	return nil
+}
+
+func registerForTest(t *testing.T, typ Type, codec Codec) {
+	require.NoError(t, Register(typ, codec))
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(registry, typ)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	registerForTest(t, "upper", upperCodec{})
+
+	codec, ok := Lookup("upper")
+	require.True(t, ok)
+	assert.Equal(t, upperCodec{}, codec)
+
+	assert.Equal(t, []Type{"upper"}, RegisteredTypes())
+
+	_, ok = Lookup("lower")
+	assert.False(t, ok)
+
+	var typ Type
+	require.NoError(t, typ.UnmarshalText([]byte("upper")))
+	assert.Equal(t, Type("upper"), typ)
+	assert.True(t, typ.IsCompressed())
+	require.NoError(t, typ.ValidateParams(CompressionParams{Level: 3}))
+	require.EqualError(t, typ.ValidateParams(CompressionParams{Level: 4}), "level too high")
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	registerForTest(t, "upper", upperCodec{})
+
+	require.EqualError(t, Register("upper", upperCodec{}), `compression type "upper" is already registered`)
+	require.EqualError(t, Register(TypeGzip, upperCodec{}), `compression type "gzip" is built-in and cannot be registered`)
+	require.EqualError(t, Register("none", upperCodec{}), "a codec cannot be registered without a compression type")
+	require.EqualError(t, Register("", upperCodec{}), "a codec cannot be registered without a compression type")
+	require.EqualError(t, Register("lower", nil), `codec for compression type "lower" is nil`)
+}
+
+func TestOnRegister(t *testing.T) {
+	registerForTest(t, "upper", upperCodec{})
+
+	var hooked []Type
+	OnRegister(func(typ Type, _ Codec) {
+		hooked = append(hooked, typ)
+	})
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		hooks = hooks[:len(hooks)-1]
+	})
+	// The codecs already registered are passed to the hook right away.
+	assert.Equal(t, []Type{"upper"}, hooked)
+
+	registerForTest(t, "lower", upperCodec{})
+	assert.Equal(t, []Type{"upper", "lower"}, hooked)
+
+	require.Error(t, Register("lower", upperCodec{}))
+	assert.Equal(t, []Type{"upper", "lower"}, hooked)
+}
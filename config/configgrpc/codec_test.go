@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc
+
+import (
+	"compress/flate"
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+// countingFlateCodec is a codec registered in configcompression for the tests.
+type countingFlateCodec struct {
+	writers atomic.Int64
+	readers atomic.Int64
+}
+
+func (c *countingFlateCodec) NewWriter(w io.Writer, _ configcompression.CompressionParams) (io.WriteCloser, error) {
+	c.writers.Add(1)
+	return flate.NewWriter(w, flate.DefaultCompression)
+}
+
+func (c *countingFlateCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	c.readers.Add(1)
+	return flate.NewReader(r), nil
+}
+
+func (*countingFlateCodec) ValidateParams(configcompression.CompressionParams) error {
+	return nil
+}
+
+func TestRegisteredCodec(t *testing.T) {
+	const typeFlate = configcompression.Type("x-flate")
+	codec := &countingFlateCodec{}
+	require.NoError(t, configcompression.Register(typeFlate, codec))
+
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint:  "localhost:0",
+			Transport: confignet.TransportTypeTCP,
+		},
+	}
+	ln, err := gss.NetAddr.Listen(context.Background())
+	require.NoError(t, err)
+	srv, err := gss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	ptraceotlp.RegisterGRPCServer(srv, &grpcTraceServer{})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	var compression configcompression.Type
+	require.NoError(t, compression.UnmarshalText([]byte(typeFlate)))
+	gcs := &ClientConfig{
+		Endpoint:    ln.Addr().String(),
+		Compression: compression,
+		TLSSetting: configtls.ClientConfig{
+			Insecure: true,
+		},
+	}
+	grpcClientConn, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, grpcClientConn.Close()) }()
+
+	c := ptraceotlp.NewGRPCClient(grpcClientConn)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = c.Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	assert.Positive(t, codec.writers.Load())
+	assert.Positive(t, codec.readers.Load())
+}
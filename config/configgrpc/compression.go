@@ -0,0 +1,211 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc // import "go.opentelemetry.io/collector/config/configgrpc"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	"go.opentelemetry.io/collector/config/configcompression"
+)
+
+func init() {
+	// The codecs registered in configcompression are registered in the gRPC compressors, unless a gRPC
+	// compressor already uses the type. gRPC only allows it during the initialization.
+	configcompression.OnRegister(func(typ configcompression.Type, _ configcompression.Codec) {
+		if encoding.GetCompressor(string(typ)) == nil {
+			encoding.RegisterCompressor(grpcCompressor{typ: typ})
+		}
+	})
+}
+
+// grpcCompressor is the gRPC compressor of a codec registered in configcompression.
+type grpcCompressor struct {
+	typ configcompression.Type
+}
+
+var _ encoding.Compressor = grpcCompressor{}
+
+func (c grpcCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	codec, ok := configcompression.Lookup(c.typ)
+	if !ok {
+		return nil, fmt.Errorf("compression type %q is not registered", c.typ)
+	}
+	return codec.NewWriter(w, configcompression.CompressionParams{})
+}
+
+func (c grpcCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	codec, ok := configcompression.Lookup(c.typ)
+	if !ok {
+		return nil, fmt.Errorf("compression type %q is not registered", c.typ)
+	}
+	return codec.NewReader(r)
+}
+
+func (c grpcCompressor) Name() string {
+	return string(c.typ)
+}
+
+type writerReset interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// paramsCompressor compresses the messages of a client with its compression parameters. The compressors
+// registered in gRPC are shared by all the clients and servers and always use the default parameters.
+type paramsCompressor struct {
+	name string
+	pool sync.Pool
+	// newWriter creates the writers of the registered codecs, which are not pooled.
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+var _ grpc.Compressor = (*paramsCompressor)(nil) //nolint:staticcheck // The compressor must be specific to the client.
+
+// newParamsCompressor returns a compressor using the compression parameters, already validated.
+func newParamsCompressor(compressionType configcompression.Type, params configcompression.CompressionParams) (*paramsCompressor, error) {
+	c := &paramsCompressor{name: string(compressionType)}
+	switch compressionType {
+	case configcompression.TypeGzip:
+		level := gzip.DefaultCompression
+		if params.Level != configcompression.DefaultCompressionLevel {
+			level = int(params.Level)
+		}
+		c.pool.New = func() any { gw, _ := gzip.NewWriterLevel(nil, level); return gw }
+	case configcompression.TypeZstd:
+		opts, err := zstdEncoderOptions(params)
+		if err != nil {
+			return nil, err
+		}
+		// Create a first encoder to report the errors of the options, the pool creates the next ones.
+		zw, err := zstd.NewWriter(nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		c.pool.New = func() any { zw, _ := zstd.NewWriter(nil, opts...); return zw }
+		c.pool.Put(zw)
+	default:
+		codec, ok := configcompression.Lookup(compressionType)
+		if !ok {
+			return nil, fmt.Errorf("unsupported compression type %q", compressionType)
+		}
+		c.newWriter = func(w io.Writer) (io.WriteCloser, error) {
+			return codec.NewWriter(w, params)
+		}
+	}
+	return c, nil
+}
+
+func (c *paramsCompressor) Do(w io.Writer, p []byte) error {
+	if c.newWriter != nil {
+		cw, err := c.newWriter(w)
+		if err != nil {
+			return err
+		}
+		if _, err = cw.Write(p); err != nil {
+			return err
+		}
+		return cw.Close()
+	}
+
+	cw := c.pool.Get().(writerReset)
+	defer c.pool.Put(cw)
+	cw.Reset(w)
+	if _, err := cw.Write(p); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+func (c *paramsCompressor) Type() string {
+	return c.name
+}
+
+// zstdEncoderOptions returns the options of the zstd encoders, loading the dictionary if one is configured.
+func zstdEncoderOptions(params configcompression.CompressionParams) ([]zstd.EOption, error) {
+	concurrency := params.Zstd.Concurrency
+	if concurrency == 0 {
+		// Concurrency 1 disables async encoding via goroutines, gRPC messages are compressed as a whole.
+		concurrency = 1
+	}
+	opts := []zstd.EOption{zstd.WithEncoderConcurrency(concurrency)}
+	if params.Level != configcompression.DefaultCompressionLevel {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(int(params.Level))))
+	}
+	if params.Zstd.WindowSize != 0 {
+		opts = append(opts, zstd.WithWindowSize(params.Zstd.WindowSize))
+	}
+	if params.Zstd.DictionaryFile != "" {
+		dict, err := os.ReadFile(params.Zstd.DictionaryFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load zstd dictionary: %w", err)
+		}
+		opts = append(opts, zstd.WithEncoderDict(dict))
+	}
+	return opts, nil
+}
+
+// zstdDecompressor decompresses the zstd messages received by a server with its decompression parameters.
+type zstdDecompressor struct {
+	pool sync.Pool
+}
+
+var _ grpc.Decompressor = (*zstdDecompressor)(nil) //nolint:staticcheck // The decompressor must be specific to the server.
+
+// newZstdDecompressor returns a zstd decompressor limiting the decompressed messages to maxSize bytes.
+func newZstdDecompressor(params configcompression.ZstdParams, maxSize int) (*zstdDecompressor, error) {
+	concurrency := params.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	opts := []zstd.DOption{
+		zstd.WithDecoderConcurrency(concurrency),
+		zstd.WithDecoderMaxMemory(uint64(maxSize)),
+	}
+	if params.WindowSize != 0 {
+		opts = append(opts, zstd.WithDecoderMaxWindow(uint64(params.WindowSize)))
+	}
+	if params.DictionaryFile != "" {
+		dict, err := os.ReadFile(params.DictionaryFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load zstd dictionary: %w", err)
+		}
+		opts = append(opts, zstd.WithDecoderDicts(dict))
+	}
+
+	// Create a first decoder to report the errors of the options, the pool creates the next ones.
+	zr, err := zstd.NewReader(nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	d := &zstdDecompressor{}
+	d.pool.New = func() any { zr, _ := zstd.NewReader(nil, opts...); return zr }
+	d.pool.Put(zr)
+	return d, nil
+}
+
+func (d *zstdDecompressor) Do(r io.Reader) ([]byte, error) {
+	zr := d.pool.Get().(*zstd.Decoder)
+	defer d.pool.Put(zr)
+	if err := zr.Reset(r); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, zr); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *zstdDecompressor) Type() string {
+	return string(configcompression.TypeZstd)
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+func TestCompressionParams(t *testing.T) {
+	dictFile := writeZstdDict(t)
+
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint:  "localhost:0",
+			Transport: confignet.TransportTypeTCP,
+		},
+		DecompressionParams: configcompression.DecompressionParams{
+			Zstd: configcompression.ZstdParams{WindowSize: 1 << 20, DictionaryFile: dictFile},
+		},
+	}
+	require.NoError(t, gss.Validate())
+	ln, err := gss.NetAddr.Listen(context.Background())
+	require.NoError(t, err)
+	srv, err := gss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	ptraceotlp.RegisterGRPCServer(srv, &grpcTraceServer{})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	td := ptrace.NewTraces()
+	for i := 0; i < 100; i++ {
+		td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(fmt.Sprintf("span-%d", i))
+	}
+
+	tests := []struct {
+		name        string
+		compression configcompression.Type
+		params      configcompression.CompressionParams
+	}{
+		{
+			name:        "GzipLevel",
+			compression: configcompression.TypeGzip,
+			params:      configcompression.CompressionParams{Level: 9},
+		},
+		{
+			name:        "ZstdLevel",
+			compression: configcompression.TypeZstd,
+			params:      configcompression.CompressionParams{Level: 3},
+		},
+		{
+			name:        "ZstdDictionary",
+			compression: configcompression.TypeZstd,
+			params:      configcompression.CompressionParams{Level: 19, Zstd: configcompression.ZstdParams{WindowSize: 1 << 16, Concurrency: 2, DictionaryFile: dictFile}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gcs := &ClientConfig{
+				Endpoint:          ln.Addr().String(),
+				Compression:       tt.compression,
+				CompressionParams: tt.params,
+				TLSSetting: configtls.ClientConfig{
+					Insecure: true,
+				},
+			}
+			require.NoError(t, gcs.Validate())
+			grpcClientConn, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+			defer func() { assert.NoError(t, grpcClientConn.Close()) }()
+
+			c := ptraceotlp.NewGRPCClient(grpcClientConn)
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_, err = c.Export(ctx, ptraceotlp.NewExportRequestFromTraces(td), grpc.WaitForReady(true))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCompressionParamsInvalid(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.dict")
+
+	gcs := &ClientConfig{
+		Endpoint:          "localhost:1234",
+		Compression:       configcompression.TypeSnappy,
+		CompressionParams: configcompression.CompressionParams{Level: 1},
+	}
+	require.EqualError(t, gcs.Validate(), `compression type "snappy" does not support setting a level`)
+
+	gcs.Compression = configcompression.TypeZstd
+	gcs.CompressionParams = configcompression.CompressionParams{Zstd: configcompression.ZstdParams{DictionaryFile: missing}}
+	require.NoError(t, gcs.Validate())
+	_, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.ErrorContains(t, err, "failed to load zstd dictionary")
+
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint:  "localhost:0",
+			Transport: confignet.TransportTypeTCP,
+		},
+		DecompressionParams: configcompression.DecompressionParams{
+			Zstd: configcompression.ZstdParams{WindowSize: 1000},
+		},
+	}
+	require.EqualError(t, gss.Validate(), "invalid zstd window size 1000, the window size must be a power of two between 1024 and 536870912")
+
+	gss.DecompressionParams.Zstd = configcompression.ZstdParams{DictionaryFile: missing}
+	_, err = gss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.ErrorContains(t, err, "failed to load zstd dictionary")
+}
+
+func TestZstdDecompressorMaxSize(t *testing.T) {
+	dc, err := newZstdDecompressor(configcompression.ZstdParams{}, 1024)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	cp, err := newParamsCompressor(configcompression.TypeZstd, configcompression.CompressionParams{Level: 1})
+	require.NoError(t, err)
+	require.NoError(t, cp.Do(&buf, make([]byte, 512)))
+	out, err := dc.Do(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Len(t, out, 512)
+
+	buf.Reset()
+	require.NoError(t, cp.Do(&buf, make([]byte, 4096)))
+	_, err = dc.Do(bytes.NewReader(buf.Bytes()))
+	require.Error(t, err)
+}
+
+// writeZstdDict writes a zstd dictionary built from span names and returns its path.
+func writeZstdDict(tb testing.TB) string {
+	var contents [][]byte
+	for i := 0; i < 100; i++ {
+		contents = append(contents, []byte(fmt.Sprintf("resource-%d scope span-%d", i, i)))
+	}
+	dict, err := zstd.BuildDict(zstd.BuildDictOptions{
+		ID:       1,
+		Contents: contents,
+		History:  bytes.Join(contents, nil),
+		Offsets:  [3]int{1, 4, 8},
+	})
+	require.NoError(tb, err)
+	dictFile := filepath.Join(tb.TempDir(), "spans.dict")
+	require.NoError(tb, os.WriteFile(dictFile, dict, 0o600))
+	return dictFile
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"strings"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/balancer"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/encoding/gzip"
@@ -26,6 +28,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
 
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
@@ -41,6 +44,9 @@ import (
 
 var errMetadataNotFound = errors.New("no request metadata found")
 
+// defaultMaxRecvMsgSize is the default maximum size of the messages received by a gRPC server.
+const defaultMaxRecvMsgSize = 4 * 1024 * 1024
+
 // KeepaliveClientConfig exposes the keepalive.ClientParameters to be used by the exporter.
 // Refer to the original data-structure for the meaning of each parameter:
 // https://godoc.org/google.golang.org/grpc/keepalive#ClientParameters
@@ -73,6 +79,10 @@ type ClientConfig struct {
 	// The compression key for supported compression types within collector.
 	Compression configcompression.Type `mapstructure:"compression"`
 
+	// CompressionParams are the settings of the compression, like its level. The compressor of the client
+	// is used instead of the one registered in gRPC when they are set.
+	CompressionParams configcompression.CompressionParams `mapstructure:"compression_params"`
+
 	// TLSSetting struct exposes TLS client configuration.
 	TLSSetting configtls.ClientConfig `mapstructure:"tls"`
 
@@ -105,6 +115,18 @@ type ClientConfig struct {
 
 	// Auth configuration for outgoing RPCs.
 	Auth *configauth.Authentication `mapstructure:"auth"`
+
+	// Endpoints is a list of "host:port" endpoints the RPCs are balanced over, with the BalancerName, instead
+	// of the Endpoint. The outcome of the unary RPCs is tracked for each address of the endpoints.
+	Endpoints []string `mapstructure:"endpoints"`
+
+	// Resolver configures how the Endpoints are resolved into addresses: "static" (default) resolves them once,
+	// "dns" resolves them every 30 seconds, and resolves the endpoints without a port with their SRV records.
+	Resolver string `mapstructure:"resolver"`
+
+	// OutlierDetection ejects temporarily the addresses of the Endpoints failing more than the others.
+	// Only used with the Endpoints, nil disables the ejection.
+	OutlierDetection *OutlierDetectionConfig `mapstructure:"outlier_detection"`
 }
 
 // NewDefaultClientConfig returns a new instance of ClientConfig with default values.
@@ -190,6 +212,13 @@ type ServerConfig struct {
 	// Auth for this receiver
 	Auth *configauth.Authentication `mapstructure:"auth"`
 
+	// MemoryLimiter is the ID of the memory_limiter extension used to refuse the requests
+	// while the memory usage is too high.
+	MemoryLimiter *component.ID `mapstructure:"memory_limiter"`
+
+	// DecompressionParams are the settings of the decompression of the received messages.
+	DecompressionParams configcompression.DecompressionParams `mapstructure:"decompression_params"`
+
 	// Include propagates the incoming connection's metadata to downstream consumers.
 	IncludeMetadata bool `mapstructure:"include_metadata"`
 }
@@ -209,7 +238,13 @@ func (gcs *ClientConfig) Validate() error {
 		}
 	}
 
-	return nil
+	if gcs.CompressionParams != (configcompression.CompressionParams{}) {
+		if err := gcs.Compression.ValidateParams(gcs.CompressionParams); err != nil {
+			return err
+		}
+	}
+
+	return gcs.validateEndpoints()
 }
 
 // sanitizedEndpoint strips the prefix of either http:// or https:// from configgrpc.ClientConfig.Endpoint.
@@ -257,21 +292,77 @@ func (gcs *ClientConfig) ToClientConn(
 	settings component.TelemetrySettings,
 	extraOpts ...ToClientConnOption,
 ) (*grpc.ClientConn, error) {
-	grpcOpts, err := gcs.getGrpcDialOptions(ctx, host, settings, extraOpts)
+	// The TLS files watched because of reload_on_file_change are watched until the connection is closed.
+	var closers []io.Closer
+	conn, err := gcs.newClientConn(ctx, host, settings, extraOpts, func(closer io.Closer) {
+		closers = append(closers, closer)
+	})
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+	if len(closers) > 0 {
+		go closeOnShutdown(conn, closers)
+	}
+	return conn, nil
+}
+
+func (gcs *ClientConfig) newClientConn(
+	ctx context.Context,
+	host component.Host,
+	settings component.TelemetrySettings,
+	extraOpts []ToClientConnOption,
+	onWatch func(io.Closer),
+) (*grpc.ClientConn, error) {
+	grpcOpts, err := gcs.getGrpcDialOptions(ctx, host, settings, extraOpts, onWatch)
 	if err != nil {
 		return nil, err
 	}
+	grpcOpts = append(grpcOpts, grpc.WithChainUnaryInterceptor(throttleUnaryClientInterceptor))
+	if len(gcs.Endpoints) > 0 {
+		er, err := newEndpointsResolver(gcs, settings)
+		if err != nil {
+			return nil, err
+		}
+		grpcOpts = append(grpcOpts, grpc.WithResolvers(er), grpc.WithChainUnaryInterceptor(er.unaryClientInterceptor))
+		return grpc.NewClient(er.target(), grpcOpts...)
+	}
 	return grpc.NewClient(gcs.sanitizedEndpoint(), grpcOpts...)
 }
 
+// closeOnShutdown closes the closers once the client connection is closed.
+func closeOnShutdown(conn *grpc.ClientConn, closers []io.Closer) {
+	for state := conn.GetState(); state != connectivity.Shutdown; state = conn.GetState() {
+		conn.WaitForStateChange(context.Background(), state)
+	}
+	closeAll(closers)
+}
+
+func closeAll(closers []io.Closer) {
+	for _, closer := range closers {
+		_ = closer.Close()
+	}
+}
+
 func (gcs *ClientConfig) getGrpcDialOptions(
 	ctx context.Context,
 	host component.Host,
 	settings component.TelemetrySettings,
 	extraOpts []ToClientConnOption,
+	onWatch func(io.Closer),
 ) ([]grpc.DialOption, error) {
 	var opts []grpc.DialOption
-	if gcs.Compression.IsCompressed() {
+	switch {
+	case gcs.Compression.IsCompressed() && gcs.CompressionParams != (configcompression.CompressionParams{}):
+		if err := gcs.Compression.ValidateParams(gcs.CompressionParams); err != nil {
+			return nil, err
+		}
+		cp, err := newParamsCompressor(gcs.Compression, gcs.CompressionParams)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.WithCompressor(cp)) //nolint:staticcheck // UseCompressor only selects the compressors registered in gRPC.
+	case gcs.Compression.IsCompressed():
 		cp, err := getGRPCCompressionName(gcs.Compression)
 		if err != nil {
 			return nil, err
@@ -279,7 +370,11 @@ func (gcs *ClientConfig) getGrpcDialOptions(
 		opts = append(opts, grpc.WithDefaultCallOptions(grpc.UseCompressor(cp)))
 	}
 
-	tlsCfg, err := gcs.TLSSetting.LoadTLSConfig(ctx)
+	reloadOpts, err := internal.TLSReloadOptions(host, settings, scopeName)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, err := gcs.TLSSetting.LoadTLSConfig(ctx, append(reloadOpts, configtls.WithReloadCloser(onWatch))...)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +459,7 @@ func (gss *ServerConfig) Validate() error {
 		return fmt.Errorf("invalid write_buffer_size value: %d", gss.WriteBufferSize)
 	}
 
-	return nil
+	return gss.DecompressionParams.Validate()
 }
 
 // ToServerOption is a sealed interface wrapping options for [ServerConfig.ToServer].
@@ -382,14 +477,27 @@ func WithGrpcServerOption(opt grpc.ServerOption) ToServerOption {
 }
 func (grpcServerOptionWrapper) isToServerOption() {}
 
-// ToServer returns a [grpc.Server] for the configuration.
+type tapHandleWrapper struct {
+	handle tap.ServerInHandle
+}
+
+// WithTapHandle adds a [tap.ServerInHandle] to the server. gRPC only allows one tap handle per server, the handles
+// added with this option are chained after the one of the memory_limiter setting, and must be used instead of
+// [grpc.InTapHandle].
+func WithTapHandle(handle tap.ServerInHandle) ToServerOption {
+	return tapHandleWrapper{handle: handle}
+}
+func (tapHandleWrapper) isToServerOption() {}
+
+// ToServer returns a [grpc.Server] for the configuration. The TLS files watched because of reload_on_file_change
+// are watched until the context is done, the callers cancel it once the server is stopped.
 func (gss *ServerConfig) ToServer(
-	_ context.Context,
+	ctx context.Context,
 	host component.Host,
 	settings component.TelemetrySettings,
 	extraOpts ...ToServerOption,
 ) (*grpc.Server, error) {
-	grpcOpts, err := gss.getGrpcServerOptions(host, settings, extraOpts)
+	grpcOpts, err := gss.getGrpcServerOptions(ctx, host, settings, extraOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -397,6 +505,7 @@ func (gss *ServerConfig) ToServer(
 }
 
 func (gss *ServerConfig) getGrpcServerOptions(
+	ctx context.Context,
 	host component.Host,
 	settings component.TelemetrySettings,
 	extraOpts []ToServerOption,
@@ -409,7 +518,11 @@ func (gss *ServerConfig) getGrpcServerOptions(
 	var opts []grpc.ServerOption
 
 	if gss.TLSSetting != nil {
-		tlsCfg, err := gss.TLSSetting.LoadTLSConfig(context.Background())
+		reloadOpts, err := internal.TLSReloadOptions(host, settings, scopeName)
+		if err != nil {
+			return nil, err
+		}
+		tlsCfg, err := gss.TLSSetting.LoadTLSConfig(ctx, reloadOpts...)
 		if err != nil {
 			return nil, err
 		}
@@ -420,6 +533,21 @@ func (gss *ServerConfig) getGrpcServerOptions(
 		opts = append(opts, grpc.MaxRecvMsgSize(gss.MaxRecvMsgSizeMiB*1024*1024))
 	}
 
+	if gss.DecompressionParams.Zstd != (configcompression.ZstdParams{}) {
+		if err := gss.DecompressionParams.Validate(); err != nil {
+			return nil, err
+		}
+		maxRecvMsgSize := defaultMaxRecvMsgSize
+		if gss.MaxRecvMsgSizeMiB > 0 && gss.MaxRecvMsgSizeMiB*1024*1024 > 0 {
+			maxRecvMsgSize = gss.MaxRecvMsgSizeMiB * 1024 * 1024
+		}
+		dc, err := newZstdDecompressor(gss.DecompressionParams.Zstd, maxRecvMsgSize)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.RPCDecompressor(dc)) //nolint:staticcheck // The registered zstd decompressor uses the default parameters.
+	}
+
 	if gss.MaxConcurrentStreams > 0 {
 		opts = append(opts, grpc.MaxConcurrentStreams(gss.MaxConcurrentStreams))
 	}
@@ -462,6 +590,16 @@ func (gss *ServerConfig) getGrpcServerOptions(
 
 	var uInterceptors []grpc.UnaryServerInterceptor
 	var sInterceptors []grpc.StreamServerInterceptor
+	var tapHandles []tap.ServerInHandle
+
+	if gss.MemoryLimiter != nil {
+		ml, err := getMemoryLimiter(host.GetExtensions(), *gss.MemoryLimiter)
+		if err != nil {
+			return nil, err
+		}
+
+		tapHandles = append(tapHandles, memoryLimiterTapHandle(ml))
+	}
 
 	if gss.Auth != nil {
 		authenticator, err := gss.Auth.GetServerAuthenticator(context.Background(), host.GetExtensions())
@@ -485,20 +623,46 @@ func (gss *ServerConfig) getGrpcServerOptions(
 
 	// Enable OpenTelemetry observability plugin.
 
-	uInterceptors = append(uInterceptors, enhanceWithClientInformation(gss.IncludeMetadata))
-	sInterceptors = append(sInterceptors, enhanceStreamWithClientInformation(gss.IncludeMetadata))
+	// The client certificates are verified by the TLS config itself for SPIFFE.
+	peerVerified := gss.TLSSetting != nil && gss.TLSSetting.SPIFFE != nil
+	uInterceptors = append(uInterceptors, enhanceWithClientInformation(gss.IncludeMetadata, peerVerified))
+	sInterceptors = append(sInterceptors, enhanceStreamWithClientInformation(gss.IncludeMetadata, peerVerified))
 
 	opts = append(opts, grpc.StatsHandler(otelgrpc.NewServerHandler(otelOpts...)), grpc.ChainUnaryInterceptor(uInterceptors...), grpc.ChainStreamInterceptor(sInterceptors...))
 
 	for _, opt := range extraOpts {
-		if wrapper, ok := opt.(grpcServerOptionWrapper); ok {
+		switch wrapper := opt.(type) {
+		case grpcServerOptionWrapper:
 			opts = append(opts, wrapper.opt)
+		case tapHandleWrapper:
+			tapHandles = append(tapHandles, wrapper.handle)
 		}
 	}
 
+	if len(tapHandles) > 0 {
+		opts = append(opts, grpc.InTapHandle(chainTapHandles(tapHandles)))
+	}
+
 	return opts, nil
 }
 
+// chainTapHandles returns a tap handle calling the given ones in order, the RPC is refused by the first handle
+// returning an error.
+func chainTapHandles(handles []tap.ServerInHandle) tap.ServerInHandle {
+	if len(handles) == 1 {
+		return handles[0]
+	}
+	return func(ctx context.Context, info *tap.Info) (context.Context, error) {
+		for _, handle := range handles {
+			var err error
+			if ctx, err = handle(ctx, info); err != nil {
+				return nil, err
+			}
+		}
+		return ctx, nil
+	}
+}
+
 // getGRPCCompressionName returns compression name registered in grpc.
 func getGRPCCompressionName(compressionType configcompression.Type) (string, error) {
 	switch compressionType {
@@ -509,30 +673,50 @@ func getGRPCCompressionName(compressionType configcompression.Type) (string, err
 	case configcompression.TypeZstd:
 		return zstd.Name, nil
 	default:
+		// The codecs registered in configcompression are also registered in the gRPC compressors.
+		if _, ok := configcompression.Lookup(compressionType); ok {
+			return string(compressionType), nil
+		}
 		return "", fmt.Errorf("unsupported compression type %q", compressionType)
 	}
 }
 
 // enhanceWithClientInformation intercepts the incoming RPC, replacing the incoming context with one that includes
 // a client.Info, potentially with the peer's address.
-func enhanceWithClientInformation(includeMetadata bool) func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+func enhanceWithClientInformation(includeMetadata bool, peerVerified bool) func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		return handler(contextWithClient(ctx, includeMetadata), req)
+		return handler(contextWithClient(ctx, includeMetadata, peerVerified), req)
 	}
 }
 
-func enhanceStreamWithClientInformation(includeMetadata bool) func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+func enhanceStreamWithClientInformation(includeMetadata bool, peerVerified bool) func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		return handler(srv, wrapServerStream(contextWithClient(ss.Context(), includeMetadata), ss))
+		return handler(srv, wrapServerStream(contextWithClient(ss.Context(), includeMetadata, peerVerified), ss))
 	}
 }
 
 // contextWithClient attempts to add the peer address to the client.Info from the context. When no
-// client.Info exists in the context, one is created.
-func contextWithClient(ctx context.Context, includeMetadata bool) context.Context {
+// client.Info exists in the context, one is created. The certificate presented by the client is used as its
+// identity when peerVerified is set, crypto/tls leaving the verified chains empty when the TLS config verifies
+// the client certificates itself.
+func contextWithClient(ctx context.Context, includeMetadata bool, peerVerified bool) context.Context {
 	cl := client.FromContext(ctx)
+	var tlsInfo *client.TLSInfo
 	if p, ok := peer.FromContext(ctx); ok {
 		cl.Addr = p.Addr
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			tlsInfo = client.NewTLSInfo(&ti.State)
+			if tlsInfo == nil && peerVerified {
+				tlsInfo = client.NewPeerTLSInfo(&ti.State)
+			}
+		}
+	}
+	// The identity verified by mutual TLS is added to the metadata even if the request metadata is not included.
+	if tlsInfo != nil {
+		cl.TLS = tlsInfo
+		md := map[string][]string{}
+		tlsInfo.SetMetadata(md)
+		cl.Metadata = client.NewMetadata(md)
 	}
 	if includeMetadata {
 		if md, ok := metadata.FromIncomingContext(ctx); ok {
@@ -540,6 +724,7 @@ func contextWithClient(ctx context.Context, includeMetadata bool) context.Contex
 			if len(md[client.MetadataHostName]) == 0 && len(md[":authority"]) > 0 {
 				copiedMD[client.MetadataHostName] = md[":authority"]
 			}
+			tlsInfo.SetMetadata(copiedMD)
 			cl.Metadata = client.NewMetadata(copiedMD)
 		}
 	}
@@ -5,6 +5,9 @@ package configgrpc
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"net"
 	"os"
@@ -19,6 +22,7 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
@@ -108,7 +112,7 @@ func TestDefaultGrpcClientSettings(t *testing.T) {
 			Insecure: true,
 		},
 	}
-	opts, err := gcs.getGrpcDialOptions(context.Background(), componenttest.NewNopHost(), tt.TelemetrySettings(), []ToClientConnOption{})
+	opts, err := gcs.getGrpcDialOptions(context.Background(), componenttest.NewNopHost(), tt.TelemetrySettings(), []ToClientConnOption{}, nil)
 	require.NoError(t, err)
 	assert.Len(t, opts, 2)
 }
@@ -129,6 +133,7 @@ func TestGrpcClientExtraOption(t *testing.T) {
 		componenttest.NewNopHost(),
 		tt.TelemetrySettings(),
 		[]ToClientConnOption{WithGrpcDialOption(extraOpt)},
+		nil,
 	)
 	require.NoError(t, err)
 	assert.Len(t, opts, 3)
@@ -235,7 +240,7 @@ func TestAllGrpcClientSettings(t *testing.T) {
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			opts, err := test.settings.getGrpcDialOptions(context.Background(), test.host, tt.TelemetrySettings(), []ToClientConnOption{})
+			opts, err := test.settings.getGrpcDialOptions(context.Background(), test.host, tt.TelemetrySettings(), []ToClientConnOption{}, nil)
 			require.NoError(t, err)
 			assert.Len(t, opts, 9)
 		})
@@ -248,7 +253,7 @@ func TestDefaultGrpcServerSettings(t *testing.T) {
 			Endpoint: "0.0.0.0:1234",
 		},
 	}
-	opts, err := gss.getGrpcServerOptions(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), []ToServerOption{})
+	opts, err := gss.getGrpcServerOptions(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), []ToServerOption{})
 	require.NoError(t, err)
 	assert.Len(t, opts, 3)
 }
@@ -261,6 +266,7 @@ func TestGrpcServerExtraOption(t *testing.T) {
 	}
 	extraOpt := grpc.ConnectionTimeout(1_000_000_000)
 	opts, err := gss.getGrpcServerOptions(
+		context.Background(),
 		componenttest.NewNopHost(),
 		componenttest.NewNopTelemetrySettings(),
 		[]ToServerOption{WithGrpcServerOption(extraOpt)},
@@ -350,7 +356,7 @@ func TestAllGrpcServerSettingsExceptAuth(t *testing.T) {
 			},
 		},
 	}
-	opts, err := gss.getGrpcServerOptions(componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), []ToServerOption{})
+	opts, err := gss.getGrpcServerOptions(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), []ToServerOption{})
 	require.NoError(t, err)
 	assert.Len(t, opts, 10)
 }
@@ -507,7 +513,7 @@ func TestUseSecure(t *testing.T) {
 		TLSSetting:  configtls.ClientConfig{},
 		Keepalive:   nil,
 	}
-	dialOpts, err := gcs.getGrpcDialOptions(context.Background(), componenttest.NewNopHost(), tt.TelemetrySettings(), []ToClientConnOption{})
+	dialOpts, err := gcs.getGrpcDialOptions(context.Background(), componenttest.NewNopHost(), tt.TelemetrySettings(), []ToClientConnOption{}, nil)
 	require.NoError(t, err)
 	assert.Len(t, dialOpts, 2)
 }
@@ -552,7 +558,7 @@ func TestGRPCServerWarning(t *testing.T) {
 			logger, observed := observer.New(zap.DebugLevel)
 			set.Logger = zap.New(logger)
 
-			opts, err := test.settings.getGrpcServerOptions(componenttest.NewNopHost(), set, []ToServerOption{})
+			opts, err := test.settings.getGrpcServerOptions(context.Background(), componenttest.NewNopHost(), set, []ToServerOption{})
 			require.NoError(t, err)
 			require.NotNil(t, opts)
 			_ = grpc.NewServer(opts...)
@@ -822,11 +828,13 @@ func TestReceiveOnUnixDomainSocket(t *testing.T) {
 }
 
 func TestContextWithClient(t *testing.T) {
+	clientCert := &x509.Certificate{Subject: pkix.Name{CommonName: "agent"}}
 	testCases := []struct {
-		desc       string
-		input      context.Context
-		doMetadata bool
-		expected   client.Info
+		desc         string
+		input        context.Context
+		doMetadata   bool
+		peerVerified bool
+		expected     client.Info
 	}{
 		{
 			desc:     "no peer information, empty client",
@@ -916,10 +924,76 @@ func TestContextWithClient(t *testing.T) {
 				Metadata: client.NewMetadata(map[string][]string{"test-metadata-key": {"test-value"}, ":authority": {"localhost:55443"}, "Host": {"localhost:55443"}}),
 			},
 		},
+		{
+			desc: "peer with verified client certificate, no metadata processing",
+			input: peer.NewContext(context.Background(), &peer.Peer{
+				Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}}},
+			}),
+			expected: client.Info{
+				Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+				Metadata: client.NewMetadata(map[string][]string{client.MetadataTLSSubject: {"CN=agent"}, client.MetadataTLSCommonName: {"agent"}}),
+				TLS:      &client.TLSInfo{Subject: "CN=agent", CommonName: "agent"},
+			},
+		},
+		{
+			desc: "peer with verified client certificate and forged identity metadata",
+			input: metadata.NewIncomingContext(
+				peer.NewContext(context.Background(), &peer.Peer{
+					Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+					AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}}},
+				}),
+				metadata.Pairs("test-metadata-key", "test-value", client.MetadataTLSCommonName, "forged"),
+			),
+			doMetadata: true,
+			expected: client.Info{
+				Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+				Metadata: client.NewMetadata(map[string][]string{"test-metadata-key": {"test-value"}, client.MetadataTLSSubject: {"CN=agent"}, client.MetadataTLSCommonName: {"agent"}}),
+				TLS:      &client.TLSInfo{Subject: "CN=agent", CommonName: "agent"},
+			},
+		},
+		{
+			desc: "peer with client certificate verified by the TLS config",
+			input: peer.NewContext(context.Background(), &peer.Peer{
+				Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{HandshakeComplete: true, PeerCertificates: []*x509.Certificate{clientCert}}},
+			}),
+			peerVerified: true,
+			expected: client.Info{
+				Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+				Metadata: client.NewMetadata(map[string][]string{client.MetadataTLSSubject: {"CN=agent"}, client.MetadataTLSCommonName: {"agent"}}),
+				TLS:      &client.TLSInfo{Subject: "CN=agent", CommonName: "agent"},
+			},
+		},
+		{
+			desc: "peer with unverified client certificate",
+			input: peer.NewContext(context.Background(), &peer.Peer{
+				Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{HandshakeComplete: true, PeerCertificates: []*x509.Certificate{clientCert}}},
+			}),
+			expected: client.Info{
+				Addr: &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+			},
+		},
+		{
+			desc: "peer without client certificate and forged identity metadata",
+			input: metadata.NewIncomingContext(
+				peer.NewContext(context.Background(), &peer.Peer{
+					Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+					AuthInfo: credentials.TLSInfo{},
+				}),
+				metadata.Pairs("test-metadata-key", "test-value", client.MetadataTLSCommonName, "forged"),
+			),
+			doMetadata: true,
+			expected: client.Info{
+				Addr:     &net.IPAddr{IP: net.IPv4(1, 2, 3, 4)},
+				Metadata: client.NewMetadata(map[string][]string{"test-metadata-key": {"test-value"}}),
+			},
+		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.desc, func(t *testing.T) {
-			cl := client.FromContext(contextWithClient(tt.input, tt.doMetadata))
+			cl := client.FromContext(contextWithClient(tt.input, tt.doMetadata, tt.peerVerified))
 			assert.Equal(t, tt.expected, cl)
 		})
 	}
@@ -942,7 +1016,7 @@ func TestStreamInterceptorEnhancesClient(t *testing.T) {
 	}
 
 	// test
-	err := enhanceStreamWithClientInformation(false)(nil, stream, nil, handler)
+	err := enhanceStreamWithClientInformation(false, false)(nil, stream, nil, handler)
 
 	// verify
 	require.NoError(t, err)
@@ -0,0 +1,459 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc // import "go.opentelemetry.io/collector/config/configgrpc"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	resolverStatic = "static"
+	resolverDNS    = "dns"
+
+	// endpointsScheme is the scheme of the resolver of the Endpoints, local to each client connection.
+	endpointsScheme = "otelcol-endpoints"
+
+	// dnsResolveInterval is how often the Endpoints are resolved again by the dns resolver.
+	dnsResolveInterval = 30 * time.Second
+
+	// dnsResolveTimeout is the timeout of the resolution of all the Endpoints.
+	dnsResolveTimeout = 10 * time.Second
+
+	scopeName = "go.opentelemetry.io/collector/config/configgrpc"
+)
+
+// OutlierDetectionConfig configures the temporary ejection of the endpoints failing more than the others.
+type OutlierDetectionConfig struct {
+	// Interval is how often the endpoints are evaluated. The counts of RPCs are reset after each evaluation.
+	Interval time.Duration `mapstructure:"interval"`
+
+	// BaseEjectionTime is how long an endpoint is ejected, multiplied by the number of times it was ejected
+	// in a row.
+	BaseEjectionTime time.Duration `mapstructure:"base_ejection_time"`
+
+	// MaxEjectionTime is the maximum time an endpoint is ejected.
+	MaxEjectionTime time.Duration `mapstructure:"max_ejection_time"`
+
+	// MaxEjectionPercent is the maximum percentage of the endpoints ejected at the same time.
+	MaxEjectionPercent int `mapstructure:"max_ejection_percent"`
+
+	// FailurePercentageThreshold is the percentage of RPCs failed over an interval ejecting an endpoint.
+	FailurePercentageThreshold int `mapstructure:"failure_percentage_threshold"`
+
+	// MinimumRequests is the minimum number of RPCs sent to an endpoint over an interval to eject it.
+	MinimumRequests int `mapstructure:"minimum_requests"`
+}
+
+// NewDefaultOutlierDetectionConfig returns a new instance of OutlierDetectionConfig with default values.
+func NewDefaultOutlierDetectionConfig() *OutlierDetectionConfig {
+	return &OutlierDetectionConfig{
+		Interval:                   10 * time.Second,
+		BaseEjectionTime:           30 * time.Second,
+		MaxEjectionTime:            5 * time.Minute,
+		MaxEjectionPercent:         50,
+		FailurePercentageThreshold: 50,
+		MinimumRequests:            10,
+	}
+}
+
+// Validate checks if the OutlierDetectionConfig configuration is valid.
+func (cfg *OutlierDetectionConfig) Validate() error {
+	if cfg.Interval <= 0 {
+		return errors.New("'interval' must be positive")
+	}
+	if cfg.BaseEjectionTime <= 0 {
+		return errors.New("'base_ejection_time' must be positive")
+	}
+	if cfg.MaxEjectionTime < cfg.BaseEjectionTime {
+		return errors.New("'max_ejection_time' must be greater than or equal to 'base_ejection_time'")
+	}
+	if cfg.MaxEjectionPercent < 0 || cfg.MaxEjectionPercent > 100 {
+		return errors.New("'max_ejection_percent' must be between 0 and 100")
+	}
+	if cfg.FailurePercentageThreshold <= 0 || cfg.FailurePercentageThreshold > 100 {
+		return errors.New("'failure_percentage_threshold' must be greater than 0 and less than or equal to 100")
+	}
+	if cfg.MinimumRequests <= 0 {
+		return errors.New("'minimum_requests' must be positive")
+	}
+	return nil
+}
+
+// validateEndpoints checks the Endpoints and the Resolver settings of the client.
+func (gcs *ClientConfig) validateEndpoints() error {
+	switch gcs.Resolver {
+	case "", resolverStatic, resolverDNS:
+	default:
+		return fmt.Errorf("invalid resolver: %q, must be %q or %q", gcs.Resolver, resolverStatic, resolverDNS)
+	}
+	if len(gcs.Endpoints) == 0 {
+		if gcs.Resolver != "" {
+			return errors.New("'resolver' requires 'endpoints'")
+		}
+		return nil
+	}
+	for _, endpoint := range gcs.Endpoints {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil && (gcs.Resolver != resolverDNS || endpoint == "") {
+			return fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+		}
+	}
+	return nil
+}
+
+// netResolver is implemented by net.Resolver.
+type netResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// endpointStats tracks the RPCs sent to an address.
+type endpointStats struct {
+	endpoint     string
+	requests     int
+	failures     int
+	ejections    int
+	ejectedUntil time.Time
+}
+
+// endpointsResolver is a resolver.Builder resolving the Endpoints of a single client connection into all
+// their addresses. It tracks the outcome of the RPCs sent to each address, and removes the addresses failing
+// more than the OutlierDetection threshold from the resolved addresses until the end of their ejection.
+type endpointsResolver struct {
+	endpoints []string
+	dns       bool
+	outlier   *OutlierDetectionConfig
+	lookup    netResolver
+	logger    *zap.Logger
+	now       func() time.Time
+
+	sentRequests   metric.Int64Counter
+	failedRequests metric.Int64Counter
+	ejections      metric.Int64Counter
+
+	mu    sync.Mutex
+	cc    resolver.ClientConn
+	addrs []resolver.Address
+	stats map[string]*endpointStats
+}
+
+func newEndpointsResolver(gcs *ClientConfig, settings component.TelemetrySettings) (*endpointsResolver, error) {
+	r := &endpointsResolver{
+		endpoints: gcs.Endpoints,
+		dns:       gcs.Resolver == resolverDNS,
+		outlier:   gcs.OutlierDetection,
+		lookup:    net.DefaultResolver,
+		logger:    settings.Logger,
+		now:       time.Now,
+		stats:     map[string]*endpointStats{},
+	}
+	meter := settings.MeterProvider.Meter(scopeName)
+	var errs, err error
+	r.sentRequests, err = meter.Int64Counter("otelcol_grpc_client_endpoint_sent_requests",
+		metric.WithDescription("Number of RPCs successfully sent to an endpoint."),
+		metric.WithUnit("{requests}"))
+	errs = errors.Join(errs, err)
+	r.failedRequests, err = meter.Int64Counter("otelcol_grpc_client_endpoint_failed_requests",
+		metric.WithDescription("Number of RPCs failed because of an endpoint."),
+		metric.WithUnit("{requests}"))
+	errs = errors.Join(errs, err)
+	r.ejections, err = meter.Int64Counter("otelcol_grpc_client_endpoint_ejections",
+		metric.WithDescription("Number of times an endpoint was ejected by the outlier detection."),
+		metric.WithUnit("{ejections}"))
+	errs = errors.Join(errs, err)
+	return r, errs
+}
+
+// target returns the target of the client connection using the resolver.
+func (r *endpointsResolver) target() string {
+	return endpointsScheme + ":///" + r.endpoints[0]
+}
+
+// Scheme implements resolver.Builder.
+func (r *endpointsResolver) Scheme() string {
+	return endpointsScheme
+}
+
+// Build implements resolver.Builder. The resolver is built again when the client connection exits the idle
+// mode, the stats of the addresses are kept.
+func (r *endpointsResolver) Build(_ resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r.mu.Lock()
+	r.cc = cc
+	r.mu.Unlock()
+
+	ri := &endpointsResolverInstance{
+		r:          r,
+		resolveNow: make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	ri.wg.Add(1)
+	go ri.run()
+	return ri, nil
+}
+
+// resolve resolves the endpoints into addresses and updates the state of the client connection.
+func (r *endpointsResolver) resolve() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsResolveTimeout)
+	defer cancel()
+
+	var addrs []resolver.Address
+	var errs error
+	for _, endpoint := range r.endpoints {
+		resolved, err := r.resolveEndpoint(ctx, endpoint)
+		errs = errors.Join(errs, err)
+		addrs = append(addrs, resolved...)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(addrs) == 0 {
+		if len(r.addrs) == 0 {
+			r.cc.ReportError(fmt.Errorf("failed to resolve the endpoints: %w", errs))
+		}
+		return false
+	}
+	if errs != nil {
+		r.logger.Warn("Failed to resolve some endpoints", zap.Error(errs))
+	}
+	r.addrs = addrs
+	r.updateStateLocked()
+	return errs == nil
+}
+
+// resolveEndpoint resolves an endpoint "host:port" into all the addresses of the host. Without port, the
+// endpoint is resolved with its SRV records.
+func (r *endpointsResolver) resolveEndpoint(ctx context.Context, endpoint string) ([]resolver.Address, error) {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err == nil {
+		return r.resolveHost(ctx, endpoint, host, port)
+	}
+	_, srvs, err := r.lookup.LookupSRV(ctx, "", "", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	var addrs []resolver.Address
+	var errs error
+	for _, srv := range srvs {
+		resolved, err := r.resolveHost(ctx, endpoint, strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port)))
+		errs = errors.Join(errs, err)
+		addrs = append(addrs, resolved...)
+	}
+	return addrs, errs
+}
+
+func (r *endpointsResolver) resolveHost(ctx context.Context, endpoint string, host string, port string) ([]resolver.Address, error) {
+	ips, err := r.lookup.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]resolver.Address, 0, len(ips))
+	for _, ip := range ips {
+		addr := resolver.Address{Addr: net.JoinHostPort(ip, port)}
+		if net.ParseIP(host) == nil {
+			// Verify the certificate of the server against the host, not the target of the connection.
+			addr.ServerName = host
+		}
+		addrs = append(addrs, addr)
+		r.mu.Lock()
+		if _, ok := r.stats[addr.Addr]; !ok {
+			r.stats[addr.Addr] = &endpointStats{endpoint: endpoint}
+		}
+		r.mu.Unlock()
+	}
+	return addrs, nil
+}
+
+// updateStateLocked updates the state of the client connection with the addresses not ejected, or all
+// the addresses if they are all ejected.
+func (r *endpointsResolver) updateStateLocked() {
+	if r.cc == nil {
+		return
+	}
+	now := r.now()
+	addrs := make([]resolver.Address, 0, len(r.addrs))
+	for _, addr := range r.addrs {
+		if st := r.stats[addr.Addr]; st == nil || !now.Before(st.ejectedUntil) {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 {
+		addrs = r.addrs
+	}
+	if err := r.cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+		r.logger.Debug("Failed to update the resolved addresses", zap.Error(err))
+	}
+}
+
+// evaluate ejects the addresses failing more than the threshold, and puts back the addresses whose ejection ended.
+func (r *endpointsResolver) evaluate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+
+	ejected := 0
+	for _, addr := range r.addrs {
+		if st := r.stats[addr.Addr]; st != nil && now.Before(st.ejectedUntil) {
+			ejected++
+		}
+	}
+	maxEjected := len(r.addrs) * r.outlier.MaxEjectionPercent / 100
+	for _, addr := range r.addrs {
+		st := r.stats[addr.Addr]
+		if st == nil || now.Before(st.ejectedUntil) {
+			continue
+		}
+		if !st.ejectedUntil.IsZero() {
+			// The ejection just ended, the address didn't receive any RPC since the previous evaluation.
+			st.ejectedUntil = time.Time{}
+			continue
+		}
+		failing := st.requests >= r.outlier.MinimumRequests && st.failures*100 >= st.requests*r.outlier.FailurePercentageThreshold
+		if failing && ejected < maxEjected {
+			ejected++
+			st.ejections++
+			ejectionTime := min(r.outlier.BaseEjectionTime*time.Duration(st.ejections), r.outlier.MaxEjectionTime)
+			st.ejectedUntil = now.Add(ejectionTime)
+			r.logger.Warn("Ejecting a failing endpoint",
+				zap.String("endpoint", st.endpoint),
+				zap.String("address", addr.Addr),
+				zap.Int("failed_requests", st.failures),
+				zap.Int("requests", st.requests),
+				zap.Duration("ejection_time", ejectionTime))
+			r.ejections.Add(context.Background(), 1, metric.WithAttributes(endpointAttributes(st.endpoint, addr.Addr)...))
+		} else if !failing && st.ejections > 0 {
+			st.ejections--
+		}
+	}
+	for _, st := range r.stats {
+		st.requests = 0
+		st.failures = 0
+	}
+	r.updateStateLocked()
+}
+
+// record tracks the outcome of an RPC sent to the address.
+func (r *endpointsResolver) record(ctx context.Context, addr string, err error) {
+	failed := isEndpointFailure(err)
+	r.mu.Lock()
+	st := r.stats[addr]
+	if st == nil {
+		r.mu.Unlock()
+		return
+	}
+	st.requests++
+	if failed {
+		st.failures++
+	}
+	endpoint := st.endpoint
+	r.mu.Unlock()
+
+	attrs := metric.WithAttributes(endpointAttributes(endpoint, addr)...)
+	if failed {
+		r.failedRequests.Add(ctx, 1, attrs)
+	} else {
+		r.sentRequests.Add(ctx, 1, attrs)
+	}
+}
+
+// unaryClientInterceptor records the outcome of the unary RPCs for the address they were sent to.
+func (r *endpointsResolver) unaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var p peer.Peer
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Peer(&p))...)
+	if p.Addr != nil {
+		r.record(ctx, p.Addr.String(), err)
+	}
+	return err
+}
+
+func endpointAttributes(endpoint string, addr string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("endpoint", endpoint),
+		attribute.String("address", addr),
+	}
+}
+
+// isEndpointFailure returns true if the error shows that the endpoint may be unhealthy, as opposed to
+// errors caused by the data or the client.
+func isEndpointFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// endpointsResolverInstance is the resolver.Resolver built for a client connection.
+type endpointsResolverInstance struct {
+	r          *endpointsResolver
+	resolveNow chan struct{}
+	done       chan struct{}
+	wg         sync.WaitGroup
+}
+
+func (ri *endpointsResolverInstance) run() {
+	defer ri.wg.Done()
+
+	resolved := ri.r.resolve()
+	var resolveTick, evaluateTick <-chan time.Time
+	if ri.r.dns {
+		ticker := time.NewTicker(dnsResolveInterval)
+		defer ticker.Stop()
+		resolveTick = ticker.C
+	}
+	if ri.r.outlier != nil {
+		ticker := time.NewTicker(ri.r.outlier.Interval)
+		defer ticker.Stop()
+		evaluateTick = ticker.C
+	}
+	for {
+		select {
+		case <-ri.done:
+			return
+		case <-ri.resolveNow:
+			// The static endpoints are only resolved again until they are all resolved.
+			if ri.r.dns || !resolved {
+				resolved = ri.r.resolve()
+			}
+		case <-resolveTick:
+			ri.r.resolve()
+		case <-evaluateTick:
+			ri.r.evaluate()
+		}
+	}
+}
+
+// ResolveNow implements resolver.Resolver.
+func (ri *endpointsResolverInstance) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case ri.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+// Close implements resolver.Resolver.
+func (ri *endpointsResolverInstance) Close() {
+	close(ri.done)
+	ri.wg.Wait()
+}
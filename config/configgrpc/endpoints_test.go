@@ -0,0 +1,319 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+func TestOutlierDetectionConfig_Validate(t *testing.T) {
+	require.NoError(t, NewDefaultOutlierDetectionConfig().Validate())
+
+	tests := []struct {
+		name    string
+		modify  func(*OutlierDetectionConfig)
+		wantErr string
+	}{
+		{
+			name:    "interval",
+			modify:  func(cfg *OutlierDetectionConfig) { cfg.Interval = 0 },
+			wantErr: "'interval' must be positive",
+		},
+		{
+			name:    "base_ejection_time",
+			modify:  func(cfg *OutlierDetectionConfig) { cfg.BaseEjectionTime = 0 },
+			wantErr: "'base_ejection_time' must be positive",
+		},
+		{
+			name:    "max_ejection_time",
+			modify:  func(cfg *OutlierDetectionConfig) { cfg.MaxEjectionTime = time.Second },
+			wantErr: "'max_ejection_time' must be greater than or equal to 'base_ejection_time'",
+		},
+		{
+			name:    "max_ejection_percent",
+			modify:  func(cfg *OutlierDetectionConfig) { cfg.MaxEjectionPercent = 101 },
+			wantErr: "'max_ejection_percent' must be between 0 and 100",
+		},
+		{
+			name:    "failure_percentage_threshold",
+			modify:  func(cfg *OutlierDetectionConfig) { cfg.FailurePercentageThreshold = 0 },
+			wantErr: "'failure_percentage_threshold' must be greater than 0 and less than or equal to 100",
+		},
+		{
+			name:    "minimum_requests",
+			modify:  func(cfg *OutlierDetectionConfig) { cfg.MinimumRequests = 0 },
+			wantErr: "'minimum_requests' must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultOutlierDetectionConfig()
+			tt.modify(cfg)
+			assert.EqualError(t, cfg.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestClientConfigValidateEndpoints(t *testing.T) {
+	cfg := NewDefaultClientConfig()
+	cfg.Endpoints = []string{"localhost:4317", "10.0.0.1:4317"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Resolver = "consul"
+	require.EqualError(t, cfg.Validate(), `invalid resolver: "consul", must be "static" or "dns"`)
+
+	// The endpoints without a port are resolved with their SRV records.
+	cfg.Resolver = "dns"
+	cfg.Endpoints = []string{"_otlp._tcp.gateway.example.com"}
+	require.NoError(t, cfg.Validate())
+	cfg.Resolver = "static"
+	require.ErrorContains(t, cfg.Validate(), `invalid endpoint "_otlp._tcp.gateway.example.com"`)
+
+	cfg.Endpoints = nil
+	require.EqualError(t, cfg.Validate(), "'resolver' requires 'endpoints'")
+}
+
+func TestEndpointsResolver_OutlierDetection(t *testing.T) {
+	cfg := NewDefaultClientConfig()
+	cfg.Endpoints = []string{"gateway:4317", "10.0.0.3:4317"}
+	cfg.OutlierDetection = NewDefaultOutlierDetectionConfig()
+	cfg.OutlierDetection.MinimumRequests = 2
+	r, reader := newTestEndpointsResolver(t, cfg, &testNetResolver{hosts: map[string][]string{
+		"gateway":  {"10.0.0.1", "10.0.0.2"},
+		"10.0.0.3": {"10.0.0.3"},
+	}})
+	cc := &testClientConn{}
+	r.cc = cc
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	require.True(t, r.resolve())
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.2:4317", "10.0.0.3:4317"}, cc.addrs())
+	assert.Equal(t, "gateway", cc.state.Addresses[0].ServerName)
+	assert.Empty(t, cc.state.Addresses[2].ServerName)
+
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	r.record(context.Background(), "10.0.0.1:4317", unavailable)
+	r.record(context.Background(), "10.0.0.1:4317", unavailable)
+	r.record(context.Background(), "10.0.0.2:4317", unavailable)
+	r.record(context.Background(), "10.0.0.2:4317", nil)
+	r.record(context.Background(), "10.0.0.2:4317", nil)
+	r.record(context.Background(), "10.0.0.3:4317", status.Error(codes.InvalidArgument, "bad data"))
+	r.record(context.Background(), "10.0.0.3:4317", unavailable)
+
+	// Only one of the failing addresses is ejected, at most 50% of the 3 addresses.
+	r.evaluate()
+	assert.Equal(t, []string{"10.0.0.2:4317", "10.0.0.3:4317"}, cc.addrs())
+
+	// The address is put back after its ejection.
+	now = now.Add(cfg.OutlierDetection.BaseEjectionTime)
+	r.evaluate()
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.2:4317", "10.0.0.3:4317"}, cc.addrs())
+
+	// It is ejected for longer when it fails again.
+	r.record(context.Background(), "10.0.0.1:4317", unavailable)
+	r.record(context.Background(), "10.0.0.1:4317", unavailable)
+	r.evaluate()
+	now = now.Add(cfg.OutlierDetection.BaseEjectionTime)
+	r.evaluate()
+	assert.Equal(t, []string{"10.0.0.2:4317", "10.0.0.3:4317"}, cc.addrs())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	counts := endpointMetrics(rm)
+	assert.Equal(t, map[string]int64{
+		"otelcol_grpc_client_endpoint_failed_requests/10.0.0.1:4317": 4,
+		"otelcol_grpc_client_endpoint_failed_requests/10.0.0.2:4317": 1,
+		"otelcol_grpc_client_endpoint_failed_requests/10.0.0.3:4317": 1,
+		"otelcol_grpc_client_endpoint_sent_requests/10.0.0.2:4317":   2,
+		"otelcol_grpc_client_endpoint_sent_requests/10.0.0.3:4317":   1,
+		"otelcol_grpc_client_endpoint_ejections/10.0.0.1:4317":       2,
+	}, counts)
+}
+
+func TestEndpointsResolver_SRV(t *testing.T) {
+	cfg := NewDefaultClientConfig()
+	cfg.Endpoints = []string{"_otlp._tcp.gateway"}
+	cfg.Resolver = "dns"
+	r, _ := newTestEndpointsResolver(t, cfg, &testNetResolver{
+		hosts: map[string][]string{
+			"gateway-0.gateway": {"10.0.0.1"},
+			"gateway-1.gateway": {"10.0.0.2"},
+		},
+		srvs: map[string][]*net.SRV{
+			"_otlp._tcp.gateway": {
+				{Target: "gateway-0.gateway.", Port: 4317},
+				{Target: "gateway-1.gateway.", Port: 4318},
+			},
+		},
+	})
+	cc := &testClientConn{}
+	r.cc = cc
+
+	require.True(t, r.resolve())
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.2:4318"}, cc.addrs())
+	assert.Equal(t, "gateway-1.gateway", cc.state.Addresses[1].ServerName)
+}
+
+func TestEndpointsResolver_ResolveError(t *testing.T) {
+	cfg := NewDefaultClientConfig()
+	cfg.Endpoints = []string{"gateway:4317"}
+	r, _ := newTestEndpointsResolver(t, cfg, &testNetResolver{})
+	cc := &testClientConn{}
+	r.cc = cc
+
+	require.False(t, r.resolve())
+	require.ErrorContains(t, cc.err, "failed to resolve the endpoints")
+}
+
+func TestClientConnEndpoints(t *testing.T) {
+	healthy := startTestTraceServer(t, nil)
+	failing := startTestTraceServer(t, status.Error(codes.Unavailable, "unavailable"))
+
+	reader := sdkmetric.NewManualReader()
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	cfg := NewDefaultClientConfig()
+	cfg.Endpoints = []string{healthy, failing}
+	cfg.TLSSetting = configtls.ClientConfig{Insecure: true}
+	cfg.Auth = nil
+	cc, err := cfg.ToClientConn(context.Background(), componenttest.NewNopHost(), settings)
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, cc.Close()) }()
+
+	client := ptraceotlp.NewGRPCClient(cc)
+	var errs int
+	for i := 0; i < 100; i++ {
+		if _, err := client.Export(context.Background(), ptraceotlp.NewExportRequest()); err != nil {
+			errs++
+		}
+	}
+	// The RPCs are balanced over both endpoints.
+	assert.Positive(t, errs)
+	assert.Less(t, errs, 100)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	counts := endpointMetrics(rm)
+	assert.EqualValues(t, errs, counts["otelcol_grpc_client_endpoint_failed_requests/"+failing])
+	assert.EqualValues(t, 100-errs, counts["otelcol_grpc_client_endpoint_sent_requests/"+healthy])
+}
+
+func newTestEndpointsResolver(t *testing.T, cfg *ClientConfig, lookup netResolver) (*endpointsResolver, *sdkmetric.ManualReader) {
+	reader := sdkmetric.NewManualReader()
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	r, err := newEndpointsResolver(cfg, settings)
+	require.NoError(t, err)
+	r.lookup = lookup
+	return r, reader
+}
+
+func startTestTraceServer(t *testing.T, err error) string {
+	ln, lerr := net.Listen("tcp", "localhost:0")
+	require.NoError(t, lerr)
+	srv, serr := (&ServerConfig{}).ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, serr)
+	ptraceotlp.RegisterGRPCServer(srv, &errTraceServer{err: err})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return ln.Addr().String()
+}
+
+// endpointMetrics returns the values of the endpoint metrics by name and address.
+func endpointMetrics(rm metricdata.ResourceMetrics) map[string]int64 {
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range sum.DataPoints {
+				addr, _ := dp.Attributes.Value("address")
+				counts[m.Name+"/"+addr.AsString()] += dp.Value
+			}
+		}
+	}
+	return counts
+}
+
+type errTraceServer struct {
+	ptraceotlp.UnimplementedGRPCServer
+	err error
+}
+
+func (s *errTraceServer) Export(context.Context, ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	return ptraceotlp.NewExportResponse(), s.err
+}
+
+type testNetResolver struct {
+	hosts map[string][]string
+	srvs  map[string][]*net.SRV
+}
+
+func (r *testNetResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if ips, ok := r.hosts[host]; ok {
+		return ips, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+func (r *testNetResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	if srvs, ok := r.srvs[name]; ok {
+		return name, srvs, nil
+	}
+	return "", nil, errors.New("no such host")
+}
+
+type testClientConn struct {
+	resolver.ClientConn
+	mu    sync.Mutex
+	state resolver.State
+	err   error
+}
+
+func (cc *testClientConn) UpdateState(state resolver.State) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.state = state
+	return nil
+}
+
+func (cc *testClientConn) ReportError(err error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.err = err
+}
+
+func (cc *testClientConn) addrs() []string {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	addrs := make([]string, 0, len(cc.state.Addresses))
+	for _, addr := range cc.state.Addresses {
+		addrs = append(addrs, addr.Addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
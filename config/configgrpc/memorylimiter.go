@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc // import "go.opentelemetry.io/collector/config/configgrpc"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	errMemoryLimiterNotFound = errors.New("memory limiter not found")
+	errNotMemoryLimiter      = errors.New("requested extension is not a memory limiter")
+)
+
+// memoryLimiter is implemented by the memory_limiter extension.
+type memoryLimiter interface {
+	MustRefuse() bool
+}
+
+// getMemoryLimiter selects the memory limiter with the given id from the list of extensions.
+func getMemoryLimiter(extensions map[component.ID]component.Component, id component.ID) (memoryLimiter, error) {
+	if ext, found := extensions[id]; found {
+		if ml, ok := ext.(memoryLimiter); ok {
+			return ml, nil
+		}
+		return nil, errNotMemoryLimiter
+	}
+	return nil, fmt.Errorf("failed to resolve memory limiter %q: %w", id, errMemoryLimiterNotFound)
+}
+
+// errMemoryLimited returns an UNAVAILABLE status, a retryable error. The details of the statuses returned by a tap
+// handle are not sent to the client, so a RESOURCE_EXHAUSTED status could not carry the RetryInfo telling the
+// clients to retry.
+func errMemoryLimited() error {
+	return status.Error(codes.Unavailable, "data refused due to high memory usage")
+}
+
+// memoryLimiterTapHandle refuses the RPCs while the memory limiter refuses data. The tap handle runs when the headers
+// of an RPC are received, before its message is read and decoded, unlike the interceptors.
+func memoryLimiterTapHandle(ml memoryLimiter) tap.ServerInHandle {
+	return func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+		if ml.MustRefuse() {
+			return nil, errMemoryLimited()
+		}
+		return ctx, nil
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/extension/auth"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+)
+
+type mockMemoryLimiter struct {
+	component.StartFunc
+	component.ShutdownFunc
+	refuse bool
+}
+
+func (ml *mockMemoryLimiter) MustRefuse() bool {
+	return ml.refuse
+}
+
+func TestGrpcServerMemoryLimiter(t *testing.T) {
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint: "0.0.0.0:1234",
+		},
+		MemoryLimiter: &mockID,
+	}
+	host := &mockHost{
+		ext: map[component.ID]component.Component{
+			mockID: &mockMemoryLimiter{},
+		},
+	}
+	srv, err := gss.ToServer(context.Background(), host, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	assert.NotNil(t, srv)
+
+	_, err = gss.ToServer(context.Background(), &mockHost{}, componenttest.NewNopTelemetrySettings())
+	require.ErrorIs(t, err, errMemoryLimiterNotFound)
+
+	host.ext[mockID] = auth.NewServer()
+	_, err = gss.ToServer(context.Background(), host, componenttest.NewNopTelemetrySettings())
+	require.ErrorIs(t, err, errNotMemoryLimiter)
+}
+
+func TestMemoryLimiterTapHandle(t *testing.T) {
+	ml := &mockMemoryLimiter{}
+	handle := memoryLimiterTapHandle(ml)
+
+	ctx, err := handle(context.Background(), &tap.Info{})
+	require.NoError(t, err)
+	assert.NotNil(t, ctx)
+
+	ml.refuse = true
+	_, err = handle(context.Background(), &tap.Info{})
+	assertMemoryLimited(t, err)
+}
+
+func TestGrpcServerMemoryLimiterRefusesRPCs(t *testing.T) {
+	ml := &mockMemoryLimiter{refuse: true}
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint:  "localhost:0",
+			Transport: confignet.TransportTypeTCP,
+		},
+		MemoryLimiter: &mockID,
+	}
+	host := &mockHost{ext: map[component.ID]component.Component{mockID: ml}}
+	ln, err := gss.NetAddr.Listen(context.Background())
+	require.NoError(t, err)
+	srv, err := gss.ToServer(context.Background(), host, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	traceServer := &grpcTraceServer{}
+	ptraceotlp.RegisterGRPCServer(srv, traceServer)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	gcs := &ClientConfig{
+		Endpoint:   ln.Addr().String(),
+		TLSSetting: configtls.ClientConfig{Insecure: true},
+	}
+	conn, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, conn.Close()) }()
+	c := ptraceotlp.NewGRPCClient(conn)
+
+	// The RPC is refused before reaching the service.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = c.Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	assertMemoryLimited(t, err)
+	assert.Nil(t, traceServer.recordedContext)
+
+	ml.refuse = false
+	_, err = c.Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	require.NoError(t, err)
+	assert.NotNil(t, traceServer.recordedContext)
+}
+
+func TestGrpcServerMemoryLimiterWithTapHandle(t *testing.T) {
+	ml := &mockMemoryLimiter{}
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{
+			Endpoint:  "localhost:0",
+			Transport: confignet.TransportTypeTCP,
+		},
+		MemoryLimiter: &mockID,
+	}
+	host := &mockHost{ext: map[component.ID]component.Component{mockID: ml}}
+	ln, err := gss.NetAddr.Listen(context.Background())
+	require.NoError(t, err)
+	var tapped atomic.Int32
+	refused := status.Error(codes.ResourceExhausted, "too many requests")
+	srv, err := gss.ToServer(context.Background(), host, componenttest.NewNopTelemetrySettings(),
+		WithTapHandle(func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+			if tapped.Add(1) > 1 {
+				return nil, refused
+			}
+			return ctx, nil
+		}))
+	require.NoError(t, err)
+	ptraceotlp.RegisterGRPCServer(srv, &grpcTraceServer{})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	defer srv.Stop()
+
+	gcs := &ClientConfig{
+		Endpoint:   ln.Addr().String(),
+		TLSSetting: configtls.ClientConfig{Insecure: true},
+	}
+	conn, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, conn.Close()) }()
+	c := ptraceotlp.NewGRPCClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = c.Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	require.NoError(t, err)
+
+	// The extra tap handle refuses the RPC.
+	_, err = c.Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// The memory limiter refuses the RPC before the extra tap handle is called.
+	ml.refuse = true
+	_, err = c.Export(ctx, ptraceotlp.NewExportRequest(), grpc.WaitForReady(true))
+	assertMemoryLimited(t, err)
+	assert.Equal(t, int32(2), tapped.Load())
+}
+
+func assertMemoryLimited(t *testing.T, err error) {
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unavailable, st.Code())
+	assert.Equal(t, "data refused due to high memory usage", st.Message())
+}
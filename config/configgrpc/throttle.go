@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc // import "go.opentelemetry.io/collector/config/configgrpc"
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/collector/config/configretry"
+)
+
+// RetryInfoDelay returns the delay requested by the RetryInfo details of the status, and true if the status
+// has RetryInfo details. The delay is zero if the RetryInfo doesn't have a positive retry delay.
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/protocol/otlp.md#otlpgrpc-throttling
+func RetryInfoDelay(st *status.Status) (time.Duration, bool) {
+	for _, detail := range st.Details() {
+		if t, ok := detail.(*errdetails.RetryInfo); ok {
+			if t.RetryDelay == nil {
+				return 0, true
+			}
+			return max(t.RetryDelay.AsDuration(), 0), true
+		}
+	}
+	return 0, false
+}
+
+// NewThrottleError returns err wrapped in a throttle error, see configretry.NewThrottleError, if err has a gRPC
+// status with RetryInfo details requesting a positive delay before retrying. It returns err unchanged otherwise.
+func NewThrottleError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	if delay, ok := RetryInfoDelay(st); ok && delay > 0 {
+		return configretry.NewThrottleError(err, delay)
+	}
+	return err
+}
+
+// throttleUnaryClientInterceptor turns the errors of the unary calls into throttle errors, see NewThrottleError, so
+// the callers of the clients created by ClientConfig.ToClientConn honor the delay requested by the server.
+// The streaming calls are not intercepted, their callers use NewThrottleError.
+func throttleUnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if err == nil {
+		return nil
+	}
+	return NewThrottleError(err)
+}
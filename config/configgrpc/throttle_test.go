@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.opentelemetry.io/collector/config/configretry"
+)
+
+func TestRetryInfoDelay(t *testing.T) {
+	st := status.New(codes.ResourceExhausted, "resource exhausted")
+	_, ok := RetryInfoDelay(st)
+	assert.False(t, ok)
+
+	stWithoutDelay, err := st.WithDetails(&errdetails.RetryInfo{})
+	require.NoError(t, err)
+	delay, ok := RetryInfoDelay(stWithoutDelay)
+	assert.True(t, ok)
+	assert.Zero(t, delay)
+
+	stWithDelay, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(15 * time.Second)})
+	require.NoError(t, err)
+	delay, ok = RetryInfoDelay(stWithDelay)
+	assert.True(t, ok)
+	assert.Equal(t, 15*time.Second, delay)
+}
+
+func TestNewThrottleError(t *testing.T) {
+	st, err := status.New(codes.Unavailable, "unavailable").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)})
+	require.NoError(t, err)
+	delay, ok := configretry.ThrottleDelay(NewThrottleError(st.Err()))
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, delay)
+
+	unavailableErr := status.Error(codes.Unavailable, "unavailable")
+	assert.Equal(t, unavailableErr, NewThrottleError(unavailableErr))
+	plainErr := errors.New("not a status")
+	assert.Equal(t, plainErr, NewThrottleError(plainErr))
+}
+
+func TestThrottleUnaryClientInterceptor(t *testing.T) {
+	st, err := status.New(codes.ResourceExhausted, "resource exhausted").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(15 * time.Second)})
+	require.NoError(t, err)
+	invoke := func(err error) error {
+		return throttleUnaryClientInterceptor(context.Background(), "/test.Service/Method", nil, nil, nil,
+			func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				return err
+			})
+	}
+
+	throttleErr := invoke(st.Err())
+	delay, ok := configretry.ThrottleDelay(throttleErr)
+	assert.True(t, ok)
+	assert.Equal(t, 15*time.Second, delay)
+	// The status of the call is still available to the caller.
+	assert.Equal(t, codes.ResourceExhausted, status.Code(throttleErr))
+	assert.Len(t, status.Convert(throttleErr).Details(), 1)
+
+	require.NoError(t, invoke(nil))
+}
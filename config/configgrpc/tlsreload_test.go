@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configgrpc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/config/internal"
+)
+
+type statusHost struct {
+	component.Host
+	mu     sync.Mutex
+	events []*componentstatus.Event
+}
+
+func (h *statusHost) Report(ev *componentstatus.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, ev)
+}
+
+func (h *statusHost) statuses() []componentstatus.Status {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	statuses := make([]componentstatus.Status, 0, len(h.events))
+	for _, ev := range h.events {
+		statuses = append(statuses, ev.Status())
+	}
+	return statuses
+}
+
+func TestTLSReloadFailures(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"ca.crt", "client.crt", "client.key"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	reader := sdkmetric.NewManualReader()
+	logger, observed := observer.New(zap.WarnLevel)
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	settings.Logger = zap.New(logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tlsCfg := configtls.ClientConfig{Config: configtls.Config{
+		CAFile:             filepath.Join(dir, "ca.crt"),
+		CertFile:           filepath.Join(dir, "client.crt"),
+		KeyFile:            filepath.Join(dir, "client.key"),
+		ReloadOnFileChange: true,
+	}}
+	host := &statusHost{Host: componenttest.NewNopHost()}
+	reloadOpts, err := internal.TLSReloadOptions(host, settings, scopeName)
+	require.NoError(t, err)
+	_, err = tlsCfg.LoadTLSConfig(ctx, reloadOpts...)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "client.crt"), []byte("invalid"), 0o600))
+	assert.Eventually(t, func() bool {
+		return observed.FilterMessage("Failed to reload the TLS certificates, the previous certificates are still used").Len() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	assert.Equal(t, "otelcol_tls_reload_failures", rm.ScopeMetrics[0].Metrics[0].Name)
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+	require.Len(t, sum.DataPoints, 1)
+	assert.Positive(t, sum.DataPoints[0].Value)
+	assert.Equal(t, componentstatus.StatusRecoverableError, host.statuses()[0])
+
+	data, err := os.ReadFile(filepath.Join("testdata", "client.crt"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "client.crt"), data, 0o600))
+	assert.Eventually(t, func() bool {
+		statuses := host.statuses()
+		return statuses[len(statuses)-1] == componentstatus.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTLSReloadStopsWithServerAndClient(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gss := &ServerConfig{
+		NetAddr: confignet.AddrConfig{Endpoint: "localhost:0", Transport: confignet.TransportTypeTCP},
+		TLSSetting: &configtls.ServerConfig{Config: configtls.Config{
+			CertFile:           filepath.Join("testdata", "server.crt"),
+			KeyFile:            filepath.Join("testdata", "server.key"),
+			ReloadOnFileChange: true,
+		}},
+	}
+	srv, err := gss.ToServer(ctx, componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	srv.Stop()
+	cancel()
+
+	gcs := &ClientConfig{
+		Endpoint: "localhost:1234",
+		TLSSetting: configtls.ClientConfig{Config: configtls.Config{
+			CAFile:             filepath.Join("testdata", "ca.crt"),
+			ReloadOnFileChange: true,
+		}},
+	}
+	conn, err := gcs.ToClientConn(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+}
@@ -17,18 +17,23 @@ type clientInfoHandler struct {
 
 	// include client metadata or not
 	includeMetadata bool
+
+	// the client certificates are verified by the TLS config itself, e.g. for SPIFFE
+	peerVerified bool
 }
 
 // ServeHTTP intercepts incoming HTTP requests, replacing the request's context with one that contains
 // a client.Info containing the client's IP address.
 func (h *clientInfoHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	req = req.WithContext(contextWithClient(req, h.includeMetadata))
+	req = req.WithContext(contextWithClient(req, h.includeMetadata, h.peerVerified))
 	h.next.ServeHTTP(w, req)
 }
 
-// contextWithClient attempts to add the client IP address to the client.Info from the context. When no
-// client.Info exists in the context, one is created.
-func contextWithClient(req *http.Request, includeMetadata bool) context.Context {
+// contextWithClient attempts to add the client IP address and the identity verified by mutual TLS to the
+// client.Info from the context. When no client.Info exists in the context, one is created. The certificate
+// presented by the client is used as its identity when peerVerified is set, crypto/tls leaving the verified
+// chains empty when the TLS config verifies the client certificates itself.
+func contextWithClient(req *http.Request, includeMetadata bool, peerVerified bool) context.Context {
 	cl := client.FromContext(req.Context())
 
 	ip := parseIP(req.RemoteAddr)
@@ -36,12 +41,26 @@ func contextWithClient(req *http.Request, includeMetadata bool) context.Context
 		cl.Addr = ip
 	}
 
+	// The identity verified by mutual TLS is added to the metadata even if the request metadata is not included.
+	tlsInfo := client.NewTLSInfo(req.TLS)
+	if tlsInfo == nil && peerVerified {
+		tlsInfo = client.NewPeerTLSInfo(req.TLS)
+	}
+	if tlsInfo != nil {
+		cl.TLS = tlsInfo
+	}
+
 	if includeMetadata {
 		md := req.Header.Clone()
 		if len(md.Get(client.MetadataHostName)) == 0 && req.Host != "" {
 			md.Add(client.MetadataHostName, req.Host)
 		}
+		tlsInfo.SetMetadata(md)
 
+		cl.Metadata = client.NewMetadata(md)
+	} else if tlsInfo != nil {
+		md := map[string][]string{}
+		tlsInfo.SetMetadata(md)
 		cl.Metadata = client.NewMetadata(md)
 	}
 
@@ -9,9 +9,11 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 
 	"github.com/golang/snappy"
 	"github.com/klauspost/compress/zstd"
@@ -26,33 +28,22 @@ type compressRoundTripper struct {
 	compressor      *compressor
 }
 
-var availableDecoders = map[string]func(body io.ReadCloser) (io.ReadCloser, error){
-	"": func(io.ReadCloser) (io.ReadCloser, error) {
+// availableDecoders are the decoders of the supported compression algorithms. The size of the decompressed
+// body is limited by the decompressor while it is read, maxSize only bounds the memory allocated by the decoder.
+var availableDecoders = map[string]func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error){
+	"": func(io.ReadCloser, int64) (io.ReadCloser, error) {
 		// Not a compressed payload. Nothing to do.
 		return nil, nil
 	},
-	"gzip": func(body io.ReadCloser) (io.ReadCloser, error) {
+	"gzip": func(body io.ReadCloser, _ int64) (io.ReadCloser, error) {
 		gr, err := gzip.NewReader(body)
 		if err != nil {
 			return nil, err
 		}
 		return gr, nil
 	},
-	"zstd": func(body io.ReadCloser) (io.ReadCloser, error) {
-		zr, err := zstd.NewReader(
-			body,
-			// Concurrency 1 disables async decoding. We don't need async decoding, it is pointless
-			// for our use-case (a server accepting decoding http requests).
-			// Disabling async improves performance (I benchmarked it previously when working
-			// on https://github.com/open-telemetry/opentelemetry-collector-contrib/pull/23257).
-			zstd.WithDecoderConcurrency(1),
-		)
-		if err != nil {
-			return nil, err
-		}
-		return zr.IOReadCloser(), nil
-	},
-	"zlib": func(body io.ReadCloser) (io.ReadCloser, error) {
+	"zstd": zstdDecoder(configcompression.ZstdParams{}, nil),
+	"zlib": func(body io.ReadCloser, _ int64) (io.ReadCloser, error) {
 		zr, err := zlib.NewReader(body)
 		if err != nil {
 			return nil, err
@@ -60,7 +51,7 @@ var availableDecoders = map[string]func(body io.ReadCloser) (io.ReadCloser, erro
 		return zr, nil
 	},
 	//nolint:unparam // Ignoring the linter request to remove error return since it needs to match the method signature
-	"snappy": func(body io.ReadCloser) (io.ReadCloser, error) {
+	"snappy": func(body io.ReadCloser, _ int64) (io.ReadCloser, error) {
 		// Lazy Reading content to improve memory efficiency
 		return &compressReadCloser{
 			Reader: snappy.NewReader(body),
@@ -68,7 +59,7 @@ var availableDecoders = map[string]func(body io.ReadCloser) (io.ReadCloser, erro
 		}, nil
 	},
 	//nolint:unparam // Ignoring the linter request to remove error return since it needs to match the method signature
-	"lz4": func(body io.ReadCloser) (io.ReadCloser, error) {
+	"lz4": func(body io.ReadCloser, _ int64) (io.ReadCloser, error) {
 		return &compressReadCloser{
 			Reader: lz4.NewReader(body),
 			orig:   body,
@@ -76,8 +67,100 @@ var availableDecoders = map[string]func(body io.ReadCloser) (io.ReadCloser, erro
 	},
 }
 
-func newCompressRoundTripper(rt http.RoundTripper, compressionType configcompression.Type) (*compressRoundTripper, error) {
-	encoder, err := newCompressor(compressionType)
+// newZstdDecoder returns a zstd decoder using the parameters, loading the dictionary if one is configured.
+func newZstdDecoder(params configcompression.ZstdParams) (func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error), error) {
+	var dicts [][]byte
+	if params.DictionaryFile != "" {
+		dict, err := os.ReadFile(params.DictionaryFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load zstd dictionary: %w", err)
+		}
+		dicts = append(dicts, dict)
+	}
+	return zstdDecoder(params, dicts), nil
+}
+
+func zstdDecoder(params configcompression.ZstdParams, dicts [][]byte) func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error) {
+	// Concurrency 1 disables async decoding. We don't need async decoding, it is pointless
+	// for our use-case (a server accepting decoding http requests).
+	// Disabling async improves performance (I benchmarked it previously when working
+	// on https://github.com/open-telemetry/opentelemetry-collector-contrib/pull/23257).
+	concurrency := params.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+	return func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error) {
+		// The window is allocated when a frame starts, a payload requesting a window larger
+		// than the maximum decompressed body is rejected before allocating it.
+		maxWindow := zstdMaxWindow(maxSize)
+		if params.WindowSize != 0 {
+			maxWindow = uint64(params.WindowSize)
+		}
+		zr, err := zstd.NewReader(
+			body,
+			zstd.WithDecoderConcurrency(concurrency),
+			zstd.WithDecoderMaxWindow(maxWindow),
+			zstd.WithDecoderDicts(dicts...),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return &zstdReadCloser{ReadCloser: zr.IOReadCloser(), maxSize: maxSize}, nil
+	}
+}
+
+// codecDecoder returns the decoder of a codec registered in configcompression.
+func codecDecoder(codec configcompression.Codec) func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error) {
+	return func(body io.ReadCloser, _ int64) (io.ReadCloser, error) {
+		r, err := codec.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		return &codecReadCloser{ReadCloser: r, orig: body}, nil
+	}
+}
+
+// codecReadCloser closes both the reader of a registered codec and the original body.
+type codecReadCloser struct {
+	io.ReadCloser
+	orig io.ReadCloser
+}
+
+func (r *codecReadCloser) Close() error {
+	return errors.Join(r.ReadCloser.Close(), r.orig.Close())
+}
+
+// zstdMinMaxWindow is the smallest maximum window allowed, the zstd encoders commonly use windows up to 8MiB
+// even for small payloads.
+const zstdMinMaxWindow = 8 << 20
+
+// zstdMaxWindow returns the maximum zstd window size allowed to decompress a body of maxSize bytes.
+func zstdMaxWindow(maxSize int64) uint64 {
+	switch {
+	case maxSize < zstdMinMaxWindow:
+		return zstdMinMaxWindow
+	case maxSize > zstd.MaxWindowSize:
+		return zstd.MaxWindowSize
+	}
+	return uint64(maxSize)
+}
+
+// zstdReadCloser reports the payloads exceeding the limits of the zstd decoder as a too large body.
+type zstdReadCloser struct {
+	io.ReadCloser
+	maxSize int64
+}
+
+func (r *zstdReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if errors.Is(err, zstd.ErrWindowSizeExceeded) || errors.Is(err, zstd.ErrDecoderSizeExceeded) {
+		err = &http.MaxBytesError{Limit: r.maxSize}
+	}
+	return n, err
+}
+
+func newCompressRoundTripper(rt http.RoundTripper, compressionType configcompression.Type, params configcompression.CompressionParams) (*compressRoundTripper, error) {
+	encoder, err := newCompressor(compressionType, params)
 	if err != nil {
 		return nil, err
 	}
@@ -118,39 +201,50 @@ func (r *compressRoundTripper) RoundTrip(req *http.Request) (*http.Response, err
 }
 
 type decompressor struct {
-	errHandler         func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int)
-	base               http.Handler
-	decoders           map[string]func(body io.ReadCloser) (io.ReadCloser, error)
-	maxRequestBodySize int64
+	errHandler              func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int)
+	base                    http.Handler
+	decoders                map[string]func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error)
+	maxDecompressedBodySize int64
 }
 
 // httpContentDecompressor offloads the task of handling compressed HTTP requests
 // by identifying the compression format in the "Content-Encoding" header and re-writing
 // request body so that the handlers further in the chain can work on decompressed data.
-func httpContentDecompressor(h http.Handler, maxRequestBodySize int64, eh func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int), enableDecoders []string, decoders map[string]func(body io.ReadCloser) (io.ReadCloser, error)) http.Handler {
+// The decompressed body is limited to maxDecompressedBodySize bytes while it is read.
+// The zstd decoder, if not nil, replaces the default zstd decoder.
+func httpContentDecompressor(h http.Handler, maxDecompressedBodySize int64, eh func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int), enableDecoders []string, decoders map[string]func(body io.ReadCloser) (io.ReadCloser, error), zstdDecoder func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error)) http.Handler {
 	errHandler := defaultErrorHandler
 	if eh != nil {
 		errHandler = eh
 	}
 
-	enabled := map[string]func(body io.ReadCloser) (io.ReadCloser, error){}
+	enabled := map[string]func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error){}
 	for _, dec := range enableDecoders {
-		enabled[dec] = availableDecoders[dec]
+		if decoder, ok := availableDecoders[dec]; ok {
+			enabled[dec] = decoder
+		} else if codec, ok := configcompression.Lookup(configcompression.Type(dec)); ok {
+			enabled[dec] = codecDecoder(codec)
+		}
 
 		if dec == "deflate" {
 			enabled["deflate"] = availableDecoders["zlib"]
 		}
+		if dec == "zstd" && zstdDecoder != nil {
+			enabled["zstd"] = zstdDecoder
+		}
 	}
 
 	d := &decompressor{
-		maxRequestBodySize: maxRequestBodySize,
-		errHandler:         errHandler,
-		base:               h,
-		decoders:           enabled,
+		maxDecompressedBodySize: maxDecompressedBodySize,
+		errHandler:              errHandler,
+		base:                    h,
+		decoders:                enabled,
 	}
 
 	for key, dec := range decoders {
-		d.decoders[key] = dec
+		d.decoders[key] = func(body io.ReadCloser, _ int64) (io.ReadCloser, error) {
+			return dec(body)
+		}
 	}
 
 	return d
@@ -170,7 +264,7 @@ func (d *decompressor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		// "Content-Length" is set to -1 as the size of the decompressed body is unknown.
 		r.Header.Del("Content-Length")
 		r.ContentLength = -1
-		r.Body = http.MaxBytesReader(w, newBody, d.maxRequestBodySize)
+		r.Body = http.MaxBytesReader(w, newBody, d.maxDecompressedBodySize)
 	}
 	d.base.ServeHTTP(w, r)
 }
@@ -181,7 +275,7 @@ func (d *decompressor) newBodyReader(r *http.Request) (io.ReadCloser, error) {
 	if !ok {
 		return nil, fmt.Errorf("unsupported %s: %s", headerContentEncoding, encoding)
 	}
-	return decoder(r.Body)
+	return decoder(r.Body, d.maxDecompressedBodySize)
 }
 
 // defaultErrorHandler writes the error message in plain text.
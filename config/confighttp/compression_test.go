@@ -5,6 +5,7 @@ package confighttp
 
 import (
 	"bytes"
+	"compress/flate"
 	"compress/gzip"
 	"compress/zlib"
 	"context"
@@ -13,6 +14,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"testing/iotest"
@@ -126,6 +129,111 @@ func TestHTTPClientCompression(t *testing.T) {
 	}
 }
 
+func TestHTTPClientCompressionLevel(t *testing.T) {
+	testBody := bytes.Repeat([]byte("uncompressed_text"), 100)
+
+	for _, compression := range []configcompression.Type{
+		configcompression.TypeGzip,
+		configcompression.TypeZlib,
+		configcompression.TypeDeflate,
+		configcompression.TypeZstd,
+		configcompression.TypeLz4,
+	} {
+		t.Run(string(compression), func(t *testing.T) {
+			srv := httptest.NewServer(httpContentDecompressor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, testBody, body)
+				w.WriteHeader(http.StatusOK)
+			}), defaultMaxRequestBodySize, defaultErrorHandler, defaultCompressionAlgorithms, nil, nil))
+			t.Cleanup(srv.Close)
+
+			clientSettings := ClientConfig{
+				Endpoint:          srv.URL,
+				Compression:       compression,
+				CompressionParams: configcompression.CompressionParams{Level: 9},
+			}
+			client, err := clientSettings.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+
+			res, err := client.Post(srv.URL, "text/plain", bytes.NewReader(testBody))
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestHTTPClientCompressionInvalidLevel(t *testing.T) {
+	clientSettings := ClientConfig{
+		Endpoint:          "localhost:1234",
+		Compression:       configcompression.TypeSnappy,
+		CompressionParams: configcompression.CompressionParams{Level: 1},
+	}
+	_, err := clientSettings.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.EqualError(t, err, `compression type "snappy" does not support setting a level`)
+}
+
+// flateCodec is a codec registered in configcompression for the tests.
+type flateCodec struct{}
+
+func (flateCodec) NewWriter(w io.Writer, params configcompression.CompressionParams) (io.WriteCloser, error) {
+	level := flate.DefaultCompression
+	if params.Level != configcompression.DefaultCompressionLevel {
+		level = int(params.Level)
+	}
+	return flate.NewWriter(w, level)
+}
+
+func (flateCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return flate.NewReader(r), nil
+}
+
+func (flateCodec) ValidateParams(params configcompression.CompressionParams) error {
+	if params.Level < 0 || params.Level > 9 {
+		return errors.New("invalid flate level")
+	}
+	return nil
+}
+
+func TestHTTPRegisteredCodec(t *testing.T) {
+	const typeFlate = configcompression.Type("x-flate")
+	require.NoError(t, configcompression.Register(typeFlate, flateCodec{}))
+
+	testBody := bytes.Repeat([]byte("uncompressed_text"), 100)
+	srv := httptest.NewServer(httpContentDecompressor(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.Header.Get(headerContentEncoding))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, testBody, body)
+		w.WriteHeader(http.StatusOK)
+	}), defaultMaxRequestBodySize, defaultErrorHandler, []string{"", string(typeFlate)}, nil, nil))
+	t.Cleanup(srv.Close)
+
+	for _, level := range []configcompression.Level{configcompression.DefaultCompressionLevel, 9} {
+		clientSettings := ClientConfig{
+			Endpoint:          srv.URL,
+			Compression:       typeFlate,
+			CompressionParams: configcompression.CompressionParams{Level: level},
+		}
+		client, err := clientSettings.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+		require.NoError(t, err)
+
+		res, err := client.Post(srv.URL, "text/plain", bytes.NewReader(testBody))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		require.NoError(t, res.Body.Close())
+	}
+
+	clientSettings := ClientConfig{
+		Endpoint:          srv.URL,
+		Compression:       typeFlate,
+		CompressionParams: configcompression.CompressionParams{Level: 10},
+	}
+	_, err := clientSettings.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.EqualError(t, err, "invalid flate level")
+}
+
 func TestHTTPCustomDecompression(t *testing.T) {
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, err := io.ReadAll(r.Body)
@@ -144,7 +252,7 @@ func TestHTTPCustomDecompression(t *testing.T) {
 			return io.NopCloser(strings.NewReader("decompressed body")), nil
 		},
 	}
-	srv := httptest.NewServer(httpContentDecompressor(handler, defaultMaxRequestBodySize, defaultErrorHandler, defaultCompressionAlgorithms, decoders))
+	srv := httptest.NewServer(httpContentDecompressor(handler, defaultMaxRequestBodySize, defaultErrorHandler, defaultCompressionAlgorithms, decoders, nil))
 
 	t.Cleanup(srv.Close)
 
@@ -269,7 +377,7 @@ func TestHTTPContentDecompressionHandler(t *testing.T) {
 				assert.NoError(t, err, "failed to read request body: %v", err)
 				assert.EqualValues(t, testBody, string(body))
 				w.WriteHeader(http.StatusOK)
-			}), defaultMaxRequestBodySize, defaultErrorHandler, defaultCompressionAlgorithms, noDecoders))
+			}), defaultMaxRequestBodySize, defaultErrorHandler, defaultCompressionAlgorithms, noDecoders, nil))
 			t.Cleanup(srv.Close)
 
 			req, err := http.NewRequest(http.MethodGet, srv.URL, tt.reqBody)
@@ -304,7 +412,7 @@ func TestHTTPContentCompressionRequestWithNilBody(t *testing.T) {
 	require.NoError(t, err, "failed to create request to test handler")
 
 	client := srv.Client()
-	client.Transport, err = newCompressRoundTripper(http.DefaultTransport, configcompression.TypeGzip)
+	client.Transport, err = newCompressRoundTripper(http.DefaultTransport, configcompression.TypeGzip, configcompression.CompressionParams{})
 	require.NoError(t, err)
 	res, err := client.Do(req)
 	require.NoError(t, err)
@@ -324,7 +432,7 @@ func TestHTTPContentCompressionCopyError(t *testing.T) {
 	require.NoError(t, err)
 
 	client := srv.Client()
-	client.Transport, err = newCompressRoundTripper(http.DefaultTransport, configcompression.TypeGzip)
+	client.Transport, err = newCompressRoundTripper(http.DefaultTransport, configcompression.TypeGzip, configcompression.CompressionParams{})
 	require.NoError(t, err)
 	_, err = client.Do(req)
 	require.Error(t, err)
@@ -348,7 +456,7 @@ func TestHTTPContentCompressionRequestBodyCloseError(t *testing.T) {
 	require.NoError(t, err)
 
 	client := srv.Client()
-	client.Transport, err = newCompressRoundTripper(http.DefaultTransport, configcompression.TypeGzip)
+	client.Transport, err = newCompressRoundTripper(http.DefaultTransport, configcompression.TypeGzip, configcompression.CompressionParams{})
 	require.NoError(t, err)
 	_, err = client.Do(req)
 	require.Error(t, err)
@@ -360,7 +468,7 @@ func TestOverrideCompressionList(t *testing.T) {
 
 	srv := httptest.NewServer(httpContentDecompressor(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
-	}), defaultMaxRequestBodySize, defaultErrorHandler, configuredDecoders, nil))
+	}), defaultMaxRequestBodySize, defaultErrorHandler, configuredDecoders, nil, nil))
 	t.Cleanup(srv.Close)
 
 	req, err := http.NewRequest(http.MethodGet, srv.URL, compressSnappy(t, []byte("123decompressed body")))
@@ -428,7 +536,8 @@ func TestDecompressorAvoidDecompressionBomb(t *testing.T) {
 				1024,
 				defaultErrorHandler,
 				defaultCompressionAlgorithms,
-				availableDecoders,
+				nil,
+				nil,
 			)
 
 			payload := tc.compress(t, make([]byte, 2*1024)) // 2KB uncompressed payload
@@ -447,6 +556,144 @@ func TestDecompressorAvoidDecompressionBomb(t *testing.T) {
 	}
 }
 
+func TestDecompressorZstdMaxWindow(t *testing.T) {
+	h := httpContentDecompressor(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := io.Copy(io.Discard, r.Body)
+			var maxBytesErr *http.MaxBytesError
+			assert.ErrorAs(t, err, &maxBytesErr)
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+		16<<20,
+		defaultErrorHandler,
+		defaultCompressionAlgorithms,
+		nil,
+		nil,
+	)
+
+	// An empty frame requesting a 32MiB window, larger than the maximum decompressed body.
+	payload := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x78, 0x01, 0x00, 0x00}
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(payload))
+	req.Header.Set("Content-Encoding", "zstd")
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	assert.Equal(t, uint64(zstdMinMaxWindow), zstdMaxWindow(10))
+	assert.Equal(t, uint64(16<<20), zstdMaxWindow(16<<20))
+	assert.Equal(t, uint64(zstd.MaxWindowSize), zstdMaxWindow(1<<40))
+}
+
+func TestZstdParams(t *testing.T) {
+	dictFile := writeZstdDict(t)
+	testBody := bytes.Repeat([]byte(`{"resource":{"attributes":[{"key":"service.name","value":"checkout"}]}}`), 10)
+
+	hss := &ServerConfig{
+		Endpoint: "localhost:0",
+		DecompressionParams: configcompression.DecompressionParams{
+			Zstd: configcompression.ZstdParams{WindowSize: 1 << 20, DictionaryFile: dictFile},
+		},
+	}
+	srv, err := hss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, testBody, body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	require.NoError(t, err)
+	hs := httptest.NewServer(srv.Handler)
+	t.Cleanup(hs.Close)
+
+	tests := []struct {
+		name           string
+		params         configcompression.CompressionParams
+		expectedStatus int
+	}{
+		{
+			name:           "Dictionary",
+			params:         configcompression.CompressionParams{Level: 19, Zstd: configcompression.ZstdParams{Concurrency: 2, DictionaryFile: dictFile}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "NoDictionary",
+			params:         configcompression.CompressionParams{Zstd: configcompression.ZstdParams{WindowSize: 1 << 10}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "WindowTooLarge",
+			params:         configcompression.CompressionParams{Zstd: configcompression.ZstdParams{WindowSize: 1 << 21}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientSettings := ClientConfig{
+				Endpoint:          hs.URL,
+				Compression:       configcompression.TypeZstd,
+				CompressionParams: tt.params,
+			}
+			client, err := clientSettings.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+			require.NoError(t, err)
+
+			// Send a body larger than the window, the frame of the encoder with the largest window requests it.
+			body := testBody
+			if tt.expectedStatus == http.StatusBadRequest {
+				body = bytes.Repeat(testBody, 5000)
+			}
+			res, err := client.Post(hs.URL, "application/json", bytes.NewReader(body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			require.NoError(t, res.Body.Close())
+		})
+	}
+}
+
+func TestZstdParamsInvalidDictionary(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.dict")
+
+	clientSettings := ClientConfig{
+		Endpoint:          "localhost:1234",
+		Compression:       configcompression.TypeZstd,
+		CompressionParams: configcompression.CompressionParams{Zstd: configcompression.ZstdParams{DictionaryFile: missing}},
+	}
+	_, err := clientSettings.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.ErrorContains(t, err, "failed to load zstd dictionary")
+
+	hss := &ServerConfig{
+		Endpoint: "localhost:0",
+		DecompressionParams: configcompression.DecompressionParams{
+			Zstd: configcompression.ZstdParams{DictionaryFile: missing},
+		},
+	}
+	_, err = hss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), http.NotFoundHandler())
+	require.ErrorContains(t, err, "failed to load zstd dictionary")
+
+	hss.DecompressionParams.Zstd = configcompression.ZstdParams{Concurrency: -1}
+	_, err = hss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), http.NotFoundHandler())
+	require.EqualError(t, err, "invalid zstd concurrency -1, the concurrency must be positive")
+}
+
+// writeZstdDict writes a zstd dictionary built from OTLP-like JSON payloads and returns its path.
+func writeZstdDict(tb testing.TB) string {
+	var contents [][]byte
+	for i := 0; i < 100; i++ {
+		contents = append(contents, []byte(fmt.Sprintf(`{"resource":{"attributes":[{"key":"service.name","value":"service-%d"}]},"scopeSpans":[{"spans":[{"name":"span-%d"}]}]}`, i, i)))
+	}
+	dict, err := zstd.BuildDict(zstd.BuildDictOptions{
+		ID:       1,
+		Contents: contents,
+		History:  bytes.Join(contents, nil),
+		Offsets:  [3]int{1, 4, 8},
+	})
+	require.NoError(tb, err)
+	dictFile := filepath.Join(tb.TempDir(), "otlp.dict")
+	require.NoError(tb, os.WriteFile(dictFile, dict, 0o600))
+	return dictFile
+}
+
 func compressGzip(t testing.TB, body []byte) *bytes.Buffer {
 	var buf bytes.Buffer
 	gw := gzip.NewWriter(&buf)
@@ -8,7 +8,9 @@ import (
 	"compress/gzip"
 	"compress/zlib"
 	"errors"
+	"fmt"
 	"io"
+	"os"
 	"sync"
 
 	"github.com/golang/snappy"
@@ -44,11 +46,16 @@ var (
 
 type compressor struct {
 	pool sync.Pool
+	// newWriter creates the writers of the registered codecs, which are not pooled.
+	newWriter func(w io.Writer) (io.WriteCloser, error)
 }
 
 // writerFactory defines writer field in CompressRoundTripper.
 // The validity of input is already checked when NewCompressRoundTripper was called in confighttp,
-func newCompressor(compressionType configcompression.Type) (*compressor, error) {
+func newCompressor(compressionType configcompression.Type, params configcompression.CompressionParams) (*compressor, error) {
+	if params != (configcompression.CompressionParams{}) {
+		return newParamsCompressor(compressionType, params)
+	}
 	switch compressionType {
 	case configcompression.TypeGzip:
 		return gZipPool, nil
@@ -61,13 +68,90 @@ func newCompressor(compressionType configcompression.Type) (*compressor, error)
 	case configcompression.TypeLz4:
 		return lz4Pool, nil
 	}
-	return nil, errors.New("unsupported compression type, ")
+	return newCodecCompressor(compressionType, params)
+}
+
+// newParamsCompressor returns a compressor using the compression parameters, already validated.
+func newParamsCompressor(compressionType configcompression.Type, params configcompression.CompressionParams) (*compressor, error) {
+	level := int(params.Level)
+	switch compressionType {
+	case configcompression.TypeGzip:
+		return &compressor{pool: sync.Pool{New: func() any { gw, _ := gzip.NewWriterLevel(nil, level); return gw }}}, nil
+	case configcompression.TypeZstd:
+		opts, err := zstdEncoderOptions(params)
+		if err != nil {
+			return nil, err
+		}
+		// Create a first encoder to report the errors of the options, the pool creates the next ones.
+		zw, err := zstd.NewWriter(nil, opts...)
+		if err != nil {
+			return nil, err
+		}
+		p := &compressor{pool: sync.Pool{New: func() any { zw, _ := zstd.NewWriter(nil, opts...); return zw }}}
+		p.pool.Put(zw)
+		return p, nil
+	case configcompression.TypeZlib, configcompression.TypeDeflate:
+		return &compressor{pool: sync.Pool{New: func() any { zw, _ := zlib.NewWriterLevel(nil, level); return zw }}}, nil
+	case configcompression.TypeLz4:
+		return &compressor{pool: sync.Pool{New: func() any {
+			lz := lz4.NewWriter(nil)
+			// The lz4 levels 1 to 9 are powers of two starting from lz4.Level1.
+			_ = lz.Apply(lz4.ConcurrencyOption(1), lz4.CompressionLevelOption(lz4.CompressionLevel(1<<(8+level))))
+			return lz
+		}}}, nil
+	}
+	return newCodecCompressor(compressionType, params)
+}
+
+// zstdEncoderOptions returns the options of the zstd encoders, loading the dictionary if one is configured.
+func zstdEncoderOptions(params configcompression.CompressionParams) ([]zstd.EOption, error) {
+	concurrency := params.Zstd.Concurrency
+	if concurrency == 0 {
+		// See zStdPool, concurrency 1 disables async encoding via goroutines.
+		concurrency = 1
+	}
+	opts := []zstd.EOption{zstd.WithEncoderConcurrency(concurrency)}
+	if params.Level != configcompression.DefaultCompressionLevel {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(int(params.Level))))
+	}
+	if params.Zstd.WindowSize != 0 {
+		opts = append(opts, zstd.WithWindowSize(params.Zstd.WindowSize))
+	}
+	if params.Zstd.DictionaryFile != "" {
+		dict, err := os.ReadFile(params.Zstd.DictionaryFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load zstd dictionary: %w", err)
+		}
+		opts = append(opts, zstd.WithEncoderDict(dict))
+	}
+	return opts, nil
+}
+
+// newCodecCompressor returns a compressor using the codec registered in configcompression.
+func newCodecCompressor(compressionType configcompression.Type, params configcompression.CompressionParams) (*compressor, error) {
+	codec, ok := configcompression.Lookup(compressionType)
+	if !ok {
+		return nil, errors.New("unsupported compression type, ")
+	}
+	return &compressor{newWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return codec.NewWriter(w, params)
+	}}, nil
 }
 
 func (p *compressor) compress(buf *bytes.Buffer, body io.ReadCloser) error {
-	writer := p.pool.Get().(writeCloserReset)
-	defer p.pool.Put(writer)
-	writer.Reset(buf)
+	var writer io.WriteCloser
+	if p.newWriter != nil {
+		w, err := p.newWriter(buf)
+		if err != nil {
+			return err
+		}
+		writer = w
+	} else {
+		w := p.pool.Get().(writeCloserReset)
+		defer p.pool.Put(w)
+		w.Reset(buf)
+		writer = w
+	}
 
 	if body != nil {
 		_, copyErr := io.Copy(writer, body)
@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/config/configcompression"
 )
@@ -94,3 +95,49 @@ func benchmarkCompressionNoConcurrency(b *testing.B, _ configcompression.Type, b
 		}
 	})
 }
+
+func BenchmarkCompressionParams(b *testing.B) {
+	dictFile := writeZstdDict(b)
+	benchmarks := []struct {
+		name   string
+		codec  configcompression.Type
+		params configcompression.CompressionParams
+	}{
+		{name: "gzipDefault", codec: configcompression.TypeGzip},
+		{name: "gzipLevel1", codec: configcompression.TypeGzip, params: configcompression.CompressionParams{Level: 1}},
+		{name: "gzipLevel9", codec: configcompression.TypeGzip, params: configcompression.CompressionParams{Level: 9}},
+		{name: "zstdDefault", codec: configcompression.TypeZstd},
+		{name: "zstdLevel1", codec: configcompression.TypeZstd, params: configcompression.CompressionParams{Level: 1}},
+		{name: "zstdLevel11", codec: configcompression.TypeZstd, params: configcompression.CompressionParams{Level: 11}},
+		{name: "zstdWindow64KiB", codec: configcompression.TypeZstd, params: configcompression.CompressionParams{Zstd: configcompression.ZstdParams{WindowSize: 64 << 10}}},
+		{name: "zstdConcurrency4", codec: configcompression.TypeZstd, params: configcompression.CompressionParams{Zstd: configcompression.ZstdParams{Concurrency: 4}}},
+		{name: "zstdDictionary", codec: configcompression.TypeZstd, params: configcompression.CompressionParams{Zstd: configcompression.ZstdParams{DictionaryFile: dictFile}}},
+		{name: "lz4Level1", codec: configcompression.TypeLz4, params: configcompression.CompressionParams{Level: 1}},
+		{name: "lz4Level9", codec: configcompression.TypeLz4, params: configcompression.CompressionParams{Level: 9}},
+	}
+
+	// Small JSON payloads, similar to the requests of a low traffic OTLP/HTTP exporter.
+	var payload []byte
+	for i := 0; len(payload) < 4<<10; i++ {
+		payload = append(payload, fmt.Sprintf(`{"resource":{"attributes":[{"key":"service.name","value":"service-%d"}]},"scopeSpans":[{"spans":[{"name":"span-%d"}]}]}`, i%7, i)...)
+	}
+
+	for _, benchmark := range benchmarks {
+		b.Run(benchmark.name, func(b *testing.B) {
+			require.NoError(b, benchmark.codec.ValidateParams(benchmark.params))
+			comp, err := newCompressor(benchmark.codec, benchmark.params)
+			require.NoError(b, err)
+			buf := &bytes.Buffer{}
+			b.ResetTimer()
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := comp.compress(buf, io.NopCloser(bytes.NewReader(payload))); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(payload))/float64(buf.Len()), "ratio")
+		})
+	}
+}
@@ -13,12 +13,15 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/cors"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
 	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 	"golang.org/x/net/publicsuffix"
 
 	"go.opentelemetry.io/collector/component"
@@ -33,6 +36,8 @@ import (
 
 const headerContentEncoding = "Content-Encoding"
 const defaultMaxRequestBodySize = 20 * 1024 * 1024 // 20MiB
+const scopeName = "go.opentelemetry.io/collector/config/confighttp"
+
 var defaultCompressionAlgorithms = []string{"", "gzip", "zstd", "zlib", "snappy", "deflate", "lz4"}
 
 // ClientConfig defines settings for creating an HTTP client.
@@ -69,6 +74,9 @@ type ClientConfig struct {
 	// The compression key for supported compression types within collector.
 	Compression configcompression.Type `mapstructure:"compression"`
 
+	// CompressionParams are the settings of the compression, like its level.
+	CompressionParams configcompression.CompressionParams `mapstructure:"compression_params"`
+
 	// MaxIdleConns is used to set a limit to the maximum idle HTTP connections the client can keep open.
 	// By default, it is set to 100.
 	MaxIdleConns *int `mapstructure:"max_idle_conns"`
@@ -103,10 +111,21 @@ type ClientConfig struct {
 	// HTTP2PingTimeout if there's no response to the ping within the configured value, the connection will be closed.
 	// If not set or set to 0, it defaults to 15s.
 	HTTP2PingTimeout time.Duration `mapstructure:"http2_ping_timeout"`
+	// HTTP2 configures the HTTP/2 transport of the client.
+	HTTP2 *HTTP2Config `mapstructure:"http2"`
+	// HTTP3 configures the experimental HTTP/3 transport of the client.
+	HTTP3 *HTTP3Config `mapstructure:"http3"`
 	// Cookies configures the cookie management of the HTTP client.
 	Cookies *CookiesConfig `mapstructure:"cookies"`
 }
 
+// HTTP2Config defines the HTTP/2 settings of the clients and servers.
+type HTTP2Config struct {
+	// H2C enables HTTP/2 over cleartext TCP. The servers accept both HTTP/1.1 and h2c on the same endpoint,
+	// the clients send all the requests with h2c, without upgrading from HTTP/1.1, to http:// endpoints only.
+	H2C bool `mapstructure:"h2c"`
+}
+
 // CookiesConfig defines the configuration of the HTTP client regarding cookies served by the server.
 type CookiesConfig struct {
 	// Enabled if true, cookies from HTTP responses will be reused in further HTTP requests with the same server.
@@ -132,9 +151,66 @@ func NewDefaultClientConfig() ClientConfig {
 	}
 }
 
+// Validate checks the client configuration is valid.
+func (hcs *ClientConfig) Validate() error {
+	if hcs.HTTP2 != nil && hcs.HTTP2.H2C {
+		tlsSetting := hcs.TLSSetting
+		if strings.HasPrefix(strings.ToLower(hcs.Endpoint), "https://") ||
+			tlsSetting.CAFile != "" || tlsSetting.CAPem != "" || tlsSetting.CertFile != "" || tlsSetting.CertPem != "" ||
+			tlsSetting.KeyFile != "" || tlsSetting.KeyPem != "" || tlsSetting.ServerName != "" || tlsSetting.InsecureSkipVerify {
+			return errH2CWithTLS
+		}
+	}
+	if hcs.HTTP3.enabled() {
+		if strings.HasPrefix(strings.ToLower(hcs.Endpoint), "http://") {
+			return errHTTP3WithoutHTTPS
+		}
+		if hcs.ProxyURL != "" {
+			return errHTTP3WithProxy
+		}
+	}
+	return nil
+}
+
+type toClientOptions struct {
+	throttleErrors bool
+}
+
+// ToClientOption is an option to change the behavior of the HTTP client
+// returned by ClientConfig.ToClient().
+type ToClientOption interface {
+	apply(*toClientOptions)
+}
+
+type toClientOptionFunc func(*toClientOptions)
+
+func (of toClientOptionFunc) apply(e *toClientOptions) {
+	of(e)
+}
+
+// WithThrottleErrors makes the client return a ThrottledResponseError, wrapped in a throttle error, instead of
+// the 429 (Too Many Requests) and 503 (Service Unavailable) responses with a valid Retry-After header.
+// The callers using this option get these responses through the error.
+func WithThrottleErrors() ToClientOption {
+	return toClientOptionFunc(func(opts *toClientOptions) {
+		opts.throttleErrors = true
+	})
+}
+
 // ToClient creates an HTTP client.
-func (hcs *ClientConfig) ToClient(ctx context.Context, host component.Host, settings component.TelemetrySettings) (*http.Client, error) {
-	tlsCfg, err := hcs.TLSSetting.LoadTLSConfig(ctx)
+// The TLS files watched because of reload_on_file_change are watched until the context is done, the callers
+// cancel it once the client is no longer used.
+func (hcs *ClientConfig) ToClient(ctx context.Context, host component.Host, settings component.TelemetrySettings, opts ...ToClientOption) (*http.Client, error) {
+	clientOpts := &toClientOptions{}
+	for _, o := range opts {
+		o.apply(clientOpts)
+	}
+
+	reloadOpts, err := internal.TLSReloadOptions(host, settings, scopeName)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, err := hcs.TLSSetting.LoadTLSConfig(ctx, reloadOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -187,6 +263,14 @@ func (hcs *ClientConfig) ToClient(ctx context.Context, host component.Host, sett
 
 	clientTransport := (http.RoundTripper)(transport)
 
+	if hcs.HTTP2 != nil && hcs.HTTP2.H2C {
+		clientTransport = newH2CRoundTripper(transport, hcs.HTTP2ReadIdleTimeout, hcs.HTTP2PingTimeout)
+	}
+
+	if hcs.HTTP3.enabled() {
+		clientTransport = newHTTP3RoundTripper(clientTransport, transport.TLSClientConfig, transport.IdleConnTimeout)
+	}
+
 	// The Auth RoundTripper should always be the innermost to ensure that
 	// request signing-based auth mechanisms operate after compression
 	// and header middleware modifies the request
@@ -215,9 +299,13 @@ func (hcs *ClientConfig) ToClient(ctx context.Context, host component.Host, sett
 	}
 
 	// Compress the body using specified compression methods if non-empty string is provided.
-	// Supporting gzip, zlib, deflate, snappy, and zstd; none is treated as uncompressed.
+	// Supporting gzip, zlib, deflate, snappy, zstd, lz4 and the codecs registered in configcompression;
+	// none is treated as uncompressed.
 	if hcs.Compression.IsCompressed() {
-		clientTransport, err = newCompressRoundTripper(clientTransport, hcs.Compression)
+		if err = hcs.Compression.ValidateParams(hcs.CompressionParams); err != nil {
+			return nil, err
+		}
+		clientTransport, err = newCompressRoundTripper(clientTransport, hcs.Compression, hcs.CompressionParams)
 		if err != nil {
 			return nil, err
 		}
@@ -233,6 +321,11 @@ func (hcs *ClientConfig) ToClient(ctx context.Context, host component.Host, sett
 		clientTransport = otelhttp.NewTransport(clientTransport, otelOpts...)
 	}
 
+	// The throttle RoundTripper is the outermost, the instrumentation records the responses of the server.
+	if clientOpts.throttleErrors {
+		clientTransport = &throttleRoundTripper{transport: clientTransport}
+	}
+
 	var jar http.CookieJar
 	if hcs.Cookies != nil && hcs.Cookies.Enabled {
 		jar, err = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
@@ -284,9 +377,25 @@ type ServerConfig struct {
 	// Auth for this receiver
 	Auth *AuthConfig `mapstructure:"auth"`
 
+	// HTTP2 configures the HTTP/2 support of the server, in addition to the HTTP/2 negotiated with TLS.
+	HTTP2 *HTTP2Config `mapstructure:"http2"`
+
+	// HTTP3 configures the experimental HTTP/3 support of the server, see ToHTTP3Server.
+	HTTP3 *HTTP3Config `mapstructure:"http3"`
+
+	// MemoryLimiter is the ID of the memory_limiter extension used to refuse the requests, before reading
+	// their body, while the memory usage is too high.
+	MemoryLimiter *component.ID `mapstructure:"memory_limiter"`
+
 	// MaxRequestBodySize sets the maximum request body size in bytes. Default: 20MiB.
 	MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
 
+	// MaxDecompressedBodySize sets the maximum size in bytes of a compressed request body once decompressed.
+	// The limit is enforced on the decompressed bytes read by the handler, for all the encodings including the
+	// decoders of WithDecoder. It also bounds the window allocated by the zstd decoder, the other decoders
+	// buffer the data themselves. Default: the MaxRequestBodySize.
+	MaxDecompressedBodySize int64 `mapstructure:"max_decompressed_body_size"`
+
 	// IncludeMetadata propagates the client metadata from the incoming requests to the downstream consumers
 	IncludeMetadata bool `mapstructure:"include_metadata"`
 
@@ -297,6 +406,9 @@ type ServerConfig struct {
 	// CompressionAlgorithms configures the list of compression algorithms the server can accept. Default: ["", "gzip", "zstd", "zlib", "snappy", "deflate"]
 	CompressionAlgorithms []string `mapstructure:"compression_algorithms"`
 
+	// DecompressionParams are the settings of the decoders of the compression algorithms.
+	DecompressionParams configcompression.DecompressionParams `mapstructure:"decompression_params"`
+
 	// ReadTimeout is the maximum duration for reading the entire
 	// request, including the body. A zero or negative value means
 	// there will be no timeout.
@@ -343,6 +455,14 @@ func NewDefaultServerConfig() ServerConfig {
 	}
 }
 
+// Validate checks the server configuration is valid.
+func (hss *ServerConfig) Validate() error {
+	if hss.HTTP3.enabled() && (hss.TLSSetting == nil || (hss.TLSSetting.CertFile == "" && hss.TLSSetting.CertPem == "" && hss.TLSSetting.SPIFFE == nil)) {
+		return errHTTP3WithoutTLS
+	}
+	return nil
+}
+
 type AuthConfig struct {
 	// Auth for this receiver.
 	configauth.Authentication `mapstructure:",squash"`
@@ -352,26 +472,91 @@ type AuthConfig struct {
 	RequestParameters []string `mapstructure:"request_params"`
 }
 
+// toListenerOptions has options that change the behavior of the listener
+// returned by ServerConfig.ToListener().
+type toListenerOptions struct {
+	host     component.Host
+	settings component.TelemetrySettings
+}
+
+// ToListenerOption is an option to change the behavior of the listener
+// returned by ServerConfig.ToListener().
+type ToListenerOption interface {
+	apply(*toListenerOptions)
+}
+
+type toListenerOptionFunc func(*toListenerOptions)
+
+func (of toListenerOptionFunc) apply(o *toListenerOptions) {
+	of(o)
+}
+
+// WithListenerTelemetry sets the host and the telemetry used to report the failed reloads of the TLS files
+// watched because of reload_on_file_change. The failures are reported as a recoverable error of the
+// component owning the host.
+func WithListenerTelemetry(host component.Host, settings component.TelemetrySettings) ToListenerOption {
+	return toListenerOptionFunc(func(o *toListenerOptions) {
+		o.host = host
+		o.settings = settings
+	})
+}
+
 // ToListener creates a net.Listener.
-func (hss *ServerConfig) ToListener(ctx context.Context) (net.Listener, error) {
+func (hss *ServerConfig) ToListener(ctx context.Context, opts ...ToListenerOption) (net.Listener, error) {
+	listenerOpts := &toListenerOptions{}
+	for _, o := range opts {
+		o.apply(listenerOpts)
+	}
+
 	listener, err := net.Listen("tcp", hss.Endpoint)
 	if err != nil {
 		return nil, err
 	}
 
 	if hss.TLSSetting != nil {
+		// The TLS files watched because of reload_on_file_change are watched until the listener is closed.
+		var closers []io.Closer
+		var reloadOpts []configtls.LoadOption
+		if reloadOpts, err = internal.TLSReloadOptions(listenerOpts.host, listenerOpts.settings, scopeName); err != nil {
+			return nil, errors.Join(err, listener.Close())
+		}
 		var tlsCfg *tls.Config
-		tlsCfg, err = hss.TLSSetting.LoadTLSConfig(ctx)
+		tlsCfg, err = hss.TLSSetting.LoadTLSConfig(ctx, append(reloadOpts, configtls.WithReloadCloser(func(closer io.Closer) {
+			closers = append(closers, closer)
+		}))...)
 		if err != nil {
-			return nil, err
+			closeAll(closers)
+			return nil, errors.Join(err, listener.Close())
 		}
 		tlsCfg.NextProtos = []string{http2.NextProtoTLS, "http/1.1"}
 		listener = tls.NewListener(listener, tlsCfg)
+		if len(closers) > 0 {
+			listener = &closingListener{Listener: listener, closers: closers}
+		}
 	}
 
 	return listener, nil
 }
 
+// closingListener closes the closers with the listener.
+type closingListener struct {
+	net.Listener
+	closers   []io.Closer
+	closeOnce sync.Once
+}
+
+func (l *closingListener) Close() error {
+	err := l.Listener.Close()
+	l.closeOnce.Do(func() { closeAll(l.closers) })
+	return err
+}
+
+func closeAll(closers []io.Closer) {
+	for _, closer := range closers {
+		_ = closer.Close()
+	}
+}
+
 // toServerOptions has options that change the behavior of the HTTP server
 // returned by ServerConfig.ToServer().
 type toServerOptions struct {
@@ -423,11 +608,26 @@ func (hss *ServerConfig) ToServer(_ context.Context, host component.Host, settin
 		hss.MaxRequestBodySize = defaultMaxRequestBodySize
 	}
 
+	if hss.MaxDecompressedBodySize <= 0 {
+		hss.MaxDecompressedBodySize = hss.MaxRequestBodySize
+	}
+
 	if hss.CompressionAlgorithms == nil {
 		hss.CompressionAlgorithms = defaultCompressionAlgorithms
 	}
 
-	handler = httpContentDecompressor(handler, hss.MaxRequestBodySize, serverOpts.errHandler, hss.CompressionAlgorithms, serverOpts.decoders)
+	var zstdDecoder func(body io.ReadCloser, maxSize int64) (io.ReadCloser, error)
+	if hss.DecompressionParams.Zstd != (configcompression.ZstdParams{}) {
+		if err := hss.DecompressionParams.Validate(); err != nil {
+			return nil, err
+		}
+		var err error
+		if zstdDecoder, err = newZstdDecoder(hss.DecompressionParams.Zstd); err != nil {
+			return nil, err
+		}
+	}
+
+	handler = httpContentDecompressor(handler, hss.MaxDecompressedBodySize, serverOpts.errHandler, hss.CompressionAlgorithms, serverOpts.decoders, zstdDecoder)
 
 	if hss.MaxRequestBodySize > 0 {
 		handler = maxRequestBodySizeInterceptor(handler, hss.MaxRequestBodySize)
@@ -442,6 +642,15 @@ func (hss *ServerConfig) ToServer(_ context.Context, host component.Host, settin
 		handler = authInterceptor(handler, server, hss.Auth.RequestParameters)
 	}
 
+	if hss.MemoryLimiter != nil {
+		ml, err := getMemoryLimiter(host.GetExtensions(), *hss.MemoryLimiter)
+		if err != nil {
+			return nil, err
+		}
+
+		handler = memoryLimiterInterceptor(handler, ml, serverOpts.errHandler)
+	}
+
 	if hss.CORS != nil && len(hss.CORS.AllowedOrigins) > 0 {
 		co := cors.Options{
 			AllowedOrigins:   hss.CORS.AllowedOrigins,
@@ -476,6 +685,14 @@ func (hss *ServerConfig) ToServer(_ context.Context, host component.Host, settin
 	handler = &clientInfoHandler{
 		next:            handler,
 		includeMetadata: hss.IncludeMetadata,
+		peerVerified:    hss.TLSSetting != nil && hss.TLSSetting.SPIFFE != nil,
+	}
+
+	var h2s *http2.Server
+	if hss.HTTP2 != nil && hss.HTTP2.H2C {
+		// The HTTP/2 streams of the cleartext connections are served by the same handler as the HTTP/1.1 requests.
+		h2s = &http2.Server{}
+		handler = h2c.NewHandler(handler, h2s)
 	}
 
 	server := &http.Server{
@@ -486,6 +703,13 @@ func (hss *ServerConfig) ToServer(_ context.Context, host component.Host, settin
 		IdleTimeout:       hss.IdleTimeout,
 	}
 
+	if h2s != nil {
+		// Close the h2c connections, hijacked from the server, when the server is shut down.
+		if err := http2.ConfigureServer(server, h2s); err != nil {
+			return nil, fmt.Errorf("failed to configure http2 server: %w", err)
+		}
+	}
+
 	return server, nil
 }
 
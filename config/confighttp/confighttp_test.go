@@ -6,6 +6,9 @@ package confighttp
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"io"
@@ -21,6 +24,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/goleak"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
@@ -738,6 +742,326 @@ func TestHttpReception(t *testing.T) {
 	}
 }
 
+func TestHttpReceptionMTLSIdentity(t *testing.T) {
+	hss := &ServerConfig{
+		Endpoint: "localhost:0",
+		TLSSetting: &configtls.ServerConfig{
+			Config: configtls.Config{
+				CertFile: filepath.Join("testdata", "server.crt"),
+				KeyFile:  filepath.Join("testdata", "server.key"),
+			},
+			ClientCAFile: filepath.Join("testdata", "ca.crt"),
+		},
+	}
+	ln, err := hss.ToListener(context.Background())
+	require.NoError(t, err)
+
+	s, err := hss.ToServer(
+		context.Background(),
+		componenttest.NewNopHost(),
+		componenttest.NewNopTelemetrySettings(),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := client.FromContext(r.Context())
+			if assert.NotNil(t, info.TLS) {
+				assert.Equal(t, "MyCommonName", info.TLS.CommonName)
+				assert.Equal(t, []string{"localhost"}, info.TLS.DNSNames)
+			}
+			assert.Equal(t, []string{"MyCommonName"}, info.Metadata.Get(client.MetadataTLSCommonName))
+			w.WriteHeader(http.StatusOK)
+		}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer func() { require.NoError(t, s.Close()) }()
+
+	hcs := &ClientConfig{
+		Endpoint: "https://" + ln.Addr().String(),
+		TLSSetting: configtls.ClientConfig{
+			Config: configtls.Config{
+				CAFile:   filepath.Join("testdata", "ca.crt"),
+				CertFile: filepath.Join("testdata", "client.crt"),
+				KeyFile:  filepath.Join("testdata", "client.key"),
+			},
+			ServerName: "localhost",
+		},
+	}
+	c, err := hcs.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	resp, err := c.Get(hcs.Endpoint)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	c.CloseIdleConnections()
+}
+
+func TestHttpReceptionH2C(t *testing.T) {
+	hss := &ServerConfig{
+		Endpoint: "localhost:0",
+		HTTP2:    &HTTP2Config{H2C: true},
+	}
+	ln, err := hss.ToListener(context.Background())
+	require.NoError(t, err)
+	s, err := hss.ToServer(
+		context.Background(),
+		componenttest.NewNopHost(),
+		componenttest.NewNopTelemetrySettings(),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NotNil(t, client.FromContext(r.Context()).Addr)
+			_, errWrite := fmt.Fprint(w, r.Proto)
+			assert.NoError(t, errWrite)
+		}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer func() { require.NoError(t, s.Shutdown(context.Background())) }()
+
+	for _, tt := range []struct {
+		name          string
+		http2         *HTTP2Config
+		expectedProto string
+	}{
+		{name: "http1", expectedProto: "HTTP/1.1"},
+		{name: "h2c", http2: &HTTP2Config{H2C: true}, expectedProto: "HTTP/2.0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			hcs := &ClientConfig{
+				Endpoint: "http://" + ln.Addr().String(),
+				HTTP2:    tt.http2,
+			}
+			c, errClient := hcs.ToClient(context.Background(), componenttest.NewNopHost(), nilProvidersSettings)
+			require.NoError(t, errClient)
+
+			resp, errResp := c.Get(hcs.Endpoint)
+			require.NoError(t, errResp)
+			body, errRead := io.ReadAll(resp.Body)
+			require.NoError(t, errRead)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, tt.expectedProto, string(body))
+			assert.Equal(t, tt.expectedProto, resp.Proto)
+			c.CloseIdleConnections()
+		})
+	}
+}
+
+func TestHttpReceptionH2CWithProxy(t *testing.T) {
+	hss := &ServerConfig{
+		Endpoint: "localhost:0",
+		HTTP2:    &HTTP2Config{H2C: true},
+	}
+	ln, err := hss.ToListener(context.Background())
+	require.NoError(t, err)
+	s, err := hss.ToServer(
+		context.Background(),
+		componenttest.NewNopHost(),
+		componenttest.NewNopTelemetrySettings(),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, errWrite := fmt.Fprint(w, r.Proto)
+			assert.NoError(t, errWrite)
+		}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer func() { require.NoError(t, s.Shutdown(context.Background())) }()
+
+	tunneled := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Equal(t, http.MethodConnect, r.Method) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		tunneled <- r.Host
+		upstream, errDial := net.Dial("tcp", r.Host)
+		if !assert.NoError(t, errDial) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, rw, errHijack := w.(http.Hijacker).Hijack()
+		if !assert.NoError(t, errHijack) {
+			_ = upstream.Close()
+			return
+		}
+		_, errWrite := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		assert.NoError(t, errWrite)
+		go func() {
+			_, _ = io.Copy(upstream, rw)
+			_ = upstream.Close()
+		}()
+		_, _ = io.Copy(conn, upstream)
+		_ = conn.Close()
+	}))
+	defer proxy.Close()
+
+	hcs := &ClientConfig{
+		Endpoint: "http://" + ln.Addr().String(),
+		ProxyURL: proxy.URL,
+		HTTP2:    &HTTP2Config{H2C: true},
+	}
+	c, err := hcs.ToClient(context.Background(), componenttest.NewNopHost(), nilProvidersSettings)
+	require.NoError(t, err)
+
+	resp, err := c.Get(hcs.Endpoint)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, "HTTP/2.0", string(body))
+	assert.Equal(t, ln.Addr().String(), <-tunneled)
+	c.CloseIdleConnections()
+}
+
+func TestClientConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      ClientConfig
+		expectedErr error
+	}{
+		{
+			name:   "http endpoint",
+			config: ClientConfig{Endpoint: "http://localhost:4318", HTTP2: &HTTP2Config{H2C: true}},
+		},
+		{
+			name:        "https endpoint",
+			config:      ClientConfig{Endpoint: "HTTPS://localhost:4318", HTTP2: &HTTP2Config{H2C: true}},
+			expectedErr: errH2CWithTLS,
+		},
+		{
+			name: "tls settings",
+			config: ClientConfig{
+				Endpoint:   "http://localhost:4318",
+				TLSSetting: configtls.ClientConfig{Config: configtls.Config{CAFile: "ca.crt"}},
+				HTTP2:      &HTTP2Config{H2C: true},
+			},
+			expectedErr: errH2CWithTLS,
+		},
+		{
+			name:        "http3 with http endpoint",
+			config:      ClientConfig{Endpoint: "http://localhost:4318", HTTP3: &HTTP3Config{Enabled: true}},
+			expectedErr: errHTTP3WithoutHTTPS,
+		},
+		{
+			name: "http3 with proxy",
+			config: ClientConfig{
+				Endpoint: "https://localhost:4318",
+				ProxyURL: "http://proxy:8080",
+				HTTP3:    &HTTP3Config{Enabled: true},
+			},
+			expectedErr: errHTTP3WithProxy,
+		},
+		{
+			name: "tls settings without h2c",
+			config: ClientConfig{
+				Endpoint:   "https://localhost:4318",
+				TLSSetting: configtls.ClientConfig{Config: configtls.Config{CAFile: "ca.crt"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHttpReceptionHTTP3(t *testing.T) {
+	hss := &ServerConfig{
+		Endpoint: "localhost:0",
+		TLSSetting: &configtls.ServerConfig{
+			Config: configtls.Config{
+				CertFile: filepath.Join("testdata", "server.crt"),
+				KeyFile:  filepath.Join("testdata", "server.key"),
+			},
+		},
+		HTTP3: &HTTP3Config{Enabled: true},
+	}
+	require.NoError(t, hss.Validate())
+	ln, err := hss.ToListener(context.Background())
+	require.NoError(t, err)
+	s, err := hss.ToServer(
+		context.Background(),
+		componenttest.NewNopHost(),
+		componenttest.NewNopTelemetrySettings(),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NotNil(t, client.FromContext(r.Context()).Addr)
+			_, errWrite := fmt.Fprint(w, r.Proto)
+			assert.NoError(t, errWrite)
+		}))
+	require.NoError(t, err)
+	s3, err := hss.ToHTTP3Server(context.Background(), s)
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	go func() {
+		_ = s3.Serve()
+	}()
+	defer func() {
+		require.NoError(t, s3.Shutdown(context.Background()))
+		require.NoError(t, s.Shutdown(context.Background()))
+	}()
+	http3Port := s3.conn.LocalAddr().(*net.UDPAddr).Port
+
+	tlsSetting := configtls.ClientConfig{
+		Config:     configtls.Config{CAFile: filepath.Join("testdata", "ca.crt")},
+		ServerName: "localhost",
+	}
+	for _, tt := range []struct {
+		name          string
+		endpoint      string
+		http3         *HTTP3Config
+		expectedProto string
+	}{
+		{name: "http2", endpoint: "https://" + ln.Addr().String(), expectedProto: "HTTP/2.0"},
+		{name: "http3", endpoint: fmt.Sprintf("https://localhost:%d", http3Port), http3: &HTTP3Config{Enabled: true}, expectedProto: "HTTP/3.0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			hcs := &ClientConfig{
+				Endpoint:   tt.endpoint,
+				TLSSetting: tlsSetting,
+				HTTP3:      tt.http3,
+			}
+			require.NoError(t, hcs.Validate())
+			c, errClient := hcs.ToClient(context.Background(), componenttest.NewNopHost(), nilProvidersSettings)
+			require.NoError(t, errClient)
+
+			resp, errResp := c.Get(hcs.Endpoint)
+			require.NoError(t, errResp)
+			body, errRead := io.ReadAll(resp.Body)
+			require.NoError(t, errRead)
+			require.NoError(t, resp.Body.Close())
+			assert.Equal(t, tt.expectedProto, string(body))
+			if tt.http3 == nil {
+				assert.Equal(t, fmt.Sprintf(`h3=":%d"; ma=2592000`, http3Port), resp.Header.Get("Alt-Svc"))
+			}
+			c.CloseIdleConnections()
+		})
+	}
+}
+
+func TestServerConfigValidateHTTP3(t *testing.T) {
+	assert.NoError(t, (&ServerConfig{}).Validate())
+	assert.ErrorIs(t, (&ServerConfig{HTTP3: &HTTP3Config{Enabled: true}}).Validate(), errHTTP3WithoutTLS)
+	assert.ErrorIs(t, (&ServerConfig{
+		TLSSetting: &configtls.ServerConfig{},
+		HTTP3:      &HTTP3Config{Enabled: true},
+	}).Validate(), errHTTP3WithoutTLS)
+	assert.NoError(t, (&ServerConfig{
+		TLSSetting: &configtls.ServerConfig{Config: configtls.Config{CertFile: "server.crt", KeyFile: "server.key"}},
+		HTTP3:      &HTTP3Config{Enabled: true},
+	}).Validate())
+	assert.NoError(t, (&ServerConfig{
+		TLSSetting: &configtls.ServerConfig{Config: configtls.Config{SPIFFE: &configtls.SPIFFEConfig{}}},
+		HTTP3:      &HTTP3Config{Enabled: true},
+	}).Validate())
+}
+
 func TestHttpCors(t *testing.T) {
 	tests := []struct {
 		name string
@@ -1077,6 +1401,7 @@ func TestHttpClientHostHeader(t *testing.T) {
 }
 
 func TestContextWithClient(t *testing.T) {
+	clientCert := &x509.Certificate{Subject: pkix.Name{CommonName: "agent"}}
 	testCases := []struct {
 		name       string
 		input      *http.Request
@@ -1128,10 +1453,45 @@ func TestContextWithClient(t *testing.T) {
 				Metadata: client.NewMetadata(map[string][]string{"x-tt-header": {"tt-value"}, "Host": {"localhost:55443"}}),
 			},
 		},
+		{
+			name: "request with verified client certificate, no metadata processing",
+			input: &http.Request{
+				Header: map[string][]string{"x-tt-header": {"tt-value"}},
+				TLS:    &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}},
+			},
+			doMetadata: false,
+			expected: client.Info{
+				Metadata: client.NewMetadata(map[string][]string{client.MetadataTLSSubject: {"CN=agent"}, client.MetadataTLSCommonName: {"agent"}}),
+				TLS:      &client.TLSInfo{Subject: "CN=agent", CommonName: "agent"},
+			},
+		},
+		{
+			name: "request with verified client certificate and forged identity headers",
+			input: &http.Request{
+				Header: map[string][]string{"x-tt-header": {"tt-value"}, "Tls.common_name": {"forged"}},
+				TLS:    &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}},
+			},
+			doMetadata: true,
+			expected: client.Info{
+				Metadata: client.NewMetadata(map[string][]string{"x-tt-header": {"tt-value"}, client.MetadataTLSSubject: {"CN=agent"}, client.MetadataTLSCommonName: {"agent"}}),
+				TLS:      &client.TLSInfo{Subject: "CN=agent", CommonName: "agent"},
+			},
+		},
+		{
+			name: "request without client certificate and forged identity headers",
+			input: &http.Request{
+				Header: map[string][]string{"x-tt-header": {"tt-value"}, "Tls.common_name": {"forged"}},
+				TLS:    &tls.ConnectionState{},
+			},
+			doMetadata: true,
+			expected: client.Info{
+				Metadata: client.NewMetadata(map[string][]string{"x-tt-header": {"tt-value"}}),
+			},
+		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
-			ctx := contextWithClient(tt.input, tt.doMetadata)
+			ctx := contextWithClient(tt.input, tt.doMetadata, false)
 			assert.Equal(t, tt.expected, client.FromContext(ctx))
 		})
 	}
@@ -1222,6 +1582,58 @@ func TestFailedServerAuth(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%v %s", http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized)), response.Result().Status)
 }
 
+func TestServerMemoryLimiter(t *testing.T) {
+	ml := &mockMemoryLimiter{}
+	hss := ServerConfig{
+		Endpoint:      "localhost:0",
+		MemoryLimiter: &mockID,
+	}
+	host := &mockHost{
+		ext: map[component.ID]component.Component{
+			mockID: ml,
+		},
+	}
+
+	handlerCalled := false
+	srv, err := hss.ToServer(context.Background(), host, componenttest.NewNopTelemetrySettings(), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		handlerCalled = true
+	}))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(response, httptest.NewRequest("POST", "/", nil))
+	assert.Equal(t, http.StatusOK, response.Result().StatusCode)
+	assert.True(t, handlerCalled)
+
+	// The requests are refused without calling the handler while the memory usage is high.
+	ml.refuse = true
+	handlerCalled = false
+	response = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(response, httptest.NewRequest("POST", "/", nil))
+	assert.Equal(t, http.StatusTooManyRequests, response.Result().StatusCode)
+	assert.Equal(t, "1", response.Result().Header.Get("Retry-After"))
+	assert.False(t, handlerCalled)
+}
+
+func TestInvalidServerMemoryLimiter(t *testing.T) {
+	hss := ServerConfig{
+		MemoryLimiter: &nonExistingID,
+	}
+	srv, err := hss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), http.NewServeMux())
+	require.ErrorIs(t, err, errMemoryLimiterNotFound)
+	require.Nil(t, srv)
+
+	hss.MemoryLimiter = &mockID
+	host := &mockHost{
+		ext: map[component.ID]component.Component{
+			mockID: auth.NewServer(),
+		},
+	}
+	srv, err = hss.ToServer(context.Background(), host, componenttest.NewNopTelemetrySettings(), http.NewServeMux())
+	require.ErrorIs(t, err, errNotMemoryLimiter)
+	require.Nil(t, srv)
+}
+
 func TestServerWithErrorHandler(t *testing.T) {
 	// prepare
 	hss := ServerConfig{
@@ -1328,6 +1740,44 @@ func TestServerWithDecompression(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
+func TestServerWithMaxDecompressedBodySize(t *testing.T) {
+	hss := ServerConfig{
+		MaxRequestBodySize:      1000,
+		MaxDecompressedBodySize: 2000,
+	}
+	srv, err := hss.ToServer(
+		context.Background(),
+		componenttest.NewNopHost(),
+		componenttest.NewNopTelemetrySettings(),
+		http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if _, err := io.ReadAll(req.Body); err != nil {
+				resp.WriteHeader(http.StatusRequestEntityTooLarge)
+				return
+			}
+			resp.WriteHeader(http.StatusOK)
+		}),
+	)
+	require.NoError(t, err)
+	testSrv := httptest.NewServer(srv.Handler)
+	defer testSrv.Close()
+
+	for _, tt := range []struct {
+		size     int
+		expected int
+	}{
+		{size: 1500, expected: http.StatusOK},
+		{size: 2500, expected: http.StatusRequestEntityTooLarge},
+	} {
+		req, err := http.NewRequest(http.MethodPost, testSrv.URL, compressGzip(t, []byte(strings.Repeat("a", tt.size))))
+		require.NoError(t, err)
+		req.Header.Set("Content-Encoding", "gzip")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, tt.expected, resp.StatusCode)
+	}
+}
+
 func TestDefaultMaxRequestBodySize(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1420,6 +1870,16 @@ func (nh *mockHost) GetExtensions() map[component.ID]component.Component {
 	return nh.ext
 }
 
+type mockMemoryLimiter struct {
+	component.StartFunc
+	component.ShutdownFunc
+	refuse bool
+}
+
+func (ml *mockMemoryLimiter) MustRefuse() bool {
+	return ml.refuse
+}
+
 func BenchmarkHttpRequest(b *testing.B) {
 	tests := []struct {
 		name            string
@@ -1534,3 +1994,28 @@ func TestDefaultHTTPServerSettings(t *testing.T) {
 	assert.Equal(t, time.Duration(0), httpServerSettings.ReadTimeout)
 	assert.Equal(t, 1*time.Minute, httpServerSettings.ReadHeaderTimeout)
 }
+
+func TestTLSReloadStopsWithListenerAndHTTP3Server(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	hss := &ServerConfig{
+		Endpoint: "localhost:0",
+		TLSSetting: &configtls.ServerConfig{
+			Config: configtls.Config{
+				CertFile:           filepath.Join("testdata", "server.crt"),
+				KeyFile:            filepath.Join("testdata", "server.key"),
+				ReloadOnFileChange: true,
+			},
+		},
+		HTTP3: &HTTP3Config{Enabled: true},
+	}
+	ln, err := hss.ToListener(context.Background())
+	require.NoError(t, err)
+	s, err := hss.ToServer(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), http.NewServeMux())
+	require.NoError(t, err)
+	s3, err := hss.ToHTTP3Server(context.Background(), s)
+	require.NoError(t, err)
+
+	require.NoError(t, ln.Close())
+	require.NoError(t, s3.Shutdown(context.Background()))
+}
@@ -0,0 +1,182 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+var errH2CWithTLS = errors.New("http2::h2c sends the requests without TLS, it cannot be used with an https:// endpoint or tls settings")
+
+// h2cRoundTripper sends the requests to http:// endpoints with HTTP/2 over cleartext TCP connections, without
+// upgrading from HTTP/1.1, and the other requests with the transport of the client.
+//
+// The h2c connections are dialed, through the proxy if any, and closed when idle like the connections of
+// the transport. The buffer sizes, the keep-alives and the limits of connections per host of the transport
+// do not apply to them: HTTP/2 multiplexes the requests on a single connection per host.
+type h2cRoundTripper struct {
+	h2c       *http2.Transport
+	transport *http.Transport
+}
+
+func newH2CRoundTripper(transport *http.Transport, readIdleTimeout, pingTimeout time.Duration) *h2cRoundTripper {
+	dialer := &h2cDialer{transport: transport}
+	return &h2cRoundTripper{
+		h2c: &http2.Transport{
+			AllowHTTP: true,
+			// The transport dials every connection with DialTLSContext, the h2c dialer returns cleartext connections.
+			DialTLSContext:     dialer.dial,
+			DisableCompression: transport.DisableCompression,
+			IdleConnTimeout:    transport.IdleConnTimeout,
+			ReadIdleTimeout:    readIdleTimeout,
+			PingTimeout:        pingTimeout,
+		},
+		transport: transport,
+	}
+}
+
+func (rt *h2cRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return rt.h2c.RoundTrip(req)
+	}
+	return rt.transport.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of both transports, see http.Client.CloseIdleConnections.
+func (rt *h2cRoundTripper) CloseIdleConnections() {
+	rt.h2c.CloseIdleConnections()
+	rt.transport.CloseIdleConnections()
+}
+
+// h2cDialer dials the cleartext connections of the h2c transport with the dialer and the proxy of the
+// HTTP/1.1 transport. The connections to a proxy are tunneled with an HTTP CONNECT request.
+type h2cDialer struct {
+	transport *http.Transport
+}
+
+func (d *h2cDialer) dial(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+	if d.transport.Proxy != nil {
+		proxyURL, err := d.transport.Proxy(&http.Request{URL: &url.URL{Scheme: "http", Host: addr}})
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL != nil {
+			return d.dialProxy(ctx, network, addr, proxyURL)
+		}
+	}
+	return d.dialContext(ctx, network, addr)
+}
+
+func (d *h2cDialer) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.transport.DialContext != nil {
+		return d.transport.DialContext(ctx, network, addr)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, addr)
+}
+
+func (d *h2cDialer) dialProxy(ctx context.Context, network, addr string, proxyURL *url.URL) (net.Conn, error) {
+	port := proxyURL.Port()
+	switch {
+	case proxyURL.Scheme != "http" && proxyURL.Scheme != "https":
+		return nil, fmt.Errorf("unsupported proxy scheme %q for http2::h2c", proxyURL.Scheme)
+	case port == "" && proxyURL.Scheme == "https":
+		port = "443"
+	case port == "":
+		port = "80"
+	}
+
+	conn, err := d.dialContext(ctx, network, net.JoinHostPort(proxyURL.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL.Scheme == "https" {
+		tlsCfg := &tls.Config{}
+		if d.transport.TLSClientConfig != nil {
+			tlsCfg = d.transport.TLSClientConfig.Clone()
+		}
+		tlsCfg.ServerName = proxyURL.Hostname()
+		tlsCfg.NextProtos = nil
+		tlsConn := tls.Client(conn, tlsCfg)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	tunnel, err := connectTunnel(ctx, conn, addr, proxyURL, d.transport.ProxyConnectHeader)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tunnel, nil
+}
+
+// connectTunnel sends a CONNECT request for addr over the connection to the proxy, and returns the tunneled
+// connection once the proxy accepted it.
+func connectTunnel(ctx context.Context, conn net.Conn, addr string, proxyURL *url.URL, header http.Header) (net.Conn, error) {
+	connectReq := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: header.Clone(),
+	}
+	if connectReq.Header == nil {
+		connectReq.Header = http.Header{}
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		connectReq.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	// Interrupt the exchange with the proxy if the context is done before the tunnel is established.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Unix(1, 0))
+	})
+	defer stop()
+
+	if err := connectReq.Write(conn); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, connectReq)
+	if err != nil {
+		return nil, err
+	}
+	// The body of a successful response is the tunnel, it is not read.
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy refused the connection to %s: %s", addr, resp.Status)
+	}
+	if !stop() {
+		return nil, ctx.Err()
+	}
+	if br.Buffered() > 0 {
+		// The proxy already sent bytes of the server, they are read before the connection.
+		return &bufferedConn{Conn: conn, reader: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a net.Conn reading the bytes buffered by reader before the ones of the connection.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/config/internal"
+)
+
+var (
+	errHTTP3WithoutTLS   = errors.New("http3 requires tls settings with a certificate")
+	errHTTP3WithoutHTTPS = errors.New("http3 sends the requests over TLS, it cannot be used with an http:// endpoint")
+	errHTTP3WithProxy    = errors.New("http3 sends the requests over UDP, it cannot be used with a proxy_url")
+)
+
+// HTTP3Config defines the experimental HTTP/3 (QUIC) settings of the clients and servers.
+type HTTP3Config struct {
+	// Enabled, for a server, also serves the requests over HTTP/3 on the UDP port of the endpoint and announces
+	// it with an Alt-Svc header in the other responses. For a client, it sends the requests to https:// endpoints
+	// over HTTP/3, without falling back to TCP.
+	Enabled bool `mapstructure:"enabled"`
+}
+
+func (cfg *HTTP3Config) enabled() bool {
+	return cfg != nil && cfg.Enabled
+}
+
+// HTTP3Server serves the requests of a server created by ServerConfig.ToServer over HTTP/3.
+// The server is experimental, see HTTP3Config.
+type HTTP3Server struct {
+	server  *http3.Server
+	conn    net.PacketConn
+	closers []io.Closer
+}
+
+// ToHTTP3Server creates the HTTP/3 server of the http.Server created by ToServer, listening on the UDP port of the
+// endpoint. It serves the requests with the same handler, and must be created before the http.Server is started:
+// the responses of the http.Server announce the HTTP/3 endpoint with an Alt-Svc header.
+func (hss *ServerConfig) ToHTTP3Server(ctx context.Context, server *http.Server, opts ...ToListenerOption) (*HTTP3Server, error) {
+	if hss.TLSSetting == nil {
+		return nil, errHTTP3WithoutTLS
+	}
+
+	listenerOpts := &toListenerOptions{}
+	for _, o := range opts {
+		o.apply(listenerOpts)
+	}
+
+	// The TLS files watched because of reload_on_file_change are watched until the server is shut down.
+	reloadOpts, err := internal.TLSReloadOptions(listenerOpts.host, listenerOpts.settings, scopeName)
+	if err != nil {
+		return nil, err
+	}
+	var closers []io.Closer
+	tlsCfg, err := hss.TLSSetting.LoadTLSConfig(ctx, append(reloadOpts, configtls.WithReloadCloser(func(closer io.Closer) {
+		closers = append(closers, closer)
+	}))...)
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+
+	conn, err := net.ListenPacket("udp", hss.Endpoint)
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+
+	h3s := &http3.Server{
+		Handler:   server.Handler,
+		TLSConfig: http3.ConfigureTLSConfig(tlsCfg),
+		// The 0-RTT data is disabled, it could be replayed.
+		QUICConfig:  &quic.Config{},
+		IdleTimeout: hss.IdleTimeout,
+		Port:        conn.LocalAddr().(*net.UDPAddr).Port,
+	}
+
+	handler := server.Handler
+	server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The header is only set while the HTTP/3 server is serving.
+		_ = h3s.SetQUICHeaders(w.Header())
+		handler.ServeHTTP(w, r)
+	})
+
+	return &HTTP3Server{server: h3s, conn: conn, closers: closers}, nil
+}
+
+// Serve serves the HTTP/3 requests until the server is shut down, it returns http.ErrServerClosed after Shutdown.
+func (s *HTTP3Server) Serve() error {
+	return s.server.Serve(s.conn)
+}
+
+// Shutdown gracefully shuts down the server: it waits for the running requests to complete, or for the context to
+// be done, and closes the UDP listener.
+func (s *HTTP3Server) Shutdown(ctx context.Context) error {
+	err := errors.Join(s.server.Shutdown(ctx), s.conn.Close())
+	closeAll(s.closers)
+	return err
+}
+
+// http3RoundTripper sends the requests to https:// endpoints over HTTP/3, and the other requests with the
+// transport of the client.
+type http3RoundTripper struct {
+	http3     *http3.Transport
+	transport http.RoundTripper
+}
+
+func newHTTP3RoundTripper(transport http.RoundTripper, tlsCfg *tls.Config, idleConnTimeout time.Duration) *http3RoundTripper {
+	return &http3RoundTripper{
+		http3: &http3.Transport{
+			TLSClientConfig: tlsCfg,
+			QUICConfig:      &quic.Config{MaxIdleTimeout: idleConnTimeout},
+			// Every connection has its own UDP socket, closed with the connection.
+			Dial: quic.DialAddrEarly,
+		},
+		transport: transport,
+	}
+}
+
+func (rt *http3RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "https" {
+		return rt.http3.RoundTrip(req)
+	}
+	return rt.transport.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of both transports, see http.Client.CloseIdleConnections.
+func (rt *http3RoundTripper) CloseIdleConnections() {
+	rt.http3.CloseIdleConnections()
+	if closer, ok := rt.transport.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
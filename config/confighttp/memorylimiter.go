@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// memoryLimiterRetryAfter is the delay, in seconds, advertised to the clients whose requests are refused
+// because of a high memory usage.
+const memoryLimiterRetryAfter = "1"
+
+var (
+	errMemoryLimiterNotFound = errors.New("memory limiter not found")
+	errNotMemoryLimiter      = errors.New("requested extension is not a memory limiter")
+)
+
+// memoryLimiter is implemented by the memory_limiter extension.
+type memoryLimiter interface {
+	MustRefuse() bool
+}
+
+// getMemoryLimiter selects the memory limiter with the given id from the list of extensions.
+func getMemoryLimiter(extensions map[component.ID]component.Component, id component.ID) (memoryLimiter, error) {
+	if ext, found := extensions[id]; found {
+		if ml, ok := ext.(memoryLimiter); ok {
+			return ml, nil
+		}
+		return nil, errNotMemoryLimiter
+	}
+	return nil, fmt.Errorf("failed to resolve memory limiter %q: %w", id, errMemoryLimiterNotFound)
+}
+
+// memoryLimiterInterceptor refuses the requests with a 429 status code, before reading their body,
+// while the memory limiter refuses data.
+func memoryLimiterInterceptor(next http.Handler, ml memoryLimiter, eh func(w http.ResponseWriter, r *http.Request, errorMsg string, statusCode int)) http.Handler {
+	errHandler := defaultErrorHandler
+	if eh != nil {
+		errHandler = eh
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ml.MustRefuse() {
+			w.Header().Set("Retry-After", memoryLimiterRetryAfter)
+			errHandler(w, r, "data refused due to high memory usage", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confighttp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// fakeWorkloadAPI sends a single X509-SVID response to the watchers.
+type fakeWorkloadAPI struct {
+	workload.UnimplementedSpiffeWorkloadAPIServer
+
+	resp *workload.X509SVIDResponse
+}
+
+func (f *fakeWorkloadAPI) FetchX509SVID(_ *workload.X509SVIDRequest, stream workload.SpiffeWorkloadAPI_FetchX509SVIDServer) error {
+	if err := stream.Send(f.resp); err != nil {
+		return err
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+// startFakeWorkloadAPI serves an X509-SVID with the SPIFFE ID, signed by a new trust domain CA.
+func startFakeWorkloadAPI(t *testing.T, spiffeID string) string {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{id},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	// The path of a unix socket is limited to about 100 bytes, t.TempDir can be longer.
+	dir, err := os.MkdirTemp("", "spiffe")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "agent.sock")
+	ln, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	workload.RegisterSpiffeWorkloadAPIServer(srv, &fakeWorkloadAPI{resp: &workload.X509SVIDResponse{
+		Svids: []*workload.X509SVID{{
+			SpiffeId:    spiffeID,
+			X509Svid:    der,
+			X509SvidKey: keyDER,
+			Bundle:      caDER,
+		}},
+	}})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return "unix://" + socketPath
+}
+
+func TestHttpReceptionSPIFFEIdentity(t *testing.T) {
+	spiffeCfg := &configtls.SPIFFEConfig{SocketPath: startFakeWorkloadAPI(t, "spiffe://example.org/agent")}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	hss := &ServerConfig{
+		Endpoint:   "localhost:0",
+		TLSSetting: &configtls.ServerConfig{Config: configtls.Config{SPIFFE: spiffeCfg}},
+	}
+	ln, err := hss.ToListener(ctx)
+	require.NoError(t, err)
+
+	s, err := hss.ToServer(
+		context.Background(),
+		componenttest.NewNopHost(),
+		componenttest.NewNopTelemetrySettings(),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := client.FromContext(r.Context())
+			if assert.NotNil(t, info.TLS) {
+				assert.Equal(t, "spiffe://example.org/agent", info.TLS.SPIFFEID)
+			}
+			assert.Equal(t, []string{"spiffe://example.org/agent"}, info.Metadata.Get(client.MetadataTLSSPIFFEID))
+			w.WriteHeader(http.StatusOK)
+		}))
+	require.NoError(t, err)
+	go func() {
+		_ = s.Serve(ln)
+	}()
+	defer func() { require.NoError(t, s.Close()) }()
+
+	hcs := &ClientConfig{
+		Endpoint:   "https://" + ln.Addr().String(),
+		TLSSetting: configtls.ClientConfig{Config: configtls.Config{SPIFFE: spiffeCfg}},
+	}
+	c, err := hcs.ToClient(ctx, componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	defer c.CloseIdleConnections()
+
+	// The handshakes fail until the X509-SVID is received.
+	assert.Eventually(t, func() bool {
+		resp, errGet := c.Get(hcs.Endpoint)
+		if errGet != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confighttp // import "go.opentelemetry.io/collector/config/confighttp"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configretry"
+)
+
+const (
+	headerRetryAfter = "Retry-After"
+
+	// maxThrottledResponseBodyBytes is the size of the body kept by a ThrottledResponseError.
+	maxThrottledResponseBodyBytes = 64 * 1024
+)
+
+// RetryAfter returns the delay requested by the Retry-After header of a 429 (Too Many Requests) or
+// 503 (Service Unavailable) response, and true if the header is present and valid. The header can be
+// either a number of seconds or an HTTP-date, a date in the past is a zero delay.
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/protocol/otlp.md#otlphttp-throttling
+func RetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	val := resp.Header.Get(headerRetryAfter)
+	if val == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(val); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(val)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(date), 0), true
+}
+
+// NewThrottleError returns err wrapped in a throttle error, see configretry.NewThrottleError, if the response
+// requested a delay before retrying with its Retry-After header. It returns err unchanged otherwise.
+func NewThrottleError(err error, resp *http.Response) error {
+	if delay, ok := RetryAfter(resp); ok {
+		return configretry.NewThrottleError(err, delay)
+	}
+	return err
+}
+
+// ThrottledResponseError is the error returned by the clients created by ClientConfig.ToClient with the
+// WithThrottleErrors option instead of a 429 (Too Many Requests) or 503 (Service Unavailable) response with a valid
+// Retry-After header. The error is wrapped in a throttle error, see NewThrottleError, so the callers honor the delay
+// requested by the server without parsing the header themselves.
+type ThrottledResponseError struct {
+	// Response is the response of the server. Its body is already read, only its first 64 KiB are kept.
+	Response *http.Response
+}
+
+func (e *ThrottledResponseError) Error() string {
+	return fmt.Sprintf("server responded with HTTP status %q", e.Response.Status)
+}
+
+// throttleRoundTripper turns the responses requesting a delay before retrying into a ThrottledResponseError.
+type throttleRoundTripper struct {
+	transport http.RoundTripper
+}
+
+func (t *throttleRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	delay, ok := RetryAfter(resp)
+	if !ok {
+		return resp, nil
+	}
+	// The http.Client ignores the response returned along with an error, its body is replaced by a copy.
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxThrottledResponseBodyBytes))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return nil, configretry.NewThrottleError(&ThrottledResponseError{Response: resp}, delay)
+}
+
+// CloseIdleConnections closes the idle connections of the transport, it is called by http.Client.CloseIdleConnections.
+func (t *throttleRoundTripper) CloseIdleConnections() {
+	if ci, ok := t.transport.(interface{ CloseIdleConnections() }); ok {
+		ci.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package confighttp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configretry"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		wantDelay  time.Duration
+		wantOK     bool
+	}{
+		{
+			name:       "seconds",
+			statusCode: http.StatusTooManyRequests,
+			retryAfter: "30",
+			wantDelay:  30 * time.Second,
+			wantOK:     true,
+		},
+		{
+			name:       "http-date",
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+			wantDelay:  time.Hour,
+			wantOK:     true,
+		},
+		{
+			name:       "http-date in the past",
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat),
+			wantDelay:  0,
+			wantOK:     true,
+		},
+		{
+			name:       "no header",
+			statusCode: http.StatusTooManyRequests,
+		},
+		{
+			name:       "invalid header",
+			statusCode: http.StatusTooManyRequests,
+			retryAfter: "soon",
+		},
+		{
+			name:       "negative seconds",
+			statusCode: http.StatusTooManyRequests,
+			retryAfter: "-1",
+		},
+		{
+			name:       "not throttled",
+			statusCode: http.StatusBadGateway,
+			retryAfter: "30",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			delay, ok := RetryAfter(resp)
+			assert.Equal(t, tt.wantOK, ok)
+			// The HTTP-date has a precision of one second.
+			assert.InDelta(t, tt.wantDelay, delay, float64(time.Second))
+		})
+	}
+	_, ok := RetryAfter(nil)
+	assert.False(t, ok)
+}
+
+func TestNewThrottleError(t *testing.T) {
+	err := errors.New("too many requests")
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"10"}}}
+	delay, ok := configretry.ThrottleDelay(NewThrottleError(err, resp))
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, delay)
+
+	resp.Header.Del("Retry-After")
+	assert.Equal(t, err, NewThrottleError(err, resp))
+}
+
+func TestClientThrottledResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/throttled" {
+			w.Header().Set("Retry-After", "30")
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("overloaded"))
+	}))
+	defer srv.Close()
+
+	cfg := NewDefaultClientConfig()
+	// By default, the responses are returned as is.
+	client, err := cfg.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	resp, err := client.Get(srv.URL + "/throttled")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+
+	client, err = cfg.ToClient(context.Background(), componenttest.NewNopHost(), componenttest.NewNopTelemetrySettings(), WithThrottleErrors())
+	require.NoError(t, err)
+
+	// The response with a Retry-After header is turned into a throttle error keeping the response.
+	_, err = client.Get(srv.URL + "/throttled")
+	delay, ok := configretry.ThrottleDelay(err)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, delay)
+	var respErr *ThrottledResponseError
+	require.ErrorAs(t, err, &respErr)
+	assert.Equal(t, http.StatusServiceUnavailable, respErr.Response.StatusCode)
+	body, err := io.ReadAll(respErr.Response.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "overloaded", string(body))
+
+	// The response without a Retry-After header is returned as is.
+	resp, err = client.Get(srv.URL + "/unavailable")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
@@ -40,6 +40,9 @@ type BackOffConfig struct {
 	// MaxElapsedTime is the maximum amount of time (including retries) spent trying to send a request/batch.
 	// Once this value is reached, the data is discarded. If set to 0, the retries are never stopped.
 	MaxElapsedTime time.Duration `mapstructure:"max_elapsed_time"`
+	// MaxThrottleDelay is the upper bound on the delay requested by the server before retrying, e.g. with the
+	// Retry-After header of an HTTP response or the RetryInfo of a gRPC status. If set to 0, the delay is not bounded.
+	MaxThrottleDelay time.Duration `mapstructure:"max_throttle_delay"`
 }
 
 func (bs *BackOffConfig) Validate() error {
@@ -61,6 +64,9 @@ func (bs *BackOffConfig) Validate() error {
 	if bs.MaxElapsedTime < 0 {
 		return errors.New("'max_elapsed_time' must be non-negative")
 	}
+	if bs.MaxThrottleDelay < 0 {
+		return errors.New("'max_throttle_delay' must be non-negative")
+	}
 	if bs.MaxElapsedTime > 0 {
 		if bs.MaxElapsedTime < bs.InitialInterval {
 			return errors.New("'max_elapsed_time' must not be less than 'initial_interval'")
@@ -92,3 +92,12 @@ func TestDisabledWithInvalidValues(t *testing.T) {
 	}
 	assert.NoError(t, cfg.Validate())
 }
+
+func TestInvalidMaxThrottleDelay(t *testing.T) {
+	cfg := NewDefaultBackOffConfig()
+	require.NoError(t, cfg.Validate())
+	cfg.MaxThrottleDelay = -1
+	require.EqualError(t, cfg.Validate(), "'max_throttle_delay' must be non-negative")
+	cfg.MaxThrottleDelay = time.Minute
+	assert.NoError(t, cfg.Validate())
+}
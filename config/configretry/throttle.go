@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configretry // import "go.opentelemetry.io/collector/config/configretry"
+
+import (
+	"errors"
+	"time"
+)
+
+// throttleError is an error returned when the server asked to wait before retrying.
+type throttleError struct {
+	err   error
+	delay time.Duration
+}
+
+func (t throttleError) Error() string {
+	return "Throttle (" + t.delay.String() + "), error: " + t.err.Error()
+}
+
+func (t throttleError) Unwrap() error {
+	return t.err
+}
+
+// NewThrottleError returns an error wrapping err, telling to wait for the given delay before retrying,
+// e.g. as requested by the server.
+func NewThrottleError(err error, delay time.Duration) error {
+	return throttleError{
+		err:   err,
+		delay: delay,
+	}
+}
+
+// ThrottleDelay returns the delay to wait before retrying and true if err, or one of the errors it wraps,
+// was created by NewThrottleError. It returns false otherwise.
+func ThrottleDelay(err error) (time.Duration, bool) {
+	var te throttleError
+	if errors.As(err, &te) {
+		return te.delay, true
+	}
+	return 0, false
+}
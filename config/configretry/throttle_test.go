@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configretry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottleError(t *testing.T) {
+	err := errors.New("resource exhausted")
+	throttleErr := NewThrottleError(err, 10*time.Second)
+	assert.EqualError(t, throttleErr, "Throttle (10s), error: resource exhausted")
+	assert.ErrorIs(t, throttleErr, err)
+
+	delay, ok := ThrottleDelay(fmt.Errorf("wrapped: %w", throttleErr))
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Second, delay)
+
+	delay, ok = ThrottleDelay(err)
+	assert.False(t, ok)
+	assert.Zero(t, delay)
+}
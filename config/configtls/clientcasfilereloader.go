@@ -7,6 +7,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
@@ -20,8 +21,11 @@ type clientCAsFileReloader struct {
 	loader          clientCAsFileLoader
 	watcher         *fsnotify.Watcher
 	shutdownCH      chan bool
+	closeOnce       sync.Once
 }
 
+var _ io.Closer = (*clientCAsFileReloader)(nil)
+
 type clientCAsFileLoader interface {
 	loadClientCAFile() (*x509.CertPool, error)
 }
@@ -139,3 +143,10 @@ func (r *clientCAsFileReloader) shutdown() error {
 	r.shutdownCH = nil
 	return nil
 }
+
+// Close stops watching the client CA file.
+func (r *clientCAsFileReloader) Close() error {
+	var err error
+	r.closeOnce.Do(func() { err = r.shutdown() })
+	return err
+}
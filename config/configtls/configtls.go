@@ -70,6 +70,14 @@ type Config struct {
 	// ReloadInterval specifies the duration after which the certificate will be reloaded
 	// If not set, it will never be reloaded (optional)
 	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+
+	// ReloadOnFileChange reloads the certificate, the key and the CA files as soon as they are modified.
+	// It cannot be used with ReloadInterval. (optional, default false)
+	ReloadOnFileChange bool `mapstructure:"reload_on_file_change"`
+
+	// SPIFFE sources the certificate and the CA certificates from the SPIFFE Workload API, rotated as they
+	// are received, rather than from the files. The peers are verified by their X509-SVID. (optional)
+	SPIFFE *SPIFFEConfig `mapstructure:"spiffe"`
 }
 
 // NewDefaultConfig creates a new TLSSetting with any default values set.
@@ -196,6 +204,14 @@ func (c Config) Validate() error {
 		return errors.New("invalid TLS configuration: min_version cannot be greater than max_version")
 	}
 
+	if c.ReloadOnFileChange && c.ReloadInterval != 0 {
+		return errors.New("invalid TLS configuration: reload_on_file_change cannot be used with reload_interval")
+	}
+
+	if c.SPIFFE != nil && (c.hasCA() || c.hasCert() || c.hasKey() || c.ReloadOnFileChange || c.ReloadInterval != 0) {
+		return errors.New("invalid TLS configuration: spiffe cannot be used with the CA, certificate and key settings")
+	}
+
 	return nil
 }
 
@@ -374,8 +390,9 @@ func (c Config) loadCert(caPath string) (*x509.CertPool, error) {
 	return certPool, nil
 }
 
-// LoadTLSConfig loads the TLS configuration.
-func (c ClientConfig) LoadTLSConfig(_ context.Context) (*tls.Config, error) {
+// LoadTLSConfig loads the TLS configuration. The files watched because of ReloadOnFileChange, and the SPIFFE
+// Workload API, are watched until the context is done, or until they are closed, see WithReloadCloser.
+func (c ClientConfig) LoadTLSConfig(ctx context.Context, opts ...LoadOption) (*tls.Config, error) {
 	if c.Insecure && !c.hasCA() {
 		return nil, nil
 	}
@@ -386,15 +403,62 @@ func (c ClientConfig) LoadTLSConfig(_ context.Context) (*tls.Config, error) {
 	}
 	tlsCfg.ServerName = c.ServerName
 	tlsCfg.InsecureSkipVerify = c.InsecureSkipVerify
+
+	lo := newLoadOptions(opts)
+
+	if c.SPIFFE != nil {
+		source, err := c.newSPIFFESource(ctx, lo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		source.hookClientConfig(tlsCfg)
+		if c.InsecureSkipVerify {
+			tlsCfg.VerifyConnection = nil
+		}
+	}
+
+	if c.ReloadOnFileChange {
+		reloader, err := c.newFileReloader(ctx, lo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		if c.hasCert() || c.hasKey() {
+			tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return reloader.getCertificate() }
+		}
+		if c.hasCAFile() && !c.InsecureSkipVerify {
+			// The server certificate is verified against the reloaded CA certificates by VerifyConnection,
+			// the verification of crypto/tls would use the CA certificates loaded at startup.
+			tlsCfg.InsecureSkipVerify = true
+			tlsCfg.VerifyConnection = reloader.verifyServerCertificate
+		}
+	}
 	return tlsCfg, nil
 }
 
-// LoadTLSConfig loads the TLS configuration.
-func (c ServerConfig) LoadTLSConfig(_ context.Context) (*tls.Config, error) {
+// LoadTLSConfig loads the TLS configuration. The files watched because of ReloadOnFileChange, and the SPIFFE
+// Workload API, are watched until the context is done, or until they are closed, see WithReloadCloser.
+func (c ServerConfig) LoadTLSConfig(ctx context.Context, opts ...LoadOption) (*tls.Config, error) {
 	tlsCfg, err := c.loadTLSConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
+	lo := newLoadOptions(opts)
+	if c.SPIFFE != nil {
+		source, err := c.newSPIFFESource(ctx, lo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		source.hookServerConfig(tlsCfg)
+	}
+	if c.ReloadOnFileChange {
+		reloader, err := c.newFileReloader(ctx, lo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS config: %w", err)
+		}
+		if c.hasCert() || c.hasKey() {
+			tlsCfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return reloader.getCertificate() }
+		}
+	}
 	if c.ClientCAFile != "" {
 		reloader, err := newClientCAsReloader(c.ClientCAFile, &c)
 		if err != nil {
@@ -405,6 +469,9 @@ func (c ServerConfig) LoadTLSConfig(_ context.Context) (*tls.Config, error) {
 			if err != nil {
 				return nil, err
 			}
+			if lo.onWatch != nil {
+				lo.onWatch(reloader)
+			}
 			tlsCfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) { return reloader.getClientConfig(tlsCfg) }
 		}
 		tlsCfg.ClientCAs = reloader.certPool
@@ -413,6 +480,28 @@ func (c ServerConfig) LoadTLSConfig(_ context.Context) (*tls.Config, error) {
 	return tlsCfg, nil
 }
 
+func (c Config) newFileReloader(ctx context.Context, lo loadOptions) (*fileReloader, error) {
+	reloader, err := newFileReloader(ctx, c, lo)
+	if err != nil {
+		return nil, err
+	}
+	if lo.onWatch != nil {
+		lo.onWatch(reloader)
+	}
+	return reloader, nil
+}
+
+func (c Config) newSPIFFESource(ctx context.Context, lo loadOptions) (*spiffeSource, error) {
+	source, err := newSPIFFESource(ctx, *c.SPIFFE, lo)
+	if err != nil {
+		return nil, err
+	}
+	if lo.onWatch != nil {
+		lo.onWatch(source)
+	}
+	return source, nil
+}
+
 func (c ServerConfig) loadClientCAFile() (*x509.CertPool, error) {
 	return c.loadCert(c.ClientCAFile)
 }
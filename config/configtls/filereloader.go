@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configtls // import "go.opentelemetry.io/collector/config/configtls"
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fileReloadDelay is the delay between the last change of the watched files and their reload, the files
+// are often replaced one after the other and a certificate does not match its key in the meantime.
+var fileReloadDelay = 100 * time.Millisecond
+
+// LoadOption is an option of LoadTLSConfig.
+type LoadOption interface {
+	apply(*loadOptions)
+}
+
+type loadOptions struct {
+	onReloadError func(error)
+	onReload      func()
+	onWatch       func(io.Closer)
+}
+
+func newLoadOptions(opts []LoadOption) loadOptions {
+	var lo loadOptions
+	for _, opt := range opts {
+		opt.apply(&lo)
+	}
+	return lo
+}
+
+type loadOptionFunc func(*loadOptions)
+
+func (of loadOptionFunc) apply(o *loadOptions) {
+	of(o)
+}
+
+// WithReloadErrorHandler sets the function called when the files watched because of ReloadOnFileChange
+// cannot be reloaded, or when the SPIFFE Workload API cannot be watched. The previous certificate and CA
+// certificates are used until the next successful reload.
+func WithReloadErrorHandler(onReloadError func(error)) LoadOption {
+	return loadOptionFunc(func(o *loadOptions) {
+		o.onReloadError = onReloadError
+	})
+}
+
+// WithReloadHandler sets the function called when the files watched because of ReloadOnFileChange are reloaded,
+// or when an X509-SVID is received from the SPIFFE Workload API.
+func WithReloadHandler(onReload func()) LoadOption {
+	return loadOptionFunc(func(o *loadOptions) {
+		o.onReload = onReload
+	})
+}
+
+// WithReloadCloser sets the function called with the io.Closer of each watch started because of ReloadOnFileChange,
+// ReloadClientCAFile or SPIFFE. The callers close them once the TLS configuration is no longer used, closing stops
+// watching the files and waits for the watch to end. The files are otherwise watched until the context is done.
+func WithReloadCloser(onWatch func(io.Closer)) LoadOption {
+	return loadOptionFunc(func(o *loadOptions) {
+		o.onWatch = onWatch
+	})
+}
+
+// fileReloader reloads the certificate, the key and the CA files of a Config when they change.
+type fileReloader struct {
+	tls           Config
+	onReloadError func(error)
+	onReload      func()
+
+	lock   sync.RWMutex
+	cert   *tls.Certificate
+	caPool *x509.CertPool
+
+	watcher   *fsnotify.Watcher
+	stop      chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var _ io.Closer = (*fileReloader)(nil)
+
+// newFileReloader loads the files and watches their directories until the context is done or the reloader is
+// closed. The directories are
+// watched rather than the files to follow the files replaced by a rename, or by a symbolic link update
+// as done for the Kubernetes secrets.
+func newFileReloader(ctx context.Context, c Config, lo loadOptions) (*fileReloader, error) {
+	r := &fileReloader{
+		tls:           c,
+		onReloadError: lo.onReloadError,
+		onReload:      lo.onReload,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create watcher to reload TLS files: %w", err)
+	}
+	dirs := map[string]struct{}{}
+	for _, file := range []string{c.CAFile, c.CertFile, c.KeyFile} {
+		if file != "" {
+			dirs[filepath.Dir(file)] = struct{}{}
+		}
+	}
+	for dir := range dirs {
+		if err = watcher.Add(dir); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("failed to add directory %q to watcher: %w", dir, err)
+		}
+	}
+	r.watcher = watcher
+
+	go r.handleWatcherEvents(ctx)
+	return r, nil
+}
+
+// load loads the files, keeping the previous certificate and CA certificates on error.
+func (r *fileReloader) load() error {
+	var cert *tls.Certificate
+	if r.tls.hasCert() || r.tls.hasKey() {
+		loaded, err := r.tls.loadCertificate()
+		if err != nil {
+			return fmt.Errorf("failed to load TLS cert and key: %w", err)
+		}
+		cert = &loaded
+	}
+	caPool, err := r.tls.loadCACertPool()
+	if err != nil {
+		return err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cert = cert
+	r.caPool = caPool
+	return nil
+}
+
+func (r *fileReloader) handleWatcherEvents(ctx context.Context) {
+	defer close(r.done)
+	defer r.watcher.Close()
+
+	timer := time.NewTimer(fileReloadDelay)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stop:
+			return
+		case _, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			timer.Reset(fileReloadDelay)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.reportError(fmt.Errorf("failed to watch TLS files: %w", err))
+		case <-timer.C:
+			if err := r.load(); err != nil {
+				r.reportError(err)
+			} else if r.onReload != nil {
+				r.onReload()
+			}
+		}
+	}
+}
+
+// Close stops watching the files and waits for the watch to end.
+func (r *fileReloader) Close() error {
+	r.closeOnce.Do(func() { close(r.stop) })
+	<-r.done
+	return nil
+}
+
+func (r *fileReloader) reportError(err error) {
+	if r.onReloadError != nil {
+		r.onReloadError(err)
+	}
+}
+
+func (r *fileReloader) getCertificate() (*tls.Certificate, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.cert, nil
+}
+
+func (r *fileReloader) getCAPool() *x509.CertPool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.caPool
+}
+
+// verifyServerCertificate verifies the certificate of the server against the CA certificates currently loaded,
+// in place of the verification of crypto/tls made against the static RootCAs.
+func (r *fileReloader) verifyServerCertificate(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: server did not provide a certificate")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         r.getCAPool(),
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
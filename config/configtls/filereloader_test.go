@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configtls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// copyTestFile copies a file of the testdata directory to the given path.
+func copyTestFile(t *testing.T, name string, path string) {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	// Write to a temporary file renamed afterwards, as done by most tools replacing certificates.
+	tmp := path + ".tmp"
+	require.NoError(t, os.WriteFile(tmp, data, 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+}
+
+func certificateDNSName(t *testing.T, cert *tls.Certificate) string {
+	require.NotNil(t, cert)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.DNSNames[0]
+}
+
+func TestServerReloadOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	copyTestFile(t, "server-1.crt", certFile)
+	copyTestFile(t, "server-1.key", keyFile)
+
+	var lock sync.Mutex
+	var reloadErrs []error
+	var reloads atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := ServerConfig{
+		Config: Config{
+			CertFile:           certFile,
+			KeyFile:            keyFile,
+			ReloadOnFileChange: true,
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	tlsCfg, err := cfg.LoadTLSConfig(ctx, WithReloadErrorHandler(func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		reloadErrs = append(reloadErrs, err)
+	}), WithReloadHandler(func() { reloads.Add(1) }))
+	require.NoError(t, err)
+
+	cert, err := tlsCfg.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "example1", certificateDNSName(t, cert))
+	assert.Zero(t, reloads.Load())
+
+	copyTestFile(t, "server-2.crt", certFile)
+	copyTestFile(t, "server-2.key", keyFile)
+	assert.Eventually(t, func() bool {
+		cert, err = tlsCfg.GetCertificate(nil)
+		return err == nil && certificateDNSName(t, cert) == "example2"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return reloads.Load() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// A failed reload keeps the previous certificate.
+	copyTestFile(t, "testCA-bad.txt", certFile)
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(reloadErrs) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	lock.Lock()
+	assert.ErrorContains(t, reloadErrs[0], "failed to load TLS cert and key")
+	lock.Unlock()
+	cert, err = tlsCfg.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "example2", certificateDNSName(t, cert))
+}
+
+func TestClientReloadOnFileChange(t *testing.T) {
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	copyTestFile(t, "server-1.crt", caFile)
+	copyTestFile(t, "client-1.crt", certFile)
+	copyTestFile(t, "client-1.key", keyFile)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := ClientConfig{
+		Config: Config{
+			CAFile:             caFile,
+			CertFile:           certFile,
+			KeyFile:            keyFile,
+			ReloadOnFileChange: true,
+		},
+	}
+	tlsCfg, err := cfg.LoadTLSConfig(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, tlsCfg.VerifyConnection)
+
+	cert, err := tlsCfg.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "example1", certificateDNSName(t, cert))
+
+	block, _ := pem.Decode(mustReadTestFile(t, "server-1.crt"))
+	require.NotNil(t, block)
+	serverCert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	cs := tls.ConnectionState{ServerName: "example1", PeerCertificates: []*x509.Certificate{serverCert}}
+	require.NoError(t, tlsCfg.VerifyConnection(cs))
+	require.Error(t, tlsCfg.VerifyConnection(tls.ConnectionState{ServerName: "example2", PeerCertificates: []*x509.Certificate{serverCert}}))
+
+	copyTestFile(t, "server-2.crt", caFile)
+	copyTestFile(t, "client-2.crt", certFile)
+	copyTestFile(t, "client-2.key", keyFile)
+	assert.Eventually(t, func() bool {
+		return tlsCfg.VerifyConnection(cs) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		cert, err = tlsCfg.GetClientCertificate(nil)
+		return err == nil && certificateDNSName(t, cert) == "example2"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestReloadOnFileChangeStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := Config{
+		CertFile:           filepath.Join("testdata", "server-1.crt"),
+		KeyFile:            filepath.Join("testdata", "server-1.key"),
+		ReloadOnFileChange: true,
+	}
+	reloader, err := cfg.newFileReloader(ctx, loadOptions{})
+	require.NoError(t, err)
+	cancel()
+	select {
+	case <-reloader.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the TLS files are still watched")
+	}
+}
+
+func TestReloadOnFileChangeStopsWithCloser(t *testing.T) {
+	cfg := ServerConfig{
+		Config: Config{
+			CertFile:           filepath.Join("testdata", "server-1.crt"),
+			KeyFile:            filepath.Join("testdata", "server-1.key"),
+			ReloadOnFileChange: true,
+		},
+		ClientCAFile:       filepath.Join("testdata", "ca-1.crt"),
+		ReloadClientCAFile: true,
+	}
+	var closers []io.Closer
+	_, err := cfg.LoadTLSConfig(context.Background(), WithReloadCloser(func(closer io.Closer) {
+		closers = append(closers, closer)
+	}))
+	require.NoError(t, err)
+	require.Len(t, closers, 2)
+	for _, closer := range closers {
+		require.NoError(t, closer.Close())
+		// Closing again is a no-op.
+		require.NoError(t, closer.Close())
+	}
+	select {
+	case <-closers[0].(*fileReloader).done:
+	default:
+		t.Fatal("the TLS files are still watched")
+	}
+}
+
+func TestReloadOnFileChangeInvalid(t *testing.T) {
+	cfg := Config{
+		CertFile:           filepath.Join("testdata", "server-1.crt"),
+		KeyFile:            filepath.Join("testdata", "server-1.key"),
+		ReloadOnFileChange: true,
+		ReloadInterval:     time.Minute,
+	}
+	require.EqualError(t, cfg.Validate(), "invalid TLS configuration: reload_on_file_change cannot be used with reload_interval")
+
+	cfg.ReloadInterval = 0
+	cfg.CertFile = filepath.Join(t.TempDir(), "missing.crt")
+	_, err := ServerConfig{Config: cfg}.LoadTLSConfig(context.Background())
+	require.ErrorContains(t, err, "failed to load TLS config")
+}
+
+func mustReadTestFile(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
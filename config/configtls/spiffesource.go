@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configtls // import "go.opentelemetry.io/collector/config/configtls"
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errNoSVID = errors.New("no X509-SVID received from the SPIFFE Workload API yet")
+
+// SPIFFEConfig sources the certificate and the CA certificates from the SPIFFE Workload API.
+type SPIFFEConfig struct {
+	// SocketPath is the address of the Workload API, e.g. unix:///run/spire/agent.sock.
+	// If empty, the SPIFFE_ENDPOINT_SOCKET environment variable is used. (optional)
+	SocketPath string `mapstructure:"socket_path"`
+}
+
+// spiffeSource keeps the X509-SVID and the trust bundles of the Workload API, rotated as they are received.
+type spiffeSource struct {
+	onReloadError func(error)
+	onReload      func()
+
+	lock    sync.RWMutex
+	svid    *x509svid.SVID
+	bundles *x509bundle.Set
+
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+var (
+	_ io.Closer                      = (*spiffeSource)(nil)
+	_ x509svid.Source                = (*spiffeSource)(nil)
+	_ x509bundle.Source              = (*spiffeSource)(nil)
+	_ workloadapi.X509ContextWatcher = (*spiffeSource)(nil)
+)
+
+// newSPIFFESource watches the Workload API until the context is done or the source is closed. It does not wait
+// for the first X509-SVID: the handshakes fail until it is received, so that the collector can start before the
+// SPIFFE agent.
+func newSPIFFESource(ctx context.Context, c SPIFFEConfig, lo loadOptions) (*spiffeSource, error) {
+	var opts []workloadapi.ClientOption
+	if c.SocketPath != "" {
+		opts = append(opts, workloadapi.WithAddr(c.SocketPath))
+	}
+	client, err := workloadapi.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SPIFFE Workload API client: %w", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	s := &spiffeSource{
+		onReloadError: lo.onReloadError,
+		onReload:      lo.onReload,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+	go func() {
+		defer close(s.done)
+		defer client.Close()
+		_ = client.WatchX509Context(watchCtx, s)
+	}()
+	return s, nil
+}
+
+// OnX509ContextUpdate keeps the X509-SVID and the trust bundles received from the Workload API.
+func (s *spiffeSource) OnX509ContextUpdate(x509Context *workloadapi.X509Context) {
+	svid := x509Context.DefaultSVID()
+	s.lock.Lock()
+	s.svid = svid
+	s.bundles = x509Context.Bundles
+	s.lock.Unlock()
+	if s.onReload != nil {
+		s.onReload()
+	}
+}
+
+// OnX509ContextWatchError reports the failures to watch the Workload API, the previous X509-SVID and trust
+// bundles are used until the next update.
+func (s *spiffeSource) OnX509ContextWatchError(err error) {
+	if status.Code(err) == codes.Canceled {
+		return
+	}
+	if s.onReloadError != nil {
+		s.onReloadError(fmt.Errorf("failed to watch the SPIFFE Workload API: %w", err))
+	}
+}
+
+// Close stops watching the Workload API and waits for the watch to end.
+func (s *spiffeSource) Close() error {
+	s.closeOnce.Do(s.cancel)
+	<-s.done
+	return nil
+}
+
+// GetX509SVID returns the last X509-SVID received, it implements x509svid.Source.
+func (s *spiffeSource) GetX509SVID() (*x509svid.SVID, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.svid == nil {
+		return nil, errNoSVID
+	}
+	return s.svid, nil
+}
+
+// GetX509BundleForTrustDomain returns the last trust bundle received for the trust domain, it implements
+// x509bundle.Source.
+func (s *spiffeSource) GetX509BundleForTrustDomain(td spiffeid.TrustDomain) (*x509bundle.Bundle, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.bundles == nil {
+		return nil, errNoSVID
+	}
+	return s.bundles.GetX509BundleForTrustDomain(td)
+}
+
+func (s *spiffeSource) getCertificate() (*tls.Certificate, error) {
+	svid, err := s.GetX509SVID()
+	if err != nil {
+		return nil, err
+	}
+	cert := &tls.Certificate{
+		PrivateKey: svid.PrivateKey,
+		Leaf:       svid.Certificates[0],
+	}
+	for _, c := range svid.Certificates {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return cert, nil
+}
+
+// verifyPeerCertificate verifies that the certificate of the peer is an X509-SVID of one of the trust domains
+// received, in place of the verification of crypto/tls: the X509-SVIDs identify the workloads by their SPIFFE ID
+// rather than by a DNS name.
+func (s *spiffeSource) verifyPeerCertificate(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("tls: peer did not provide a certificate")
+	}
+	_, _, err := x509svid.Verify(cs.PeerCertificates, s)
+	return err
+}
+
+// hookClientConfig presents the X509-SVID to the servers and verifies their X509-SVID.
+func (s *spiffeSource) hookClientConfig(tlsCfg *tls.Config) {
+	tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) { return s.getCertificate() }
+	tlsCfg.InsecureSkipVerify = true
+	tlsCfg.VerifyConnection = s.verifyPeerCertificate
+}
+
+// hookServerConfig presents the X509-SVID to the clients, and requires and verifies their X509-SVID.
+func (s *spiffeSource) hookServerConfig(tlsCfg *tls.Config) {
+	tlsCfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return s.getCertificate() }
+	tlsCfg.ClientAuth = tls.RequireAnyClientCert
+	tlsCfg.VerifyConnection = s.verifyPeerCertificate
+}
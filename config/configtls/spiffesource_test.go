@@ -0,0 +1,252 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package configtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// fakeWorkloadAPI sends its last X509-SVID response to the watchers.
+type fakeWorkloadAPI struct {
+	workload.UnimplementedSpiffeWorkloadAPIServer
+
+	lock    sync.Mutex
+	resp    *workload.X509SVIDResponse
+	updated chan struct{}
+}
+
+func (f *fakeWorkloadAPI) FetchX509SVID(_ *workload.X509SVIDRequest, stream workload.SpiffeWorkloadAPI_FetchX509SVIDServer) error {
+	for {
+		f.lock.Lock()
+		resp, updated := f.resp, f.updated
+		f.lock.Unlock()
+		if resp != nil {
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-updated:
+		}
+	}
+}
+
+func (f *fakeWorkloadAPI) setResponse(resp *workload.X509SVIDResponse) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.resp = resp
+	close(f.updated)
+	f.updated = make(chan struct{})
+}
+
+func startFakeWorkloadAPI(t *testing.T) (string, *fakeWorkloadAPI) {
+	// The path of a unix socket is limited to about 100 bytes, t.TempDir can be longer.
+	dir, err := os.MkdirTemp("", "spiffe")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "agent.sock")
+
+	ln, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	fake := &fakeWorkloadAPI{updated: make(chan struct{})}
+	srv := grpc.NewServer()
+	workload.RegisterSpiffeWorkloadAPIServer(srv, fake)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+	return "unix://" + socketPath, fake
+}
+
+// newX509SVIDResponse creates a trust domain CA and an X509-SVID signed by it.
+func newX509SVIDResponse(t *testing.T, spiffeID string) *workload.X509SVIDResponse {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	id, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		URIs:         []*url.URL{id},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	return &workload.X509SVIDResponse{
+		Svids: []*workload.X509SVID{{
+			SpiffeId:    spiffeID,
+			X509Svid:    der,
+			X509SvidKey: keyDER,
+			Bundle:      caDER,
+		}},
+	}
+}
+
+func TestSPIFFEHandshake(t *testing.T) {
+	socketPath, fake := startFakeWorkloadAPI(t)
+	spiffeCfg := &SPIFFEConfig{SocketPath: socketPath}
+
+	var closers []io.Closer
+	onWatch := WithReloadCloser(func(closer io.Closer) { closers = append(closers, closer) })
+	serverCfg, err := ServerConfig{Config: Config{SPIFFE: spiffeCfg}}.LoadTLSConfig(context.Background(), onWatch)
+	require.NoError(t, err)
+	clientCfg, err := ClientConfig{Config: Config{SPIFFE: spiffeCfg}}.LoadTLSConfig(context.Background(), onWatch)
+	require.NoError(t, err)
+	require.Len(t, closers, 2)
+	defer func() {
+		for _, closer := range closers {
+			assert.NoError(t, closer.Close())
+		}
+	}()
+
+	// The handshakes fail until the X509-SVID is received.
+	_, err = handshake(serverCfg, clientCfg)
+	require.ErrorContains(t, err, errNoSVID.Error())
+
+	fake.setResponse(newX509SVIDResponse(t, "spiffe://example.org/collector"))
+	assert.Eventually(t, func() bool {
+		peerID, errHandshake := handshake(serverCfg, clientCfg)
+		return errHandshake == nil && peerID == "spiffe://example.org/collector"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The rotated X509-SVID and trust bundle are used without reloading the TLS configuration.
+	fake.setResponse(newX509SVIDResponse(t, "spiffe://other.org/collector"))
+	assert.Eventually(t, func() bool {
+		peerID, errHandshake := handshake(serverCfg, clientCfg)
+		return errHandshake == nil && peerID == "spiffe://other.org/collector"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSPIFFERejectsUntrustedPeer(t *testing.T) {
+	serverSocketPath, serverFake := startFakeWorkloadAPI(t)
+	clientSocketPath, clientFake := startFakeWorkloadAPI(t)
+
+	var closers []io.Closer
+	onWatch := WithReloadCloser(func(closer io.Closer) { closers = append(closers, closer) })
+	serverCfg, err := ServerConfig{Config: Config{SPIFFE: &SPIFFEConfig{SocketPath: serverSocketPath}}}.LoadTLSConfig(context.Background(), onWatch)
+	require.NoError(t, err)
+	clientCfg, err := ClientConfig{Config: Config{SPIFFE: &SPIFFEConfig{SocketPath: clientSocketPath}}}.LoadTLSConfig(context.Background(), onWatch)
+	require.NoError(t, err)
+	defer func() {
+		for _, closer := range closers {
+			assert.NoError(t, closer.Close())
+		}
+	}()
+
+	// The server and the client are in the same trust domain, but with different CAs.
+	serverFake.setResponse(newX509SVIDResponse(t, "spiffe://example.org/server"))
+	clientFake.setResponse(newX509SVIDResponse(t, "spiffe://example.org/client"))
+	assert.Eventually(t, func() bool {
+		_, errHandshake := handshake(serverCfg, clientCfg)
+		return errHandshake != nil && !strings.Contains(errHandshake.Error(), errNoSVID.Error())
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSPIFFEReportsWatchErrors(t *testing.T) {
+	errs := make(chan error, 10)
+	tlsCfg, err := ClientConfig{Config: Config{SPIFFE: &SPIFFEConfig{SocketPath: "unix:///nonexistent/agent.sock"}}}.LoadTLSConfig(
+		context.Background(),
+		WithReloadErrorHandler(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}),
+		WithReloadCloser(func(closer io.Closer) { t.Cleanup(func() { assert.NoError(t, closer.Close()) }) }))
+	require.NoError(t, err)
+	require.NotNil(t, tlsCfg)
+
+	select {
+	case err = <-errs:
+		assert.ErrorContains(t, err, "failed to watch the SPIFFE Workload API")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the watch error was not reported")
+	}
+}
+
+func TestSPIFFEValidate(t *testing.T) {
+	spiffeCfg := &SPIFFEConfig{SocketPath: "unix:///run/spire/agent.sock"}
+	require.NoError(t, Config{SPIFFE: spiffeCfg}.Validate())
+	assert.Error(t, Config{SPIFFE: spiffeCfg, CAFile: "ca.crt"}.Validate())
+	assert.Error(t, Config{SPIFFE: spiffeCfg, CertFile: "cert.crt", KeyFile: "cert.key"}.Validate())
+	assert.Error(t, Config{SPIFFE: spiffeCfg, ReloadOnFileChange: true}.Validate())
+}
+
+// handshake runs a TLS handshake between a server and a client over an in-memory connection, and returns the
+// SPIFFE ID of the server seen by the client.
+func handshake(serverCfg, clientCfg *tls.Config) (string, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		server := tls.Server(serverConn, serverCfg)
+		errs <- server.Handshake()
+		_ = server.Close()
+	}()
+	client := tls.Client(clientConn, clientCfg)
+	clientErr := client.Handshake()
+	if clientErr == nil {
+		// The server verifies the client certificate after the client considers the handshake done, and sends
+		// the session tickets the client reads until the server closes the connection.
+		_, clientErr = client.Read(make([]byte, 1))
+		if errors.Is(clientErr, io.EOF) {
+			clientErr = nil
+		}
+	}
+	_ = client.Close()
+	if serverErr := <-errs; serverErr != nil {
+		return "", serverErr
+	}
+	if clientErr != nil {
+		return "", clientErr
+	}
+	return client.ConnectionState().PeerCertificates[0].URIs[0].String(), nil
+}
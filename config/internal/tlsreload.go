@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/config/internal"
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// TLSReloadOptions returns the options of configtls reporting the failed reloads of the TLS files watched because
+// of reload_on_file_change, and of the X509-SVIDs of spiffe. A failure is logged, counted by the
+// otelcol_tls_reload_failures metric of the given scope, and reported as a recoverable error of the component
+// owning the host. The status of the component is OK again after the next successful reload.
+func TLSReloadOptions(host component.Host, settings component.TelemetrySettings, scopeName string) ([]configtls.LoadOption, error) {
+	var failures metric.Int64Counter
+	if settings.MeterProvider != nil {
+		var err error
+		failures, err = settings.MeterProvider.Meter(scopeName).Int64Counter("otelcol_tls_reload_failures",
+			metric.WithDescription("Number of failed reloads of the TLS certificate, key and CA files, or of the SPIFFE X509-SVID."),
+			metric.WithUnit("{failures}"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	var failed atomic.Bool
+	return []configtls.LoadOption{
+		configtls.WithReloadErrorHandler(func(err error) {
+			if settings.Logger != nil {
+				settings.Logger.Warn("Failed to reload the TLS certificates, the previous certificates are still used", zap.Error(err))
+			}
+			if failures != nil {
+				failures.Add(context.Background(), 1)
+			}
+			failed.Store(true)
+			componentstatus.ReportStatus(host, componentstatus.NewRecoverableErrorEvent(err))
+		}),
+		configtls.WithReloadHandler(func() {
+			if failed.Swap(false) {
+				componentstatus.ReportStatus(host, componentstatus.NewEvent(componentstatus.StatusOK))
+			}
+		}),
+	}, nil
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumererror // import "go.opentelemetry.io/collector/consumer/consumererror"
+
+import (
+	"errors"
+	"strconv"
+)
+
+// PartialSuccess is an error indicating that the destination accepted the data, except for
+// a number of rejected items. The accepted items must not be sent again, so the data must
+// not be retried as a whole.
+type PartialSuccess struct {
+	err      error
+	rejected int64
+}
+
+// NewPartialSuccess creates a PartialSuccess reporting the number of items rejected by
+// the destination, and the reason given for rejecting them.
+func NewPartialSuccess(err error, rejected int64) error {
+	return PartialSuccess{err: err, rejected: rejected}
+}
+
+func (p PartialSuccess) Error() string {
+	return "Partial success, " + strconv.FormatInt(p.rejected, 10) + " items rejected: " + p.err.Error()
+}
+
+// Unwrap returns the wrapped error for functions Is and As in standard package errors.
+func (p PartialSuccess) Unwrap() error {
+	return p.err
+}
+
+// Rejected returns the number of items rejected by the destination.
+func (p PartialSuccess) Rejected() int64 {
+	return p.rejected
+}
+
+// RejectedItems returns the number of items rejected by the destination if the error,
+// or any error it wraps, is a PartialSuccess.
+func RejectedItems(err error) (int64, bool) {
+	var p PartialSuccess
+	if !errors.As(err, &p) {
+		return 0, false
+	}
+	return p.rejected, true
+}
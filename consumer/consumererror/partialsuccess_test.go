@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package consumererror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartialSuccess(t *testing.T) {
+	reason := errors.New("invalid attributes")
+	err := NewPartialSuccess(reason, 3)
+	require.EqualError(t, err, "Partial success, 3 items rejected: invalid attributes")
+	require.ErrorIs(t, err, reason)
+
+	var p PartialSuccess
+	require.ErrorAs(t, err, &p)
+	assert.Equal(t, int64(3), p.Rejected())
+}
+
+func TestRejectedItems(t *testing.T) {
+	_, ok := RejectedItems(nil)
+	assert.False(t, ok)
+
+	_, ok = RejectedItems(errors.New("testError"))
+	assert.False(t, ok)
+
+	rejected, ok := RejectedItems(fmt.Errorf("export failed: %w", NewPermanent(NewPartialSuccess(errors.New("testError"), 5))))
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), rejected)
+	assert.True(t, IsPermanent(NewPermanent(NewPartialSuccess(errors.New("testError"), 5))))
+}
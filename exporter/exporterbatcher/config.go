@@ -8,8 +8,8 @@ import (
 	"time"
 )
 
-// Config defines a configuration for batching requests based on a timeout and a minimum number of items.
-// MaxSizeItems defines batch splitting functionality if it's more than zero.
+// Config defines a configuration for batching requests based on a timeout and a minimum number of items or bytes.
+// MaxSizeItems and MaxSizeBytes define batch splitting functionality if any of them is more than zero.
 // Experimental: This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 type Config struct {
@@ -23,7 +23,7 @@ type Config struct {
 	MaxSizeConfig `mapstructure:",squash"`
 }
 
-// MinSizeConfig defines the configuration for the minimum number of items in a batch.
+// MinSizeConfig defines the configuration for the minimum size of a batch.
 // Experimental: This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 type MinSizeConfig struct {
@@ -31,9 +31,15 @@ type MinSizeConfig struct {
 	// sent regardless of the timeout. There is no guarantee that the batch size always greater than this value.
 	// This option requires the Request to implement RequestItemsCounter interface. Otherwise, it will be ignored.
 	MinSizeItems int `mapstructure:"min_size_items"`
+
+	// MinSizeBytes is the size of the batch in bytes, serialized as OTLP protobuf, at which the batch should be
+	// sent regardless of the timeout. There is no guarantee that the batch size always greater than this value.
+	// This option requires the Request to implement RequestBytesSizer interface. Otherwise, it will be ignored.
+	// Setting this value to zero disables the byte size threshold.
+	MinSizeBytes int `mapstructure:"min_size_bytes"`
 }
 
-// MaxSizeConfig defines the configuration for the maximum number of items in a batch.
+// MaxSizeConfig defines the configuration for the maximum size of a batch.
 // Experimental: This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 type MaxSizeConfig struct {
@@ -41,6 +47,12 @@ type MaxSizeConfig struct {
 	// If the batch size exceeds this value, it will be broken up into smaller batches if possible.
 	// Setting this value to zero disables the maximum size limit.
 	MaxSizeItems int `mapstructure:"max_size_items"`
+
+	// MaxSizeBytes is the maximum size of the batch in bytes, serialized as OTLP protobuf.
+	// If the batch size exceeds this value, it will be broken up into smaller batches if possible.
+	// A single item that exceeds this value on its own is sent in a batch of its own.
+	// Setting this value to zero disables the maximum size limit.
+	MaxSizeBytes int `mapstructure:"max_size_bytes"`
 }
 
 func (c Config) Validate() error {
@@ -53,6 +65,15 @@ func (c Config) Validate() error {
 	if c.MaxSizeItems != 0 && c.MaxSizeItems < c.MinSizeItems {
 		return errors.New("max_size_items must be greater than or equal to min_size_items")
 	}
+	if c.MinSizeBytes < 0 {
+		return errors.New("min_size_bytes must be greater than or equal to zero")
+	}
+	if c.MaxSizeBytes < 0 {
+		return errors.New("max_size_bytes must be greater than or equal to zero")
+	}
+	if c.MaxSizeBytes != 0 && c.MaxSizeBytes < c.MinSizeBytes {
+		return errors.New("max_size_bytes must be greater than or equal to min_size_bytes")
+	}
 	if c.FlushTimeout <= 0 {
 		return errors.New("timeout must be greater than zero")
 	}
@@ -28,4 +28,20 @@ func TestConfig_Validate(t *testing.T) {
 	cfg.MaxSizeItems = 20000
 	cfg.MinSizeItems = 20001
 	assert.EqualError(t, cfg.Validate(), "max_size_items must be greater than or equal to min_size_items")
+
+	cfg = NewDefaultConfig()
+	cfg.MinSizeBytes = -1
+	require.EqualError(t, cfg.Validate(), "min_size_bytes must be greater than or equal to zero")
+
+	cfg = NewDefaultConfig()
+	cfg.MaxSizeBytes = -1
+	require.EqualError(t, cfg.Validate(), "max_size_bytes must be greater than or equal to zero")
+
+	cfg = NewDefaultConfig()
+	cfg.MinSizeBytes = 1 << 20
+	cfg.MaxSizeBytes = 4 << 20
+	require.NoError(t, cfg.Validate())
+
+	cfg.MaxSizeBytes = 1<<20 - 1
+	assert.EqualError(t, cfg.Validate(), "max_size_bytes must be greater than or equal to min_size_bytes")
 }
@@ -0,0 +1,16 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// CircuitBreakerConfig defines when the exporter stops sending requests to a backend that is considered unavailable.
+type CircuitBreakerConfig = internal.CircuitBreakerConfig
+
+// NewDefaultCircuitBreakerConfig returns the default config for CircuitBreakerConfig.
+func NewDefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return internal.NewDefaultCircuitBreakerConfig()
+}
@@ -39,6 +39,18 @@ func WithRetry(config configretry.BackOffConfig) Option {
 	return internal.WithRetry(config)
 }
 
+// WithCircuitBreaker overrides the default CircuitBreakerConfig for an exporter.
+// The default CircuitBreakerConfig is to always send the requests to the backend.
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return internal.WithCircuitBreaker(config)
+}
+
+// WithRateLimit overrides the default RateLimitConfig for an exporter.
+// The default RateLimitConfig is to not limit the rate of the requests.
+func WithRateLimit(config RateLimitConfig) Option {
+	return internal.WithRateLimit(config)
+}
+
 // WithQueue overrides the default QueueConfig for an exporter.
 // The default QueueConfig is to disable queueing.
 // This option cannot be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
@@ -46,6 +58,14 @@ func WithQueue(config internal.QueueConfig) Option {
 	return internal.WithQueue(config)
 }
 
+// WithDeadLetter sets where the requests that failed permanently, or for which the retries were exhausted, are sent.
+// The dead-letter exporter is only available for the OTLP-based exporters created with New[Traces|Metrics|Logs],
+// and the dead-letter storage requires the requests to be marshalable, like for the persistent queue.
+// The default DeadLetterConfig is to drop the failed requests.
+func WithDeadLetter(cfg DeadLetterConfig) Option {
+	return internal.WithDeadLetter(cfg)
+}
+
 // WithRequestQueue enables queueing for an exporter.
 // This option should be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
 // Experimental: This API is at the early stage of development and may change without backward compatibility
@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+const (
+	// DeadLetterExporterAttribute is the resource attribute added to the dead-lettered data
+	// with the ID of the exporter that failed to send it.
+	DeadLetterExporterAttribute = internal.DeadLetterExporterAttribute
+	// DeadLetterReasonAttribute is the resource attribute added to the dead-lettered data
+	// with the error returned by the last attempt to send it.
+	DeadLetterReasonAttribute = internal.DeadLetterReasonAttribute
+	// DeadLetterAttemptsAttribute is the resource attribute added to the dead-lettered data
+	// with the number of attempts made to send it.
+	DeadLetterAttemptsAttribute = internal.DeadLetterAttemptsAttribute
+)
+
+// DeadLetterConfig defines where the requests that failed permanently, or for which the retries were exhausted,
+// are sent instead of being dropped.
+type DeadLetterConfig = internal.DeadLetterConfig
+
+// NewDefaultDeadLetterConfig returns the default config for DeadLetterConfig.
+func NewDefaultDeadLetterConfig() DeadLetterConfig {
+	return internal.NewDefaultDeadLetterConfig()
+}
@@ -16,3 +16,9 @@ type Request = internal.Request
 // Experimental: This API is at the early stage of development and may change without backward compatibility
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 type RequestErrorHandler = internal.RequestErrorHandler
+
+// RequestBytesSizer is an optional interface that can be implemented by Request to report its size in bytes,
+// usually the size of its serialized form. It's required to measure the sending queue in bytes.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestBytesSizer = internal.RequestBytesSizer
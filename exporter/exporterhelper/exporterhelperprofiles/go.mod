@@ -1,6 +1,7 @@
 module go.opentelemetry.io/collector/exporter/exporterhelper/exporterhelperprofiles
 
-go 1.22.0
+go 1.23.0
+
 toolchain go1.23.7
 
 require (
@@ -34,6 +35,7 @@ require (
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/collector/component/componentstatus v0.111.0 // indirect
 	go.opentelemetry.io/collector/config/configtelemetry v0.111.0 // indirect
 	go.opentelemetry.io/collector/extension v0.111.0 // indirect
 	go.opentelemetry.io/collector/extension/experimental/storage v0.111.0 // indirect
@@ -87,3 +89,5 @@ replace go.opentelemetry.io/collector/pipeline/pipelineprofiles => ../../../pipe
 replace go.opentelemetry.io/collector/extension/experimental/storage => ../../../extension/experimental/storage
 
 replace go.opentelemetry.io/collector/pipeline => ../../../pipeline
+
+replace go.opentelemetry.io/collector/component/componentstatus => ../../../component/componentstatus
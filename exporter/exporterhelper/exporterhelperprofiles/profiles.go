@@ -67,6 +67,29 @@ func (req *profilesRequest) ItemsCount() int {
 	return req.pd.SampleCount()
 }
 
+func (req *profilesRequest) BytesSize() int {
+	return profilesMarshaler.ProfilesSize(req.pd)
+}
+
+// exportProfilesDeadLetter sends a copy of the profiles of a failed request, annotated with the dead-letter
+// information, to the dead-letter exporter.
+func exportProfilesDeadLetter(ctx context.Context, exp component.Component, req exporterhelper.Request, info internal.DeadLetterInfo) error {
+	pr, ok := req.(*profilesRequest)
+	if !ok {
+		return errors.New("invalid input type")
+	}
+	dlExp, ok := exp.(consumerprofiles.Profiles)
+	if !ok {
+		return errors.New("dead-letter exporter does not support profiles")
+	}
+	pd := pprofile.NewProfiles()
+	pr.pd.CopyTo(pd)
+	for i := 0; i < pd.ResourceProfiles().Len(); i++ {
+		info.PutAttributes(pd.ResourceProfiles().At(i).Resource().Attributes())
+	}
+	return dlExp.ConsumeProfiles(ctx, pd)
+}
+
 type profileExporter struct {
 	*internal.BaseExporter
 	consumerprofiles.Profiles
@@ -89,6 +112,7 @@ func NewProfilesExporter(
 	profilesOpts := []exporterhelper.Option{
 		internal.WithMarshaler(profilesRequestMarshaler), internal.WithUnmarshaler(newProfileRequestUnmarshalerFunc(pusher)),
 		internal.WithBatchFuncs(mergeProfiles, mergeSplitProfiles),
+		internal.WithDeadLetterExportFunc(exportProfilesDeadLetter),
 	}
 	return NewProfilesRequestExporter(ctx, set, requestFromProfiles(pusher), append(profilesOpts, options...)...)
 }
@@ -9,6 +9,7 @@ import (
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/pprofile"
 )
 
@@ -26,9 +27,9 @@ func mergeProfiles(_ context.Context, r1 exporterhelper.Request, r2 exporterhelp
 // mergeSplitProfiles splits and/or merges the profiles into multiple requests based on the MaxSizeConfig.
 func mergeSplitProfiles(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 exporterhelper.Request, r2 exporterhelper.Request) ([]exporterhelper.Request, error) {
 	var (
-		res          []exporterhelper.Request
-		destReq      *profilesRequest
-		capacityLeft = cfg.MaxSizeItems
+		res      []exporterhelper.Request
+		destReq  *profilesRequest
+		capacity = internal.NewBatchCapacity(cfg)
 	)
 	for _, req := range []exporterhelper.Request{r1, r2} {
 		if req == nil {
@@ -38,33 +39,39 @@ func mergeSplitProfiles(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1
 		if !ok {
 			return nil, errors.New("invalid input type")
 		}
-		if srcReq.pd.SampleCount() <= capacityLeft {
+		srcCount := srcReq.pd.SampleCount()
+		srcSize := 0
+		if capacity.BytesLimited() {
+			srcSize = profilesMarshaler.ProfilesSize(srcReq.pd)
+		}
+		if capacity.Fits(srcCount, srcSize) {
 			if destReq == nil {
 				destReq = srcReq
 			} else {
 				srcReq.pd.ResourceProfiles().MoveAndAppendTo(destReq.pd.ResourceProfiles())
 			}
-			capacityLeft -= destReq.pd.SampleCount()
+			capacity.Consume(srcCount, srcSize)
 			continue
 		}
 
 		for {
-			extractedProfiles := extractProfiles(srcReq.pd, capacityLeft)
-			if extractedProfiles.SampleCount() == 0 {
-				break
+			extractedProfiles := extractProfiles(srcReq.pd, capacity, destReq == nil)
+			if extractedProfiles.ResourceProfiles().Len() > 0 {
+				if destReq == nil {
+					destReq = &profilesRequest{pd: extractedProfiles, pusher: srcReq.pusher}
+				} else {
+					extractedProfiles.ResourceProfiles().MoveAndAppendTo(destReq.pd.ResourceProfiles())
+				}
 			}
-			capacityLeft -= extractedProfiles.SampleCount()
-			if destReq == nil {
-				destReq = &profilesRequest{pd: extractedProfiles, pusher: srcReq.pusher}
-			} else {
-				extractedProfiles.ResourceProfiles().MoveAndAppendTo(destReq.pd.ResourceProfiles())
+			if srcReq.pd.ResourceProfiles().Len() == 0 {
+				break
 			}
 			// Create new batch once capacity is reached.
-			if capacityLeft == 0 {
+			if destReq != nil {
 				res = append(res, destReq)
 				destReq = nil
-				capacityLeft = cfg.MaxSizeItems
 			}
+			capacity.Reset()
 		}
 	}
 
@@ -74,70 +81,97 @@ func mergeSplitProfiles(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1
 	return res, nil
 }
 
-// extractProfiles extracts a new profiles with a maximum number of samples.
-func extractProfiles(srcProfiles pprofile.Profiles, count int) pprofile.Profiles {
+// extractProfiles extracts a new profiles with the profiles that fit in the capacity.
+// If force is true, at least one profile is extracted even if it doesn't fit.
+func extractProfiles(srcProfiles pprofile.Profiles, capacity *internal.BatchCapacity, force bool) pprofile.Profiles {
 	destProfiles := pprofile.NewProfiles()
 	srcProfiles.ResourceProfiles().RemoveIf(func(srcRS pprofile.ResourceProfiles) bool {
-		if count == 0 {
+		mustExtract := force && destProfiles.ResourceProfiles().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := samplesCount(srcRS) > count
-		if needToExtract {
-			srcRS = extractResourceProfiles(srcRS, count)
+		count := samplesCount(srcRS)
+		size := capacity.FieldSize(func() int { return profilesMarshaler.ResourceProfilesSize(srcRS) })
+		if capacity.Fits(count, size) || (mustExtract && srcRS.ScopeProfiles().Len() == 0) {
+			capacity.Consume(count, size)
+			srcRS.MoveTo(destProfiles.ResourceProfiles().AppendEmpty())
+			return true
 		}
-		count -= samplesCount(srcRS)
-		srcRS.MoveTo(destProfiles.ResourceProfiles().AppendEmpty())
-		return !needToExtract
+		destRS := extractResourceProfiles(srcRS, capacity, mustExtract)
+		if destRS.ScopeProfiles().Len() > 0 {
+			destRS.MoveTo(destProfiles.ResourceProfiles().AppendEmpty())
+		}
+		return srcRS.ScopeProfiles().Len() == 0
 	})
 	return destProfiles
 }
 
-// extractResourceProfiles extracts profiles and returns a new resource profiles with the specified number of profiles.
-func extractResourceProfiles(srcRS pprofile.ResourceProfiles, count int) pprofile.ResourceProfiles {
+// extractResourceProfiles extracts profiles and returns a new resource profiles with the profiles that fit in the capacity.
+// If force is true, at least one profile is extracted even if it doesn't fit.
+func extractResourceProfiles(srcRS pprofile.ResourceProfiles, capacity *internal.BatchCapacity, force bool) pprofile.ResourceProfiles {
 	destRS := pprofile.NewResourceProfiles()
 	destRS.SetSchemaUrl(srcRS.SchemaUrl())
 	srcRS.Resource().CopyTo(destRS.Resource())
+	defer capacity.ReserveContainer(func() int { return profilesMarshaler.ResourceProfilesSize(destRS) }, 1)()
 	srcRS.ScopeProfiles().RemoveIf(func(srcSS pprofile.ScopeProfiles) bool {
-		if count == 0 {
+		mustExtract := force && destRS.ScopeProfiles().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := srcSS.Profiles().Len() > count
-		if needToExtract {
-			srcSS = extractScopeProfiles(srcSS, count)
+		count := scopeSamplesCount(srcSS)
+		size := capacity.FieldSize(func() int { return profilesMarshaler.ScopeProfilesSize(srcSS) })
+		if capacity.Fits(count, size) || (mustExtract && srcSS.Profiles().Len() == 0) {
+			capacity.Consume(count, size)
+			srcSS.MoveTo(destRS.ScopeProfiles().AppendEmpty())
+			return true
+		}
+		destSS := extractScopeProfiles(srcSS, capacity, mustExtract)
+		if destSS.Profiles().Len() > 0 {
+			destSS.MoveTo(destRS.ScopeProfiles().AppendEmpty())
 		}
-		count -= srcSS.Profiles().Len()
-		srcSS.MoveTo(destRS.ScopeProfiles().AppendEmpty())
-		return !needToExtract
+		return srcSS.Profiles().Len() == 0
 	})
-	srcRS.Resource().CopyTo(destRS.Resource())
 	return destRS
 }
 
-// extractScopeProfiles extracts profiles and returns a new scope profiles with the specified number of profiles.
-func extractScopeProfiles(srcSS pprofile.ScopeProfiles, count int) pprofile.ScopeProfiles {
+// extractScopeProfiles extracts profiles and returns a new scope profiles with the profiles that fit in the capacity.
+// If force is true, at least one profile is extracted even if it doesn't fit.
+func extractScopeProfiles(srcSS pprofile.ScopeProfiles, capacity *internal.BatchCapacity, force bool) pprofile.ScopeProfiles {
 	destSS := pprofile.NewScopeProfiles()
 	destSS.SetSchemaUrl(srcSS.SchemaUrl())
 	srcSS.Scope().CopyTo(destSS.Scope())
+	defer capacity.ReserveContainer(func() int { return profilesMarshaler.ScopeProfilesSize(destSS) }, 1)()
 	srcSS.Profiles().RemoveIf(func(srcProfile pprofile.ProfileContainer) bool {
-		if count == 0 {
+		mustExtract := force && destSS.Profiles().Len() == 0
+		if capacity.Full() && !mustExtract {
+			return false
+		}
+		count := srcProfile.Profile().Sample().Len()
+		size := capacity.FieldSize(func() int { return profilesMarshaler.ProfileContainerSize(srcProfile) })
+		if !capacity.FitsLeaf(count, size) && !mustExtract {
 			return false
 		}
+		capacity.Consume(count, size)
 		srcProfile.MoveTo(destSS.Profiles().AppendEmpty())
-		count--
 		return true
 	})
 	return destSS
 }
 
-// resourceProfilessCount calculates the total number of profiles in the pdata.ResourceProfiles.
+// samplesCount calculates the total number of samples in the pprofile.ResourceProfiles.
 func samplesCount(rs pprofile.ResourceProfiles) int {
 	count := 0
-	rs.ScopeProfiles().RemoveIf(func(ss pprofile.ScopeProfiles) bool {
-		ss.Profiles().RemoveIf(func(sp pprofile.ProfileContainer) bool {
-			count += sp.Profile().Sample().Len()
-			return false
-		})
-		return false
-	})
+	for i := 0; i < rs.ScopeProfiles().Len(); i++ {
+		count += scopeSamplesCount(rs.ScopeProfiles().At(i))
+	}
+	return count
+}
+
+// scopeSamplesCount calculates the total number of samples in the pprofile.ScopeProfiles.
+func scopeSamplesCount(ss pprofile.ScopeProfiles) int {
+	count := 0
+	for i := 0; i < ss.Profiles().Len(); i++ {
+		count += ss.Profiles().At(i).Profile().Sample().Len()
+	}
 	return count
 }
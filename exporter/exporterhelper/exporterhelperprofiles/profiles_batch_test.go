@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/testdata"
@@ -151,10 +152,57 @@ func TestMergeSplitProfilesInvalidInput(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMergeSplitProfilesMaxSizeBytes(t *testing.T) {
+	maxSizeBytes := profilesMarshaler.ProfilesSize(testdata.GenerateProfiles(4))
+	tests := []struct {
+		name          string
+		cfg           exporterbatcher.MaxSizeConfig
+		pr1           exporterhelper.Request
+		pr2           exporterhelper.Request
+		expectedCount []int
+	}{
+		{
+			name:          "merge_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 2 * maxSizeBytes},
+			pr1:           &profilesRequest{pd: testdata.GenerateProfiles(2)},
+			pr2:           &profilesRequest{pd: testdata.GenerateProfiles(2)},
+			expectedCount: []int{4},
+		},
+		{
+			name:          "split_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes},
+			pr1:           nil,
+			pr2:           &profilesRequest{pd: testdata.GenerateProfiles(10)},
+			expectedCount: []int{4, 4, 2},
+		},
+		{
+			name:          "profile_bigger_than_limit",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 10},
+			pr1:           &profilesRequest{pd: testdata.GenerateProfiles(1)},
+			pr2:           &profilesRequest{pd: testdata.GenerateProfiles(2)},
+			expectedCount: []int{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := mergeSplitProfiles(context.Background(), tt.cfg, tt.pr1, tt.pr2)
+			require.NoError(t, err)
+			require.Len(t, res, len(tt.expectedCount))
+			for i, r := range res {
+				pd := r.(*profilesRequest).pd
+				assert.Equal(t, tt.expectedCount[i], pd.SampleCount())
+				if pd.SampleCount() > 1 {
+					assert.LessOrEqual(t, profilesMarshaler.ProfilesSize(pd), tt.cfg.MaxSizeBytes)
+				}
+			}
+		})
+	}
+}
+
 func TestExtractProfiles(t *testing.T) {
-	for i := 0; i < 10; i++ {
+	for i := 1; i < 10; i++ {
 		ld := testdata.GenerateProfiles(10)
-		extractedProfiles := extractProfiles(ld, i)
+		extractedProfiles := extractProfiles(ld, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: i}), false)
 		assert.Equal(t, i, extractedProfiles.SampleCount())
 		assert.Equal(t, 10-i, ld.SampleCount())
 	}
@@ -5,6 +5,7 @@ package internal // import "go.opentelemetry.io/collector/exporter/exporterhelpe
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -17,6 +18,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/exporter/exporterqueue" // BaseExporter contains common fields between different exporter types.
@@ -41,6 +43,8 @@ type BaseExporter struct {
 	Marshaler   exporterqueue.Marshaler[internal.Request]
 	Unmarshaler exporterqueue.Unmarshaler[internal.Request]
 
+	DeadLetterExportFunc DeadLetterExportFunc
+
 	Set    exporter.Settings
 	Obsrep *ObsReport
 
@@ -50,17 +54,21 @@ type BaseExporter struct {
 	// Chain of senders that the exporter helper applies before passing the data to the actual exporter.
 	// The data is handled by each sender in the respective order starting from the queueSender.
 	// Most of the senders are optional, and initialized with a no-op path-through sender.
-	BatchSender   RequestSender
-	QueueSender   RequestSender
-	ObsrepSender  RequestSender
-	RetrySender   RequestSender
-	TimeoutSender *TimeoutSender // TimeoutSender is always initialized.
+	BatchSender          RequestSender
+	QueueSender          RequestSender
+	ObsrepSender         RequestSender
+	DeadLetterSender     RequestSender
+	RetrySender          RequestSender
+	CircuitBreakerSender RequestSender
+	RateLimitSender      RequestSender
+	TimeoutSender        *TimeoutSender // TimeoutSender is always initialized.
 
 	ConsumerOptions []consumer.Option
 
-	queueCfg     exporterqueue.Config
-	queueFactory exporterqueue.Factory[internal.Request]
-	BatcherCfg   exporterbatcher.Config
+	queueCfg      exporterqueue.Config
+	queueFactory  exporterqueue.Factory[internal.Request]
+	BatcherCfg    exporterbatcher.Config
+	deadLetterCfg DeadLetterConfig
 }
 
 func NewBaseExporter(set exporter.Settings, signal pipeline.Signal, osf ObsrepSenderFactory, options ...Option) (*BaseExporter, error) {
@@ -72,11 +80,14 @@ func NewBaseExporter(set exporter.Settings, signal pipeline.Signal, osf ObsrepSe
 	be := &BaseExporter{
 		Signal: signal,
 
-		BatchSender:   &BaseRequestSender{},
-		QueueSender:   &BaseRequestSender{},
-		ObsrepSender:  osf(obsReport),
-		RetrySender:   &BaseRequestSender{},
-		TimeoutSender: &TimeoutSender{cfg: NewDefaultTimeoutConfig()},
+		BatchSender:          &BaseRequestSender{},
+		QueueSender:          &BaseRequestSender{},
+		ObsrepSender:         osf(obsReport),
+		DeadLetterSender:     &BaseRequestSender{},
+		RetrySender:          &BaseRequestSender{},
+		CircuitBreakerSender: &BaseRequestSender{},
+		RateLimitSender:      &BaseRequestSender{},
+		TimeoutSender:        &TimeoutSender{cfg: NewDefaultTimeoutConfig()},
 
 		Set:    set,
 		Obsrep: obsReport,
@@ -97,7 +108,11 @@ func NewBaseExporter(set exporter.Settings, signal pipeline.Signal, osf ObsrepSe
 				ExporterSettings: be.Set,
 			},
 			be.queueCfg)
-		be.QueueSender = NewQueueSender(q, be.Set, be.queueCfg.NumConsumers, be.ExportFailureMessage, be.Obsrep)
+		qs := NewQueueSender(q, be.Set, be.queueCfg, be.ExportFailureMessage, be.Obsrep)
+		if be.Marshaler != nil && be.Unmarshaler != nil {
+			qs.unsent = newUnsentRequests(be.Set.ID, be.Signal, be.Marshaler, be.Unmarshaler)
+		}
+		be.QueueSender = qs
 		for _, op := range options {
 			err = multierr.Append(err, op(be))
 		}
@@ -111,6 +126,17 @@ func NewBaseExporter(set exporter.Settings, signal pipeline.Signal, osf ObsrepSe
 		be.BatchSender = bs
 	}
 
+	if be.deadLetterCfg.Exporter != nil || be.deadLetterCfg.Storage != nil {
+		switch {
+		case be.deadLetterCfg.Exporter != nil && be.DeadLetterExportFunc == nil:
+			err = multierr.Append(err, errors.New("dead-letter exporter is not available for the new request exporters"))
+		case be.deadLetterCfg.Storage != nil && be.Marshaler == nil:
+			err = multierr.Append(err, errors.New("dead-letter storage is not available for the new request exporters"))
+		default:
+			be.DeadLetterSender = newDeadLetterSender(be.deadLetterCfg, be.Set, be.Signal, be.DeadLetterExportFunc, be.Marshaler)
+		}
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +158,10 @@ func NewBaseExporter(set exporter.Settings, signal pipeline.Signal, osf ObsrepSe
 // send sends the request using the first sender in the chain.
 func (be *BaseExporter) Send(ctx context.Context, req internal.Request) error {
 	err := be.QueueSender.Send(ctx, req)
-	if err != nil {
+	if rejected, ok := consumererror.RejectedItems(err); ok {
+		be.Set.Logger.Warn("Exporting partially failed. The destination rejected some items.",
+			zap.Error(err), zap.Int64("rejected_items", rejected))
+	} else if err != nil {
 		be.Set.Logger.Error("Exporting failed. Rejecting data."+be.ExportFailureMessage,
 			zap.Error(err), zap.Int("rejected_items", req.ItemsCount()))
 	}
@@ -143,8 +172,11 @@ func (be *BaseExporter) Send(ctx context.Context, req internal.Request) error {
 func (be *BaseExporter) connectSenders() {
 	be.QueueSender.SetNextSender(be.BatchSender)
 	be.BatchSender.SetNextSender(be.ObsrepSender)
-	be.ObsrepSender.SetNextSender(be.RetrySender)
-	be.RetrySender.SetNextSender(be.TimeoutSender)
+	be.ObsrepSender.SetNextSender(be.DeadLetterSender)
+	be.DeadLetterSender.SetNextSender(be.RetrySender)
+	be.RetrySender.SetNextSender(be.CircuitBreakerSender)
+	be.CircuitBreakerSender.SetNextSender(be.RateLimitSender)
+	be.RateLimitSender.SetNextSender(be.TimeoutSender)
 }
 
 func (be *BaseExporter) Start(ctx context.Context, host component.Host) error {
@@ -153,6 +185,16 @@ func (be *BaseExporter) Start(ctx context.Context, host component.Host) error {
 		return err
 	}
 
+	// Then resolve the dead-letter target, it must be ready before any request is sent.
+	if err := be.DeadLetterSender.Start(ctx, host); err != nil {
+		return err
+	}
+
+	// The circuit breaker reports the availability of the backend through the host.
+	if err := be.CircuitBreakerSender.Start(ctx, host); err != nil {
+		return err
+	}
+
 	// If no error then start the BatchSender.
 	if err := be.BatchSender.Start(ctx, host); err != nil {
 		return err
@@ -170,6 +212,8 @@ func (be *BaseExporter) Shutdown(ctx context.Context) error {
 		be.BatchSender.Shutdown(ctx),
 		// Then shutdown the queue sender.
 		be.QueueSender.Shutdown(ctx),
+		// Then shutdown the dead-letter sender, that may receive the failed requests drained from the queue.
+		be.DeadLetterSender.Shutdown(ctx),
 		// Last shutdown the wrapped exporter itself.
 		be.ShutdownFunc.Shutdown(ctx))
 }
@@ -214,6 +258,30 @@ func WithRetry(config configretry.BackOffConfig) Option {
 	}
 }
 
+// WithCircuitBreaker overrides the default CircuitBreakerConfig for an exporter.
+// The default CircuitBreakerConfig is to always send the requests to the backend.
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(o *BaseExporter) error {
+		if !config.Enabled {
+			return nil
+		}
+		o.CircuitBreakerSender = newCircuitBreakerSender(config, o.Set)
+		return nil
+	}
+}
+
+// WithRateLimit overrides the default RateLimitConfig for an exporter.
+// The default RateLimitConfig is to not limit the rate of the requests.
+func WithRateLimit(config RateLimitConfig) Option {
+	return func(o *BaseExporter) error {
+		if !config.Enabled {
+			return nil
+		}
+		o.RateLimitSender = newRateLimitSender(config, o.Obsrep)
+		return nil
+	}
+}
+
 // WithQueue overrides the default QueueConfig for an exporter.
 // The default QueueConfig is to disable queueing.
 // This option cannot be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
@@ -227,9 +295,11 @@ func WithQueue(config QueueConfig) Option {
 			return nil
 		}
 		o.queueCfg = exporterqueue.Config{
-			Enabled:      config.Enabled,
-			NumConsumers: config.NumConsumers,
-			QueueSize:    config.QueueSize,
+			Enabled:             config.Enabled,
+			NumConsumers:        config.NumConsumers,
+			QueueSize:           config.QueueSize,
+			Sizer:               config.Sizer,
+			AdaptiveConcurrency: config.AdaptiveConcurrency,
 		}
 		o.queueFactory = exporterqueue.NewPersistentQueueFactory[internal.Request](config.StorageID, exporterqueue.PersistentQueueSettings[internal.Request]{
 			Marshaler:   o.Marshaler,
@@ -239,6 +309,24 @@ func WithQueue(config QueueConfig) Option {
 	}
 }
 
+// WithDeadLetter sets where the requests that failed permanently, or for which the retries were exhausted, are sent.
+// The default DeadLetterConfig is to drop the failed requests.
+func WithDeadLetter(cfg DeadLetterConfig) Option {
+	return func(o *BaseExporter) error {
+		o.deadLetterCfg = cfg
+		return nil
+	}
+}
+
+// WithDeadLetterExportFunc is used to set the function sending the failed requests to a dead-letter exporter
+// for the OTLP-based exporters. It must be provided as the first option when creating a new exporter helper.
+func WithDeadLetterExportFunc(f DeadLetterExportFunc) Option {
+	return func(o *BaseExporter) error {
+		o.DeadLetterExportFunc = f
+		return nil
+	}
+}
+
 // WithRequestQueue enables queueing for an exporter.
 // This option should be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
 // Experimental: This API is at the early stage of development and may change without backward compatibility
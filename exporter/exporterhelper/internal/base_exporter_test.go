@@ -8,6 +8,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
@@ -15,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/exporter/exportertest"
@@ -87,3 +89,21 @@ func TestBaseExporterLogging(t *testing.T) {
 
 	require.Len(t, observed.FilterLevelExact(zap.ErrorLevel).All(), 1)
 }
+
+func TestBaseExporterLoggingPartialSuccess(t *testing.T) {
+	set := exportertest.NewNopSettings()
+	logger, observed := observer.New(zap.DebugLevel)
+	set.Logger = zap.New(logger)
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.Enabled = false
+	bs, err := NewBaseExporter(set, defaultSignal, newNoopObsrepSender, WithRetry(rCfg))
+	require.NoError(t, err)
+	partialErr := consumererror.NewPermanent(consumererror.NewPartialSuccess(errors.New("invalid data"), 2))
+	require.ErrorIs(t, bs.Send(context.Background(), newMockRequest(5, partialErr)), partialErr)
+
+	// Only the items rejected by the destination are reported.
+	require.Empty(t, observed.FilterLevelExact(zap.ErrorLevel).All())
+	warns := observed.FilterLevelExact(zap.WarnLevel).All()
+	require.Len(t, warns, 1)
+	assert.Equal(t, int64(2), warns[0].ContextMap()["rejected_items"])
+}
@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"math"
+	"math/bits"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+)
+
+// BatchCapacity tracks the room left in a batch assembled by the merge-split functions, both in items and in bytes
+// of the batch serialized as OTLP protobuf. Limits set to zero in the MaxSizeConfig are not enforced.
+type BatchCapacity struct {
+	cfg   exporterbatcher.MaxSizeConfig
+	items int
+	bytes int
+	// minLeafBytes is the size of the smallest leaf seen by FitsLeaf, the batch is full once fewer bytes are left.
+	minLeafBytes int
+}
+
+// NewBatchCapacity returns the capacity of an empty batch.
+func NewBatchCapacity(cfg exporterbatcher.MaxSizeConfig) *BatchCapacity {
+	bc := &BatchCapacity{cfg: cfg}
+	bc.Reset()
+	return bc
+}
+
+// Reset restores the capacity of an empty batch.
+func (bc *BatchCapacity) Reset() {
+	bc.items = math.MaxInt
+	if bc.cfg.MaxSizeItems > 0 {
+		bc.items = bc.cfg.MaxSizeItems
+	}
+	bc.bytes = math.MaxInt
+	if bc.cfg.MaxSizeBytes > 0 {
+		bc.bytes = bc.cfg.MaxSizeBytes
+	}
+}
+
+// BytesLimited returns true if the size of the batch in bytes is limited. Callers can skip computing
+// the byte sizes passed to the other methods when it's not, zero can be passed instead.
+func (bc *BatchCapacity) BytesLimited() bool {
+	return bc.cfg.MaxSizeBytes > 0
+}
+
+// Fits returns true if the given number of items and bytes fit in the batch.
+func (bc *BatchCapacity) Fits(items, bytes int) bool {
+	return items <= bc.items && (!bc.BytesLimited() || bytes <= bc.bytes)
+}
+
+// FitsLeaf returns true if a leaf element, i.e. an element that is not split further like a span, with the given
+// number of items and bytes fits in the batch.
+func (bc *BatchCapacity) FitsLeaf(items, bytes int) bool {
+	if bc.BytesLimited() && (bc.minLeafBytes == 0 || bytes < bc.minLeafBytes) {
+		bc.minLeafBytes = bytes
+	}
+	return bc.Fits(items, bytes)
+}
+
+// Full returns true if no more items fit in the batch, or if the bytes left are fewer than the size of the smallest
+// leaf seen. The merge-split functions keep looking for elements that fit in the batch until it's full, so that
+// the batches are filled even when the elements have different sizes.
+func (bc *BatchCapacity) Full() bool {
+	return bc.items <= 0 || (bc.BytesLimited() && (bc.bytes <= 0 || bc.bytes < bc.minLeafBytes))
+}
+
+// Consume takes the given number of items and bytes from the batch capacity.
+// The capacity can become negative if an element that doesn't fit is forced into the batch.
+func (bc *BatchCapacity) Consume(items, bytes int) {
+	bc.items -= items
+	if bc.BytesLimited() {
+		bc.bytes -= bytes
+	}
+}
+
+// FieldSize returns the number of bytes taken by an element with the given message size when it's added to
+// the batch as an embedded message field. The size function is not called if the size in bytes is not limited.
+func (bc *BatchCapacity) FieldSize(size func() int) int {
+	if !bc.BytesLimited() {
+		return 0
+	}
+	return ProtoFieldSize(size())
+}
+
+// ReserveContainer takes the bytes needed to add a new container message, e.g. a ResourceLogs split from a bigger one,
+// to the batch. The size function returns the current size of the container message, it's called before the nested
+// elements are added and once again by the returned function. The length prefixes are reserved for the worst case,
+// so the nested elements that fit in the remaining capacity cannot make the container exceed the capacity.
+// levels is the number of length-delimited messages wrapping the nested elements in the container field, including
+// the container itself, e.g. 1 for a ScopeLogs holding LogRecords and 2 for a Metric holding data points in a Gauge.
+// The returned function must be called once the container is complete, it replaces the reserved bytes and the bytes
+// consumed by the nested elements with the actual size of the container field. If no nested element was added,
+// the container is not added to the batch and the reserved bytes are released.
+func (bc *BatchCapacity) ReserveContainer(size func() int, levels int) func() {
+	if !bc.BytesLimited() {
+		return func() {}
+	}
+	before := bc.bytes
+	prefixSize := 1
+	if before > 0 {
+		prefixSize = varintSize(uint64(before))
+	}
+	emptySize := size()
+	bc.bytes -= emptySize + 1 + prefixSize + (levels-1)*(prefixSize-1)
+	return func() {
+		if containerSize := size(); containerSize != emptySize {
+			bc.bytes = before - ProtoFieldSize(containerSize)
+		} else {
+			bc.bytes = before
+		}
+	}
+}
+
+// ProtoFieldSize returns the size in bytes of an embedded message field with the given message size,
+// i.e. the size of the message, its length prefix and a single byte field tag.
+func ProtoFieldSize(size int) int {
+	if size < 0 {
+		size = 0
+	}
+	return 1 + varintSize(uint64(size)) + size
+}
+
+// varintSize returns the size in bytes of the varint encoding of x.
+func varintSize(x uint64) int {
+	return (bits.Len64(x|1) + 6) / 7
+}
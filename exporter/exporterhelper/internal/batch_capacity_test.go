@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+)
+
+func TestBatchCapacity(t *testing.T) {
+	bc := NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: 10, MaxSizeBytes: 100})
+	assert.True(t, bc.BytesLimited())
+	assert.True(t, bc.Fits(10, 100))
+	assert.False(t, bc.Fits(11, 100))
+	assert.False(t, bc.Fits(10, 101))
+
+	bc.Consume(5, 60)
+	assert.True(t, bc.Fits(5, 40))
+	assert.False(t, bc.Fits(5, 41))
+
+	bc.Reset()
+	assert.True(t, bc.Fits(10, 100))
+}
+
+func TestBatchCapacityUnlimitedBytes(t *testing.T) {
+	bc := NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: 10})
+	assert.False(t, bc.BytesLimited())
+	assert.True(t, bc.Fits(10, 1<<30))
+	assert.Equal(t, 0, bc.FieldSize(func() int {
+		assert.Fail(t, "size must not be computed")
+		return 0
+	}))
+	bc.ReserveContainer(func() int {
+		assert.Fail(t, "size must not be computed")
+		return 0
+	}, 1)()
+}
+
+func TestBatchCapacityReserveContainer(t *testing.T) {
+	bc := NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeBytes: 200})
+	containerSize := 10
+	complete := bc.ReserveContainer(func() int { return containerSize }, 1)
+	// 10 bytes of the container fields, 1 byte for the tag and 2 bytes for the length prefix.
+	assert.True(t, bc.Fits(0, 187))
+	assert.False(t, bc.Fits(0, 188))
+
+	bc.Consume(0, 150)
+	containerSize += 150
+	complete()
+	assert.True(t, bc.Fits(0, 200-ProtoFieldSize(160)))
+	assert.False(t, bc.Fits(0, 200-ProtoFieldSize(160)+1))
+}
+
+func TestProtoFieldSize(t *testing.T) {
+	assert.Equal(t, 2, ProtoFieldSize(0))
+	assert.Equal(t, 129, ProtoFieldSize(127))
+	assert.Equal(t, 131, ProtoFieldSize(128))
+	assert.Equal(t, 1<<14+4, ProtoFieldSize(1<<14))
+}
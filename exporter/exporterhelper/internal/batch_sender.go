@@ -19,7 +19,7 @@ import (
 
 // BatchSender is a component that places requests into batches before passing them to the downstream senders.
 // Batches are sent out with any of the following conditions:
-// - batch size reaches cfg.MinSizeItems
+// - batch size reaches cfg.MinSizeItems or cfg.MinSizeBytes
 // - cfg.FlushTimeout is elapsed since the timestamp when the previous batch was sent out.
 // - concurrencyLimit is reached.
 type BatchSender struct {
@@ -108,6 +108,9 @@ type batch struct {
 	done    chan struct{}
 	err     error
 
+	// bytesSize is the size of the request in bytes, only tracked if cfg.MinSizeBytes is configured.
+	bytesSize int
+
 	// requestsBlocked is the number of requests blocked in this batch
 	// that can be immediately released from activeRequests when batch sending completes.
 	requestsBlocked int64
@@ -136,24 +139,49 @@ func (bs *BatchSender) exportActiveBatch() {
 // The batch is ready if it has reached the minimum size or the concurrency limit is reached.
 // Caller must hold the lock.
 func (bs *BatchSender) isActiveBatchReady() bool {
-	return bs.activeBatch.request.ItemsCount() >= bs.cfg.MinSizeItems ||
+	return bs.isActiveBatchMinSizeReached() ||
 		(bs.concurrencyLimit > 0 && bs.activeRequests.Load() >= bs.concurrencyLimit)
 }
 
+// isActiveBatchMinSizeReached returns true if the active batch has reached any of the configured minimum sizes.
+// Caller must hold the lock.
+func (bs *BatchSender) isActiveBatchMinSizeReached() bool {
+	if _, ok := bs.activeBatch.request.(internal.RequestBytesSizer); ok && bs.cfg.MinSizeBytes > 0 {
+		if bs.activeBatch.bytesSize >= bs.cfg.MinSizeBytes {
+			return true
+		}
+		// Only the bytes threshold is configured.
+		if bs.cfg.MinSizeItems == 0 {
+			return false
+		}
+	}
+	return bs.activeBatch.request.ItemsCount() >= bs.cfg.MinSizeItems
+}
+
+// requestBytesSize returns the size of the request in bytes if the bytes threshold is configured.
+// It's computed before taking the lock, the size of the merged request is the sum of the sizes of its parts.
+func (bs *BatchSender) requestBytesSize(req internal.Request) int {
+	if bytesSizer, ok := req.(internal.RequestBytesSizer); ok && bs.cfg.MinSizeBytes > 0 {
+		return bytesSizer.BytesSize()
+	}
+	return 0
+}
+
 func (bs *BatchSender) Send(ctx context.Context, req internal.Request) error {
 	// Stopped batch sender should act as pass-through to allow the queue to be drained.
 	if bs.stopped.Load() {
 		return bs.NextSender.Send(ctx, req)
 	}
 
-	if bs.cfg.MaxSizeItems > 0 {
-		return bs.sendMergeSplitBatch(ctx, req)
+	bytesSize := bs.requestBytesSize(req)
+	if bs.cfg.MaxSizeItems > 0 || bs.cfg.MaxSizeBytes > 0 {
+		return bs.sendMergeSplitBatch(ctx, req, bytesSize)
 	}
-	return bs.sendMergeBatch(ctx, req)
+	return bs.sendMergeBatch(ctx, req, bytesSize)
 }
 
 // sendMergeSplitBatch sends the request to the batch which may be split into multiple requests.
-func (bs *BatchSender) sendMergeSplitBatch(ctx context.Context, req internal.Request) error {
+func (bs *BatchSender) sendMergeSplitBatch(ctx context.Context, req internal.Request, bytesSize int) error {
 	bs.mu.Lock()
 
 	reqs, err := bs.mergeSplitFunc(ctx, bs.cfg.MaxSizeConfig, bs.activeBatch.request, req)
@@ -170,9 +198,11 @@ func (bs *BatchSender) sendMergeSplitBatch(ctx context.Context, req internal.Req
 		defer bs.activeRequests.Add(-1)
 	}
 	if len(reqs) == 1 || bs.activeBatch.request != nil {
-		bs.updateActiveBatch(ctx, reqs[0])
+		// Without a split, the request is merged as a whole into the active batch. With a split, the batch is
+		// exported right away and its size is not needed.
+		bs.updateActiveBatch(ctx, reqs[0], bytesSize)
 		batch := bs.activeBatch
-		if bs.isActiveBatchReady() || len(reqs) > 1 {
+		if len(reqs) > 1 || bs.isActiveBatchReady() {
 			bs.exportActiveBatch()
 		}
 		bs.mu.Unlock()
@@ -196,7 +226,7 @@ func (bs *BatchSender) sendMergeSplitBatch(ctx context.Context, req internal.Req
 }
 
 // sendMergeBatch sends the request to the batch and waits for the batch to be exported.
-func (bs *BatchSender) sendMergeBatch(ctx context.Context, req internal.Request) error {
+func (bs *BatchSender) sendMergeBatch(ctx context.Context, req internal.Request, bytesSize int) error {
 	bs.mu.Lock()
 
 	if bs.activeBatch.request != nil {
@@ -209,7 +239,7 @@ func (bs *BatchSender) sendMergeBatch(ctx context.Context, req internal.Request)
 	}
 
 	bs.activeRequests.Add(1)
-	bs.updateActiveBatch(ctx, req)
+	bs.updateActiveBatch(ctx, req, bytesSize)
 	batch := bs.activeBatch
 	batch.requestsBlocked++
 	if bs.isActiveBatchReady() {
@@ -224,11 +254,12 @@ func (bs *BatchSender) sendMergeBatch(ctx context.Context, req internal.Request)
 // The context is only set once and is not updated after the first call.
 // Merging the context would be complex and require an additional goroutine to handle the context cancellation.
 // We take the approach of using the context from the first request since it's likely to have the shortest timeout.
-func (bs *BatchSender) updateActiveBatch(ctx context.Context, req internal.Request) {
+func (bs *BatchSender) updateActiveBatch(ctx context.Context, req internal.Request, bytesSize int) {
 	if bs.activeBatch.request == nil {
 		bs.activeBatch.ctx = ctx
 	}
 	bs.activeBatch.request = req
+	bs.activeBatch.bytesSize += bytesSize
 }
 
 func (bs *BatchSender) Shutdown(context.Context) error {
@@ -83,6 +83,32 @@ func TestBatchSender_Merge(t *testing.T) {
 	}
 }
 
+func TestBatchSender_MergeMinSizeBytes(t *testing.T) {
+	cfg := exporterbatcher.NewDefaultConfig()
+	cfg.MinSizeItems = 0
+	cfg.MinSizeBytes = 100
+	cfg.FlushTimeout = time.Hour
+	be := queueBatchExporter(t, WithBatcher(cfg), WithBatchFuncs(fakeBatchMergeFunc, fakeBatchMergeSplitFunc))
+
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	sink := newFakeRequestSink()
+
+	// 80 bytes, below the minimum size.
+	require.NoError(t, be.Send(context.Background(), &fakeRequest{items: 8, sink: sink}))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(0), sink.requestsCount.Load())
+
+	// 110 bytes, the batch should be sent by reaching the minimum bytes size.
+	require.NoError(t, be.Send(context.Background(), &fakeRequest{items: 3, sink: sink}))
+	assert.Eventually(t, func() bool {
+		return sink.requestsCount.Load() == 1 && sink.itemsCount.Load() == 11
+	}, 100*time.Millisecond, 10*time.Millisecond)
+}
+
 func TestBatchSender_BatchExportError(t *testing.T) {
 	cfg := exporterbatcher.NewDefaultConfig()
 	cfg.MinSizeItems = 10
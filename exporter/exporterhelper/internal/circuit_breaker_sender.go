@@ -0,0 +1,226 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/internal"
+)
+
+var errCircuitOpen = errors.New("circuit breaker is open, the request is not sent to the backend")
+
+// CircuitBreakerConfig defines when the exporter stops sending requests to a backend that is considered unavailable.
+type CircuitBreakerConfig struct {
+	// Enabled indicates whether to stop sending requests when most of them fail.
+	Enabled bool `mapstructure:"enabled"`
+	// FailureRatio is the ratio of failed attempts, out of the attempts made during the Interval, opening the circuit.
+	FailureRatio float64 `mapstructure:"failure_ratio"`
+	// MinRequests is the minimum number of attempts made during the Interval before the circuit can be opened.
+	MinRequests int `mapstructure:"min_requests"`
+	// Interval is the period after which the counts of attempts and failures are reset while the circuit is closed.
+	Interval time.Duration `mapstructure:"interval"`
+	// CoolDown is how long the circuit stays open before probing the backend again.
+	CoolDown time.Duration `mapstructure:"cool_down"`
+	// HalfOpenRequests is the number of probes that must succeed to close the circuit again.
+	HalfOpenRequests int `mapstructure:"half_open_requests"`
+}
+
+// NewDefaultCircuitBreakerConfig returns the default config for CircuitBreakerConfig.
+func NewDefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		Enabled:          false,
+		FailureRatio:     0.5,
+		MinRequests:      10,
+		Interval:         time.Minute,
+		CoolDown:         30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// Validate checks if the CircuitBreakerConfig configuration is valid.
+func (cfg *CircuitBreakerConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.FailureRatio <= 0 || cfg.FailureRatio > 1 {
+		return errors.New("'failure_ratio' must be greater than 0 and less than or equal to 1")
+	}
+	if cfg.MinRequests <= 0 {
+		return errors.New("'min_requests' must be positive")
+	}
+	if cfg.Interval <= 0 {
+		return errors.New("'interval' must be positive")
+	}
+	if cfg.CoolDown <= 0 {
+		return errors.New("'cool_down' must be positive")
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		return errors.New("'half_open_requests' must be positive")
+	}
+	return nil
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreakerSender is a RequestSender rejecting the requests without sending them while the backend is
+// considered unavailable. The circuit opens when the ratio of failed attempts reaches the threshold, rejects
+// the requests during the cool-down, then lets a few probes through: it closes again if they all succeed,
+// and opens again otherwise. The rejected requests are retried by the retry sender after the cool-down.
+type circuitBreakerSender struct {
+	BaseRequestSender
+	cfg    CircuitBreakerConfig
+	logger *zap.Logger
+	host   component.Host
+	now    func() time.Time
+
+	mu          sync.Mutex
+	state       circuitState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	succeeded   int
+}
+
+func newCircuitBreakerSender(cfg CircuitBreakerConfig, set exporter.Settings) *circuitBreakerSender {
+	return &circuitBreakerSender{
+		cfg:         cfg,
+		logger:      set.Logger,
+		now:         time.Now,
+		windowStart: time.Now(),
+	}
+}
+
+func (cs *circuitBreakerSender) Start(_ context.Context, host component.Host) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.host = host
+	return nil
+}
+
+func (cs *circuitBreakerSender) Send(ctx context.Context, req internal.Request) error {
+	probe, err := cs.allow()
+	if err != nil {
+		return err
+	}
+	err = cs.NextSender.Send(ctx, req)
+	// Cancelled requests are not counted, the cancellation comes from the collector, not the backend.
+	if errors.Is(err, context.Canceled) {
+		cs.onCancel(probe)
+		return err
+	}
+	cs.onResult(probe, isBackendFailure(err))
+	return err
+}
+
+// allow returns an error if the request must not be sent, and whether the request is a probe of a half-open circuit.
+func (cs *circuitBreakerSender) allow() (bool, error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	now := cs.now()
+	switch cs.state {
+	case circuitClosed:
+		if now.Sub(cs.windowStart) >= cs.cfg.Interval {
+			cs.resetCounts(now)
+		}
+		return false, nil
+	case circuitOpen:
+		if remaining := cs.cfg.CoolDown - now.Sub(cs.openedAt); remaining > 0 {
+			return false, NewThrottleRetry(errCircuitOpen, remaining)
+		}
+		cs.state = circuitHalfOpen
+		cs.probes = 0
+		cs.succeeded = 0
+		cs.logger.Info("Circuit breaker is half-open, probing the backend.")
+	}
+	if cs.probes >= cs.cfg.HalfOpenRequests {
+		return false, NewThrottleRetry(errCircuitOpen, cs.cfg.CoolDown)
+	}
+	cs.probes++
+	return true, nil
+}
+
+func (cs *circuitBreakerSender) onResult(probe bool, failed bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	now := cs.now()
+	if probe {
+		if cs.state != circuitHalfOpen {
+			return
+		}
+		if failed {
+			cs.trip(now)
+			return
+		}
+		cs.succeeded++
+		if cs.succeeded >= cs.cfg.HalfOpenRequests {
+			cs.state = circuitClosed
+			cs.resetCounts(now)
+			cs.logger.Info("Circuit breaker is closed, the backend is available again.")
+			componentstatus.ReportStatus(cs.host, componentstatus.NewEvent(componentstatus.StatusOK))
+		}
+		return
+	}
+
+	// Ignore the requests started before the circuit was opened.
+	if cs.state != circuitClosed {
+		return
+	}
+	cs.requests++
+	if failed {
+		cs.failures++
+	}
+	if cs.requests >= cs.cfg.MinRequests && float64(cs.failures)/float64(cs.requests) >= cs.cfg.FailureRatio {
+		cs.trip(now)
+	}
+}
+
+// onCancel releases the probe slot of a cancelled request. The request did not get a response from the backend, so it
+// is neither a success nor a failure.
+func (cs *circuitBreakerSender) onCancel(probe bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if probe && cs.state == circuitHalfOpen && cs.probes > 0 {
+		cs.probes--
+	}
+}
+
+func (cs *circuitBreakerSender) trip(now time.Time) {
+	cs.state = circuitOpen
+	cs.openedAt = now
+	err := fmt.Errorf("circuit breaker is open for %s, too many requests failed", cs.cfg.CoolDown)
+	cs.logger.Warn("Circuit breaker is open, the requests are not sent to the backend.",
+		zap.Duration("cool_down", cs.cfg.CoolDown))
+	componentstatus.ReportStatus(cs.host, componentstatus.NewRecoverableErrorEvent(err))
+}
+
+func (cs *circuitBreakerSender) resetCounts(now time.Time) {
+	cs.windowStart = now
+	cs.requests = 0
+	cs.failures = 0
+}
+
+// isBackendFailure returns true if the error shows that the backend may be unavailable. Permanent errors are
+// caused by the data, so they are not counted as failures.
+func isBackendFailure(err error) bool {
+	return err != nil && !consumererror.IsPermanent(err)
+}
@@ -0,0 +1,218 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/internal"
+)
+
+func TestCircuitBreakerConfig_Validate(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerConfig()
+	require.NoError(t, cfg.Validate())
+	cfg.Enabled = true
+	require.NoError(t, cfg.Validate())
+
+	tests := []struct {
+		name    string
+		modify  func(*CircuitBreakerConfig)
+		wantErr string
+	}{
+		{
+			name:    "failure_ratio",
+			modify:  func(cfg *CircuitBreakerConfig) { cfg.FailureRatio = 1.5 },
+			wantErr: "'failure_ratio' must be greater than 0 and less than or equal to 1",
+		},
+		{
+			name:    "min_requests",
+			modify:  func(cfg *CircuitBreakerConfig) { cfg.MinRequests = 0 },
+			wantErr: "'min_requests' must be positive",
+		},
+		{
+			name:    "interval",
+			modify:  func(cfg *CircuitBreakerConfig) { cfg.Interval = 0 },
+			wantErr: "'interval' must be positive",
+		},
+		{
+			name:    "cool_down",
+			modify:  func(cfg *CircuitBreakerConfig) { cfg.CoolDown = -time.Second },
+			wantErr: "'cool_down' must be positive",
+		},
+		{
+			name:    "half_open_requests",
+			modify:  func(cfg *CircuitBreakerConfig) { cfg.HalfOpenRequests = 0 },
+			wantErr: "'half_open_requests' must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewDefaultCircuitBreakerConfig()
+			cfg.Enabled = true
+			tt.modify(&cfg)
+			require.EqualError(t, cfg.Validate(), tt.wantErr)
+
+			// Confirm Validate doesn't return error with invalid config when feature is disabled
+			cfg.Enabled = false
+			assert.NoError(t, cfg.Validate())
+		})
+	}
+}
+
+func TestCircuitBreakerSender(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerConfig()
+	cfg.Enabled = true
+	cfg.MinRequests = 3
+	cfg.HalfOpenRequests = 2
+	cs, next, host, clock := newTestCircuitBreakerSender(t, cfg)
+
+	// Permanent errors are not failures of the backend, cancelled requests are not counted.
+	next.err = consumererror.NewPermanent(errors.New("bad data"))
+	require.Error(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	next.err = context.Canceled
+	require.Error(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	next.err = errors.New("unavailable")
+	require.Error(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	assert.Empty(t, host.events)
+
+	// The circuit opens once half of the requests failed.
+	require.Error(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	require.Len(t, host.events, 1)
+	assert.Equal(t, componentstatus.StatusRecoverableError, host.events[0].Status())
+	assert.Equal(t, 4, next.sent)
+
+	// The requests are rejected during the cool-down, and retried after it.
+	clock.now = clock.now.Add(10 * time.Second)
+	err := cs.Send(context.Background(), newMockRequest(1, nil))
+	require.ErrorIs(t, err, errCircuitOpen)
+	assert.Equal(t, NewThrottleRetry(errCircuitOpen, 20*time.Second), err)
+	assert.Equal(t, 4, next.sent)
+
+	// A failed probe opens the circuit again.
+	clock.now = clock.now.Add(20 * time.Second)
+	require.EqualError(t, cs.Send(context.Background(), newMockRequest(1, nil)), "unavailable")
+	require.ErrorIs(t, cs.Send(context.Background(), newMockRequest(1, nil)), errCircuitOpen)
+	require.Len(t, host.events, 2)
+	assert.Equal(t, 5, next.sent)
+
+	// The circuit closes once all the probes succeed.
+	clock.now = clock.now.Add(30 * time.Second)
+	next.err = nil
+	require.NoError(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitHalfOpen, cs.state)
+	require.NoError(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitClosed, cs.state)
+	require.Len(t, host.events, 3)
+	assert.Equal(t, componentstatus.StatusOK, host.events[2].Status())
+	assert.Equal(t, 7, next.sent)
+}
+
+func TestCircuitBreakerSender_Interval(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerConfig()
+	cfg.Enabled = true
+	cfg.MinRequests = 2
+	cs, next, host, clock := newTestCircuitBreakerSender(t, cfg)
+
+	next.err = errors.New("unavailable")
+	require.Error(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	// The counts are reset after the interval.
+	clock.now = clock.now.Add(time.Minute)
+	require.Error(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	assert.Empty(t, host.events)
+	assert.Equal(t, circuitClosed, cs.state)
+}
+
+func TestCircuitBreakerSender_ProbesLimit(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerConfig()
+	cfg.Enabled = true
+	cfg.MinRequests = 1
+	cs, next, _, clock := newTestCircuitBreakerSender(t, cfg)
+
+	next.err = errors.New("unavailable")
+	require.Error(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	clock.now = clock.now.Add(cfg.CoolDown)
+
+	// Only one probe is sent at a time.
+	probe, err := cs.allow()
+	require.NoError(t, err)
+	assert.True(t, probe)
+	_, err = cs.allow()
+	require.ErrorIs(t, err, errCircuitOpen)
+
+	// A cancelled probe releases its slot without closing the circuit.
+	cs.onCancel(probe)
+	assert.Equal(t, circuitHalfOpen, cs.state)
+	next.err = context.Canceled
+	require.ErrorIs(t, cs.Send(context.Background(), newMockRequest(1, nil)), context.Canceled)
+	assert.Equal(t, circuitHalfOpen, cs.state)
+	next.err = nil
+	require.NoError(t, cs.Send(context.Background(), newMockRequest(1, nil)))
+	assert.Equal(t, circuitClosed, cs.state)
+}
+
+func TestCircuitBreakerSender_BaseExporter(t *testing.T) {
+	cfg := NewDefaultCircuitBreakerConfig()
+	cfg.Enabled = true
+	cfg.MinRequests = 1
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender, WithCircuitBreaker(cfg))
+	require.NoError(t, err)
+	require.IsType(t, &circuitBreakerSender{}, be.CircuitBreakerSender)
+	host := &statusHost{Host: componenttest.NewNopHost()}
+	require.NoError(t, be.Start(context.Background(), host))
+
+	// The first failure opens the circuit, the next requests are rejected without being sent.
+	require.EqualError(t, be.Send(context.Background(), newErrorRequest()), "transient error")
+	mockR := newMockRequest(1, nil)
+	require.ErrorIs(t, be.Send(context.Background(), mockR), errCircuitOpen)
+	mockR.checkNumRequests(t, 0)
+	require.Len(t, host.events, 1)
+	assert.Equal(t, componentstatus.StatusRecoverableError, host.events[0].Status())
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+type testClock struct {
+	now time.Time
+}
+
+type errSender struct {
+	BaseRequestSender
+	err  error
+	sent int
+}
+
+func (s *errSender) Send(context.Context, internal.Request) error {
+	s.sent++
+	return s.err
+}
+
+type statusHost struct {
+	component.Host
+	events []*componentstatus.Event
+}
+
+func (h *statusHost) Report(ev *componentstatus.Event) {
+	h.events = append(h.events, ev)
+}
+
+func newTestCircuitBreakerSender(t *testing.T, cfg CircuitBreakerConfig) (*circuitBreakerSender, *errSender, *statusHost, *testClock) {
+	clock := &testClock{now: time.Now()}
+	cs := newCircuitBreakerSender(cfg, defaultSettings)
+	cs.now = func() time.Time { return clock.now }
+	cs.windowStart = clock.now
+	next := &errSender{}
+	cs.SetNextSender(next)
+	host := &statusHost{Host: componenttest.NewNopHost()}
+	require.NoError(t, cs.Start(context.Background(), host))
+	return cs, next, host, clock
+}
@@ -0,0 +1,283 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/exporter/internal"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+const (
+	// DeadLetterExporterAttribute is the resource attribute with the ID of the exporter that failed to send the data.
+	DeadLetterExporterAttribute = "otelcol.dead_letter.exporter"
+	// DeadLetterReasonAttribute is the resource attribute with the error that caused the data to be dead-lettered.
+	DeadLetterReasonAttribute = "otelcol.dead_letter.reason"
+	// DeadLetterAttemptsAttribute is the resource attribute with the number of attempts made to send the data.
+	DeadLetterAttemptsAttribute = "otelcol.dead_letter.attempts"
+
+	// deadLetterNextIndexKey is the storage key of the index of the next dead-letter record.
+	deadLetterNextIndexKey = "ni"
+)
+
+var errDeadLetterExportersNotAvailable = errors.New("the host does not provide access to the exporters")
+
+// DeadLetterConfig defines where the requests that failed permanently, or for which the retries were exhausted,
+// are sent instead of being dropped.
+type DeadLetterConfig struct {
+	// Exporter if not empty, sends the failed data to the exporter with the given ID. The exporter must be part
+	// of a pipeline of the same signal. The reason of the failure and the number of attempts are added as resource
+	// attributes to the data.
+	Exporter *component.ID `mapstructure:"exporter"`
+	// Storage if not empty, persists the failed requests, along with the reason of the failure and the number
+	// of attempts, in the storage extension with the given ID.
+	Storage *component.ID `mapstructure:"storage"`
+}
+
+// NewDefaultDeadLetterConfig returns the default config for DeadLetterConfig.
+// By default, the failed requests are dropped.
+func NewDefaultDeadLetterConfig() DeadLetterConfig {
+	return DeadLetterConfig{}
+}
+
+// Validate checks if the DeadLetterConfig configuration is valid.
+func (cfg *DeadLetterConfig) Validate() error {
+	if cfg.Exporter != nil && cfg.Storage != nil {
+		return errors.New("only one of 'exporter' and 'storage' can be set")
+	}
+	return nil
+}
+
+// DeadLetterExporterID returns the ID of the dead-letter exporter, if any. The service looks it up in the
+// configuration of the exporters to restart an exporter when its dead-letter exporter is replaced.
+func (cfg DeadLetterConfig) DeadLetterExporterID() (component.ID, bool) {
+	if cfg.Exporter == nil {
+		return component.ID{}, false
+	}
+	return *cfg.Exporter, true
+}
+
+// DeadLetterInfo describes why a request was dead-lettered.
+type DeadLetterInfo struct {
+	// Exporter is the ID of the exporter that failed to send the request.
+	Exporter component.ID
+	// Reason is the error returned by the last attempt to send the request.
+	Reason error
+	// Attempts is the number of attempts made to send the request.
+	Attempts int64
+}
+
+// PutAttributes adds the dead-letter information to the given attributes.
+func (di DeadLetterInfo) PutAttributes(attrs pcommon.Map) {
+	attrs.PutStr(DeadLetterExporterAttribute, di.Exporter.String())
+	attrs.PutStr(DeadLetterReasonAttribute, di.Reason.Error())
+	attrs.PutInt(DeadLetterAttemptsAttribute, di.Attempts)
+}
+
+// DeadLetterExportFunc sends the data of a failed request to the dead-letter exporter.
+// The exporter is guaranteed to be part of a pipeline of the signal of the request.
+type DeadLetterExportFunc func(ctx context.Context, exporter component.Component, req internal.Request, info DeadLetterInfo) error
+
+// deadLetterRecord is the format of the requests persisted in the dead-letter storage.
+type deadLetterRecord struct {
+	Signal   string    `json:"signal"`
+	Exporter string    `json:"exporter"`
+	Reason   string    `json:"reason"`
+	Attempts int64     `json:"attempts"`
+	Time     time.Time `json:"time"`
+	// Request is the request encoded with the request marshaler of the exporter,
+	// e.g. OTLP protobuf for the OTLP based exporters.
+	Request []byte `json:"request"`
+}
+
+// deadLetterSender sends the requests that the next senders failed to send to the dead-letter exporter or storage.
+// It returns the original error in any case, so the requests are still reported as failed.
+type deadLetterSender struct {
+	BaseRequestSender
+	cfg        DeadLetterConfig
+	id         component.ID
+	signal     pipeline.Signal
+	logger     *zap.Logger
+	exportFunc DeadLetterExportFunc
+	marshaler  exporterqueue.Marshaler[internal.Request]
+
+	exporter component.Component
+
+	// mu guards the storage client and the next index.
+	mu        sync.Mutex
+	client    storage.Client
+	nextIndex uint64
+}
+
+func newDeadLetterSender(cfg DeadLetterConfig, set exporter.Settings, signal pipeline.Signal,
+	exportFunc DeadLetterExportFunc, marshaler exporterqueue.Marshaler[internal.Request]) *deadLetterSender {
+	return &deadLetterSender{
+		cfg:        cfg,
+		id:         set.ID,
+		signal:     signal,
+		logger:     set.Logger,
+		exportFunc: exportFunc,
+		marshaler:  marshaler,
+	}
+}
+
+// Start resolves the dead-letter exporter or storage client.
+func (ds *deadLetterSender) Start(ctx context.Context, host component.Host) error {
+	if ds.cfg.Exporter != nil {
+		return ds.startExporter(host)
+	}
+	return ds.startStorage(ctx, host)
+}
+
+func (ds *deadLetterSender) startExporter(host component.Host) error {
+	if *ds.cfg.Exporter == ds.id {
+		return errors.New("the dead-letter exporter cannot be the exporter itself")
+	}
+	h, ok := host.(interface {
+		GetExporter(signal pipeline.Signal, id component.ID) (component.Component, bool)
+	})
+	if !ok {
+		return errDeadLetterExportersNotAvailable
+	}
+	exp, found := h.GetExporter(ds.signal, *ds.cfg.Exporter)
+	if !found {
+		return fmt.Errorf("dead-letter exporter %q is not part of any %s pipeline", ds.cfg.Exporter, ds.signal)
+	}
+	ds.exporter = exp
+	return nil
+}
+
+func (ds *deadLetterSender) startStorage(ctx context.Context, host component.Host) error {
+	ext, found := host.GetExtensions()[*ds.cfg.Storage]
+	if !found {
+		return fmt.Errorf("dead-letter storage extension %q not found", ds.cfg.Storage)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("extension %q is not a storage extension", ds.cfg.Storage)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindExporter, ds.id, ds.signal.String()+"_dead_letter")
+	if err != nil {
+		return err
+	}
+	buf, err := client.Get(ctx, deadLetterNextIndexKey)
+	if err != nil {
+		return multierr.Append(err, client.Close(ctx))
+	}
+	if len(buf) >= 8 {
+		ds.nextIndex = binary.LittleEndian.Uint64(buf)
+	}
+	ds.client = client
+	return nil
+}
+
+// Shutdown closes the dead-letter storage client.
+func (ds *deadLetterSender) Shutdown(ctx context.Context) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if ds.client == nil {
+		return nil
+	}
+	err := ds.client.Close(ctx)
+	ds.client = nil
+	return err
+}
+
+func (ds *deadLetterSender) Send(ctx context.Context, req internal.Request) error {
+	attempts := new(atomic.Int64)
+	err := ds.NextSender.Send(contextWithAttemptsCounter(ctx, attempts), req)
+	// The request is sent again after a restart if the collector is shutting down.
+	if err == nil || experr.IsShutdownErr(err) {
+		return err
+	}
+	// The destination accepted the request except for the rejected items, which cannot be told apart.
+	if _, ok := consumererror.RejectedItems(err); ok {
+		return err
+	}
+
+	info := DeadLetterInfo{Exporter: ds.id, Reason: err, Attempts: attempts.Load()}
+	// The request context may already be cancelled, the dead-letter target must not be affected.
+	dlCtx := context.WithoutCancel(ctx)
+	var dlErr error
+	if ds.exporter != nil {
+		dlErr = ds.exportFunc(dlCtx, ds.exporter, req, info)
+	} else {
+		dlErr = ds.store(dlCtx, req, info)
+	}
+	if dlErr != nil {
+		ds.logger.Error("Failed to send the failed request to the dead-letter target. Dropping data.",
+			zap.Error(dlErr), zap.Int("dropped_items", req.ItemsCount()))
+	} else {
+		ds.logger.Warn("Exporting failed. Sent the request to the dead-letter target.",
+			zap.Error(err), zap.Int64("attempts", info.Attempts), zap.Int("items", req.ItemsCount()))
+	}
+	return err
+}
+
+// store persists the request in the dead-letter storage under an increasing index.
+func (ds *deadLetterSender) store(ctx context.Context, req internal.Request, info DeadLetterInfo) error {
+	reqBuf, err := ds.marshaler(req)
+	if err != nil {
+		return err
+	}
+	buf, err := json.Marshal(deadLetterRecord{
+		Signal:   ds.signal.String(),
+		Exporter: info.Exporter.String(),
+		Reason:   info.Reason.Error(),
+		Attempts: info.Attempts,
+		Time:     time.Now().UTC(),
+		Request:  reqBuf,
+	})
+	if err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if ds.client == nil {
+		return errors.New("dead-letter storage is closed")
+	}
+	index := ds.nextIndex
+	err = ds.client.Batch(ctx,
+		storage.SetOperation(strconv.FormatUint(index, 10), buf),
+		storage.SetOperation(deadLetterNextIndexKey, binary.LittleEndian.AppendUint64(nil, index+1)))
+	if err != nil {
+		return err
+	}
+	ds.nextIndex = index + 1
+	return nil
+}
+
+type attemptsCounterKey struct{}
+
+// contextWithAttemptsCounter returns a context that carries a counter of the attempts made to send a request.
+func contextWithAttemptsCounter(ctx context.Context, counter *atomic.Int64) context.Context {
+	return context.WithValue(ctx, attemptsCounterKey{}, counter)
+}
+
+// countAttempt increments the attempts counter carried by the context if any.
+func countAttempt(ctx context.Context) {
+	if counter, ok := ctx.Value(attemptsCounterKey{}).(*atomic.Int64); ok {
+		counter.Add(1)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/internal"
+	"go.opentelemetry.io/collector/exporter/internal/experr"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+type mockExportersHost struct {
+	component.Host
+	exporters map[pipeline.Signal]map[component.ID]component.Component
+}
+
+func (h *mockExportersHost) GetExporter(signal pipeline.Signal, id component.ID) (component.Component, bool) {
+	exp, ok := h.exporters[signal][id]
+	return exp, ok
+}
+
+type deadLetterCall struct {
+	exporter component.Component
+	req      internal.Request
+	info     DeadLetterInfo
+}
+
+func TestDeadLetterConfig_Validate(t *testing.T) {
+	cfg := NewDefaultDeadLetterConfig()
+	require.NoError(t, cfg.Validate())
+
+	id := component.MustNewID("foo")
+	cfg.Exporter = &id
+	require.NoError(t, cfg.Validate())
+
+	cfg.Storage = &id
+	require.EqualError(t, cfg.Validate(), "only one of 'exporter' and 'storage' can be set")
+
+	cfg.Exporter = nil
+	require.NoError(t, cfg.Validate())
+}
+
+func TestDeadLetterInfo_PutAttributes(t *testing.T) {
+	attrs := pcommon.NewMap()
+	DeadLetterInfo{Exporter: defaultID, Reason: errors.New("bad data"), Attempts: 3}.PutAttributes(attrs)
+	assert.Equal(t, map[string]any{
+		DeadLetterExporterAttribute: defaultID.String(),
+		DeadLetterReasonAttribute:   "bad data",
+		DeadLetterAttemptsAttribute: int64(3),
+	}, attrs.AsRaw())
+}
+
+func TestDeadLetterSender_Exporter(t *testing.T) {
+	dlID := component.MustNewID("dead_letter")
+	var calls []deadLetterCall
+	exportFunc := func(_ context.Context, exp component.Component, req internal.Request, info DeadLetterInfo) error {
+		calls = append(calls, deadLetterCall{exporter: exp, req: req, info: info})
+		return nil
+	}
+	dlExp := &mockDeadLetterExporter{}
+
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = time.Millisecond
+	rCfg.MaxElapsedTime = 50 * time.Millisecond
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithRetry(rCfg),
+		WithDeadLetterExportFunc(exportFunc),
+		WithDeadLetter(DeadLetterConfig{Exporter: &dlID}))
+	require.NoError(t, err)
+	host := &mockExportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[pipeline.Signal]map[component.ID]component.Component{defaultSignal: {dlID: dlExp}},
+	}
+	require.NoError(t, be.Start(context.Background(), host))
+
+	// A permanent error is dead-lettered after a single attempt.
+	permanentReq := newMockRequest(2, consumererror.NewPermanent(errors.New("bad data")))
+	require.Error(t, be.Send(context.Background(), permanentReq))
+	require.Len(t, calls, 1)
+	assert.Same(t, dlExp, calls[0].exporter)
+	assert.Same(t, permanentReq, calls[0].req)
+	assert.Equal(t, defaultID, calls[0].info.Exporter)
+	assert.EqualError(t, calls[0].info.Reason, "not retryable error: Permanent error: bad data")
+	assert.Equal(t, int64(1), calls[0].info.Attempts)
+
+	// A transient error is dead-lettered once the retries are exhausted.
+	transientReq := &mockErrorRequest{}
+	require.Error(t, be.Send(context.Background(), transientReq))
+	require.Len(t, calls, 2)
+	assert.Same(t, transientReq, calls[1].req)
+	assert.Equal(t, int64(transientReq.getNumRequests()), calls[1].info.Attempts)
+	assert.Greater(t, calls[1].info.Attempts, int64(1))
+
+	// Successful requests are not dead-lettered.
+	require.NoError(t, be.Send(context.Background(), newMockRequest(2, nil)))
+	require.Len(t, calls, 2)
+
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestDeadLetterSender_ShutdownError(t *testing.T) {
+	dlID := component.MustNewID("dead_letter")
+	called := false
+	exportFunc := func(context.Context, component.Component, internal.Request, DeadLetterInfo) error {
+		called = true
+		return nil
+	}
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithDeadLetterExportFunc(exportFunc),
+		WithDeadLetter(DeadLetterConfig{Exporter: &dlID}))
+	require.NoError(t, err)
+	host := &mockExportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[pipeline.Signal]map[component.ID]component.Component{defaultSignal: {dlID: &mockDeadLetterExporter{}}},
+	}
+	require.NoError(t, be.Start(context.Background(), host))
+	// Requests failing because of the shutdown are sent again after the restart, they are not dead-lettered.
+	require.Error(t, be.Send(context.Background(), newMockRequest(2, experr.NewShutdownErr(errors.New("shutting down")))))
+	assert.False(t, called)
+	// Requests partially accepted by the destination are not dead-lettered either.
+	partialErr := consumererror.NewPermanent(consumererror.NewPartialSuccess(errors.New("invalid data"), 1))
+	require.Error(t, be.Send(context.Background(), newMockRequest(2, partialErr)))
+	assert.False(t, called)
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestDeadLetterSender_ExporterFailure(t *testing.T) {
+	dlID := component.MustNewID("dead_letter")
+	exportFunc := func(context.Context, component.Component, internal.Request, DeadLetterInfo) error {
+		return errors.New("dead-letter failure")
+	}
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithDeadLetterExportFunc(exportFunc),
+		WithDeadLetter(DeadLetterConfig{Exporter: &dlID}))
+	require.NoError(t, err)
+	host := &mockExportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[pipeline.Signal]map[component.ID]component.Component{defaultSignal: {dlID: &mockDeadLetterExporter{}}},
+	}
+	require.NoError(t, be.Start(context.Background(), host))
+	// The original error is returned.
+	require.EqualError(t, be.Send(context.Background(), newMockRequest(2, consumererror.NewPermanent(errors.New("bad data")))),
+		"Permanent error: bad data")
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func TestDeadLetterSender_ExporterStartErrors(t *testing.T) {
+	dlID := component.MustNewID("dead_letter")
+	exportFunc := func(context.Context, component.Component, internal.Request, DeadLetterInfo) error { return nil }
+	tests := []struct {
+		name    string
+		id      component.ID
+		host    component.Host
+		wantErr string
+	}{
+		{
+			name:    "no_exporters",
+			id:      dlID,
+			host:    componenttest.NewNopHost(),
+			wantErr: "the host does not provide access to the exporters",
+		},
+		{
+			name: "not_found",
+			id:   dlID,
+			host: &mockExportersHost{
+				Host:      componenttest.NewNopHost(),
+				exporters: map[pipeline.Signal]map[component.ID]component.Component{pipeline.SignalLogs: {dlID: &mockDeadLetterExporter{}}},
+			},
+			wantErr: `dead-letter exporter "dead_letter" is not part of any metrics pipeline`,
+		},
+		{
+			name: "itself",
+			id:   defaultID,
+			host: &mockExportersHost{
+				Host:      componenttest.NewNopHost(),
+				exporters: map[pipeline.Signal]map[component.ID]component.Component{defaultSignal: {defaultID: &mockDeadLetterExporter{}}},
+			},
+			wantErr: "the dead-letter exporter cannot be the exporter itself",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+				WithDeadLetterExportFunc(exportFunc),
+				WithDeadLetter(DeadLetterConfig{Exporter: &tt.id}))
+			require.NoError(t, err)
+			require.EqualError(t, be.Start(context.Background(), tt.host), tt.wantErr)
+			require.NoError(t, be.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestDeadLetterSender_Storage(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	ext := queue.NewMockStorageExtension(nil)
+	host := &MockHost{Ext: map[component.ID]component.Component{storageID: ext}}
+
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler),
+		WithUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithDeadLetter(DeadLetterConfig{Storage: &storageID}))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+
+	require.Error(t, be.Send(context.Background(), newMockRequest(2, consumererror.NewPermanent(errors.New("bad data")))))
+	require.Error(t, be.Send(context.Background(), newMockRequest(3, consumererror.NewPermanent(errors.New("bad data")))))
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	client, err := ext.GetClient(context.Background(), component.KindExporter, defaultID, "metrics_dead_letter")
+	require.NoError(t, err)
+	buf, err := client.Get(context.Background(), deadLetterNextIndexKey)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), binary.LittleEndian.Uint64(buf))
+	for _, key := range []string{"0", "1"} {
+		buf, err = client.Get(context.Background(), key)
+		require.NoError(t, err)
+		var record deadLetterRecord
+		require.NoError(t, json.Unmarshal(buf, &record))
+		assert.Equal(t, "metrics", record.Signal)
+		assert.Equal(t, defaultID.String(), record.Exporter)
+		assert.Equal(t, "Permanent error: bad data", record.Reason)
+		assert.Equal(t, int64(1), record.Attempts)
+		assert.Equal(t, []byte("mockRequest"), record.Request)
+	}
+	require.NoError(t, client.Close(context.Background()))
+}
+
+func TestDeadLetterSender_StorageRestoresIndex(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	ext := queue.NewMockStorageExtension(nil)
+	host := &MockHost{Ext: map[component.ID]component.Component{storageID: ext}}
+
+	client, err := ext.GetClient(context.Background(), component.KindExporter, defaultID, "metrics_dead_letter")
+	require.NoError(t, err)
+	require.NoError(t, client.Set(context.Background(), deadLetterNextIndexKey, binary.LittleEndian.AppendUint64(nil, 5)))
+	require.NoError(t, client.Close(context.Background()))
+
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler),
+		WithUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithDeadLetter(DeadLetterConfig{Storage: &storageID}))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), host))
+	require.Error(t, be.Send(context.Background(), newMockRequest(2, consumererror.NewPermanent(errors.New("bad data")))))
+	require.NoError(t, be.Shutdown(context.Background()))
+
+	client, err = ext.GetClient(context.Background(), component.KindExporter, defaultID, "metrics_dead_letter")
+	require.NoError(t, err)
+	buf, err := client.Get(context.Background(), "5")
+	require.NoError(t, err)
+	assert.NotNil(t, buf)
+	require.NoError(t, client.Close(context.Background()))
+}
+
+func TestDeadLetterSender_StorageStartErrors(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	tests := []struct {
+		name    string
+		ext     map[component.ID]component.Component
+		wantErr string
+	}{
+		{
+			name:    "not_found",
+			ext:     map[component.ID]component.Component{},
+			wantErr: `dead-letter storage extension "file_storage" not found`,
+		},
+		{
+			name:    "not_storage",
+			ext:     map[component.ID]component.Component{storageID: &mockDeadLetterExporter{}},
+			wantErr: `extension "file_storage" is not a storage extension`,
+		},
+		{
+			name:    "client_error",
+			ext:     map[component.ID]component.Component{storageID: queue.NewMockStorageExtension(errors.New("client error"))},
+			wantErr: "client error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+				WithMarshaler(mockRequestMarshaler),
+				WithUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+				WithDeadLetter(DeadLetterConfig{Storage: &storageID}))
+			require.NoError(t, err)
+			require.EqualError(t, be.Start(context.Background(), &MockHost{Ext: tt.ext}), tt.wantErr)
+			require.NoError(t, be.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestDeadLetterSender_NewRequestExporters(t *testing.T) {
+	id := component.MustNewID("foo")
+	_, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithDeadLetter(DeadLetterConfig{Exporter: &id}))
+	require.EqualError(t, err, "dead-letter exporter is not available for the new request exporters")
+
+	_, err = NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithDeadLetter(DeadLetterConfig{Storage: &id}))
+	require.EqualError(t, err, "dead-letter storage is not available for the new request exporters")
+}
+
+type mockDeadLetterExporter struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
@@ -34,13 +34,21 @@ type TelemetryBuilder struct {
 	ExporterEnqueueFailedMetricPoints metric.Int64Counter
 	ExporterEnqueueFailedSpans        metric.Int64Counter
 	ExporterQueueCapacity             metric.Int64ObservableGauge
+	ExporterQueueConcurrency          metric.Int64ObservableGauge
 	ExporterQueueSize                 metric.Int64ObservableGauge
+	ExporterRateLimitDelay            metric.Float64Counter
+	ExporterRateLimitedRequests       metric.Int64Counter
+	ExporterRejectedLogRecords        metric.Int64Counter
+	ExporterRejectedMetricPoints      metric.Int64Counter
+	ExporterRejectedSpans             metric.Int64Counter
 	ExporterSendFailedLogRecords      metric.Int64Counter
 	ExporterSendFailedMetricPoints    metric.Int64Counter
 	ExporterSendFailedSpans           metric.Int64Counter
 	ExporterSentLogRecords            metric.Int64Counter
 	ExporterSentMetricPoints          metric.Int64Counter
 	ExporterSentSpans                 metric.Int64Counter
+	ExporterShutdownDroppedRequests   metric.Int64Counter
+	ExporterShutdownPersistedRequests metric.Int64Counter
 	meters                            map[configtelemetry.Level]metric.Meter
 }
 
@@ -60,8 +68,8 @@ func (builder *TelemetryBuilder) InitExporterQueueCapacity(cb func() int64, opts
 	var err error
 	builder.ExporterQueueCapacity, err = builder.meters[configtelemetry.LevelBasic].Int64ObservableGauge(
 		"otelcol_exporter_queue_capacity",
-		metric.WithDescription("Fixed capacity of the retry queue (in batches)"),
-		metric.WithUnit("{batches}"),
+		metric.WithDescription("Fixed capacity of the retry queue (in batches, items or bytes, depending on the configured sizer)"),
+		metric.WithUnit("{units}"),
 	)
 	if err != nil {
 		return err
@@ -73,13 +81,31 @@ func (builder *TelemetryBuilder) InitExporterQueueCapacity(cb func() int64, opts
 	return err
 }
 
+// InitExporterQueueConcurrency configures the ExporterQueueConcurrency metric.
+func (builder *TelemetryBuilder) InitExporterQueueConcurrency(cb func() int64, opts ...metric.ObserveOption) error {
+	var err error
+	builder.ExporterQueueConcurrency, err = builder.meters[configtelemetry.LevelBasic].Int64ObservableGauge(
+		"otelcol_exporter_queue_concurrency",
+		metric.WithDescription("Current number of consumers allowed to send requests concurrently when the adaptive concurrency is enabled"),
+		metric.WithUnit("{consumers}"),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = builder.meters[configtelemetry.LevelBasic].RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(builder.ExporterQueueConcurrency, cb(), opts...)
+		return nil
+	}, builder.ExporterQueueConcurrency)
+	return err
+}
+
 // InitExporterQueueSize configures the ExporterQueueSize metric.
 func (builder *TelemetryBuilder) InitExporterQueueSize(cb func() int64, opts ...metric.ObserveOption) error {
 	var err error
 	builder.ExporterQueueSize, err = builder.meters[configtelemetry.LevelBasic].Int64ObservableGauge(
 		"otelcol_exporter_queue_size",
-		metric.WithDescription("Current size of the retry queue (in batches)"),
-		metric.WithUnit("{batches}"),
+		metric.WithDescription("Current size of the retry queue (in batches, items or bytes, depending on the configured sizer)"),
+		metric.WithUnit("{units}"),
 	)
 	if err != nil {
 		return err
@@ -118,6 +144,36 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("{spans}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterRateLimitDelay, err = builder.meters[configtelemetry.LevelBasic].Float64Counter(
+		"otelcol_exporter_rate_limit_delay",
+		metric.WithDescription("Time spent by the requests waiting for the rate limit of the exporter."),
+		metric.WithUnit("s"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterRateLimitedRequests, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_rate_limited_requests",
+		metric.WithDescription("Number of requests delayed by the rate limit of the exporter."),
+		metric.WithUnit("{requests}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterRejectedLogRecords, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_rejected_log_records",
+		metric.WithDescription("Number of log records rejected by the destination in partial success responses."),
+		metric.WithUnit("{records}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterRejectedMetricPoints, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_rejected_metric_points",
+		metric.WithDescription("Number of metric points rejected by the destination in partial success responses."),
+		metric.WithUnit("{datapoints}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterRejectedSpans, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_rejected_spans",
+		metric.WithDescription("Number of spans rejected by the destination in partial success responses."),
+		metric.WithUnit("{spans}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ExporterSendFailedLogRecords, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
 		"otelcol_exporter_send_failed_log_records",
 		metric.WithDescription("Number of log records in failed attempts to send to destination."),
@@ -154,5 +210,17 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("{spans}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ExporterShutdownDroppedRequests, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_shutdown_dropped_requests",
+		metric.WithDescription("Number of requests left in the queue at the shutdown deadline and dropped."),
+		metric.WithUnit("{requests}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ExporterShutdownPersistedRequests, err = builder.meters[configtelemetry.LevelBasic].Int64Counter(
+		"otelcol_exporter_shutdown_persisted_requests",
+		metric.WithDescription("Number of requests left in the queue at the shutdown deadline and persisted to be sent after the restart."),
+		metric.WithUnit("{requests}"),
+	)
+	errs = errors.Join(errs, err)
 	return &builder, errs
 }
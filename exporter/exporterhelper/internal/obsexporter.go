@@ -5,6 +5,7 @@ package internal // import "go.opentelemetry.io/collector/exporter/exporterhelpe
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -12,6 +13,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal/metadata"
 	"go.opentelemetry.io/collector/pipeline"
@@ -58,8 +60,8 @@ func (or *ObsReport) StartTracesOp(ctx context.Context) context.Context {
 
 // EndTracesOp completes the export operation that was started with startTracesOp.
 func (or *ObsReport) EndTracesOp(ctx context.Context, numSpans int, err error) {
-	numSent, numFailedToSend := toNumItems(numSpans, err)
-	or.recordMetrics(context.WithoutCancel(ctx), pipeline.SignalTraces, numSent, numFailedToSend)
+	numSent, numFailedToSend, numRejected := toNumItems(numSpans, err)
+	or.recordMetrics(context.WithoutCancel(ctx), pipeline.SignalTraces, numSent, numFailedToSend, numRejected)
 	endSpan(ctx, err, numSent, numFailedToSend, SentSpansKey, FailedToSendSpansKey)
 }
 
@@ -75,8 +77,8 @@ func (or *ObsReport) StartMetricsOp(ctx context.Context) context.Context {
 //
 // If needed, report your use case in https://github.com/open-telemetry/opentelemetry-collector/issues/10592.
 func (or *ObsReport) EndMetricsOp(ctx context.Context, numMetricPoints int, err error) {
-	numSent, numFailedToSend := toNumItems(numMetricPoints, err)
-	or.recordMetrics(context.WithoutCancel(ctx), pipeline.SignalMetrics, numSent, numFailedToSend)
+	numSent, numFailedToSend, numRejected := toNumItems(numMetricPoints, err)
+	or.recordMetrics(context.WithoutCancel(ctx), pipeline.SignalMetrics, numSent, numFailedToSend, numRejected)
 	endSpan(ctx, err, numSent, numFailedToSend, SentMetricPointsKey, FailedToSendMetricPointsKey)
 }
 
@@ -89,8 +91,8 @@ func (or *ObsReport) StartLogsOp(ctx context.Context) context.Context {
 
 // EndLogsOp completes the export operation that was started with startLogsOp.
 func (or *ObsReport) EndLogsOp(ctx context.Context, numLogRecords int, err error) {
-	numSent, numFailedToSend := toNumItems(numLogRecords, err)
-	or.recordMetrics(context.WithoutCancel(ctx), pipeline.SignalLogs, numSent, numFailedToSend)
+	numSent, numFailedToSend, numRejected := toNumItems(numLogRecords, err)
+	or.recordMetrics(context.WithoutCancel(ctx), pipeline.SignalLogs, numSent, numFailedToSend, numRejected)
 	endSpan(ctx, err, numSent, numFailedToSend, SentLogRecordsKey, FailedToSendLogRecordsKey)
 }
 
@@ -103,7 +105,7 @@ func (or *ObsReport) StartProfilesOp(ctx context.Context) context.Context {
 
 // EndProfilesOp completes the export operation that was started with startProfilesOp.
 func (or *ObsReport) EndProfilesOp(ctx context.Context, numSpans int, err error) {
-	numSent, numFailedToSend := toNumItems(numSpans, err)
+	numSent, numFailedToSend, _ := toNumItems(numSpans, err)
 	endSpan(ctx, err, numSent, numFailedToSend, SentSamplesKey, FailedToSendSamplesKey)
 }
 
@@ -115,22 +117,28 @@ func (or *ObsReport) startOp(ctx context.Context, operationSuffix string) contex
 	return ctx
 }
 
-func (or *ObsReport) recordMetrics(ctx context.Context, signal pipeline.Signal, sent, failed int64) {
-	var sentMeasure, failedMeasure metric.Int64Counter
+func (or *ObsReport) recordMetrics(ctx context.Context, signal pipeline.Signal, sent, failed, rejected int64) {
+	var sentMeasure, failedMeasure, rejectedMeasure metric.Int64Counter
 	switch signal {
 	case pipeline.SignalTraces:
 		sentMeasure = or.TelemetryBuilder.ExporterSentSpans
 		failedMeasure = or.TelemetryBuilder.ExporterSendFailedSpans
+		rejectedMeasure = or.TelemetryBuilder.ExporterRejectedSpans
 	case pipeline.SignalMetrics:
 		sentMeasure = or.TelemetryBuilder.ExporterSentMetricPoints
 		failedMeasure = or.TelemetryBuilder.ExporterSendFailedMetricPoints
+		rejectedMeasure = or.TelemetryBuilder.ExporterRejectedMetricPoints
 	case pipeline.SignalLogs:
 		sentMeasure = or.TelemetryBuilder.ExporterSentLogRecords
 		failedMeasure = or.TelemetryBuilder.ExporterSendFailedLogRecords
+		rejectedMeasure = or.TelemetryBuilder.ExporterRejectedLogRecords
 	}
 
 	sentMeasure.Add(ctx, sent, or.otelAttrs)
 	failedMeasure.Add(ctx, failed, or.otelAttrs)
+	if rejected > 0 {
+		rejectedMeasure.Add(ctx, rejected, or.otelAttrs)
+	}
 }
 
 func endSpan(ctx context.Context, err error, numSent, numFailedToSend int64, sentItemsKey, failedToSendItemsKey string) {
@@ -148,11 +156,17 @@ func endSpan(ctx context.Context, err error, numSent, numFailedToSend int64, sen
 	span.End()
 }
 
-func toNumItems(numExportedItems int, err error) (int64, int64) {
-	if err != nil {
-		return 0, int64(numExportedItems)
+// toNumItems returns the number of items sent, failed to send and rejected by the destination.
+// The items of a partial success response that are not rejected are considered sent.
+func toNumItems(numExportedItems int, err error) (int64, int64, int64) {
+	if err == nil {
+		return int64(numExportedItems), 0, 0
+	}
+	if rejected, ok := consumererror.RejectedItems(err); ok {
+		rejected = min(rejected, int64(numExportedItems))
+		return int64(numExportedItems) - rejected, 0, rejected
 	}
-	return int64(numExportedItems), 0
+	return 0, int64(numExportedItems), 0
 }
 
 func (or *ObsReport) RecordEnqueueFailure(ctx context.Context, signal pipeline.Signal, failed int64) {
@@ -168,3 +182,20 @@ func (or *ObsReport) RecordEnqueueFailure(ctx context.Context, signal pipeline.S
 
 	enqueueFailedMeasure.Add(ctx, failed, or.otelAttrs)
 }
+
+// RecordRateLimitDelay records that a request was delayed by the rate limit for the given duration.
+func (or *ObsReport) RecordRateLimitDelay(ctx context.Context, delay time.Duration) {
+	or.TelemetryBuilder.ExporterRateLimitedRequests.Add(ctx, 1, or.otelAttrs)
+	or.TelemetryBuilder.ExporterRateLimitDelay.Add(ctx, delay.Seconds(), or.otelAttrs)
+}
+
+// RecordShutdownUnsent records the requests left in the queue at the shutdown deadline,
+// either persisted to be sent after the restart or dropped.
+func (or *ObsReport) RecordShutdownUnsent(ctx context.Context, persisted, dropped int64) {
+	if persisted > 0 {
+		or.TelemetryBuilder.ExporterShutdownPersistedRequests.Add(ctx, persisted, or.otelAttrs)
+	}
+	if dropped > 0 {
+		or.TelemetryBuilder.ExporterShutdownDroppedRequests.Add(ctx, dropped, or.otelAttrs)
+	}
+}
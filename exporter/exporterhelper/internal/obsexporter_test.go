@@ -12,10 +12,16 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 )
 
 var (
@@ -242,3 +248,39 @@ func testTelemetry(t *testing.T, id component.ID, testFunc func(t *testing.T, tt
 
 	testFunc(t, tt)
 }
+
+func TestExportOp_PartialSuccess(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	set := exportertest.NewNopSettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	set.LeveledMeterProvider = func(configtelemetry.Level) metric.MeterProvider { return set.MeterProvider }
+	obsrep, err := NewExporter(ObsReportSettings{ExporterID: exporterID, ExporterCreateSettings: set})
+	require.NoError(t, err)
+
+	partialErr := consumererror.NewPermanent(consumererror.NewPartialSuccess(errFake, 3))
+	obsrep.EndTracesOp(obsrep.StartTracesOp(context.Background()), 10, partialErr)
+	obsrep.EndMetricsOp(obsrep.StartMetricsOp(context.Background()), 2, partialErr)
+	obsrep.EndLogsOp(obsrep.StartLogsOp(context.Background()), 5, nil)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	sums := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				sums[m.Name] += dp.Value
+			}
+		}
+	}
+	// The items that are not rejected are accepted by the destination.
+	assert.Equal(t, map[string]int64{
+		"otelcol_exporter_sent_spans":                7,
+		"otelcol_exporter_send_failed_spans":         0,
+		"otelcol_exporter_rejected_spans":            3,
+		"otelcol_exporter_sent_metric_points":        0,
+		"otelcol_exporter_send_failed_metric_points": 0,
+		"otelcol_exporter_rejected_metric_points":    2,
+		"otelcol_exporter_sent_log_records":          5,
+		"otelcol_exporter_send_failed_log_records":   0,
+	}, sums)
+}
@@ -14,6 +14,7 @@ import (
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/exporter/internal"
@@ -30,11 +31,17 @@ type QueueConfig struct {
 	// If batching is enabled, a combined batch cannot contain more requests than the number of consumers.
 	// So it's recommended to set higher number of consumers if batching is enabled.
 	NumConsumers int `mapstructure:"num_consumers"`
-	// QueueSize is the maximum number of batches allowed in queue at a given time.
+	// QueueSize is the maximum size of the queue at a given time, measured in the units of the Sizer.
 	QueueSize int `mapstructure:"queue_size"`
+	// Sizer defines the unit of the QueueSize: requests (batches), items (spans, data points or log records)
+	// or bytes (size of the serialized batches). Defaults to requests.
+	Sizer exporterqueue.SizerType `mapstructure:"sizer"`
 	// StorageID if not empty, enables the persistent storage and uses the component specified
 	// as a storage extension for the persistent queue
 	StorageID *component.ID `mapstructure:"storage"`
+	// AdaptiveConcurrency if enabled, adjusts the number of consumers sending requests concurrently,
+	// starting from NumConsumers, to the latency of the requests and the throttling responses of the backend.
+	AdaptiveConcurrency exporterqueue.AdaptiveConcurrencyConfig `mapstructure:"adaptive_concurrency"`
 }
 
 // NewDefaultQueueConfig returns the default config for QueueConfig.
@@ -45,7 +52,9 @@ func NewDefaultQueueConfig() QueueConfig {
 		// By default, batches are 8192 spans, for a total of up to 8 million spans in the queue
 		// This can be estimated at 1-4 GB worth of maximum memory usage
 		// This default is probably still too high, and may be adjusted further down in a future release
-		QueueSize: defaultQueueSize,
+		QueueSize:           defaultQueueSize,
+		Sizer:               exporterqueue.SizerTypeRequests,
+		AdaptiveConcurrency: exporterqueue.NewDefaultAdaptiveConcurrencyConfig(),
 	}
 }
 
@@ -63,6 +72,10 @@ func (qCfg *QueueConfig) Validate() error {
 		return errors.New("number of queue consumers must be positive")
 	}
 
+	if !qCfg.AdaptiveConcurrency.ContainsConsumers(qCfg.NumConsumers) {
+		return errors.New("number of queue consumers must be between the minimum and the maximum number of consumers")
+	}
+
 	return nil
 }
 
@@ -70,31 +83,55 @@ type QueueSender struct {
 	BaseRequestSender
 	queue          exporterqueue.Queue[internal.Request]
 	numConsumers   int
+	adaptive       bool
 	traceAttribute attribute.KeyValue
 	consumers      *queue.Consumers[internal.Request]
 
 	obsrep     *ObsReport
 	exporterID component.ID
+	logger     *zap.Logger
+
+	// unsent if not nil, persists the requests left in the queue at the shutdown deadline.
+	unsent *unsentRequests
+	host   component.Host
 }
 
-func NewQueueSender(q exporterqueue.Queue[internal.Request], set exporter.Settings, numConsumers int,
+func NewQueueSender(q exporterqueue.Queue[internal.Request], set exporter.Settings, qCfg exporterqueue.Config,
 	exportFailureMessage string, obsrep *ObsReport) *QueueSender {
 	qs := &QueueSender{
 		queue:          q,
-		numConsumers:   numConsumers,
+		numConsumers:   qCfg.NumConsumers,
+		adaptive:       qCfg.AdaptiveConcurrency.Enabled,
 		traceAttribute: attribute.String(ExporterKey, set.ID.String()),
 		obsrep:         obsrep,
 		exporterID:     set.ID,
+		logger:         set.Logger,
 	}
 	consumeFunc := func(ctx context.Context, req internal.Request) error {
 		err := qs.NextSender.Send(ctx, req)
-		if err != nil {
+		if rejected, ok := consumererror.RejectedItems(err); ok {
+			set.Logger.Warn("Exporting partially failed. The destination rejected some items.",
+				zap.Error(err), zap.Int64("rejected_items", rejected))
+		} else if err != nil {
 			set.Logger.Error("Exporting failed. Dropping data."+exportFailureMessage,
 				zap.Error(err), zap.Int("dropped_items", req.ItemsCount()))
 		}
 		return err
 	}
-	qs.consumers = queue.NewQueueConsumers[internal.Request](q, numConsumers, consumeFunc)
+	if acCfg := qCfg.AdaptiveConcurrency; acCfg.Enabled {
+		var limiter queue.ConcurrencyLimiter
+		switch acCfg.Algorithm {
+		case exporterqueue.ConcurrencyAlgorithmGradient:
+			limiter = queue.NewGradientLimiter(qCfg.NumConsumers, acCfg.MinConsumers, acCfg.MaxConsumers)
+		default:
+			limiter = queue.NewAIMDLimiter(qCfg.NumConsumers, acCfg.MinConsumers, acCfg.MaxConsumers)
+		}
+		// Start the maximum number of consumers, the limiter decides how many of them send requests concurrently.
+		qs.numConsumers = acCfg.MaxConsumers
+		qs.consumers = queue.NewAdaptiveQueueConsumers[internal.Request](q, acCfg.MaxConsumers, limiter, consumeFunc)
+	} else {
+		qs.consumers = queue.NewQueueConsumers[internal.Request](q, qCfg.NumConsumers, consumeFunc)
+	}
 	return qs
 }
 
@@ -103,21 +140,102 @@ func (qs *QueueSender) Start(ctx context.Context, host component.Host) error {
 	if err := qs.consumers.Start(ctx, host); err != nil {
 		return err
 	}
+	qs.host = host
+	qs.restoreUnsent(ctx)
 
 	dataTypeAttr := attribute.String(DataTypeKey, qs.obsrep.Signal.String())
-	return multierr.Append(
+	err := multierr.Append(
 		qs.obsrep.TelemetryBuilder.InitExporterQueueSize(func() int64 { return int64(qs.queue.Size()) },
 			metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute, dataTypeAttr))),
 		qs.obsrep.TelemetryBuilder.InitExporterQueueCapacity(func() int64 { return int64(qs.queue.Capacity()) },
 			metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute))),
 	)
+	if qs.adaptive {
+		err = multierr.Append(err,
+			qs.obsrep.TelemetryBuilder.InitExporterQueueConcurrency(func() int64 { return int64(qs.consumers.Concurrency()) },
+				metric.WithAttributeSet(attribute.NewSet(qs.traceAttribute, dataTypeAttr))))
+	}
+	return err
 }
 
 // Shutdown is invoked during service shutdown.
 func (qs *QueueSender) Shutdown(ctx context.Context) error {
 	// Stop the queue and consumers, this will drain the queue and will call the retry (which is stopped) that will only
-	// try once every request.
-	return qs.consumers.Shutdown(ctx)
+	// try once every request. The consumers stop draining the queue once the context is done.
+	if err := qs.consumers.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	var unsent []internal.Request
+	for {
+		index, _, req, ok := qs.queue.Read(ctx)
+		if !ok {
+			break
+		}
+		unsent = append(unsent, req)
+		qs.queue.OnProcessingFinished(index, nil)
+	}
+	if len(unsent) > 0 {
+		qs.persistUnsent(ctx, unsent)
+	}
+	return nil
+}
+
+// restoreUnsent queues again the requests persisted by persistUnsent during the previous shutdown. The requests
+// that cannot be queued are kept in the storage extension, and are queued again by the next start.
+func (qs *QueueSender) restoreUnsent(ctx context.Context) {
+	storageID, ok := exporterqueue.UnsentStorageFromContext(ctx)
+	if !ok || qs.unsent == nil {
+		return
+	}
+	var restored, keptItems int
+	var offerErr error
+	err := qs.unsent.restore(ctx, qs.host, storageID, func(req internal.Request) error {
+		if err := qs.queue.Offer(context.Background(), req); err != nil {
+			offerErr = err
+			keptItems += req.ItemsCount()
+			return err
+		}
+		restored++
+		return nil
+	})
+	if err != nil {
+		qs.logger.Error("Failed to restore the requests not sent before the previous shutdown.", zap.Error(err))
+	}
+	if keptItems > 0 {
+		qs.logger.Warn("Failed to queue the requests not sent before the previous shutdown, they are kept for the next start.",
+			zap.Error(offerErr), zap.Int("kept_items", keptItems))
+	}
+	if restored > 0 {
+		qs.logger.Info("Queued the requests not sent before the previous shutdown.", zap.Int("requests", restored))
+	}
+}
+
+// persistUnsent persists the requests left in the queue at the shutdown deadline in the storage extension
+// set by exporterqueue.ContextWithUnsentStorage, or drops them.
+func (qs *QueueSender) persistUnsent(ctx context.Context, reqs []internal.Request) {
+	var items int
+	for _, req := range reqs {
+		items += req.ItemsCount()
+	}
+	// The deadline of the context is exceeded, the storage must not be affected.
+	storeCtx := context.WithoutCancel(ctx)
+	storageID, ok := exporterqueue.UnsentStorageFromContext(ctx)
+	if !ok || qs.unsent == nil {
+		qs.logger.Error("Shutdown deadline exceeded before the queue was drained. Dropping data.",
+			zap.Int("dropped_items", items))
+		qs.obsrep.RecordShutdownUnsent(storeCtx, 0, int64(len(reqs)))
+		return
+	}
+	if err := qs.unsent.persist(storeCtx, qs.host, storageID, reqs); err != nil {
+		qs.logger.Error("Failed to persist the requests not sent before the shutdown deadline. Dropping data.",
+			zap.Error(err), zap.Int("dropped_items", items))
+		qs.obsrep.RecordShutdownUnsent(storeCtx, 0, int64(len(reqs)))
+		return
+	}
+	qs.logger.Warn("Shutdown deadline exceeded before the queue was drained. Persisted the requests not sent.",
+		zap.String("storage", storageID.String()), zap.Int("items", items))
+	qs.obsrep.RecordShutdownUnsent(storeCtx, int64(len(reqs)), 0)
 }
 
 // send implements the requestSender interface. It puts the request in the queue.
@@ -12,12 +12,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/exporter/exportertest"
@@ -233,6 +237,36 @@ func TestQueuedRetry_QueueMetricsReported(t *testing.T) {
 	}
 }
 
+func TestQueuedRetry_AdaptiveConcurrency(t *testing.T) {
+	tt, err := componenttest.SetupTelemetry(defaultID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, tt.Shutdown(context.Background())) })
+
+	qCfg := NewDefaultQueueConfig()
+	qCfg.NumConsumers = 2
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MaxConsumers = 4
+	set := exporter.Settings{ID: defaultID, TelemetrySettings: tt.TelemetrySettings(), BuildInfo: component.NewDefaultBuildInfo()}
+	be, err := NewBaseExporter(set, defaultSignal, newObservabilityConsumerSender,
+		WithMarshaler(mockRequestMarshaler), WithUnmarshaler(mockRequestUnmarshaler(&mockRequest{})),
+		WithRetry(configretry.NewDefaultBackOffConfig()), WithQueue(qCfg))
+	require.NoError(t, err)
+	ocs := be.ObsrepSender.(*observabilityConsumerSender)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, tt.CheckExporterMetricGauge("otelcol_exporter_queue_concurrency", int64(2),
+		attribute.String(DataTypeKey, defaultSignal.String())))
+
+	for i := 0; i < 20; i++ {
+		ocs.run(func() {
+			require.NoError(t, be.Send(context.Background(), newMockRequest(2, nil)))
+		})
+	}
+	ocs.awaitAsyncProcessing()
+	ocs.checkSendItemsCount(t, 40)
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
 func TestNoCancellationContext(t *testing.T) {
 	deadline := time.Now().Add(1 * time.Second)
 	ctx, cancelFunc := context.WithDeadline(context.Background(), deadline)
@@ -261,6 +295,11 @@ func TestQueueConfig_Validate(t *testing.T) {
 
 	require.EqualError(t, qCfg.Validate(), "number of queue consumers must be positive")
 
+	qCfg = NewDefaultQueueConfig()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MaxConsumers = 5
+	require.EqualError(t, qCfg.Validate(), "number of queue consumers must be between the minimum and the maximum number of consumers")
+
 	// Confirm Validate doesn't return error with invalid config when feature is disabled
 	qCfg.Enabled = false
 	assert.NoError(t, qCfg.Validate())
@@ -430,6 +469,133 @@ func TestQueuedRetryPersistentEnabled_NoDataLossOnShutdown(t *testing.T) {
 	replacedReq.checkNumRequests(t, 1)
 }
 
+func TestQueuedRetry_PersistUnsentOnShutdownDeadline(t *testing.T) {
+	qCfg := NewDefaultQueueConfig()
+	qCfg.NumConsumers = 1
+	storageID := component.MustNewIDWithName("file_storage", "unsent")
+	host := &MockHost{Ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	ctx := exporterqueue.ContextWithUnsentStorage(context.Background(), storageID)
+
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler), WithUnmarshaler(mockRequestUnmarshaler(&mockRequest{})), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(ctx, host))
+
+	// The first request blocks the only consumer until after the deadline.
+	blockingReq := newMockRequest(1, nil)
+	blockingReq.mu.Lock()
+	require.NoError(t, be.Send(context.Background(), blockingReq))
+	blockingReq.checkNumRequests(t, 1)
+	require.NoError(t, be.Send(context.Background(), newMockRequest(1, nil)))
+	require.NoError(t, be.Send(context.Background(), newMockRequest(1, nil)))
+
+	shutdownCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	time.AfterFunc(100*time.Millisecond, blockingReq.mu.Unlock)
+	require.NoError(t, be.Shutdown(shutdownCtx))
+
+	// The requests persisted are queued again by the next start.
+	restoredReq := newMockRequest(1, nil)
+	be, err = NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler), WithUnmarshaler(mockRequestUnmarshaler(restoredReq)), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(ctx, host))
+	restoredReq.checkNumRequests(t, 2)
+	require.NoError(t, be.Shutdown(ctx))
+}
+
+func TestQueuedRetry_KeepUnsentNotQueued(t *testing.T) {
+	qCfg := NewDefaultQueueConfig()
+	qCfg.NumConsumers = 1
+	storageID := component.MustNewIDWithName("file_storage", "unsent")
+	host := &MockHost{Ext: map[component.ID]component.Component{
+		storageID: queue.NewMockStorageExtension(nil),
+	}}
+	ctx := exporterqueue.ContextWithUnsentStorage(context.Background(), storageID)
+
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler), WithUnmarshaler(mockRequestUnmarshaler(&mockRequest{})), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(ctx, host))
+	blockingReq := newMockRequest(1, nil)
+	blockingReq.mu.Lock()
+	require.NoError(t, be.Send(context.Background(), blockingReq))
+	blockingReq.checkNumRequests(t, 1)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, be.Send(context.Background(), newMockRequest(1, nil)))
+	}
+	shutdownCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	time.AfterFunc(100*time.Millisecond, blockingReq.mu.Unlock)
+	require.NoError(t, be.Shutdown(shutdownCtx))
+
+	// The queue of the next start is full once the consumer is blocked by a request and another one is queued,
+	// the requests that cannot be queued are kept in the storage.
+	restoredReq := newMockRequest(1, nil)
+	restoredReq.mu.Lock()
+	smallQCfg := qCfg
+	smallQCfg.QueueSize = 1
+	be, err = NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler), WithUnmarshaler(mockRequestUnmarshaler(restoredReq)), WithQueue(smallQCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(ctx, host))
+	client, err := newUnsentRequests(defaultSettings.ID, defaultSignal, nil, nil).client(ctx, host, storageID)
+	require.NoError(t, err)
+	kept, err := unsentCount(ctx, client)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, kept, uint64(1))
+	require.NoError(t, client.Close(ctx))
+	restoredReq.mu.Unlock()
+	require.NoError(t, be.Shutdown(ctx))
+
+	// The kept requests are queued by the following start.
+	be, err = NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler), WithUnmarshaler(mockRequestUnmarshaler(restoredReq)), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(ctx, host))
+	restoredReq.checkNumRequests(t, 3)
+	require.NoError(t, be.Shutdown(ctx))
+}
+
+func TestQueuedRetry_DropUnsentOnShutdownDeadline(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	set := exportertest.NewNopSettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	set.LeveledMeterProvider = func(configtelemetry.Level) metric.MeterProvider { return set.MeterProvider }
+
+	qCfg := NewDefaultQueueConfig()
+	qCfg.NumConsumers = 1
+	be, err := NewBaseExporter(set, defaultSignal, newNoopObsrepSender,
+		WithMarshaler(mockRequestMarshaler), WithUnmarshaler(mockRequestUnmarshaler(&mockRequest{})), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	blockingReq := newMockRequest(1, nil)
+	blockingReq.mu.Lock()
+	require.NoError(t, be.Send(context.Background(), blockingReq))
+	blockingReq.checkNumRequests(t, 1)
+	require.NoError(t, be.Send(context.Background(), newMockRequest(1, nil)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	time.AfterFunc(100*time.Millisecond, blockingReq.mu.Unlock)
+	require.NoError(t, be.Shutdown(ctx))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var dropped int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "otelcol_exporter_shutdown_dropped_requests" {
+				dropped += m.Data.(metricdata.Sum[int64]).DataPoints[0].Value
+			}
+		}
+	}
+	assert.Equal(t, int64(1), dropped)
+}
+
 func TestQueueSenderNoStartShutdown(t *testing.T) {
 	queue := queue.NewBoundedMemoryQueue[internal.Request](queue.MemoryQueueSettings[internal.Request]{})
 	set := exportertest.NewNopSettings()
@@ -438,6 +604,6 @@ func TestQueueSenderNoStartShutdown(t *testing.T) {
 		ExporterCreateSettings: exportertest.NewNopSettings(),
 	})
 	require.NoError(t, err)
-	qs := NewQueueSender(queue, set, 1, "", obsrep)
+	qs := NewQueueSender(queue, set, exporterqueue.Config{NumConsumers: 1}, "", obsrep)
 	assert.NoError(t, qs.Shutdown(context.Background()))
 }
@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/exporter/internal"
+)
+
+// RateLimitConfig defines the maximum throughput of an exporter. Every attempt to send a request, including
+// the retries, waits until it fits in all the configured limits.
+type RateLimitConfig struct {
+	// Enabled indicates whether to limit the rate of the requests sent to the backend.
+	Enabled bool `mapstructure:"enabled"`
+	// RequestsPerSecond is the maximum number of requests sent per second, 0 means no limit.
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	// ItemsPerSecond is the maximum number of items (spans, metric data points, log records or profile samples)
+	// sent per second, 0 means no limit.
+	ItemsPerSecond float64 `mapstructure:"items_per_second"`
+	// BytesPerSecond is the maximum number of bytes sent per second, measured as the size of the requests
+	// serialized as OTLP protobuf, 0 means no limit.
+	BytesPerSecond float64 `mapstructure:"bytes_per_second"`
+	// Burst is how long the unused throughput can be accumulated and sent at once, at the configured rates.
+	Burst time.Duration `mapstructure:"burst"`
+}
+
+// NewDefaultRateLimitConfig returns the default config for RateLimitConfig.
+func NewDefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Enabled: false,
+		Burst:   time.Second,
+	}
+}
+
+// Validate checks if the RateLimitConfig configuration is valid.
+func (cfg *RateLimitConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.RequestsPerSecond < 0 || cfg.ItemsPerSecond < 0 || cfg.BytesPerSecond < 0 {
+		return errors.New("rate limits must be non-negative")
+	}
+	if cfg.RequestsPerSecond == 0 && cfg.ItemsPerSecond == 0 && cfg.BytesPerSecond == 0 {
+		return errors.New("at least one of 'requests_per_second', 'items_per_second' or 'bytes_per_second' must be set")
+	}
+	if cfg.Burst <= 0 {
+		return errors.New("'burst' must be positive")
+	}
+	return nil
+}
+
+// tokenBucket is a token bucket refilled at a fixed rate. The tokens can be reserved in advance:
+// the bucket goes into debt and the next reservations wait longer.
+type tokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(rate float64, burst time.Duration, now time.Time) *tokenBucket {
+	capacity := rate * burst.Seconds()
+	return &tokenBucket{rate: rate, capacity: capacity, tokens: capacity, last: now}
+}
+
+// reserve takes n tokens from the bucket and returns how long to wait until they are available.
+func (b *tokenBucket) reserve(now time.Time, n float64) time.Duration {
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel gives back n tokens reserved but not used.
+func (b *tokenBucket) cancel(n float64) {
+	b.tokens = min(b.capacity, b.tokens+n)
+}
+
+type bytesSizer interface {
+	BytesSize() int
+}
+
+// rateLimitSender is a RequestSender delaying the requests to stay under the configured rate limits.
+type rateLimitSender struct {
+	BaseRequestSender
+	obsrep *ObsReport
+
+	mu       sync.Mutex
+	requests *tokenBucket
+	items    *tokenBucket
+	bytes    *tokenBucket
+}
+
+func newRateLimitSender(cfg RateLimitConfig, obsrep *ObsReport) *rateLimitSender {
+	rs := &rateLimitSender{obsrep: obsrep}
+	now := time.Now()
+	if cfg.RequestsPerSecond > 0 {
+		rs.requests = newTokenBucket(cfg.RequestsPerSecond, cfg.Burst, now)
+	}
+	if cfg.ItemsPerSecond > 0 {
+		rs.items = newTokenBucket(cfg.ItemsPerSecond, cfg.Burst, now)
+	}
+	if cfg.BytesPerSecond > 0 {
+		rs.bytes = newTokenBucket(cfg.BytesPerSecond, cfg.Burst, now)
+	}
+	return rs
+}
+
+func (rs *rateLimitSender) Send(ctx context.Context, req internal.Request) error {
+	sizes := rs.sizes(req)
+	delay := rs.reserve(sizes)
+	if delay > 0 {
+		rs.obsrep.RecordRateLimitDelay(ctx, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			rs.cancel(sizes)
+			return fmt.Errorf("request is cancelled or timed out while waiting for the rate limit: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+	return rs.NextSender.Send(ctx, req)
+}
+
+// sizes returns the number of requests, items and bytes of the request, in this order.
+func (rs *rateLimitSender) sizes(req internal.Request) [3]float64 {
+	sizes := [3]float64{1, float64(req.ItemsCount()), 0}
+	if rs.bytes != nil {
+		if bs, ok := req.(bytesSizer); ok {
+			sizes[2] = float64(bs.BytesSize())
+		}
+	}
+	return sizes
+}
+
+func (rs *rateLimitSender) buckets() [3]*tokenBucket {
+	return [3]*tokenBucket{rs.requests, rs.items, rs.bytes}
+}
+
+// reserve reserves the sizes of the request in all the limits and returns how long to wait until the request
+// fits in all of them.
+func (rs *rateLimitSender) reserve(sizes [3]float64) time.Duration {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	now := time.Now()
+	var delay time.Duration
+	for i, b := range rs.buckets() {
+		if b != nil {
+			delay = max(delay, b.reserve(now, sizes[i]))
+		}
+	}
+	return delay
+}
+
+func (rs *rateLimitSender) cancel(sizes [3]float64) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	for i, b := range rs.buckets() {
+		if b != nil {
+			b.cancel(sizes[i])
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	cfg := NewDefaultRateLimitConfig()
+	require.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	require.EqualError(t, cfg.Validate(), "at least one of 'requests_per_second', 'items_per_second' or 'bytes_per_second' must be set")
+
+	cfg.ItemsPerSecond = 100
+	require.NoError(t, cfg.Validate())
+
+	cfg.BytesPerSecond = -1
+	require.EqualError(t, cfg.Validate(), "rate limits must be non-negative")
+
+	cfg.BytesPerSecond = 0
+	cfg.Burst = 0
+	require.EqualError(t, cfg.Validate(), "'burst' must be positive")
+
+	// Confirm Validate doesn't return error with invalid config when feature is disabled
+	cfg.Enabled = false
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(10, 2*time.Second, now)
+
+	// The burst is available immediately.
+	assert.Zero(t, b.reserve(now, 20))
+	// Then the tokens are refilled at the configured rate.
+	assert.Equal(t, 500*time.Millisecond, b.reserve(now, 5))
+	assert.Equal(t, time.Second, b.reserve(now, 5))
+	b.cancel(5)
+	assert.Zero(t, b.reserve(now.Add(time.Second), 5))
+
+	// The unused tokens are capped to the burst.
+	assert.Zero(t, b.reserve(now.Add(time.Hour), 20))
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now.Add(time.Hour), 1))
+}
+
+func TestRateLimitSender(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	set := exportertest.NewNopSettings()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	set.LeveledMeterProvider = func(configtelemetry.Level) metric.MeterProvider { return set.MeterProvider }
+	obsrep, err := NewExporter(ObsReportSettings{ExporterID: defaultID, ExporterCreateSettings: set, Signal: defaultSignal})
+	require.NoError(t, err)
+
+	cfg := NewDefaultRateLimitConfig()
+	cfg.Enabled = true
+	cfg.RequestsPerSecond = 1000
+	cfg.ItemsPerSecond = 100
+	cfg.Burst = 100 * time.Millisecond
+	rs := newRateLimitSender(cfg, obsrep)
+	rs.SetNextSender(&TimeoutSender{cfg: NewDefaultTimeoutConfig()})
+
+	// The first requests fit in the burst.
+	start := time.Now()
+	require.NoError(t, rs.Send(context.Background(), newMockRequest(2, nil)))
+	require.NoError(t, rs.Send(context.Background(), newMockRequest(8, nil)))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// The next one waits for the items to be refilled.
+	require.NoError(t, rs.Send(context.Background(), newMockRequest(5, nil)))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	delayed, delay := rateLimitMetrics(rm)
+	assert.EqualValues(t, 1, delayed)
+	assert.Positive(t, delay)
+
+	// A cancelled request gives back its reservation.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, rs.Send(ctx, newMockRequest(100, nil)), context.Canceled)
+	assert.Less(t, rs.reserve([3]float64{1, 0, 0}), 100*time.Millisecond)
+}
+
+func TestRateLimitSender_BaseExporter(t *testing.T) {
+	cfg := NewDefaultRateLimitConfig()
+	cfg.Enabled = true
+	cfg.BytesPerSecond = 10_000
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newNoopObsrepSender, WithRateLimit(cfg))
+	require.NoError(t, err)
+	require.IsType(t, &rateLimitSender{}, be.RateLimitSender)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+
+	mockR := newMockRequest(2, nil)
+	require.NoError(t, be.Send(context.Background(), mockR))
+	mockR.checkNumRequests(t, 1)
+	require.NoError(t, be.Shutdown(context.Background()))
+}
+
+func rateLimitMetrics(rm metricdata.ResourceMetrics) (delayed int64, delay float64) {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case "otelcol_exporter_rate_limited_requests":
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					delayed += dp.Value
+				}
+			case "otelcol_exporter_rate_limit_delay":
+				for _, dp := range m.Data.(metricdata.Sum[float64]).DataPoints {
+					delay += dp.Value
+				}
+			}
+		}
+	}
+	return delayed, delay
+}
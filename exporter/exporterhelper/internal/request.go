@@ -55,6 +55,11 @@ func (r *fakeRequest) ItemsCount() int {
 	return r.items
 }
 
+// BytesSize returns a fake size of the request, every item takes 10 bytes.
+func (r *fakeRequest) BytesSize() int {
+	return r.items * 10
+}
+
 type FakeRequestConverter struct {
 	MetricsError  error
 	TracesError   error
@@ -5,7 +5,6 @@ package internal // import "go.opentelemetry.io/collector/exporter/exporterhelpe
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
@@ -21,26 +20,9 @@ import (
 	"go.opentelemetry.io/collector/exporter/internal/experr"
 )
 
-// TODO: Clean this by forcing all exporters to return an internal error type that always include the information about retries.
-type throttleRetry struct {
-	err   error
-	delay time.Duration
-}
-
-func (t throttleRetry) Error() string {
-	return "Throttle (" + t.delay.String() + "), error: " + t.err.Error()
-}
-
-func (t throttleRetry) Unwrap() error {
-	return t.err
-}
-
 // NewThrottleRetry creates a new throttle retry error.
 func NewThrottleRetry(err error, delay time.Duration) error {
-	return throttleRetry{
-		err:   err,
-		delay: delay,
-	}
+	return configretry.NewThrottleError(err, delay)
 }
 
 type retrySender struct {
@@ -105,9 +87,11 @@ func (rs *retrySender) Send(ctx context.Context, req internal.Request) error {
 			return fmt.Errorf("no more retries left: %w", err)
 		}
 
-		throttleErr := throttleRetry{}
-		if errors.As(err, &throttleErr) {
-			backoffDelay = max(backoffDelay, throttleErr.delay)
+		if throttleDelay, ok := configretry.ThrottleDelay(err); ok {
+			if rs.cfg.MaxThrottleDelay > 0 && throttleDelay > rs.cfg.MaxThrottleDelay {
+				throttleDelay = rs.cfg.MaxThrottleDelay
+			}
+			backoffDelay = max(backoffDelay, throttleDelay)
 		}
 
 		if deadline, has := ctx.Deadline(); has && time.Until(deadline) < backoffDelay {
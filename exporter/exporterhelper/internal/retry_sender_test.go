@@ -197,6 +197,35 @@ func TestQueuedRetry_ThrottleError(t *testing.T) {
 	require.Zero(t, be.QueueSender.(*QueueSender).queue.Size())
 }
 
+func TestQueuedRetry_MaxThrottleDelay(t *testing.T) {
+	rCfg := configretry.NewDefaultBackOffConfig()
+	rCfg.InitialInterval = 10 * time.Millisecond
+	rCfg.MaxThrottleDelay = 50 * time.Millisecond
+	be, err := NewBaseExporter(defaultSettings, defaultSignal, newObservabilityConsumerSender, WithRetry(rCfg))
+	require.NoError(t, err)
+	ocs := be.ObsrepSender.(*observabilityConsumerSender)
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+
+	retry := NewThrottleRetry(errors.New("throttle error"), time.Hour)
+	mockR := newMockRequest(2, retry)
+	start := time.Now()
+	ocs.run(func() {
+		require.NoError(t, be.Send(context.Background(), mockR))
+	})
+	ocs.awaitAsyncProcessing()
+
+	// The server asked to wait one hour, but the delay is capped to 50ms.
+	assert.Less(t, 50*time.Millisecond, time.Since(start))
+	assert.Less(t, time.Since(start), time.Minute)
+
+	mockR.checkNumRequests(t, 2)
+	ocs.checkSendItemsCount(t, 2)
+	ocs.checkDroppedItemsCount(t, 0)
+}
+
 func TestQueuedRetry_RetryOnError(t *testing.T) {
 	qCfg := NewDefaultQueueConfig()
 	qCfg.NumConsumers = 1
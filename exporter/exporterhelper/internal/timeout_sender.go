@@ -8,7 +8,9 @@ import (
 	"errors"
 	"time"
 
+	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/internal"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
 )
 
 // TimeoutConfig for timeout. The timeout applies to individual attempts to send data to the backend.
@@ -40,6 +42,15 @@ type TimeoutSender struct {
 }
 
 func (ts *TimeoutSender) Send(ctx context.Context, req internal.Request) error {
+	countAttempt(ctx)
+	start := time.Now()
+	err := ts.export(ctx, req)
+	// Report the attempt to the adaptive concurrency of the queue consumers.
+	queue.RecordAttempt(ctx, time.Since(start), isOverloadedErr(err))
+	return err
+}
+
+func (ts *TimeoutSender) export(ctx context.Context, req internal.Request) error {
 	// TODO: Remove this by avoiding to create the timeout sender if timeout is 0.
 	if ts.cfg.Timeout == 0 {
 		return req.Export(ctx)
@@ -50,3 +61,13 @@ func (ts *TimeoutSender) Send(ctx context.Context, req internal.Request) error {
 	defer cancelFunc()
 	return req.Export(tCtx)
 }
+
+// isOverloadedErr returns true if the error shows that the backend is overloaded:
+// the request was throttled or timed out.
+func isOverloadedErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, throttled := configretry.ThrottleDelay(err)
+	return throttled || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/multierr"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/exporter/internal"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// unsentCountKey is the storage key of the number of unsent requests persisted.
+const unsentCountKey = "n"
+
+// unsentRequests persists the requests left in the queue at the shutdown deadline in the storage extension
+// set by exporterqueue.ContextWithUnsentStorage, so that they are queued again when the exporter starts.
+// The requests are stored under their index, from 0 to the number of requests persisted.
+type unsentRequests struct {
+	id          component.ID
+	signal      pipeline.Signal
+	marshaler   exporterqueue.Marshaler[internal.Request]
+	unmarshaler exporterqueue.Unmarshaler[internal.Request]
+}
+
+func newUnsentRequests(id component.ID, signal pipeline.Signal, marshaler exporterqueue.Marshaler[internal.Request],
+	unmarshaler exporterqueue.Unmarshaler[internal.Request]) *unsentRequests {
+	return &unsentRequests{
+		id:          id,
+		signal:      signal,
+		marshaler:   marshaler,
+		unmarshaler: unmarshaler,
+	}
+}
+
+func (ur *unsentRequests) client(ctx context.Context, host component.Host, storageID component.ID) (storage.Client, error) {
+	ext, found := host.GetExtensions()[storageID]
+	if !found {
+		return nil, fmt.Errorf("storage extension %q not found", storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %q is not a storage extension", storageID)
+	}
+	return storageExt.GetClient(ctx, component.KindExporter, ur.id, ur.signal.String()+"_unsent")
+}
+
+// restore queues again with offer the requests persisted during the previous shutdown, and removes the queued
+// ones from the storage. The requests that cannot be read or queued are kept, and are queued again by the next
+// restore: they are moved to the first indexes, before the requests persisted by the next shutdown.
+func (ur *unsentRequests) restore(ctx context.Context, host component.Host, storageID component.ID,
+	offer func(internal.Request) error) (err error) {
+	client, err := ur.client(ctx, host, storageID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Append(err, client.Close(ctx))
+	}()
+
+	count, err := unsentCount(ctx, client)
+	if err != nil || count == 0 {
+		return err
+	}
+	ops := make([]storage.Operation, 0, count)
+	for i := uint64(0); i < count; i++ {
+		ops = append(ops, storage.GetOperation(strconv.FormatUint(i, 10)))
+	}
+	if err = client.Batch(ctx, ops...); err != nil {
+		return err
+	}
+	var kept [][]byte
+	for _, op := range ops {
+		req, unmarshalErr := ur.unmarshaler(op.Value)
+		if unmarshalErr != nil {
+			err = multierr.Append(err, unmarshalErr)
+			kept = append(kept, op.Value)
+			continue
+		}
+		if offer(req) != nil {
+			kept = append(kept, op.Value)
+		}
+	}
+
+	updateOps := make([]storage.Operation, 0, count+1)
+	for i, value := range kept {
+		updateOps = append(updateOps, storage.SetOperation(strconv.Itoa(i), value))
+	}
+	for i := uint64(len(kept)); i < count; i++ {
+		updateOps = append(updateOps, storage.DeleteOperation(strconv.FormatUint(i, 10)))
+	}
+	if len(kept) > 0 {
+		updateOps = append(updateOps, storage.SetOperation(unsentCountKey, binary.LittleEndian.AppendUint64(nil, uint64(len(kept)))))
+	} else {
+		updateOps = append(updateOps, storage.DeleteOperation(unsentCountKey))
+	}
+	return multierr.Append(err, client.Batch(ctx, updateOps...))
+}
+
+// persist stores the requests after the ones already persisted, if any.
+func (ur *unsentRequests) persist(ctx context.Context, host component.Host, storageID component.ID, reqs []internal.Request) (err error) {
+	client, err := ur.client(ctx, host, storageID)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Append(err, client.Close(ctx))
+	}()
+
+	count, err := unsentCount(ctx, client)
+	if err != nil {
+		return err
+	}
+	ops := make([]storage.Operation, 0, len(reqs)+1)
+	for _, req := range reqs {
+		buf, marshalErr := ur.marshaler(req)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		ops = append(ops, storage.SetOperation(strconv.FormatUint(count, 10), buf))
+		count++
+	}
+	ops = append(ops, storage.SetOperation(unsentCountKey, binary.LittleEndian.AppendUint64(nil, count)))
+	return client.Batch(ctx, ops...)
+}
+
+func unsentCount(ctx context.Context, client storage.Client) (uint64, error) {
+	buf, err := client.Get(ctx, unsentCountKey)
+	if err != nil || len(buf) < 8 {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
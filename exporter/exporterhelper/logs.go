@@ -65,6 +65,29 @@ func (req *logsRequest) ItemsCount() int {
 	return req.ld.LogRecordCount()
 }
 
+func (req *logsRequest) BytesSize() int {
+	return logsMarshaler.LogsSize(req.ld)
+}
+
+// exportLogsDeadLetter sends a copy of the logs of a failed request, annotated with the dead-letter information,
+// to the dead-letter exporter.
+func exportLogsDeadLetter(ctx context.Context, exp component.Component, req Request, info internal.DeadLetterInfo) error {
+	lr, ok := req.(*logsRequest)
+	if !ok {
+		return errors.New("invalid input type")
+	}
+	dlExp, ok := exp.(consumer.Logs)
+	if !ok {
+		return errors.New("dead-letter exporter does not support logs")
+	}
+	ld := plog.NewLogs()
+	lr.ld.CopyTo(ld)
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		info.PutAttributes(ld.ResourceLogs().At(i).Resource().Attributes())
+	}
+	return dlExp.ConsumeLogs(ctx, ld)
+}
+
 type logsExporter struct {
 	*internal.BaseExporter
 	consumer.Logs
@@ -87,6 +110,7 @@ func NewLogs(
 	logsOpts := []Option{
 		internal.WithMarshaler(logsRequestMarshaler), internal.WithUnmarshaler(newLogsRequestUnmarshalerFunc(pusher)),
 		internal.WithBatchFuncs(mergeLogs, mergeSplitLogs),
+		internal.WithDeadLetterExportFunc(exportLogsDeadLetter),
 	}
 	return NewLogsRequest(ctx, set, requestFromLogs(pusher), append(logsOpts, options...)...)
 }
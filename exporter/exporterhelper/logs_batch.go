@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/plog"
 )
 
@@ -25,9 +26,9 @@ func mergeLogs(_ context.Context, r1 Request, r2 Request) (Request, error) {
 // mergeSplitLogs splits and/or merges the logs into multiple requests based on the MaxSizeConfig.
 func mergeSplitLogs(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Request, r2 Request) ([]Request, error) {
 	var (
-		res          []Request
-		destReq      *logsRequest
-		capacityLeft = cfg.MaxSizeItems
+		res      []Request
+		destReq  *logsRequest
+		capacity = internal.NewBatchCapacity(cfg)
 	)
 	for _, req := range []Request{r1, r2} {
 		if req == nil {
@@ -37,33 +38,39 @@ func mergeSplitLogs(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Req
 		if !ok {
 			return nil, errors.New("invalid input type")
 		}
-		if srcReq.ld.LogRecordCount() <= capacityLeft {
+		srcCount := srcReq.ld.LogRecordCount()
+		srcSize := 0
+		if capacity.BytesLimited() {
+			srcSize = logsMarshaler.LogsSize(srcReq.ld)
+		}
+		if capacity.Fits(srcCount, srcSize) {
 			if destReq == nil {
 				destReq = srcReq
 			} else {
 				srcReq.ld.ResourceLogs().MoveAndAppendTo(destReq.ld.ResourceLogs())
 			}
-			capacityLeft -= destReq.ld.LogRecordCount()
+			capacity.Consume(srcCount, srcSize)
 			continue
 		}
 
 		for {
-			extractedLogs := extractLogs(srcReq.ld, capacityLeft)
-			if extractedLogs.LogRecordCount() == 0 {
-				break
+			extractedLogs := extractLogs(srcReq.ld, capacity, destReq == nil)
+			if extractedLogs.ResourceLogs().Len() > 0 {
+				if destReq == nil {
+					destReq = &logsRequest{ld: extractedLogs, pusher: srcReq.pusher}
+				} else {
+					extractedLogs.ResourceLogs().MoveAndAppendTo(destReq.ld.ResourceLogs())
+				}
 			}
-			capacityLeft -= extractedLogs.LogRecordCount()
-			if destReq == nil {
-				destReq = &logsRequest{ld: extractedLogs, pusher: srcReq.pusher}
-			} else {
-				extractedLogs.ResourceLogs().MoveAndAppendTo(destReq.ld.ResourceLogs())
+			if srcReq.ld.ResourceLogs().Len() == 0 {
+				break
 			}
 			// Create new batch once capacity is reached.
-			if capacityLeft == 0 {
+			if destReq != nil {
 				res = append(res, destReq)
 				destReq = nil
-				capacityLeft = cfg.MaxSizeItems
 			}
+			capacity.Reset()
 		}
 	}
 
@@ -73,55 +80,77 @@ func mergeSplitLogs(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Req
 	return res, nil
 }
 
-// extractLogs extracts logs from the input logs and returns a new logs with the specified number of log records.
-func extractLogs(srcLogs plog.Logs, count int) plog.Logs {
+// extractLogs extracts logs from the input logs and returns new logs that fit in the capacity.
+// If force is true, at least one log record is extracted even if it doesn't fit.
+func extractLogs(srcLogs plog.Logs, capacity *internal.BatchCapacity, force bool) plog.Logs {
 	destLogs := plog.NewLogs()
 	srcLogs.ResourceLogs().RemoveIf(func(srcRL plog.ResourceLogs) bool {
-		if count == 0 {
+		mustExtract := force && destLogs.ResourceLogs().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := resourceLogsCount(srcRL) > count
-		if needToExtract {
-			srcRL = extractResourceLogs(srcRL, count)
+		count := resourceLogsCount(srcRL)
+		size := capacity.FieldSize(func() int { return logsMarshaler.ResourceLogsSize(srcRL) })
+		if capacity.Fits(count, size) || (mustExtract && srcRL.ScopeLogs().Len() == 0) {
+			capacity.Consume(count, size)
+			srcRL.MoveTo(destLogs.ResourceLogs().AppendEmpty())
+			return true
 		}
-		count -= resourceLogsCount(srcRL)
-		srcRL.MoveTo(destLogs.ResourceLogs().AppendEmpty())
-		return !needToExtract
+		destRL := extractResourceLogs(srcRL, capacity, mustExtract)
+		if destRL.ScopeLogs().Len() > 0 {
+			destRL.MoveTo(destLogs.ResourceLogs().AppendEmpty())
+		}
+		return srcRL.ScopeLogs().Len() == 0
 	})
 	return destLogs
 }
 
-// extractResourceLogs extracts resource logs and returns a new resource logs with the specified number of log records.
-func extractResourceLogs(srcRL plog.ResourceLogs, count int) plog.ResourceLogs {
+// extractResourceLogs extracts resource logs and returns a new resource logs with the log records that fit in the capacity.
+// If force is true, at least one log record is extracted even if it doesn't fit.
+func extractResourceLogs(srcRL plog.ResourceLogs, capacity *internal.BatchCapacity, force bool) plog.ResourceLogs {
 	destRL := plog.NewResourceLogs()
 	destRL.SetSchemaUrl(srcRL.SchemaUrl())
 	srcRL.Resource().CopyTo(destRL.Resource())
+	defer capacity.ReserveContainer(func() int { return logsMarshaler.ResourceLogsSize(destRL) }, 1)()
 	srcRL.ScopeLogs().RemoveIf(func(srcSL plog.ScopeLogs) bool {
-		if count == 0 {
+		mustExtract := force && destRL.ScopeLogs().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := srcSL.LogRecords().Len() > count
-		if needToExtract {
-			srcSL = extractScopeLogs(srcSL, count)
+		count := srcSL.LogRecords().Len()
+		size := capacity.FieldSize(func() int { return logsMarshaler.ScopeLogsSize(srcSL) })
+		if capacity.Fits(count, size) || (mustExtract && count == 0) {
+			capacity.Consume(count, size)
+			srcSL.MoveTo(destRL.ScopeLogs().AppendEmpty())
+			return true
+		}
+		destSL := extractScopeLogs(srcSL, capacity, mustExtract)
+		if destSL.LogRecords().Len() > 0 {
+			destSL.MoveTo(destRL.ScopeLogs().AppendEmpty())
 		}
-		count -= srcSL.LogRecords().Len()
-		srcSL.MoveTo(destRL.ScopeLogs().AppendEmpty())
-		return !needToExtract
+		return srcSL.LogRecords().Len() == 0
 	})
 	return destRL
 }
 
-// extractScopeLogs extracts scope logs and returns a new scope logs with the specified number of log records.
-func extractScopeLogs(srcSL plog.ScopeLogs, count int) plog.ScopeLogs {
+// extractScopeLogs extracts scope logs and returns a new scope logs with the log records that fit in the capacity.
+// If force is true, at least one log record is extracted even if it doesn't fit.
+func extractScopeLogs(srcSL plog.ScopeLogs, capacity *internal.BatchCapacity, force bool) plog.ScopeLogs {
 	destSL := plog.NewScopeLogs()
 	destSL.SetSchemaUrl(srcSL.SchemaUrl())
 	srcSL.Scope().CopyTo(destSL.Scope())
+	defer capacity.ReserveContainer(func() int { return logsMarshaler.ScopeLogsSize(destSL) }, 1)()
 	srcSL.LogRecords().RemoveIf(func(srcLR plog.LogRecord) bool {
-		if count == 0 {
+		mustExtract := force && destSL.LogRecords().Len() == 0
+		if capacity.Full() && !mustExtract {
+			return false
+		}
+		size := capacity.FieldSize(func() int { return logsMarshaler.LogRecordSize(srcLR) })
+		if !capacity.FitsLeaf(1, size) && !mustExtract {
 			return false
 		}
+		capacity.Consume(1, size)
 		srcLR.MoveTo(destSL.LogRecords().AppendEmpty())
-		count--
 		return true
 	})
 	return destSL
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/testdata"
 )
@@ -150,11 +151,90 @@ func TestMergeSplitLogsInvalidInput(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMergeSplitLogsMaxSizeBytes(t *testing.T) {
+	maxSizeBytes := logsMarshaler.LogsSize(testdata.GenerateLogs(4))
+	tests := []struct {
+		name          string
+		cfg           exporterbatcher.MaxSizeConfig
+		lr1           Request
+		lr2           Request
+		expectedCount []int
+	}{
+		{
+			name:          "merge_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 2 * maxSizeBytes},
+			lr1:           &logsRequest{ld: testdata.GenerateLogs(2)},
+			lr2:           &logsRequest{ld: testdata.GenerateLogs(2)},
+			expectedCount: []int{4},
+		},
+		{
+			name:          "split_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes},
+			lr1:           nil,
+			lr2:           &logsRequest{ld: testdata.GenerateLogs(10)},
+			expectedCount: []int{4, 4, 2},
+		},
+		{
+			name:          "merge_and_split",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes},
+			lr1:           &logsRequest{ld: testdata.GenerateLogs(2)},
+			lr2:           &logsRequest{ld: testdata.GenerateLogs(4)},
+			expectedCount: []int{3, 3},
+		},
+		{
+			name:          "items_limit_reached_first",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeItems: 2, MaxSizeBytes: maxSizeBytes},
+			lr1:           nil,
+			lr2:           &logsRequest{ld: testdata.GenerateLogs(5)},
+			expectedCount: []int{2, 2, 1},
+		},
+		{
+			name:          "log_record_bigger_than_limit",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 10},
+			lr1:           &logsRequest{ld: testdata.GenerateLogs(1)},
+			lr2:           &logsRequest{ld: testdata.GenerateLogs(2)},
+			expectedCount: []int{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := mergeSplitLogs(context.Background(), tt.cfg, tt.lr1, tt.lr2)
+			require.NoError(t, err)
+			require.Len(t, res, len(tt.expectedCount))
+			for i, r := range res {
+				ld := r.(*logsRequest).ld
+				assert.Equal(t, tt.expectedCount[i], ld.LogRecordCount())
+				if ld.LogRecordCount() > 1 {
+					assert.LessOrEqual(t, logsMarshaler.LogsSize(ld), tt.cfg.MaxSizeBytes)
+				}
+			}
+		})
+	}
+}
+
 func TestExtractLogs(t *testing.T) {
-	for i := 0; i < 10; i++ {
+	for i := 1; i < 10; i++ {
 		ld := testdata.GenerateLogs(10)
-		extractedLogs := extractLogs(ld, i)
+		extractedLogs := extractLogs(ld, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: i}), false)
 		assert.Equal(t, i, extractedLogs.LogRecordCount())
 		assert.Equal(t, 10-i, ld.LogRecordCount())
 	}
 }
+
+func TestExtractLogsMaxSizeBytes(t *testing.T) {
+	for i := 1; i < 10; i++ {
+		maxSizeBytes := logsMarshaler.LogsSize(testdata.GenerateLogs(i))
+		ld := testdata.GenerateLogs(10)
+		extractedLogs := extractLogs(ld, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes}), false)
+		assert.LessOrEqual(t, logsMarshaler.LogsSize(extractedLogs), maxSizeBytes)
+		assert.Equal(t, 10, extractedLogs.LogRecordCount()+ld.LogRecordCount())
+	}
+}
+
+func TestExtractLogsForce(t *testing.T) {
+	ld := testdata.GenerateLogs(2)
+	assert.Equal(t, 0, extractLogs(ld, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeBytes: 1}), false).LogRecordCount())
+	extractedLogs := extractLogs(ld, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeBytes: 1}), true)
+	assert.Equal(t, 1, extractedLogs.LogRecordCount())
+	assert.Equal(t, 1, ld.LogRecordCount())
+}
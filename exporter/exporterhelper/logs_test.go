@@ -26,10 +26,12 @@ import (
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/internal/queue"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/pipeline"
 )
 
 const (
@@ -52,6 +54,13 @@ func TestLogsRequest(t *testing.T) {
 	)
 }
 
+func TestLogsRequest_BytesSize(t *testing.T) {
+	ld := testdata.GenerateLogs(3)
+	req := newLogsRequest(ld, nil)
+	assert.Equal(t, logsMarshaler.LogsSize(ld), req.(RequestBytesSizer).BytesSize())
+	assert.Positive(t, req.(RequestBytesSizer).BytesSize())
+}
+
 func TestLogs_InvalidName(t *testing.T) {
 	le, err := NewLogs(context.Background(), exportertest.NewNopSettings(), nil, newPushLogsData(nil))
 	require.Nil(t, le)
@@ -135,6 +144,39 @@ func TestLogs_Default_ReturnError(t *testing.T) {
 	require.Equal(t, want, le.ConsumeLogs(context.Background(), ld))
 }
 
+func TestLogs_WithDeadLetter(t *testing.T) {
+	dlID := component.MustNewID("dead_letter")
+	sink := new(consumertest.LogsSink)
+	want := consumererror.NewPermanent(errors.New("bad data"))
+	set := exportertest.NewNopSettings()
+	le, err := NewLogs(context.Background(), set, &fakeLogsConfig, newPushLogsData(want),
+		WithDeadLetter(DeadLetterConfig{Exporter: &dlID}))
+	require.NoError(t, err)
+	host := newDeadLetterHost(pipeline.SignalLogs, dlID, struct {
+		component.StartFunc
+		component.ShutdownFunc
+		*consumertest.LogsSink
+	}{LogsSink: sink})
+	require.NoError(t, le.Start(context.Background(), host))
+
+	ld := testdata.GenerateLogs(2)
+	require.ErrorIs(t, le.ConsumeLogs(context.Background(), ld), want)
+	require.Len(t, sink.AllLogs(), 1)
+	got := sink.AllLogs()[0]
+	assert.Equal(t, 2, got.LogRecordCount())
+	attrs := got.ResourceLogs().At(0).Resource().Attributes()
+	exp, _ := attrs.Get(DeadLetterExporterAttribute)
+	assert.Equal(t, set.ID.String(), exp.Str())
+	reason, _ := attrs.Get(DeadLetterReasonAttribute)
+	assert.Equal(t, "Permanent error: bad data", reason.Str())
+	attempts, _ := attrs.Get(DeadLetterAttemptsAttribute)
+	assert.Equal(t, int64(1), attempts.Int())
+	// The original data is not modified.
+	_, found := ld.ResourceLogs().At(0).Resource().Attributes().Get(DeadLetterReasonAttribute)
+	assert.False(t, found)
+	require.NoError(t, le.Shutdown(context.Background()))
+}
+
 func TestLogsRequest_Default_ConvertError(t *testing.T) {
 	ld := plog.NewLogs()
 	want := errors.New("convert_error")
@@ -272,6 +314,50 @@ func TestLogs_WithRecordEnqueueFailedMetrics(t *testing.T) {
 	require.NoError(t, tt.CheckExporterEnqueueFailedLogs(int64(15)))
 }
 
+func TestLogs_WithRecordEnqueueFailedMetricsSizers(t *testing.T) {
+	md := testdata.GenerateLogs(3)
+	tests := []struct {
+		name      string
+		sizer     exporterqueue.SizerType
+		queueSize int
+	}{
+		{
+			name:      "items",
+			sizer:     exporterqueue.SizerTypeItems,
+			queueSize: 2*md.LogRecordCount() + 1,
+		},
+		{
+			name:      "bytes",
+			sizer:     exporterqueue.SizerTypeBytes,
+			queueSize: 2*logsMarshaler.LogsSize(md) + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tel, err := componenttest.SetupTelemetry(fakeLogsName)
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, tel.Shutdown(context.Background())) })
+
+			qCfg := NewDefaultQueueConfig()
+			qCfg.NumConsumers = 1
+			qCfg.QueueSize = tt.queueSize
+			qCfg.Sizer = tt.sizer
+			te, err := NewLogs(context.Background(), exporter.Settings{ID: fakeLogsName, TelemetrySettings: tel.TelemetrySettings(), BuildInfo: component.NewDefaultBuildInfo()},
+				&fakeLogsConfig, newPushLogsData(errors.New("some-error")), WithRetry(configretry.NewDefaultBackOffConfig()), WithQueue(qCfg))
+			require.NoError(t, err)
+			require.NotNil(t, te)
+
+			const numBatches = 7
+			for i := 0; i < numBatches; i++ {
+				_ = te.ConsumeLogs(context.Background(), md)
+			}
+
+			// 2 batches must be in queue, and 5 batches (15 log records) rejected due to queue overflow
+			require.NoError(t, tel.CheckExporterEnqueueFailedLogs(int64(15)))
+		})
+	}
+}
+
 func TestLogs_WithSpan(t *testing.T) {
 	set := exportertest.NewNopSettings()
 	sr := new(tracetest.SpanRecorder)
@@ -436,3 +522,20 @@ func checkWrapSpanForLogs(t *testing.T, sr *tracetest.SpanRecorder, tracer trace
 		require.Containsf(t, sd.Attributes(), attribute.KeyValue{Key: internal.FailedToSendLogRecordsKey, Value: attribute.Int64Value(failedToSendLogRecords)}, "SpanData %v", sd)
 	}
 }
+
+type deadLetterHost struct {
+	component.Host
+	exporters map[pipeline.Signal]map[component.ID]component.Component
+}
+
+func newDeadLetterHost(signal pipeline.Signal, id component.ID, exp component.Component) *deadLetterHost {
+	return &deadLetterHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[pipeline.Signal]map[component.ID]component.Component{signal: {id: exp}},
+	}
+}
+
+func (h *deadLetterHost) GetExporter(signal pipeline.Signal, id component.ID) (component.Component, bool) {
+	exp, ok := h.exporters[signal][id]
+	return exp, ok
+}
@@ -65,6 +65,29 @@ func (req *metricsRequest) ItemsCount() int {
 	return req.md.DataPointCount()
 }
 
+func (req *metricsRequest) BytesSize() int {
+	return metricsMarshaler.MetricsSize(req.md)
+}
+
+// exportMetricsDeadLetter sends a copy of the metrics of a failed request, annotated with the dead-letter information,
+// to the dead-letter exporter.
+func exportMetricsDeadLetter(ctx context.Context, exp component.Component, req Request, info internal.DeadLetterInfo) error {
+	mr, ok := req.(*metricsRequest)
+	if !ok {
+		return errors.New("invalid input type")
+	}
+	dlExp, ok := exp.(consumer.Metrics)
+	if !ok {
+		return errors.New("dead-letter exporter does not support metrics")
+	}
+	md := pmetric.NewMetrics()
+	mr.md.CopyTo(md)
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		info.PutAttributes(md.ResourceMetrics().At(i).Resource().Attributes())
+	}
+	return dlExp.ConsumeMetrics(ctx, md)
+}
+
 type metricsExporter struct {
 	*internal.BaseExporter
 	consumer.Metrics
@@ -87,6 +110,7 @@ func NewMetrics(
 	metricsOpts := []Option{
 		internal.WithMarshaler(metricsRequestMarshaler), internal.WithUnmarshaler(newMetricsRequestUnmarshalerFunc(pusher)),
 		internal.WithBatchFuncs(mergeMetrics, mergeSplitMetrics),
+		internal.WithDeadLetterExportFunc(exportMetricsDeadLetter),
 	}
 	return NewMetricsRequest(ctx, set, requestFromMetrics(pusher), append(metricsOpts, options...)...)
 }
@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
@@ -25,9 +26,9 @@ func mergeMetrics(_ context.Context, r1 Request, r2 Request) (Request, error) {
 // mergeSplitMetrics splits and/or merges the metrics into multiple requests based on the MaxSizeConfig.
 func mergeSplitMetrics(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Request, r2 Request) ([]Request, error) {
 	var (
-		res          []Request
-		destReq      *metricsRequest
-		capacityLeft = cfg.MaxSizeItems
+		res      []Request
+		destReq  *metricsRequest
+		capacity = internal.NewBatchCapacity(cfg)
 	)
 	for _, req := range []Request{r1, r2} {
 		if req == nil {
@@ -37,33 +38,39 @@ func mergeSplitMetrics(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1
 		if !ok {
 			return nil, errors.New("invalid input type")
 		}
-		if srcReq.md.DataPointCount() <= capacityLeft {
+		srcCount := srcReq.md.DataPointCount()
+		srcSize := 0
+		if capacity.BytesLimited() {
+			srcSize = metricsMarshaler.MetricsSize(srcReq.md)
+		}
+		if capacity.Fits(srcCount, srcSize) {
 			if destReq == nil {
 				destReq = srcReq
 			} else {
 				srcReq.md.ResourceMetrics().MoveAndAppendTo(destReq.md.ResourceMetrics())
 			}
-			capacityLeft -= destReq.md.DataPointCount()
+			capacity.Consume(srcCount, srcSize)
 			continue
 		}
 
 		for {
-			extractedMetrics := extractMetrics(srcReq.md, capacityLeft)
-			if extractedMetrics.DataPointCount() == 0 {
-				break
+			extractedMetrics := extractMetrics(srcReq.md, capacity, destReq == nil)
+			if extractedMetrics.ResourceMetrics().Len() > 0 {
+				if destReq == nil {
+					destReq = &metricsRequest{md: extractedMetrics, pusher: srcReq.pusher}
+				} else {
+					extractedMetrics.ResourceMetrics().MoveAndAppendTo(destReq.md.ResourceMetrics())
+				}
 			}
-			capacityLeft -= extractedMetrics.DataPointCount()
-			if destReq == nil {
-				destReq = &metricsRequest{md: extractedMetrics, pusher: srcReq.pusher}
-			} else {
-				extractedMetrics.ResourceMetrics().MoveAndAppendTo(destReq.md.ResourceMetrics())
+			if srcReq.md.ResourceMetrics().Len() == 0 {
+				break
 			}
 			// Create new batch once capacity is reached.
-			if capacityLeft == 0 {
+			if destReq != nil {
 				res = append(res, destReq)
 				destReq = nil
-				capacityLeft = cfg.MaxSizeItems
 			}
+			capacity.Reset()
 		}
 	}
 
@@ -74,133 +81,157 @@ func mergeSplitMetrics(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1
 	return res, nil
 }
 
-// extractMetrics extracts metrics from srcMetrics until count of data points is reached.
-func extractMetrics(srcMetrics pmetric.Metrics, count int) pmetric.Metrics {
+// extractMetrics extracts metrics from srcMetrics with the data points that fit in the capacity.
+// If force is true, at least one data point is extracted even if it doesn't fit.
+func extractMetrics(srcMetrics pmetric.Metrics, capacity *internal.BatchCapacity, force bool) pmetric.Metrics {
 	destMetrics := pmetric.NewMetrics()
 	srcMetrics.ResourceMetrics().RemoveIf(func(srcRM pmetric.ResourceMetrics) bool {
-		if count == 0 {
+		mustExtract := force && destMetrics.ResourceMetrics().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := resourceDataPointsCount(srcRM) > count
-		if needToExtract {
-			srcRM = extractResourceMetrics(srcRM, count)
+		count := resourceDataPointsCount(srcRM)
+		size := capacity.FieldSize(func() int { return metricsMarshaler.ResourceMetricsSize(srcRM) })
+		if capacity.Fits(count, size) || (mustExtract && srcRM.ScopeMetrics().Len() == 0) {
+			capacity.Consume(count, size)
+			srcRM.MoveTo(destMetrics.ResourceMetrics().AppendEmpty())
+			return true
 		}
-		count -= resourceDataPointsCount(srcRM)
-		srcRM.MoveTo(destMetrics.ResourceMetrics().AppendEmpty())
-		return !needToExtract
+		destRM := extractResourceMetrics(srcRM, capacity, mustExtract)
+		if destRM.ScopeMetrics().Len() > 0 {
+			destRM.MoveTo(destMetrics.ResourceMetrics().AppendEmpty())
+		}
+		return srcRM.ScopeMetrics().Len() == 0
 	})
 	return destMetrics
 }
 
-// extractResourceMetrics extracts resource metrics and returns a new resource metrics with the specified number of data points.
-func extractResourceMetrics(srcRM pmetric.ResourceMetrics, count int) pmetric.ResourceMetrics {
+// extractResourceMetrics extracts resource metrics and returns a new resource metrics with the data points that fit
+// in the capacity. If force is true, at least one data point is extracted even if it doesn't fit.
+func extractResourceMetrics(srcRM pmetric.ResourceMetrics, capacity *internal.BatchCapacity, force bool) pmetric.ResourceMetrics {
 	destRM := pmetric.NewResourceMetrics()
 	destRM.SetSchemaUrl(srcRM.SchemaUrl())
 	srcRM.Resource().CopyTo(destRM.Resource())
+	defer capacity.ReserveContainer(func() int { return metricsMarshaler.ResourceMetricsSize(destRM) }, 1)()
 	srcRM.ScopeMetrics().RemoveIf(func(srcSM pmetric.ScopeMetrics) bool {
-		if count == 0 {
+		mustExtract := force && destRM.ScopeMetrics().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := scopeDataPointsCount(srcSM) > count
-		if needToExtract {
-			srcSM = extractScopeMetrics(srcSM, count)
+		count := scopeDataPointsCount(srcSM)
+		size := capacity.FieldSize(func() int { return metricsMarshaler.ScopeMetricsSize(srcSM) })
+		if capacity.Fits(count, size) || (mustExtract && srcSM.Metrics().Len() == 0) {
+			capacity.Consume(count, size)
+			srcSM.MoveTo(destRM.ScopeMetrics().AppendEmpty())
+			return true
+		}
+		destSM := extractScopeMetrics(srcSM, capacity, mustExtract)
+		if destSM.Metrics().Len() > 0 {
+			destSM.MoveTo(destRM.ScopeMetrics().AppendEmpty())
 		}
-		count -= scopeDataPointsCount(srcSM)
-		srcSM.MoveTo(destRM.ScopeMetrics().AppendEmpty())
-		return !needToExtract
+		return srcSM.Metrics().Len() == 0
 	})
 	return destRM
 }
 
-// extractScopeMetrics extracts scope metrics and returns a new scope metrics with the specified number of data points.
-func extractScopeMetrics(srcSM pmetric.ScopeMetrics, count int) pmetric.ScopeMetrics {
+// extractScopeMetrics extracts scope metrics and returns a new scope metrics with the data points that fit
+// in the capacity. If force is true, at least one data point is extracted even if it doesn't fit.
+func extractScopeMetrics(srcSM pmetric.ScopeMetrics, capacity *internal.BatchCapacity, force bool) pmetric.ScopeMetrics {
 	destSM := pmetric.NewScopeMetrics()
 	destSM.SetSchemaUrl(srcSM.SchemaUrl())
 	srcSM.Scope().CopyTo(destSM.Scope())
+	defer capacity.ReserveContainer(func() int { return metricsMarshaler.ScopeMetricsSize(destSM) }, 1)()
 	srcSM.Metrics().RemoveIf(func(srcMetric pmetric.Metric) bool {
-		if count == 0 {
+		mustExtract := force && destSM.Metrics().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := metricDataPointCount(srcMetric) > count
-		if needToExtract {
-			srcMetric = extractMetricDataPoints(srcMetric, count)
+		count := metricDataPointCount(srcMetric)
+		size := capacity.FieldSize(func() int { return metricsMarshaler.MetricSize(srcMetric) })
+		if capacity.Fits(count, size) || (mustExtract && count == 0) {
+			capacity.Consume(count, size)
+			srcMetric.MoveTo(destSM.Metrics().AppendEmpty())
+			return true
 		}
-		count -= metricDataPointCount(srcMetric)
-		srcMetric.MoveTo(destSM.Metrics().AppendEmpty())
-		return !needToExtract
+		destMetric := extractMetricDataPoints(srcMetric, capacity, mustExtract)
+		if metricDataPointCount(destMetric) > 0 {
+			destMetric.MoveTo(destSM.Metrics().AppendEmpty())
+		}
+		return metricDataPointCount(srcMetric) == 0
 	})
 	return destSM
 }
 
-func extractMetricDataPoints(srcMetric pmetric.Metric, count int) pmetric.Metric {
+// extractMetricDataPoints extracts the data points that fit in the capacity and returns a new metric with them.
+// If force is true, at least one data point is extracted even if it doesn't fit.
+func extractMetricDataPoints(srcMetric pmetric.Metric, capacity *internal.BatchCapacity, force bool) pmetric.Metric {
 	destMetric := pmetric.NewMetric()
+	destMetric.SetName(srcMetric.Name())
+	destMetric.SetDescription(srcMetric.Description())
+	destMetric.SetUnit(srcMetric.Unit())
+	srcMetric.Metadata().CopyTo(destMetric.Metadata())
 	switch srcMetric.Type() {
 	case pmetric.MetricTypeGauge:
-		extractGaugeDataPoints(srcMetric.Gauge(), count, destMetric.SetEmptyGauge())
+		destMetric.SetEmptyGauge()
 	case pmetric.MetricTypeSum:
-		extractSumDataPoints(srcMetric.Sum(), count, destMetric.SetEmptySum())
+		destMetric.SetEmptySum().SetAggregationTemporality(srcMetric.Sum().AggregationTemporality())
+		destMetric.Sum().SetIsMonotonic(srcMetric.Sum().IsMonotonic())
 	case pmetric.MetricTypeHistogram:
-		extractHistogramDataPoints(srcMetric.Histogram(), count, destMetric.SetEmptyHistogram())
+		destMetric.SetEmptyHistogram().SetAggregationTemporality(srcMetric.Histogram().AggregationTemporality())
 	case pmetric.MetricTypeExponentialHistogram:
-		extractExponentialHistogramDataPoints(srcMetric.ExponentialHistogram(), count,
-			destMetric.SetEmptyExponentialHistogram())
+		destMetric.SetEmptyExponentialHistogram().SetAggregationTemporality(srcMetric.ExponentialHistogram().AggregationTemporality())
 	case pmetric.MetricTypeSummary:
-		extractSummaryDataPoints(srcMetric.Summary(), count, destMetric.SetEmptySummary())
+		destMetric.SetEmptySummary()
 	}
-	return destMetric
-}
+	// The data points are nested in both the metric and its Gauge, Sum, Histogram, etc. messages.
+	defer capacity.ReserveContainer(func() int { return metricsMarshaler.MetricSize(destMetric) }, 2)()
 
-func extractGaugeDataPoints(srcGauge pmetric.Gauge, count int, destGauge pmetric.Gauge) {
-	srcGauge.DataPoints().RemoveIf(func(srcDP pmetric.NumberDataPoint) bool {
-		if count == 0 {
-			return false
-		}
-		srcDP.MoveTo(destGauge.DataPoints().AppendEmpty())
-		count--
-		return true
-	})
+	switch srcMetric.Type() {
+	case pmetric.MetricTypeGauge:
+		extractDataPoints(srcMetric.Gauge().DataPoints(), destMetric.Gauge().DataPoints(), capacity, force,
+			metricsMarshaler.NumberDataPointSize)
+	case pmetric.MetricTypeSum:
+		extractDataPoints(srcMetric.Sum().DataPoints(), destMetric.Sum().DataPoints(), capacity, force,
+			metricsMarshaler.NumberDataPointSize)
+	case pmetric.MetricTypeHistogram:
+		extractDataPoints(srcMetric.Histogram().DataPoints(), destMetric.Histogram().DataPoints(), capacity, force,
+			metricsMarshaler.HistogramDataPointSize)
+	case pmetric.MetricTypeExponentialHistogram:
+		extractDataPoints(srcMetric.ExponentialHistogram().DataPoints(), destMetric.ExponentialHistogram().DataPoints(),
+			capacity, force, metricsMarshaler.ExponentialHistogramDataPointSize)
+	case pmetric.MetricTypeSummary:
+		extractDataPoints(srcMetric.Summary().DataPoints(), destMetric.Summary().DataPoints(), capacity, force,
+			metricsMarshaler.SummaryDataPointSize)
+	}
+	return destMetric
 }
 
-func extractSumDataPoints(srcSum pmetric.Sum, count int, destSum pmetric.Sum) {
-	srcSum.DataPoints().RemoveIf(func(srcDP pmetric.NumberDataPoint) bool {
-		if count == 0 {
-			return false
-		}
-		srcDP.MoveTo(destSum.DataPoints().AppendEmpty())
-		count--
-		return true
-	})
+// dataPointSlice is implemented by the slices of all the data point types.
+type dataPointSlice[DP any] interface {
+	AppendEmpty() DP
+	Len() int
+	RemoveIf(func(DP) bool)
 }
 
-func extractHistogramDataPoints(srcHistogram pmetric.Histogram, count int, destHistogram pmetric.Histogram) {
-	srcHistogram.DataPoints().RemoveIf(func(srcDP pmetric.HistogramDataPoint) bool {
-		if count == 0 {
-			return false
-		}
-		srcDP.MoveTo(destHistogram.DataPoints().AppendEmpty())
-		count--
-		return true
-	})
+// dataPoint is implemented by all the data point types.
+type dataPoint[DP any] interface {
+	MoveTo(DP)
 }
 
-func extractExponentialHistogramDataPoints(srcExponentialHistogram pmetric.ExponentialHistogram, count int, destExponentialHistogram pmetric.ExponentialHistogram) {
-	srcExponentialHistogram.DataPoints().RemoveIf(func(srcDP pmetric.ExponentialHistogramDataPoint) bool {
-		if count == 0 {
+// extractDataPoints moves the data points that fit in the capacity from srcDPs to destDPs.
+// If force is true, at least one data point is moved even if it doesn't fit.
+func extractDataPoints[DP dataPoint[DP], S dataPointSlice[DP]](srcDPs S, destDPs S, capacity *internal.BatchCapacity, force bool, dpSize func(DP) int) {
+	srcDPs.RemoveIf(func(srcDP DP) bool {
+		mustExtract := force && destDPs.Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		srcDP.MoveTo(destExponentialHistogram.DataPoints().AppendEmpty())
-		count--
-		return true
-	})
-}
-
-func extractSummaryDataPoints(srcSummary pmetric.Summary, count int, destSummary pmetric.Summary) {
-	srcSummary.DataPoints().RemoveIf(func(srcDP pmetric.SummaryDataPoint) bool {
-		if count == 0 {
+		size := capacity.FieldSize(func() int { return dpSize(srcDP) })
+		if !capacity.FitsLeaf(1, size) && !mustExtract {
 			return false
 		}
-		srcDP.MoveTo(destSummary.DataPoints().AppendEmpty())
-		count--
+		capacity.Consume(1, size)
+		srcDP.MoveTo(destDPs.AppendEmpty())
 		return true
 	})
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/testdata"
 )
@@ -150,18 +151,103 @@ func TestMergeSplitMetricsInvalidInput(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMergeSplitMetricsMaxSizeBytes(t *testing.T) {
+	maxSizeBytes := metricsMarshaler.MetricsSize(testdata.GenerateMetrics(4))
+	tests := []struct {
+		name          string
+		cfg           exporterbatcher.MaxSizeConfig
+		mr1           Request
+		mr2           Request
+		expectedCount []int
+	}{
+		{
+			name:          "merge_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 2 * maxSizeBytes},
+			mr1:           &metricsRequest{md: testdata.GenerateMetrics(2)},
+			mr2:           &metricsRequest{md: testdata.GenerateMetrics(2)},
+			expectedCount: []int{8},
+		},
+		{
+			name:          "split_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes},
+			mr1:           nil,
+			mr2:           &metricsRequest{md: testdata.GenerateMetrics(10)},
+			expectedCount: []int{8, 4, 5, 3},
+		},
+		{
+			name:          "items_limit_reached_first",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeItems: 3, MaxSizeBytes: maxSizeBytes},
+			mr1:           nil,
+			mr2:           &metricsRequest{md: testdata.GenerateMetrics(4)},
+			expectedCount: []int{3, 3, 2},
+		},
+		{
+			name:          "data_point_bigger_than_limit",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 10},
+			mr1:           &metricsRequest{md: testdata.GenerateMetrics(1)},
+			mr2:           nil,
+			expectedCount: []int{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := mergeSplitMetrics(context.Background(), tt.cfg, tt.mr1, tt.mr2)
+			require.NoError(t, err)
+			require.Len(t, res, len(tt.expectedCount))
+			for i, r := range res {
+				md := r.(*metricsRequest).md
+				assert.Equal(t, tt.expectedCount[i], md.DataPointCount())
+				if md.DataPointCount() > 1 {
+					assert.LessOrEqual(t, metricsMarshaler.MetricsSize(md), tt.cfg.MaxSizeBytes)
+				}
+			}
+			// The last request is no larger than any other one.
+			last := res[len(res)-1].(*metricsRequest).md
+			for _, r := range res[:len(res)-1] {
+				md := r.(*metricsRequest).md
+				assert.LessOrEqual(t, last.DataPointCount(), md.DataPointCount())
+				assert.LessOrEqual(t, metricsMarshaler.MetricsSize(last), metricsMarshaler.MetricsSize(md))
+			}
+		})
+	}
+}
+
 func TestExtractMetrics(t *testing.T) {
-	for i := 0; i < 20; i++ {
+	for i := 1; i < 20; i++ {
 		md := testdata.GenerateMetrics(10)
-		extractedMetrics := extractMetrics(md, i)
+		extractedMetrics := extractMetrics(md, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: i}), false)
 		assert.Equal(t, i, extractedMetrics.DataPointCount())
 		assert.Equal(t, 20-i, md.DataPointCount())
 	}
 }
 
+func TestExtractMetricsMaxSizeBytes(t *testing.T) {
+	for i := 1; i < 10; i++ {
+		maxSizeBytes := metricsMarshaler.MetricsSize(testdata.GenerateMetrics(i))
+		md := testdata.GenerateMetrics(10)
+		extractedMetrics := extractMetrics(md, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes}), false)
+		assert.LessOrEqual(t, metricsMarshaler.MetricsSize(extractedMetrics), maxSizeBytes)
+		assert.Equal(t, 20, extractedMetrics.DataPointCount()+md.DataPointCount())
+	}
+}
+
+func TestExtractMetricsKeepsMetricFields(t *testing.T) {
+	md := testdata.GenerateMetrics(3)
+	srcMetric := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(2)
+	require.Equal(t, pmetric.MetricTypeSum, srcMetric.Type())
+	extractedMetrics := extractMetrics(md, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: 5}), false)
+	extractedMetric := extractedMetrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(2)
+	assert.Equal(t, 1, extractedMetric.Sum().DataPoints().Len())
+	assert.Equal(t, srcMetric.Name(), extractedMetric.Name())
+	assert.Equal(t, srcMetric.Description(), extractedMetric.Description())
+	assert.Equal(t, srcMetric.Unit(), extractedMetric.Unit())
+	assert.Equal(t, srcMetric.Sum().AggregationTemporality(), extractedMetric.Sum().AggregationTemporality())
+	assert.Equal(t, srcMetric.Sum().IsMonotonic(), extractedMetric.Sum().IsMonotonic())
+}
+
 func TestExtractMetricsInvalidMetric(t *testing.T) {
 	md := testdata.GenerateMetricsMetricTypeInvalid()
-	extractedMetrics := extractMetrics(md, 10)
+	extractedMetrics := extractMetrics(md, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: 10}), false)
 	assert.Equal(t, testdata.GenerateMetricsMetricTypeInvalid(), extractedMetrics)
 	assert.Equal(t, 0, md.ResourceMetrics().Len())
 }
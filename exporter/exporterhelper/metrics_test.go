@@ -30,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/internal/queue"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/pipeline"
 )
 
 const (
@@ -52,6 +53,13 @@ func TestMetricsRequest(t *testing.T) {
 	)
 }
 
+func TestMetricsRequest_BytesSize(t *testing.T) {
+	md := testdata.GenerateMetrics(3)
+	req := newMetricsRequest(md, nil)
+	assert.Equal(t, metricsMarshaler.MetricsSize(md), req.(RequestBytesSizer).BytesSize())
+	assert.Positive(t, req.(RequestBytesSizer).BytesSize())
+}
+
 func TestMetrics_NilConfig(t *testing.T) {
 	me, err := NewMetrics(context.Background(), exportertest.NewNopSettings(), nil, newPushMetricsData(nil))
 	require.Nil(t, me)
@@ -136,6 +144,27 @@ func TestMetrics_Default_ReturnError(t *testing.T) {
 	require.Equal(t, want, me.ConsumeMetrics(context.Background(), md))
 }
 
+func TestMetrics_WithDeadLetter(t *testing.T) {
+	dlID := component.MustNewID("dead_letter")
+	sink := new(consumertest.MetricsSink)
+	want := consumererror.NewPermanent(errors.New("bad data"))
+	me, err := NewMetrics(context.Background(), exportertest.NewNopSettings(), &fakeMetricsConfig, newPushMetricsData(want),
+		WithDeadLetter(DeadLetterConfig{Exporter: &dlID}))
+	require.NoError(t, err)
+	require.NoError(t, me.Start(context.Background(), newDeadLetterHost(pipeline.SignalMetrics, dlID, struct {
+		component.StartFunc
+		component.ShutdownFunc
+		*consumertest.MetricsSink
+	}{MetricsSink: sink})))
+
+	require.ErrorIs(t, me.ConsumeMetrics(context.Background(), testdata.GenerateMetrics(2)), want)
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 2, sink.AllMetrics()[0].MetricCount())
+	reason, _ := sink.AllMetrics()[0].ResourceMetrics().At(0).Resource().Attributes().Get(DeadLetterReasonAttribute)
+	assert.Equal(t, "Permanent error: bad data", reason.Str())
+	require.NoError(t, me.Shutdown(context.Background()))
+}
+
 func TestMetricsRequest_Default_ConvertError(t *testing.T) {
 	md := pmetric.NewMetrics()
 	want := errors.New("convert_error")
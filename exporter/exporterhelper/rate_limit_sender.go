@@ -0,0 +1,16 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterhelper // import "go.opentelemetry.io/collector/exporter/exporterhelper"
+
+import (
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
+)
+
+// RateLimitConfig defines the maximum throughput of an exporter.
+type RateLimitConfig = internal.RateLimitConfig
+
+// NewDefaultRateLimitConfig returns the default config for RateLimitConfig.
+func NewDefaultRateLimitConfig() RateLimitConfig {
+	return internal.NewDefaultRateLimitConfig()
+}
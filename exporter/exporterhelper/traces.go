@@ -65,6 +65,29 @@ func (req *tracesRequest) ItemsCount() int {
 	return req.td.SpanCount()
 }
 
+func (req *tracesRequest) BytesSize() int {
+	return tracesMarshaler.TracesSize(req.td)
+}
+
+// exportTracesDeadLetter sends a copy of the traces of a failed request, annotated with the dead-letter information,
+// to the dead-letter exporter.
+func exportTracesDeadLetter(ctx context.Context, exp component.Component, req Request, info internal.DeadLetterInfo) error {
+	tr, ok := req.(*tracesRequest)
+	if !ok {
+		return errors.New("invalid input type")
+	}
+	dlExp, ok := exp.(consumer.Traces)
+	if !ok {
+		return errors.New("dead-letter exporter does not support traces")
+	}
+	td := ptrace.NewTraces()
+	tr.td.CopyTo(td)
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		info.PutAttributes(td.ResourceSpans().At(i).Resource().Attributes())
+	}
+	return dlExp.ConsumeTraces(ctx, td)
+}
+
 type tracesExporter struct {
 	*internal.BaseExporter
 	consumer.Traces
@@ -87,6 +110,7 @@ func NewTraces(
 	tracesOpts := []Option{
 		internal.WithMarshaler(tracesRequestMarshaler), internal.WithUnmarshaler(newTraceRequestUnmarshalerFunc(pusher)),
 		internal.WithBatchFuncs(mergeTraces, mergeSplitTraces),
+		internal.WithDeadLetterExportFunc(exportTracesDeadLetter),
 	}
 	return NewTracesRequest(ctx, set, requestFromTraces(pusher), append(tracesOpts, options...)...)
 }
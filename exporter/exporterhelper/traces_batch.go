@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
@@ -25,9 +26,9 @@ func mergeTraces(_ context.Context, r1 Request, r2 Request) (Request, error) {
 // mergeSplitTraces splits and/or merges the traces into multiple requests based on the MaxSizeConfig.
 func mergeSplitTraces(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 Request, r2 Request) ([]Request, error) {
 	var (
-		res          []Request
-		destReq      *tracesRequest
-		capacityLeft = cfg.MaxSizeItems
+		res      []Request
+		destReq  *tracesRequest
+		capacity = internal.NewBatchCapacity(cfg)
 	)
 	for _, req := range []Request{r1, r2} {
 		if req == nil {
@@ -37,33 +38,39 @@ func mergeSplitTraces(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 R
 		if !ok {
 			return nil, errors.New("invalid input type")
 		}
-		if srcReq.td.SpanCount() <= capacityLeft {
+		srcCount := srcReq.td.SpanCount()
+		srcSize := 0
+		if capacity.BytesLimited() {
+			srcSize = tracesMarshaler.TracesSize(srcReq.td)
+		}
+		if capacity.Fits(srcCount, srcSize) {
 			if destReq == nil {
 				destReq = srcReq
 			} else {
 				srcReq.td.ResourceSpans().MoveAndAppendTo(destReq.td.ResourceSpans())
 			}
-			capacityLeft -= destReq.td.SpanCount()
+			capacity.Consume(srcCount, srcSize)
 			continue
 		}
 
 		for {
-			extractedTraces := extractTraces(srcReq.td, capacityLeft)
-			if extractedTraces.SpanCount() == 0 {
-				break
+			extractedTraces := extractTraces(srcReq.td, capacity, destReq == nil)
+			if extractedTraces.ResourceSpans().Len() > 0 {
+				if destReq == nil {
+					destReq = &tracesRequest{td: extractedTraces, pusher: srcReq.pusher}
+				} else {
+					extractedTraces.ResourceSpans().MoveAndAppendTo(destReq.td.ResourceSpans())
+				}
 			}
-			capacityLeft -= extractedTraces.SpanCount()
-			if destReq == nil {
-				destReq = &tracesRequest{td: extractedTraces, pusher: srcReq.pusher}
-			} else {
-				extractedTraces.ResourceSpans().MoveAndAppendTo(destReq.td.ResourceSpans())
+			if srcReq.td.ResourceSpans().Len() == 0 {
+				break
 			}
 			// Create new batch once capacity is reached.
-			if capacityLeft == 0 {
+			if destReq != nil {
 				res = append(res, destReq)
 				destReq = nil
-				capacityLeft = cfg.MaxSizeItems
 			}
+			capacity.Reset()
 		}
 	}
 
@@ -73,56 +80,77 @@ func mergeSplitTraces(_ context.Context, cfg exporterbatcher.MaxSizeConfig, r1 R
 	return res, nil
 }
 
-// extractTraces extracts a new traces with a maximum number of spans.
-func extractTraces(srcTraces ptrace.Traces, count int) ptrace.Traces {
+// extractTraces extracts a new traces with the spans that fit in the capacity.
+// If force is true, at least one span is extracted even if it doesn't fit.
+func extractTraces(srcTraces ptrace.Traces, capacity *internal.BatchCapacity, force bool) ptrace.Traces {
 	destTraces := ptrace.NewTraces()
 	srcTraces.ResourceSpans().RemoveIf(func(srcRS ptrace.ResourceSpans) bool {
-		if count == 0 {
+		mustExtract := force && destTraces.ResourceSpans().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := resourceTracesCount(srcRS) > count
-		if needToExtract {
-			srcRS = extractResourceSpans(srcRS, count)
+		count := resourceTracesCount(srcRS)
+		size := capacity.FieldSize(func() int { return tracesMarshaler.ResourceSpansSize(srcRS) })
+		if capacity.Fits(count, size) || (mustExtract && srcRS.ScopeSpans().Len() == 0) {
+			capacity.Consume(count, size)
+			srcRS.MoveTo(destTraces.ResourceSpans().AppendEmpty())
+			return true
 		}
-		count -= resourceTracesCount(srcRS)
-		srcRS.MoveTo(destTraces.ResourceSpans().AppendEmpty())
-		return !needToExtract
+		destRS := extractResourceSpans(srcRS, capacity, mustExtract)
+		if destRS.ScopeSpans().Len() > 0 {
+			destRS.MoveTo(destTraces.ResourceSpans().AppendEmpty())
+		}
+		return srcRS.ScopeSpans().Len() == 0
 	})
 	return destTraces
 }
 
-// extractResourceSpans extracts spans and returns a new resource spans with the specified number of spans.
-func extractResourceSpans(srcRS ptrace.ResourceSpans, count int) ptrace.ResourceSpans {
+// extractResourceSpans extracts spans and returns a new resource spans with the spans that fit in the capacity.
+// If force is true, at least one span is extracted even if it doesn't fit.
+func extractResourceSpans(srcRS ptrace.ResourceSpans, capacity *internal.BatchCapacity, force bool) ptrace.ResourceSpans {
 	destRS := ptrace.NewResourceSpans()
 	destRS.SetSchemaUrl(srcRS.SchemaUrl())
 	srcRS.Resource().CopyTo(destRS.Resource())
+	defer capacity.ReserveContainer(func() int { return tracesMarshaler.ResourceSpansSize(destRS) }, 1)()
 	srcRS.ScopeSpans().RemoveIf(func(srcSS ptrace.ScopeSpans) bool {
-		if count == 0 {
+		mustExtract := force && destRS.ScopeSpans().Len() == 0
+		if capacity.Full() && !mustExtract {
 			return false
 		}
-		needToExtract := srcSS.Spans().Len() > count
-		if needToExtract {
-			srcSS = extractScopeSpans(srcSS, count)
+		count := srcSS.Spans().Len()
+		size := capacity.FieldSize(func() int { return tracesMarshaler.ScopeSpansSize(srcSS) })
+		if capacity.Fits(count, size) || (mustExtract && count == 0) {
+			capacity.Consume(count, size)
+			srcSS.MoveTo(destRS.ScopeSpans().AppendEmpty())
+			return true
+		}
+		destSS := extractScopeSpans(srcSS, capacity, mustExtract)
+		if destSS.Spans().Len() > 0 {
+			destSS.MoveTo(destRS.ScopeSpans().AppendEmpty())
 		}
-		count -= srcSS.Spans().Len()
-		srcSS.MoveTo(destRS.ScopeSpans().AppendEmpty())
-		return !needToExtract
+		return srcSS.Spans().Len() == 0
 	})
-	srcRS.Resource().CopyTo(destRS.Resource())
 	return destRS
 }
 
-// extractScopeSpans extracts spans and returns a new scope spans with the specified number of spans.
-func extractScopeSpans(srcSS ptrace.ScopeSpans, count int) ptrace.ScopeSpans {
+// extractScopeSpans extracts spans and returns a new scope spans with the spans that fit in the capacity.
+// If force is true, at least one span is extracted even if it doesn't fit.
+func extractScopeSpans(srcSS ptrace.ScopeSpans, capacity *internal.BatchCapacity, force bool) ptrace.ScopeSpans {
 	destSS := ptrace.NewScopeSpans()
 	destSS.SetSchemaUrl(srcSS.SchemaUrl())
 	srcSS.Scope().CopyTo(destSS.Scope())
+	defer capacity.ReserveContainer(func() int { return tracesMarshaler.ScopeSpansSize(destSS) }, 1)()
 	srcSS.Spans().RemoveIf(func(srcSpan ptrace.Span) bool {
-		if count == 0 {
+		mustExtract := force && destSS.Spans().Len() == 0
+		if capacity.Full() && !mustExtract {
+			return false
+		}
+		size := capacity.FieldSize(func() int { return tracesMarshaler.SpanSize(srcSpan) })
+		if !capacity.FitsLeaf(1, size) && !mustExtract {
 			return false
 		}
+		capacity.Consume(1, size)
 		srcSpan.MoveTo(destSS.Spans().AppendEmpty())
-		count--
 		return true
 	})
 	return destSS
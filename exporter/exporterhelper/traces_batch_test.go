@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
+	"go.opentelemetry.io/collector/exporter/exporterhelper/internal"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/testdata"
 )
@@ -150,11 +151,75 @@ func TestMergeSplitTracesInvalidInput(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMergeSplitTracesMaxSizeBytes(t *testing.T) {
+	maxSizeBytes := tracesMarshaler.TracesSize(testdata.GenerateTraces(4))
+	tests := []struct {
+		name          string
+		cfg           exporterbatcher.MaxSizeConfig
+		tr1           Request
+		tr2           Request
+		expectedCount []int
+	}{
+		{
+			name:          "merge_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 2 * maxSizeBytes},
+			tr1:           &tracesRequest{td: testdata.GenerateTraces(2)},
+			tr2:           &tracesRequest{td: testdata.GenerateTraces(2)},
+			expectedCount: []int{4},
+		},
+		{
+			name:          "split_only",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes},
+			tr1:           nil,
+			tr2:           &tracesRequest{td: testdata.GenerateTraces(10)},
+			expectedCount: []int{4, 4, 2},
+		},
+		{
+			name:          "items_limit_reached_first",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeItems: 2, MaxSizeBytes: maxSizeBytes},
+			tr1:           nil,
+			tr2:           &tracesRequest{td: testdata.GenerateTraces(5)},
+			expectedCount: []int{2, 2, 1},
+		},
+		{
+			name:          "span_bigger_than_limit",
+			cfg:           exporterbatcher.MaxSizeConfig{MaxSizeBytes: 10},
+			tr1:           &tracesRequest{td: testdata.GenerateTraces(1)},
+			tr2:           &tracesRequest{td: testdata.GenerateTraces(2)},
+			expectedCount: []int{1, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := mergeSplitTraces(context.Background(), tt.cfg, tt.tr1, tt.tr2)
+			require.NoError(t, err)
+			require.Len(t, res, len(tt.expectedCount))
+			for i, r := range res {
+				td := r.(*tracesRequest).td
+				assert.Equal(t, tt.expectedCount[i], td.SpanCount())
+				if td.SpanCount() > 1 {
+					assert.LessOrEqual(t, tracesMarshaler.TracesSize(td), tt.cfg.MaxSizeBytes)
+				}
+			}
+		})
+	}
+}
+
 func TestExtractTraces(t *testing.T) {
-	for i := 0; i < 10; i++ {
+	for i := 1; i < 10; i++ {
 		td := testdata.GenerateTraces(10)
-		extractedTraces := extractTraces(td, i)
+		extractedTraces := extractTraces(td, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeItems: i}), false)
 		assert.Equal(t, i, extractedTraces.SpanCount())
 		assert.Equal(t, 10-i, td.SpanCount())
 	}
 }
+
+func TestExtractTracesMaxSizeBytes(t *testing.T) {
+	for i := 1; i < 10; i++ {
+		maxSizeBytes := tracesMarshaler.TracesSize(testdata.GenerateTraces(i))
+		td := testdata.GenerateTraces(10)
+		extractedTraces := extractTraces(td, internal.NewBatchCapacity(exporterbatcher.MaxSizeConfig{MaxSizeBytes: maxSizeBytes}), false)
+		assert.LessOrEqual(t, tracesMarshaler.TracesSize(extractedTraces), maxSizeBytes)
+		assert.Equal(t, 10, extractedTraces.SpanCount()+td.SpanCount())
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/internal/queue"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/pipeline"
 )
 
 const (
@@ -48,6 +49,13 @@ func TestTracesRequest(t *testing.T) {
 	assert.EqualValues(t, newTracesRequest(ptrace.NewTraces(), nil), mr.(RequestErrorHandler).OnError(traceErr))
 }
 
+func TestTracesRequest_BytesSize(t *testing.T) {
+	td := testdata.GenerateTraces(3)
+	req := newTracesRequest(td, nil)
+	assert.Equal(t, tracesMarshaler.TracesSize(td), req.(RequestBytesSizer).BytesSize())
+	assert.Positive(t, req.(RequestBytesSizer).BytesSize())
+}
+
 func TestTraces_InvalidName(t *testing.T) {
 	te, err := NewTraces(context.Background(), exportertest.NewNopSettings(), nil, newTraceDataPusher(nil))
 	require.Nil(t, te)
@@ -133,6 +141,27 @@ func TestTraces_Default_ReturnError(t *testing.T) {
 	require.Equal(t, want, err)
 }
 
+func TestTraces_WithDeadLetter(t *testing.T) {
+	dlID := component.MustNewID("dead_letter")
+	sink := new(consumertest.TracesSink)
+	want := consumererror.NewPermanent(errors.New("bad data"))
+	te, err := NewTraces(context.Background(), exportertest.NewNopSettings(), &fakeTracesConfig, newTraceDataPusher(want),
+		WithDeadLetter(DeadLetterConfig{Exporter: &dlID}))
+	require.NoError(t, err)
+	require.NoError(t, te.Start(context.Background(), newDeadLetterHost(pipeline.SignalTraces, dlID, struct {
+		component.StartFunc
+		component.ShutdownFunc
+		*consumertest.TracesSink
+	}{TracesSink: sink})))
+
+	require.ErrorIs(t, te.ConsumeTraces(context.Background(), testdata.GenerateTraces(2)), want)
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 2, sink.AllTraces()[0].SpanCount())
+	reason, _ := sink.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().Get(DeadLetterReasonAttribute)
+	assert.Equal(t, "Permanent error: bad data", reason.Str())
+	require.NoError(t, te.Shutdown(context.Background()))
+}
+
 func TestTracesRequest_Default_ConvertError(t *testing.T) {
 	td := ptrace.NewTraces()
 	want := errors.New("convert_error")
@@ -1,6 +1,7 @@
 module go.opentelemetry.io/collector/exporter/exporterprofiles
 
-go 1.22.0
+go 1.23.0
+
 toolchain go1.23.7
 
 require (
@@ -66,3 +67,5 @@ replace go.opentelemetry.io/collector/exporter => ../
 replace go.opentelemetry.io/collector/receiver/receiverprofiles => ../../receiver/receiverprofiles
 
 replace go.opentelemetry.io/collector/pipeline => ../../pipeline
+
+replace go.opentelemetry.io/collector/component/componentstatus => ../../component/componentstatus
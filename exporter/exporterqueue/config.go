@@ -5,10 +5,38 @@ package exporterqueue // import "go.opentelemetry.io/collector/exporter/exporter
 
 import (
 	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 )
 
+// SizerType defines the unit used to measure the size of the queue.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type SizerType string
+
+const (
+	// SizerTypeRequests measures the queue size as the number of requests.
+	SizerTypeRequests SizerType = "requests"
+	// SizerTypeItems measures the queue size as the number of items (spans, data points or log records)
+	// in the requests. The requests must implement `ItemsCount() int`.
+	SizerTypeItems SizerType = "items"
+	// SizerTypeBytes measures the queue size as the size of the serialized requests in bytes.
+	// The requests must implement `BytesSize() int`, see exporterhelper.RequestBytesSizer.
+	SizerTypeBytes SizerType = "bytes"
+)
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *SizerType) UnmarshalText(text []byte) error {
+	switch st := SizerType(text); st {
+	case SizerTypeRequests, SizerTypeItems, SizerTypeBytes:
+		*s = st
+		return nil
+	default:
+		return fmt.Errorf("invalid sizer %q, must be one of %q, %q or %q", st, SizerTypeRequests, SizerTypeItems, SizerTypeBytes)
+	}
+}
+
 // Config defines configuration for queueing requests before exporting.
 // It's supposed to be used with the new exporter helpers New[Traces|Metrics|Logs]RequestExporter.
 // Experimental: This API is at the early stage of development and may change without backward compatibility
@@ -18,8 +46,90 @@ type Config struct {
 	Enabled bool `mapstructure:"enabled"`
 	// NumConsumers is the number of consumers from the queue.
 	NumConsumers int `mapstructure:"num_consumers"`
-	// QueueSize is the maximum number of requests allowed in queue at any given time.
+	// QueueSize is the maximum size of the queue at any given time, measured in the units of the Sizer.
 	QueueSize int `mapstructure:"queue_size"`
+	// Sizer defines the unit of the QueueSize: requests, items or bytes. Defaults to requests.
+	Sizer SizerType `mapstructure:"sizer"`
+	// AdaptiveConcurrency if enabled, adjusts the number of consumers sending requests concurrently,
+	// starting from NumConsumers.
+	AdaptiveConcurrency AdaptiveConcurrencyConfig `mapstructure:"adaptive_concurrency"`
+}
+
+// ConcurrencyAlgorithm defines the algorithm used to adjust the number of consumers.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type ConcurrencyAlgorithm string
+
+const (
+	// ConcurrencyAlgorithmAIMD increases the number of consumers by one while the requests are successful,
+	// and decreases it by 10% when the backend throttles the requests or they time out.
+	ConcurrencyAlgorithmAIMD ConcurrencyAlgorithm = "aimd"
+	// ConcurrencyAlgorithmGradient adjusts the number of consumers to the ratio between the long term latency
+	// and the latency of the last requests, and decreases it by 10% when the backend throttles the requests
+	// or they time out.
+	ConcurrencyAlgorithmGradient ConcurrencyAlgorithm = "gradient"
+)
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *ConcurrencyAlgorithm) UnmarshalText(text []byte) error {
+	switch ca := ConcurrencyAlgorithm(text); ca {
+	case ConcurrencyAlgorithmAIMD, ConcurrencyAlgorithmGradient:
+		*a = ca
+		return nil
+	default:
+		return fmt.Errorf("invalid algorithm %q, must be one of %q or %q", ca, ConcurrencyAlgorithmAIMD, ConcurrencyAlgorithmGradient)
+	}
+}
+
+// AdaptiveConcurrencyConfig defines how the number of consumers sending requests concurrently is adjusted
+// to the latency of the requests and the throttling responses of the backend.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type AdaptiveConcurrencyConfig struct {
+	// Enabled indicates whether to adjust the number of consumers.
+	Enabled bool `mapstructure:"enabled"`
+	// Algorithm is the algorithm used to adjust the number of consumers: aimd or gradient. Defaults to aimd.
+	Algorithm ConcurrencyAlgorithm `mapstructure:"algorithm"`
+	// MinConsumers is the minimum number of consumers sending requests concurrently.
+	MinConsumers int `mapstructure:"min_consumers"`
+	// MaxConsumers is the maximum number of consumers sending requests concurrently.
+	MaxConsumers int `mapstructure:"max_consumers"`
+}
+
+// NewDefaultAdaptiveConcurrencyConfig returns the default AdaptiveConcurrencyConfig.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewDefaultAdaptiveConcurrencyConfig() AdaptiveConcurrencyConfig {
+	return AdaptiveConcurrencyConfig{
+		Enabled:      false,
+		Algorithm:    ConcurrencyAlgorithmAIMD,
+		MinConsumers: 1,
+		MaxConsumers: 100,
+	}
+}
+
+// Validate checks if the AdaptiveConcurrencyConfig is valid.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func (cfg *AdaptiveConcurrencyConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.MinConsumers <= 0 {
+		return errors.New("minimum number of consumers must be positive")
+	}
+	if cfg.MaxConsumers < cfg.MinConsumers {
+		return errors.New("maximum number of consumers must be greater than or equal to the minimum number of consumers")
+	}
+	return nil
+}
+
+// ContainsConsumers returns true if the adaptive concurrency is disabled, or if the given number of consumers
+// is between the minimum and the maximum number of consumers.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func (cfg *AdaptiveConcurrencyConfig) ContainsConsumers(numConsumers int) bool {
+	return !cfg.Enabled || (numConsumers >= cfg.MinConsumers && numConsumers <= cfg.MaxConsumers)
 }
 
 // NewDefaultConfig returns the default Config.
@@ -27,9 +137,11 @@ type Config struct {
 // until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
 func NewDefaultConfig() Config {
 	return Config{
-		Enabled:      true,
-		NumConsumers: 10,
-		QueueSize:    1_000,
+		Enabled:             true,
+		NumConsumers:        10,
+		QueueSize:           1_000,
+		Sizer:               SizerTypeRequests,
+		AdaptiveConcurrency: NewDefaultAdaptiveConcurrencyConfig(),
 	}
 }
 
@@ -44,6 +156,12 @@ func (qCfg *Config) Validate() error {
 	if qCfg.QueueSize <= 0 {
 		return errors.New("queue size must be positive")
 	}
+	if err := qCfg.AdaptiveConcurrency.Validate(); err != nil {
+		return err
+	}
+	if !qCfg.AdaptiveConcurrency.ContainsConsumers(qCfg.NumConsumers) {
+		return errors.New("number of consumers must be between the minimum and the maximum number of consumers")
+	}
 	return nil
 }
 
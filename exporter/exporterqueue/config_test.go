@@ -25,3 +25,52 @@ func TestQueueConfig_Validate(t *testing.T) {
 	qCfg.Enabled = false
 	assert.NoError(t, qCfg.Validate())
 }
+
+func TestSizerType_UnmarshalText(t *testing.T) {
+	for _, sizer := range []SizerType{SizerTypeRequests, SizerTypeItems, SizerTypeBytes} {
+		t.Run(string(sizer), func(t *testing.T) {
+			var st SizerType
+			require.NoError(t, st.UnmarshalText([]byte(sizer)))
+			assert.Equal(t, sizer, st)
+		})
+	}
+
+	var st SizerType
+	assert.EqualError(t, st.UnmarshalText([]byte("spans")), `invalid sizer "spans", must be one of "requests", "items" or "bytes"`)
+}
+
+func TestQueueConfig_ValidateAdaptiveConcurrency(t *testing.T) {
+	qCfg := NewDefaultConfig()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	require.NoError(t, qCfg.Validate())
+
+	qCfg.AdaptiveConcurrency.MinConsumers = 0
+	require.EqualError(t, qCfg.Validate(), "minimum number of consumers must be positive")
+
+	qCfg = NewDefaultConfig()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MaxConsumers = 0
+	require.EqualError(t, qCfg.Validate(), "maximum number of consumers must be greater than or equal to the minimum number of consumers")
+
+	qCfg = NewDefaultConfig()
+	qCfg.AdaptiveConcurrency.Enabled = true
+	qCfg.AdaptiveConcurrency.MinConsumers = 20
+	require.EqualError(t, qCfg.Validate(), "number of consumers must be between the minimum and the maximum number of consumers")
+
+	// Confirm Validate doesn't return error with invalid config when adaptive concurrency is disabled
+	qCfg.AdaptiveConcurrency.Enabled = false
+	assert.NoError(t, qCfg.Validate())
+}
+
+func TestConcurrencyAlgorithm_UnmarshalText(t *testing.T) {
+	for _, algorithm := range []ConcurrencyAlgorithm{ConcurrencyAlgorithmAIMD, ConcurrencyAlgorithmGradient} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			var ca ConcurrencyAlgorithm
+			require.NoError(t, ca.UnmarshalText([]byte(algorithm)))
+			assert.Equal(t, algorithm, ca)
+		})
+	}
+
+	var ca ConcurrencyAlgorithm
+	assert.EqualError(t, ca.UnmarshalText([]byte("vegas")), `invalid algorithm "vegas", must be one of "aimd" or "gradient"`)
+}
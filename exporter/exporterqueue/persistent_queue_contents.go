@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterqueue // import "go.opentelemetry.io/collector/exporter/exporterqueue"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/internal/queue"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// PersistentQueueItem is a request persisted by a persistent queue that was not sent yet.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PersistentQueueItem = queue.PersistentQueueItem
+
+// PersistentQueueContents gives offline access to the items persisted by a persistent queue, e.g. to debug
+// the queue of an exporter while the collector is stopped. It must not be used while the queue is running.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type PersistentQueueContents = queue.PersistentQueueContents
+
+// NewPersistentQueueContents reads the state of the persistent queue of the given exporter and signal
+// from the storage extension. PersistentQueueContents.Close must be called once done.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func NewPersistentQueueContents(ctx context.Context, ext storage.Extension, exporterID component.ID,
+	signal pipeline.Signal) (*PersistentQueueContents, error) {
+	client, err := ext.GetClient(ctx, component.KindExporter, exporterID, signal.String())
+	if err != nil {
+		return nil, err
+	}
+	return queue.NewPersistentQueueContents(ctx, client)
+}
@@ -50,7 +50,7 @@ type Factory[T any] func(context.Context, Settings, Config) Queue[T]
 func NewMemoryQueueFactory[T any]() Factory[T] {
 	return func(_ context.Context, _ Settings, cfg Config) Queue[T] {
 		return queue.NewBoundedMemoryQueue[T](queue.MemoryQueueSettings[T]{
-			Sizer:    &queue.RequestSizer[T]{},
+			Sizer:    newSizer[T](cfg.Sizer),
 			Capacity: int64(cfg.QueueSize),
 		})
 	}
@@ -76,7 +76,7 @@ func NewPersistentQueueFactory[T any](storageID *component.ID, factorySettings P
 	}
 	return func(_ context.Context, set Settings, cfg Config) Queue[T] {
 		return queue.NewPersistentQueue[T](queue.PersistentQueueSettings[T]{
-			Sizer:            &queue.RequestSizer[T]{},
+			Sizer:            newSizer[T](cfg.Sizer),
 			Capacity:         int64(cfg.QueueSize),
 			Signal:           set.Signal,
 			StorageID:        *storageID,
@@ -86,3 +86,15 @@ func NewPersistentQueueFactory[T any](storageID *component.ID, factorySettings P
 		})
 	}
 }
+
+// newSizer returns the queue.Sizer measuring the queue elements in the given unit.
+func newSizer[T any](sizerType SizerType) queue.Sizer[T] {
+	switch sizerType {
+	case SizerTypeItems:
+		return &queue.ItemsSizer[T]{}
+	case SizerTypeBytes:
+		return &queue.BytesSizer[T]{}
+	default:
+		return &queue.RequestSizer[T]{}
+	}
+}
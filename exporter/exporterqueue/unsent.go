@@ -0,0 +1,29 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package exporterqueue // import "go.opentelemetry.io/collector/exporter/exporterqueue"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+type unsentStorageKey struct{}
+
+// ContextWithUnsentStorage returns a context telling the exporters started or shut down with it to use the storage
+// extension with the given ID for the requests left in their memory queue once the deadline of the shutdown
+// is exceeded. The requests are persisted during the shutdown, and queued again by the next start.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func ContextWithUnsentStorage(ctx context.Context, storageID component.ID) context.Context {
+	return context.WithValue(ctx, unsentStorageKey{}, storageID)
+}
+
+// UnsentStorageFromContext returns the ID of the storage extension set by ContextWithUnsentStorage, if any.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+func UnsentStorageFromContext(ctx context.Context) (component.ID, bool) {
+	id, ok := ctx.Value(unsentStorageKey{}).(component.ID)
+	return id, ok
+}
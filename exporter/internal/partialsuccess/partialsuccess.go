@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package partialsuccess handles the partial success of the OTLP export responses, shared by the OTLP exporters.
+package partialsuccess // import "go.opentelemetry.io/collector/exporter/internal/partialsuccess"
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+// ToError returns a permanent error if some items were rejected by the backend: the other items were accepted,
+// so the request must not be retried. The message of a response without rejected items is logged as a warning.
+func ToError(logger *zap.Logger, message string, rejected int64) error {
+	if rejected == 0 {
+		if message != "" {
+			logger.Warn("Partial success response", zap.String("message", message))
+		}
+		return nil
+	}
+	if message == "" {
+		message = "rejected by the backend"
+	}
+	return consumererror.NewPermanent(consumererror.NewPartialSuccess(errors.New(message), rejected))
+}
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package partialsuccess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+)
+
+func TestToError(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	logger := zap.New(core)
+
+	require.NoError(t, ToError(logger, "", 0))
+	assert.Zero(t, logs.Len())
+
+	require.NoError(t, ToError(logger, "some warning", 0))
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "some warning", logs.All()[0].ContextMap()["message"])
+
+	err := ToError(logger, "invalid spans", 3)
+	require.EqualError(t, err, "Permanent error: Partial success, 3 items rejected: invalid spans")
+	assert.True(t, consumererror.IsPermanent(err))
+	rejected, ok := consumererror.RejectedItems(err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 3, rejected)
+
+	require.EqualError(t, ToError(logger, "", 1), "Permanent error: Partial success, 1 items rejected: rejected by the backend")
+}
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -96,6 +97,42 @@ func TestShutdownWhileNotEmpty(t *testing.T) {
 	}))
 }
 
+func TestQueueConsumersShutdownDeadline(t *testing.T) {
+	q := NewBoundedMemoryQueue[string](MemoryQueueSettings[string]{Sizer: &RequestSizer[string]{}, Capacity: 1000})
+	consumed := make(chan string, 10)
+	release := make(chan struct{})
+	qc := NewQueueConsumers(q, 1, func(_ context.Context, item string) error {
+		consumed <- item
+		<-release
+		return nil
+	})
+	require.NoError(t, qc.Start(context.Background(), componenttest.NewNopHost()))
+	for i := 0; i < 10; i++ {
+		require.NoError(t, q.Offer(context.Background(), strconv.FormatInt(int64(i), 10)))
+	}
+	assert.Equal(t, "0", <-consumed)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	shutdownDone := make(chan struct{})
+	go func() {
+		assert.NoError(t, qc.Shutdown(ctx))
+		close(shutdownDone)
+	}()
+	// Once the deadline is exceeded, the request being sent is finished before Shutdown returns.
+	assert.Eventually(t, qc.aborted.Load, time.Second, 10*time.Millisecond)
+	close(release)
+	<-shutdownDone
+
+	// The requests not read before the deadline are left in the queue.
+	assert.Empty(t, consumed)
+	var left int
+	for consume(q, func(context.Context, string) error { return nil }) {
+		left++
+	}
+	assert.Equal(t, 9, left)
+}
+
 func Benchmark_QueueUsage_1000_requests(b *testing.B) {
 	benchmarkQueueUsage(b, &RequestSizer[fakeReq]{}, 1000)
 }
@@ -106,12 +143,12 @@ func Benchmark_QueueUsage_100000_requests(b *testing.B) {
 
 func Benchmark_QueueUsage_10000_items(b *testing.B) {
 	// each request has 10 items: 1000 requests = 10000 items
-	benchmarkQueueUsage(b, &itemsSizer[fakeReq]{}, 1000)
+	benchmarkQueueUsage(b, &ItemsSizer[fakeReq]{}, 1000)
 }
 
 func Benchmark_QueueUsage_1M_items(b *testing.B) {
 	// each request has 10 items: 100000 requests = 1M items
-	benchmarkQueueUsage(b, &itemsSizer[fakeReq]{}, 100000)
+	benchmarkQueueUsage(b, &ItemsSizer[fakeReq]{}, 100000)
 }
 
 func TestQueueUsage(t *testing.T) {
@@ -119,7 +156,7 @@ func TestQueueUsage(t *testing.T) {
 		queueUsage(t, &RequestSizer[fakeReq]{}, 10)
 	})
 	t.Run("items_based", func(t *testing.T) {
-		queueUsage(t, &itemsSizer[fakeReq]{}, 10)
+		queueUsage(t, &ItemsSizer[fakeReq]{}, 10)
 	})
 }
 
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// ConcurrencyLimiter estimates the number of requests that can be sent concurrently, from the latency
+// and the outcome of the requests. Implementations don't need to be safe for concurrent use.
+type ConcurrencyLimiter interface {
+	// Limit returns the current concurrency limit.
+	Limit() int
+	// OnSample updates the limit with the outcome of a request.
+	// latency is the average latency of the attempts to send the request, inflight is the number of requests
+	// being sent when the request was started, and overloaded is true if the backend throttled the request
+	// or an attempt timed out.
+	OnSample(latency time.Duration, inflight int, overloaded bool)
+}
+
+const (
+	// aimdBackoffRatio is the ratio applied to the limit by AIMDLimiter when the backend is overloaded.
+	aimdBackoffRatio = 0.9
+
+	// gradientSmoothing is the weight of a new limit estimated by GradientLimiter.
+	gradientSmoothing = 0.2
+	// gradientLongWindow is the number of samples averaged by the long term latency of GradientLimiter.
+	gradientLongWindow = 600
+	// gradientTolerance is the ratio of the long term latency tolerated by GradientLimiter
+	// before the limit is decreased.
+	gradientTolerance = 1.5
+)
+
+// AIMDLimiter is a ConcurrencyLimiter using additive increase and multiplicative decrease: the limit
+// is increased by one after each successful request sent while the limit is used, and multiplied
+// by 0.9 when the backend is overloaded.
+type AIMDLimiter struct {
+	limit    float64
+	min, max int
+}
+
+// NewAIMDLimiter returns an AIMDLimiter with the given initial limit, bounded by minLimit and maxLimit.
+func NewAIMDLimiter(initial, minLimit, maxLimit int) *AIMDLimiter {
+	return &AIMDLimiter{limit: float64(initial), min: minLimit, max: maxLimit}
+}
+
+func (l *AIMDLimiter) Limit() int {
+	return int(l.limit)
+}
+
+func (l *AIMDLimiter) OnSample(_ time.Duration, inflight int, overloaded bool) {
+	switch {
+	case overloaded:
+		l.limit = max(math.Floor(l.limit*aimdBackoffRatio), float64(l.min))
+	case inflight*2 >= int(l.limit):
+		// Only grow the limit if it's used, it would grow unbounded when the exporter is idle otherwise.
+		l.limit = min(l.limit+1, float64(l.max))
+	}
+}
+
+// GradientLimiter is a ConcurrencyLimiter adjusting the limit to the ratio between the long term latency
+// and the latency of the last request, similar to the gradient limit of Netflix concurrency-limits.
+// The limit grows while the latency is stable, decreases when the latency increases, and is multiplied
+// by 0.9 when the backend is overloaded.
+type GradientLimiter struct {
+	limit       float64
+	min, max    int
+	longLatency float64
+	samples     int
+}
+
+// NewGradientLimiter returns a GradientLimiter with the given initial limit, bounded by minLimit and maxLimit.
+func NewGradientLimiter(initial, minLimit, maxLimit int) *GradientLimiter {
+	return &GradientLimiter{limit: float64(initial), min: minLimit, max: maxLimit}
+}
+
+func (l *GradientLimiter) Limit() int {
+	return int(l.limit)
+}
+
+func (l *GradientLimiter) OnSample(latency time.Duration, inflight int, overloaded bool) {
+	if overloaded {
+		l.limit = max(math.Floor(l.limit*aimdBackoffRatio), float64(l.min))
+		return
+	}
+
+	shortLatency := float64(max(latency, time.Microsecond))
+	// The long term latency is the average of the first samples, then an exponential moving average.
+	l.samples = min(l.samples+1, gradientLongWindow)
+	l.longLatency += (shortLatency - l.longLatency) / float64(l.samples)
+	// The long term latency slowly follows a latency that stays higher, so it doesn't drift
+	// away from the short term latency forever.
+	if l.longLatency/shortLatency > 2 {
+		l.longLatency *= 0.95
+	}
+
+	// Don't grow the limit if it's not used, it would grow unbounded when the exporter is idle otherwise.
+	if float64(inflight) < l.limit/2 {
+		return
+	}
+
+	gradient := max(0.5, min(1.0, gradientTolerance*l.longLatency/shortLatency))
+	// The square root of the limit allows some queuing in the backend and lets the limit grow.
+	newLimit := l.limit*gradient + math.Sqrt(l.limit)
+	newLimit = l.limit*(1-gradientSmoothing) + newLimit*gradientSmoothing
+	l.limit = max(float64(l.min), min(float64(l.max), newLimit))
+}
+
+type sampleKey struct{}
+
+// requestSample accumulates the outcome of the attempts to send a request consumed from the queue.
+type requestSample struct {
+	attempts   atomic.Int64
+	latency    atomic.Int64
+	overloaded atomic.Bool
+}
+
+func contextWithSample(ctx context.Context, s *requestSample) context.Context {
+	return context.WithValue(ctx, sampleKey{}, s)
+}
+
+// RecordAttempt records the outcome of an attempt to send the request consumed from the queue with the given
+// context, it's used by the adaptive concurrency of the queue consumers. overloaded is true if the backend
+// throttled the request or the attempt timed out. It's a no-op if the context is not from a queue consumer.
+func RecordAttempt(ctx context.Context, latency time.Duration, overloaded bool) {
+	s, ok := ctx.Value(sampleKey{}).(*requestSample)
+	if !ok {
+		return
+	}
+	s.attempts.Add(1)
+	s.latency.Add(int64(latency))
+	if overloaded {
+		s.overloaded.Store(true)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func TestAIMDLimiter(t *testing.T) {
+	l := NewAIMDLimiter(10, 2, 12)
+	assert.Equal(t, 10, l.Limit())
+
+	// The limit doesn't grow if it's not used.
+	l.OnSample(time.Millisecond, 1, false)
+	assert.Equal(t, 10, l.Limit())
+
+	l.OnSample(time.Millisecond, 5, false)
+	assert.Equal(t, 11, l.Limit())
+	l.OnSample(time.Millisecond, 11, false)
+	l.OnSample(time.Millisecond, 12, false)
+	assert.Equal(t, 12, l.Limit())
+
+	l.OnSample(time.Millisecond, 12, true)
+	assert.Equal(t, 10, l.Limit())
+	for i := 0; i < 20; i++ {
+		l.OnSample(time.Millisecond, 10, true)
+	}
+	assert.Equal(t, 2, l.Limit())
+}
+
+func TestGradientLimiter(t *testing.T) {
+	l := NewGradientLimiter(10, 2, 50)
+	assert.Equal(t, 10, l.Limit())
+
+	// The limit grows while the latency is stable and the limit is used.
+	for i := 0; i < 50; i++ {
+		l.OnSample(10*time.Millisecond, l.Limit(), false)
+	}
+	grown := l.Limit()
+	assert.Greater(t, grown, 10)
+
+	// The limit doesn't grow if it's not used.
+	l.OnSample(10*time.Millisecond, 1, false)
+	assert.Equal(t, grown, l.Limit())
+
+	// The limit decreases when the latency increases.
+	for i := 0; i < 10; i++ {
+		l.OnSample(100*time.Millisecond, l.Limit(), false)
+	}
+	assert.Less(t, l.Limit(), grown)
+
+	for i := 0; i < 50; i++ {
+		l.OnSample(10*time.Millisecond, l.Limit(), true)
+	}
+	assert.Equal(t, 2, l.Limit())
+}
+
+func TestRecordAttempt(t *testing.T) {
+	// No-op without a sample in the context.
+	RecordAttempt(context.Background(), time.Second, true)
+
+	s := &requestSample{}
+	ctx := contextWithSample(context.Background(), s)
+	RecordAttempt(ctx, time.Second, false)
+	RecordAttempt(ctx, 3*time.Second, true)
+	assert.EqualValues(t, 2, s.attempts.Load())
+	assert.Equal(t, int64(4*time.Second), s.latency.Load())
+	assert.True(t, s.overloaded.Load())
+}
+
+func TestAdaptiveQueueConsumers(t *testing.T) {
+	q := NewBoundedMemoryQueue[int](MemoryQueueSettings[int]{Sizer: &RequestSizer[int]{}, Capacity: 1000})
+	var overloaded atomic.Bool
+	var inflight, maxInflight atomic.Int64
+	consumers := NewAdaptiveQueueConsumers(q, 20, NewAIMDLimiter(2, 1, 20), func(ctx context.Context, _ int) error {
+		cur := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			prev := maxInflight.Load()
+			if cur <= prev || maxInflight.CompareAndSwap(prev, cur) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		RecordAttempt(ctx, time.Millisecond, overloaded.Load())
+		if overloaded.Load() {
+			return errors.New("overloaded")
+		}
+		return nil
+	})
+	require.NoError(t, consumers.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Equal(t, 2, consumers.Concurrency())
+
+	offer := func(n int) {
+		for i := 0; i < n; i++ {
+			require.NoError(t, q.Offer(context.Background(), i))
+		}
+	}
+
+	// The concurrency grows while the requests are successful.
+	offer(200)
+	assert.Eventually(t, func() bool { return q.Size() == 0 && inflight.Load() == 0 }, time.Second, time.Millisecond)
+	grown := consumers.Concurrency()
+	assert.Greater(t, grown, 2)
+	assert.LessOrEqual(t, maxInflight.Load(), int64(20))
+
+	// The concurrency decreases when the backend is overloaded.
+	overloaded.Store(true)
+	offer(50)
+	assert.Eventually(t, func() bool { return q.Size() == 0 && inflight.Load() == 0 }, time.Second, time.Millisecond)
+	assert.Less(t, consumers.Concurrency(), grown)
+
+	require.NoError(t, consumers.Shutdown(context.Background()))
+}
+
+func TestAdaptiveQueueConsumers_ShutdownDrainsQueue(t *testing.T) {
+	q := NewBoundedMemoryQueue[int](MemoryQueueSettings[int]{Sizer: &RequestSizer[int]{}, Capacity: 100})
+	var mu sync.Mutex
+	consumed := 0
+	consumers := NewAdaptiveQueueConsumers(q, 10, NewAIMDLimiter(1, 1, 10), func(context.Context, int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		consumed++
+		return nil
+	})
+	for i := 0; i < 50; i++ {
+		require.NoError(t, q.Offer(context.Background(), i))
+	}
+	require.NoError(t, consumers.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, consumers.Shutdown(context.Background()))
+	assert.Equal(t, 50, consumed)
+}
@@ -6,6 +6,8 @@ package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
 import (
 	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 )
@@ -15,6 +17,19 @@ type Consumers[T any] struct {
 	numConsumers int
 	consumeFunc  func(context.Context, T) error
 	stopWG       sync.WaitGroup
+	// aborted is set once the deadline of the shutdown is exceeded, the consumers stop reading the queue.
+	aborted atomic.Bool
+
+	// limiter if not nil, limits the number of consumers sending requests concurrently.
+	// mu guards the limiter and the counters below, cond is signaled when a consumer finishes a request.
+	limiter ConcurrencyLimiter
+	mu      sync.Mutex
+	cond    *sync.Cond
+	// reading is the number of consumers allowed to read from the queue, including the active ones.
+	reading int
+	// active is the number of consumers sending a request.
+	active  int
+	stopped bool
 }
 
 func NewQueueConsumers[T any](q Queue[T], numConsumers int, consumeFunc func(context.Context, T) error) *Consumers[T] {
@@ -26,6 +41,26 @@ func NewQueueConsumers[T any](q Queue[T], numConsumers int, consumeFunc func(con
 	}
 }
 
+// NewAdaptiveQueueConsumers returns consumers whose number of concurrent requests is adjusted by the limiter.
+// maxConsumers is the number of consumers started, it must be greater than or equal to the maximum limit.
+func NewAdaptiveQueueConsumers[T any](q Queue[T], maxConsumers int, limiter ConcurrencyLimiter,
+	consumeFunc func(context.Context, T) error) *Consumers[T] {
+	qc := NewQueueConsumers(q, maxConsumers, consumeFunc)
+	qc.limiter = limiter
+	qc.cond = sync.NewCond(&qc.mu)
+	return qc
+}
+
+// Concurrency returns the number of consumers allowed to send requests concurrently.
+func (qc *Consumers[T]) Concurrency() int {
+	if qc.limiter == nil {
+		return qc.numConsumers
+	}
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+	return qc.limiter.Limit()
+}
+
 // Start ensures that queue and all consumers are started.
 func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 	if err := qc.queue.Start(ctx, host); err != nil {
@@ -39,7 +74,13 @@ func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 		go func() {
 			startWG.Done()
 			defer qc.stopWG.Done()
-			for {
+			for !qc.aborted.Load() {
+				if qc.limiter != nil {
+					if !qc.consumeLimited() {
+						return
+					}
+					continue
+				}
 				index, ctx, req, ok := qc.queue.Read(context.Background())
 				if !ok {
 					return
@@ -54,11 +95,78 @@ func (qc *Consumers[T]) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
-// Shutdown ensures that queue and all consumers are stopped.
+// consumeLimited waits until the limit allows one more consumer to read from the queue, then consumes a request
+// and updates the limit. It returns false once the queue is stopped and empty.
+func (qc *Consumers[T]) consumeLimited() bool {
+	qc.mu.Lock()
+	for !qc.stopped && qc.reading >= qc.limiter.Limit() {
+		qc.cond.Wait()
+	}
+	if qc.aborted.Load() {
+		qc.mu.Unlock()
+		return false
+	}
+	qc.reading++
+	qc.mu.Unlock()
+
+	index, ctx, req, ok := qc.queue.Read(context.Background())
+	if !ok {
+		qc.mu.Lock()
+		qc.reading--
+		qc.mu.Unlock()
+		qc.cond.Broadcast()
+		return false
+	}
+
+	qc.mu.Lock()
+	qc.active++
+	inflight := qc.active
+	qc.mu.Unlock()
+
+	sample := &requestSample{}
+	start := time.Now()
+	consumeErr := qc.consumeFunc(contextWithSample(ctx, sample), req)
+	latency := time.Since(start)
+	qc.queue.OnProcessingFinished(index, consumeErr)
+
+	// Use the latency of the attempts if reported, it doesn't include the time spent waiting between retries.
+	if attempts := sample.attempts.Load(); attempts > 0 {
+		latency = time.Duration(sample.latency.Load() / attempts)
+	}
+	qc.mu.Lock()
+	qc.active--
+	qc.reading--
+	qc.limiter.OnSample(latency, inflight, sample.overloaded.Load())
+	qc.mu.Unlock()
+	qc.cond.Broadcast()
+	return true
+}
+
+// Shutdown ensures that queue and all consumers are stopped. The consumers drain the queue until the context
+// is done, then they only finish sending the requests already read: the requests left can be read from the queue
+// once Shutdown returns.
 func (qc *Consumers[T]) Shutdown(ctx context.Context) error {
 	if err := qc.queue.Shutdown(ctx); err != nil {
 		return err
 	}
-	qc.stopWG.Wait()
+	if qc.limiter != nil {
+		// Let all the consumers drain the queue.
+		qc.mu.Lock()
+		qc.stopped = true
+		qc.mu.Unlock()
+		qc.cond.Broadcast()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		qc.stopWG.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		qc.aborted.Store(true)
+		<-stopped
+	}
 	return nil
 }
@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue // import "go.opentelemetry.io/collector/exporter/internal/queue"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/multierr"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// PersistentQueueItem is a request persisted by a persistent queue that was not sent yet.
+type PersistentQueueItem struct {
+	// Index is the index of the item in the queue.
+	Index uint64
+	// Dispatched is true if the item was being sent when the queue was stopped.
+	// Such items are moved to the back of the queue when it's started again.
+	Dispatched bool
+	// Value is the marshaled request.
+	Value []byte
+}
+
+// PersistentQueueContents gives offline access to the items persisted by a persistent queue, e.g. to debug
+// the queue of an exporter while the collector is stopped. It must not be used while the queue is running.
+// The items are handled in the order they are sent once the queue is started again.
+type PersistentQueueContents struct {
+	client     storage.Client
+	readIndex  uint64
+	writeIndex uint64
+	dispatched []uint64
+}
+
+// NewPersistentQueueContents reads the state of the persistent queue stored with the given storage client.
+// The client is owned by the returned PersistentQueueContents, it's closed if an error is returned.
+func NewPersistentQueueContents(ctx context.Context, client storage.Client) (*PersistentQueueContents, error) {
+	pc := &PersistentQueueContents{client: client}
+	if err := pc.load(ctx); err != nil {
+		return nil, multierr.Append(err, client.Close(ctx))
+	}
+	return pc, nil
+}
+
+func (pc *PersistentQueueContents) load(ctx context.Context) error {
+	riOp := storage.GetOperation(readIndexKey)
+	wiOp := storage.GetOperation(writeIndexKey)
+	diOp := storage.GetOperation(currentlyDispatchedItemsKey)
+	err := pc.client.Batch(ctx, riOp, wiOp, diOp)
+	if err != nil {
+		return err
+	}
+	if pc.readIndex, err = bytesToItemIndex(riOp.Value); err != nil && !errors.Is(err, errValueNotSet) {
+		return err
+	}
+	if pc.writeIndex, err = bytesToItemIndex(wiOp.Value); err != nil && !errors.Is(err, errValueNotSet) {
+		return err
+	}
+	pc.dispatched, err = bytesToItemIndexArray(diOp.Value)
+	return err
+}
+
+// Close closes the storage client.
+func (pc *PersistentQueueContents) Close(ctx context.Context) error {
+	return pc.client.Close(ctx)
+}
+
+// Len returns the number of items in the queue, including the dispatched ones.
+func (pc *PersistentQueueContents) Len() int {
+	// nolint: gosec
+	return int(pc.writeIndex-pc.readIndex) + len(pc.dispatched)
+}
+
+// Items returns the items in the queue. The items that cannot be found in the storage are skipped.
+func (pc *PersistentQueueContents) Items(ctx context.Context) ([]PersistentQueueItem, error) {
+	indexes := pc.indexes()
+	ops := make([]storage.Operation, len(indexes))
+	for i, index := range indexes {
+		ops[i] = storage.GetOperation(getItemKey(index))
+	}
+	if len(ops) > 0 {
+		if err := pc.client.Batch(ctx, ops...); err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]PersistentQueueItem, 0, len(indexes))
+	pending := pc.writeIndex - pc.readIndex
+	for i, op := range ops {
+		if op.Value == nil {
+			continue
+		}
+		items = append(items, PersistentQueueItem{
+			Index:      indexes[i],
+			Dispatched: uint64(i) >= pending,
+			Value:      op.Value,
+		})
+	}
+	return items, nil
+}
+
+// RemoveFirst removes the first n items of the queue, all the items if n is greater than the queue length.
+func (pc *PersistentQueueContents) RemoveFirst(ctx context.Context, n int) error {
+	n = min(max(n, 0), pc.Len())
+	// nolint: gosec
+	pending := int(pc.writeIndex - pc.readIndex)
+	removed := pc.indexes()[:n]
+	readIndex, dispatched := pc.readIndex, pc.dispatched
+	if n <= pending {
+		// nolint: gosec
+		readIndex += uint64(n)
+	} else {
+		readIndex = pc.writeIndex
+		dispatched = dispatched[n-pending:]
+	}
+	return pc.update(ctx, readIndex, pc.writeIndex, dispatched, removed)
+}
+
+// Remove removes the items with the given indexes, e.g. the first ones returned by Items. The dispatched items can
+// be removed in any order, the other ones must be the first items of the queue: the items that cannot be found in
+// the storage before a removed item are removed too.
+func (pc *PersistentQueueContents) Remove(ctx context.Context, indexes []uint64) error {
+	removed := make(map[uint64]bool, len(indexes))
+	lastPending, hasPending := pc.readIndex, false
+	for _, index := range indexes {
+		removed[index] = true
+		if index >= pc.readIndex && index < pc.writeIndex {
+			lastPending, hasPending = max(lastPending, index), true
+		}
+	}
+
+	readIndex := pc.readIndex
+	if hasPending {
+		// The read index is moved after the leading items that are removed or missing.
+		var kept []uint64
+		var ops []storage.Operation
+		for index := pc.readIndex; index < lastPending; index++ {
+			if !removed[index] {
+				kept = append(kept, index)
+				ops = append(ops, storage.GetOperation(getItemKey(index)))
+			}
+		}
+		if len(ops) > 0 {
+			if err := pc.client.Batch(ctx, ops...); err != nil {
+				return err
+			}
+		}
+		missing := make(map[uint64]bool, len(ops))
+		for i, op := range ops {
+			if op.Value == nil {
+				missing[kept[i]] = true
+			}
+		}
+		for readIndex < pc.writeIndex && (removed[readIndex] || missing[readIndex]) {
+			readIndex++
+		}
+		if readIndex <= lastPending {
+			return fmt.Errorf("item %d cannot be removed, it is not one of the first items of the queue", lastPending)
+		}
+	}
+	dispatched := make([]uint64, 0, len(pc.dispatched))
+	for _, index := range pc.dispatched {
+		if !removed[index] {
+			dispatched = append(dispatched, index)
+		}
+	}
+	return pc.update(ctx, readIndex, pc.writeIndex, dispatched, indexes)
+}
+
+// Truncate removes the items at the back of the queue, keeping only the first n items.
+func (pc *PersistentQueueContents) Truncate(ctx context.Context, n int) error {
+	n = min(max(n, 0), pc.Len())
+	// nolint: gosec
+	pending := int(pc.writeIndex - pc.readIndex)
+	removed := pc.indexes()[n:]
+	writeIndex, dispatched := pc.writeIndex, pc.dispatched
+	if n <= pending {
+		// nolint: gosec
+		writeIndex = pc.readIndex + uint64(n)
+		dispatched = nil
+	} else {
+		dispatched = dispatched[:n-pending]
+	}
+	return pc.update(ctx, pc.readIndex, writeIndex, dispatched, removed)
+}
+
+// indexes returns the indexes of the items in the order they are sent once the queue is started again.
+func (pc *PersistentQueueContents) indexes() []uint64 {
+	indexes := make([]uint64, 0, pc.Len())
+	for index := pc.readIndex; index < pc.writeIndex; index++ {
+		indexes = append(indexes, index)
+	}
+	return append(indexes, pc.dispatched...)
+}
+
+// update persists the new state of the queue and deletes the removed items.
+func (pc *PersistentQueueContents) update(ctx context.Context, readIndex, writeIndex uint64, dispatched, removed []uint64) error {
+	ops := make([]storage.Operation, 0, len(removed)+4)
+	for _, index := range removed {
+		ops = append(ops, storage.DeleteOperation(getItemKey(index)))
+	}
+	ops = append(ops,
+		storage.SetOperation(readIndexKey, itemIndexToBytes(readIndex)),
+		storage.SetOperation(writeIndexKey, itemIndexToBytes(writeIndex)),
+		storage.SetOperation(currentlyDispatchedItemsKey, itemIndexArrayToBytes(dispatched)))
+	if writeIndex == readIndex && len(dispatched) == 0 {
+		ops = append(ops, storage.SetOperation(queueSizeKey, itemIndexToBytes(0)))
+	} else {
+		// The size of the remaining items is unknown as it depends on the sizer used by the queue.
+		// The queue falls back to the number of items when the snapshot is not available.
+		ops = append(ops, storage.DeleteOperation(queueSizeKey))
+	}
+	if err := pc.client.Batch(ctx, ops...); err != nil {
+		return err
+	}
+	pc.readIndex, pc.writeIndex, pc.dispatched = readIndex, writeIndex, dispatched
+	return nil
+}
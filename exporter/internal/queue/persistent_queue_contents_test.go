@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// createTestPersistentQueueContents fills a persistent queue with requests of 1 to 5 spans,
+// the first one being dispatched when the queue is stopped.
+func createTestPersistentQueueContents(t *testing.T, ext storage.Extension) *PersistentQueueContents {
+	pq := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+	for i := 1; i <= 5; i++ {
+		require.NoError(t, pq.Offer(context.Background(), newTracesRequest(1, i)))
+	}
+	_, _, req, found := pq.Read(context.Background())
+	require.True(t, found)
+	assert.Equal(t, 1, req.ItemsCount())
+	require.NoError(t, pq.Shutdown(context.Background()))
+
+	client, err := ext.GetClient(context.Background(), component.KindExporter, exportertest.NewNopSettings().ID, pipeline.SignalTraces.String())
+	require.NoError(t, err)
+	pc, err := NewPersistentQueueContents(context.Background(), client)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, pc.Close(context.Background()))
+	})
+	return pc
+}
+
+// itemsCounts returns the number of spans of the items in the queue.
+func itemsCounts(t *testing.T, pc *PersistentQueueContents) []int {
+	items, err := pc.Items(context.Background())
+	require.NoError(t, err)
+	var counts []int
+	for _, item := range items {
+		req, err := unmarshalTracesRequest(item.Value)
+		require.NoError(t, err)
+		counts = append(counts, req.ItemsCount())
+	}
+	return counts
+}
+
+// readAll returns the number of spans of the requests read by a persistent queue restarted on the given storage.
+func readAll(t *testing.T, ext storage.Extension) []int {
+	pq := createTestPersistentQueueWithRequestsCapacity(t, ext, 1000)
+	var counts []int
+	for pq.Size() > 0 {
+		index, _, req, found := pq.Read(context.Background())
+		require.True(t, found)
+		counts = append(counts, req.ItemsCount())
+		pq.OnProcessingFinished(index, nil)
+	}
+	require.NoError(t, pq.Shutdown(context.Background()))
+	return counts
+}
+
+func TestPersistentQueueContents_Empty(t *testing.T) {
+	client, err := NewMockStorageExtension(nil).GetClient(context.Background(), component.KindExporter, component.ID{}, "")
+	require.NoError(t, err)
+	pc, err := NewPersistentQueueContents(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, 0, pc.Len())
+	items, err := pc.Items(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	require.NoError(t, pc.RemoveFirst(context.Background(), 1))
+	require.NoError(t, pc.Truncate(context.Background(), 0))
+	assert.Equal(t, 0, pc.Len())
+	require.NoError(t, pc.Close(context.Background()))
+}
+
+func TestPersistentQueueContents_Items(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	pc := createTestPersistentQueueContents(t, ext)
+	assert.Equal(t, 5, pc.Len())
+
+	items, err := pc.Items(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, 5)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 0}, []uint64{items[0].Index, items[1].Index, items[2].Index, items[3].Index, items[4].Index})
+	assert.Equal(t, []bool{false, false, false, false, true},
+		[]bool{items[0].Dispatched, items[1].Dispatched, items[2].Dispatched, items[3].Dispatched, items[4].Dispatched})
+	// The items are listed in the order they are read by the restarted queue.
+	assert.Equal(t, []int{2, 3, 4, 5, 1}, itemsCounts(t, pc))
+	assert.Equal(t, []int{2, 3, 4, 5, 1}, readAll(t, ext))
+}
+
+func TestPersistentQueueContents_RemoveFirst(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{name: "none", n: 0, want: []int{2, 3, 4, 5, 1}},
+		{name: "pending", n: 2, want: []int{4, 5, 1}},
+		{name: "dispatched", n: 4, want: []int{1}},
+		{name: "all", n: 5, want: nil},
+		{name: "more", n: 10, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := NewMockStorageExtension(nil)
+			pc := createTestPersistentQueueContents(t, ext)
+			require.NoError(t, pc.RemoveFirst(context.Background(), tt.n))
+			assert.Equal(t, len(tt.want), pc.Len())
+			assert.Equal(t, tt.want, itemsCounts(t, pc))
+			assert.Equal(t, tt.want, readAll(t, ext))
+		})
+	}
+}
+
+func TestPersistentQueueContents_Remove(t *testing.T) {
+	tests := []struct {
+		name    string
+		missing []uint64
+		removed []uint64
+		want    []int
+		wantLen int
+	}{
+		{name: "none", want: []int{2, 3, 4, 5, 1}, wantLen: 5},
+		{name: "pending", removed: []uint64{1, 2}, want: []int{4, 5, 1}, wantLen: 3},
+		{name: "dispatched", removed: []uint64{0}, want: []int{2, 3, 4, 5}, wantLen: 4},
+		{name: "after_missing", missing: []uint64{1}, removed: []uint64{2, 3}, want: []int{5, 1}, wantLen: 2},
+		{name: "all", removed: []uint64{1, 2, 3, 4, 0}, want: nil, wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := NewMockStorageExtension(nil)
+			pc := createTestPersistentQueueContents(t, ext)
+			for _, index := range tt.missing {
+				require.NoError(t, pc.client.Delete(context.Background(), getItemKey(index)))
+			}
+			require.NoError(t, pc.Remove(context.Background(), tt.removed))
+			assert.Equal(t, tt.wantLen, pc.Len())
+			assert.Equal(t, tt.want, itemsCounts(t, pc))
+			assert.Equal(t, tt.want, readAll(t, ext))
+		})
+	}
+}
+
+func TestPersistentQueueContents_RemoveNotFirst(t *testing.T) {
+	ext := NewMockStorageExtension(nil)
+	pc := createTestPersistentQueueContents(t, ext)
+	require.EqualError(t, pc.Remove(context.Background(), []uint64{3}), "item 3 cannot be removed, it is not one of the first items of the queue")
+	assert.Equal(t, []int{2, 3, 4, 5, 1}, itemsCounts(t, pc))
+}
+
+func TestPersistentQueueContents_Truncate(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want []int
+	}{
+		{name: "all", n: 0, want: nil},
+		{name: "pending", n: 2, want: []int{2, 3}},
+		{name: "dispatched", n: 4, want: []int{2, 3, 4, 5}},
+		{name: "none", n: 5, want: []int{2, 3, 4, 5, 1}},
+		{name: "more", n: 10, want: []int{2, 3, 4, 5, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := NewMockStorageExtension(nil)
+			pc := createTestPersistentQueueContents(t, ext)
+			require.NoError(t, pc.Truncate(context.Background(), tt.n))
+			assert.Equal(t, len(tt.want), pc.Len())
+			assert.Equal(t, tt.want, itemsCounts(t, pc))
+			assert.Equal(t, tt.want, readAll(t, ext))
+		})
+	}
+}
@@ -49,3 +49,33 @@ type RequestSizer[T any] struct{}
 func (rs *RequestSizer[T]) Sizeof(T) int64 {
 	return 1
 }
+
+type itemsCounter interface {
+	ItemsCount() int
+}
+
+// ItemsSizer is a Sizer implementation that returns the size of a queue element as the number of items it contains.
+// The elements are expected to implement `ItemsCount() int`, other elements are sized as one item.
+type ItemsSizer[T any] struct{}
+
+func (is *ItemsSizer[T]) Sizeof(el T) int64 {
+	if ic, ok := any(el).(itemsCounter); ok {
+		return int64(ic.ItemsCount())
+	}
+	return 1
+}
+
+type bytesSizer interface {
+	BytesSize() int
+}
+
+// BytesSizer is a Sizer implementation that returns the size of a queue element in bytes.
+// The elements are expected to implement `BytesSize() int`, other elements are sized as one byte.
+type BytesSizer[T any] struct{}
+
+func (bs *BytesSizer[T]) Sizeof(el T) int64 {
+	if b, ok := any(el).(bytesSizer); ok {
+		return int64(b.BytesSize())
+	}
+	return 1
+}
@@ -5,6 +5,9 @@ package queue
 
 import (
 	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func consume[T any](q Queue[T], consumeFunc func(context.Context, T) error) bool {
@@ -16,3 +19,23 @@ func consume[T any](q Queue[T], consumeFunc func(context.Context, T) error) bool
 	q.OnProcessingFinished(index, consumeErr)
 	return true
 }
+
+type bytesSizedReq struct {
+	fakeReq
+	bytes int
+}
+
+func (r bytesSizedReq) BytesSize() int {
+	return r.bytes
+}
+
+func TestSizers(t *testing.T) {
+	req := bytesSizedReq{fakeReq: fakeReq{itemsCount: 10}, bytes: 1024}
+	assert.Equal(t, int64(1), (&RequestSizer[bytesSizedReq]{}).Sizeof(req))
+	assert.Equal(t, int64(10), (&ItemsSizer[bytesSizedReq]{}).Sizeof(req))
+	assert.Equal(t, int64(1024), (&BytesSizer[bytesSizedReq]{}).Sizeof(req))
+
+	// Elements that cannot report their size are sized as one unit.
+	assert.Equal(t, int64(1), (&ItemsSizer[string]{}).Sizeof("a"))
+	assert.Equal(t, int64(1), (&BytesSizer[fakeReq]{}).Sizeof(fakeReq{itemsCount: 10}))
+}
@@ -19,6 +19,16 @@ type Request interface {
 	ItemsCount() int
 }
 
+// RequestBytesSizer is an optional interface that can be implemented by Request to report its size in bytes,
+// usually the size of its serialized form. It's required to measure the sending queue in bytes.
+// Experimental: This API is at the early stage of development and may change without backward compatibility
+// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved.
+type RequestBytesSizer interface {
+	Request
+	// BytesSize returns the size of the request in bytes.
+	BytesSize() int
+}
+
 // RequestErrorHandler is an optional interface that can be implemented by Request to provide a way handle partial
 // temporary failures. For example, if some items failed to process and can be retried, this interface allows to
 // return a new Request that contains the items left to be sent. Otherwise, the original Request should be returned.
@@ -22,7 +22,10 @@ import (
 type Config struct {
 	exporterhelper.TimeoutConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 	exporterhelper.QueueConfig   `mapstructure:"sending_queue"`
-	RetryConfig                  configretry.BackOffConfig `mapstructure:"retry_on_failure"`
+	RetryConfig                  configretry.BackOffConfig           `mapstructure:"retry_on_failure"`
+	DeadLetterConfig             exporterhelper.DeadLetterConfig     `mapstructure:"dead_letter"`
+	RateLimitConfig              exporterhelper.RateLimitConfig      `mapstructure:"rate_limit"`
+	CircuitBreakerConfig         exporterhelper.CircuitBreakerConfig `mapstructure:"circuit_breaker"`
 
 	// Experimental: This configuration is at the early stage of development and may change without backward compatibility
 	// until https://github.com/open-telemetry/opentelemetry-collector/issues/8122 is resolved
@@ -32,9 +35,14 @@ type Config struct {
 }
 
 func (c *Config) Validate() error {
+	// The endpoints are validated by the configgrpc.ClientConfig.
+	if len(c.Endpoints) > 0 {
+		return nil
+	}
+
 	endpoint := c.sanitizedEndpoint()
 	if endpoint == "" {
-		return errors.New(`requires a non-empty "endpoint"`)
+		return errors.New(`requires a non-empty "endpoint" or "endpoints"`)
 	}
 
 	// Validate that the port is in the address
@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/exporter/exporterbatcher"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
 )
 
 func TestUnmarshalDefaultConfig(t *testing.T) {
@@ -31,6 +32,7 @@ func TestUnmarshalDefaultConfig(t *testing.T) {
 }
 
 func TestUnmarshalConfig(t *testing.T) {
+	fileStorageID := component.MustNewID("file_storage")
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
 	factory := NewFactory()
@@ -49,10 +51,33 @@ func TestUnmarshalConfig(t *testing.T) {
 				MaxInterval:         1 * time.Minute,
 				MaxElapsedTime:      10 * time.Minute,
 			},
+			DeadLetterConfig: exporterhelper.DeadLetterConfig{
+				Storage: &fileStorageID,
+			},
+			RateLimitConfig: exporterhelper.RateLimitConfig{
+				Enabled:        true,
+				ItemsPerSecond: 10000,
+				Burst:          2 * time.Second,
+			},
+			CircuitBreakerConfig: exporterhelper.CircuitBreakerConfig{
+				Enabled:          true,
+				FailureRatio:     0.8,
+				MinRequests:      10,
+				Interval:         time.Minute,
+				CoolDown:         time.Minute,
+				HalfOpenRequests: 1,
+			},
 			QueueConfig: exporterhelper.QueueConfig{
 				Enabled:      true,
 				NumConsumers: 2,
 				QueueSize:    10,
+				Sizer:        exporterqueue.SizerTypeBytes,
+				AdaptiveConcurrency: exporterqueue.AdaptiveConcurrencyConfig{
+					Enabled:      true,
+					Algorithm:    exporterqueue.ConcurrencyAlgorithmGradient,
+					MinConsumers: 1,
+					MaxConsumers: 10,
+				},
 			},
 			BatcherConfig: exporterbatcher.Config{
 				Enabled:      true,
@@ -100,15 +125,15 @@ func TestUnmarshalInvalidConfig(t *testing.T) {
 	}{
 		{
 			name:     "no_endpoint",
-			errorMsg: `requires a non-empty "endpoint"`,
+			errorMsg: `requires a non-empty "endpoint" or "endpoints"`,
 		},
 		{
 			name:     "https_endpoint",
-			errorMsg: `requires a non-empty "endpoint"`,
+			errorMsg: `requires a non-empty "endpoint" or "endpoints"`,
 		},
 		{
 			name:     "http_endpoint",
-			errorMsg: `requires a non-empty "endpoint"`,
+			errorMsg: `requires a non-empty "endpoint" or "endpoints"`,
 		},
 		{
 			name:     "invalid_timeout",
@@ -159,3 +184,10 @@ func TestSanitizeEndpoint(t *testing.T) {
 	cfg.Endpoint = "dns:////backend.example.com:4317"
 	assert.Equal(t, "/backend.example.com:4317", cfg.sanitizedEndpoint())
 }
+
+func TestValidEndpoints(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoints = []string{"gateway-0.example.com:4317", "gateway-1.example.com:4317"}
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
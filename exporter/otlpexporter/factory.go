@@ -34,10 +34,13 @@ func createDefaultConfig() component.Config {
 	batcherCfg.Enabled = false
 
 	return &Config{
-		TimeoutConfig: exporterhelper.NewDefaultTimeoutConfig(),
-		RetryConfig:   configretry.NewDefaultBackOffConfig(),
-		QueueConfig:   exporterhelper.NewDefaultQueueConfig(),
-		BatcherConfig: batcherCfg,
+		TimeoutConfig:        exporterhelper.NewDefaultTimeoutConfig(),
+		RetryConfig:          configretry.NewDefaultBackOffConfig(),
+		QueueConfig:          exporterhelper.NewDefaultQueueConfig(),
+		BatcherConfig:        batcherCfg,
+		DeadLetterConfig:     exporterhelper.NewDefaultDeadLetterConfig(),
+		RateLimitConfig:      exporterhelper.NewDefaultRateLimitConfig(),
+		CircuitBreakerConfig: exporterhelper.NewDefaultCircuitBreakerConfig(),
 		ClientConfig: configgrpc.ClientConfig{
 			Headers: map[string]configopaque.String{},
 			// Default to gzip compression
@@ -60,6 +63,9 @@ func createTraces(
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		exporterhelper.WithTimeout(oCfg.TimeoutConfig),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig),
 		exporterhelper.WithBatcher(oCfg.BatcherConfig),
 		exporterhelper.WithStart(oce.start),
@@ -79,6 +85,9 @@ func createMetrics(
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		exporterhelper.WithTimeout(oCfg.TimeoutConfig),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig),
 		exporterhelper.WithBatcher(oCfg.BatcherConfig),
 		exporterhelper.WithStart(oce.start),
@@ -98,6 +107,9 @@ func createLogs(
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		exporterhelper.WithTimeout(oCfg.TimeoutConfig),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig),
 		exporterhelper.WithBatcher(oCfg.BatcherConfig),
 		exporterhelper.WithStart(oce.start),
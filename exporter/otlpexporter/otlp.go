@@ -7,10 +7,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
-	"time"
 
-	"go.uber.org/zap"
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -20,7 +17,7 @@ import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
-	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/internal/partialsuccess"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -92,13 +89,7 @@ func (e *baseExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
 		return err
 	}
 	partialSuccess := resp.PartialSuccess()
-	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedSpans() == 0) {
-		e.settings.Logger.Warn("Partial success response",
-			zap.String("message", resp.PartialSuccess().ErrorMessage()),
-			zap.Int64("dropped_spans", resp.PartialSuccess().RejectedSpans()),
-		)
-	}
-	return nil
+	return partialsuccess.ToError(e.settings.Logger, partialSuccess.ErrorMessage(), partialSuccess.RejectedSpans())
 }
 
 func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
@@ -108,13 +99,7 @@ func (e *baseExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) erro
 		return err
 	}
 	partialSuccess := resp.PartialSuccess()
-	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedDataPoints() == 0) {
-		e.settings.Logger.Warn("Partial success response",
-			zap.String("message", resp.PartialSuccess().ErrorMessage()),
-			zap.Int64("dropped_data_points", resp.PartialSuccess().RejectedDataPoints()),
-		)
-	}
-	return nil
+	return partialsuccess.ToError(e.settings.Logger, partialSuccess.ErrorMessage(), partialSuccess.RejectedDataPoints())
 }
 
 func (e *baseExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
@@ -124,13 +109,7 @@ func (e *baseExporter) pushLogs(ctx context.Context, ld plog.Logs) error {
 		return err
 	}
 	partialSuccess := resp.PartialSuccess()
-	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedLogRecords() == 0) {
-		e.settings.Logger.Warn("Partial success response",
-			zap.String("message", resp.PartialSuccess().ErrorMessage()),
-			zap.Int64("dropped_log_records", resp.PartialSuccess().RejectedLogRecords()),
-		)
-	}
-	return nil
+	return partialsuccess.ToError(e.settings.Logger, partialSuccess.ErrorMessage(), partialSuccess.RejectedLogRecords())
 }
 
 func (e *baseExporter) enhanceContext(ctx context.Context) context.Context {
@@ -154,25 +133,19 @@ func processError(err error) error {
 	}
 
 	// Now, this is a real error.
-	retryInfo := getRetryInfo(st)
+	_, hasRetryInfo := configgrpc.RetryInfoDelay(st)
 
-	if !shouldRetry(st.Code(), retryInfo) {
+	if !shouldRetry(st.Code(), hasRetryInfo) {
 		// It is not a retryable error, we should not retry.
 		return consumererror.NewPermanent(err)
 	}
 
-	// Check if server returned throttling information.
-	throttleDuration := getThrottleDuration(retryInfo)
-	if throttleDuration != 0 {
-		// We are throttled. Wait before retrying as requested by the server.
-		return exporterhelper.NewThrottleRetry(err, throttleDuration)
-	}
-
-	// Need to retry.
+	// Need to retry. If the server returned throttling information, the client
+	// already turned err into a throttle error, see configgrpc.ClientConfig.ToClientConn.
 	return err
 }
 
-func shouldRetry(code codes.Code, retryInfo *errdetails.RetryInfo) bool {
+func shouldRetry(code codes.Code, hasRetryInfo bool) bool {
 	switch code {
 	case codes.Canceled,
 		codes.DeadlineExceeded,
@@ -185,27 +158,8 @@ func shouldRetry(code codes.Code, retryInfo *errdetails.RetryInfo) bool {
 	case codes.ResourceExhausted:
 		// Retry only if RetryInfo was supplied by the server.
 		// This indicates that the server can still recover from resource exhaustion.
-		return retryInfo != nil
+		return hasRetryInfo
 	}
 	// Don't retry on any other code.
 	return false
 }
-
-func getRetryInfo(status *status.Status) *errdetails.RetryInfo {
-	for _, detail := range status.Details() {
-		if t, ok := detail.(*errdetails.RetryInfo); ok {
-			return t
-		}
-	}
-	return nil
-}
-
-func getThrottleDuration(t *errdetails.RetryInfo) time.Duration {
-	if t == nil || t.RetryDelay == nil {
-		return 0
-	}
-	if t.RetryDelay.Seconds > 0 || t.RetryDelay.Nanos > 0 {
-		return time.Duration(t.RetryDelay.Seconds)*time.Second + time.Duration(t.RetryDelay.Nanos)*time.Nanosecond
-	}
-	return 0
-}
@@ -29,6 +29,7 @@ import (
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/plog"
@@ -316,8 +317,20 @@ func TestSendTraces(t *testing.T) {
 	// A request with 2 Trace entries.
 	td = testdata.GenerateTraces(2)
 
+	// The rejected items are reported, the request is not retried.
 	err = exp.ConsumeTraces(context.Background(), td)
-	require.NoError(t, err)
+	rejected, ok := consumererror.RejectedItems(err)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, rejected)
+	assert.True(t, consumererror.IsPermanent(err))
+
+	// A partial success without rejected items is only a warning.
+	rcv.setExportResponse(func() ptraceotlp.ExportResponse {
+		response := ptraceotlp.NewExportResponse()
+		response.PartialSuccess().SetErrorMessage("Some spans were modified")
+		return response
+	})
+	require.NoError(t, exp.ConsumeTraces(context.Background(), td))
 	assert.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
 	assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
 }
@@ -422,10 +435,6 @@ func TestSendMetrics(t *testing.T) {
 	set.BuildInfo.Description = "Collector"
 	set.BuildInfo.Version = "1.2.3test"
 
-	// For testing the "Partial success" warning.
-	logger, observed := observer.New(zap.DebugLevel)
-	set.TelemetrySettings.Logger = zap.New(logger)
-
 	exp, err := factory.CreateMetrics(context.Background(), set, cfg)
 	require.NoError(t, err)
 	require.NotNil(t, exp)
@@ -498,9 +507,11 @@ func TestSendMetrics(t *testing.T) {
 
 	// Send two metrics.
 	md = testdata.GenerateMetrics(2)
-	require.NoError(t, exp.ConsumeMetrics(context.Background(), md))
-	assert.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
-	assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+	err = exp.ConsumeMetrics(context.Background(), md)
+	rejected, ok := consumererror.RejectedItems(err)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, rejected)
+	assert.True(t, consumererror.IsPermanent(err))
 }
 
 func TestSendTraceDataServerDownAndUp(t *testing.T) {
@@ -716,10 +727,6 @@ func TestSendLogData(t *testing.T) {
 	set.BuildInfo.Description = "Collector"
 	set.BuildInfo.Version = "1.2.3test"
 
-	// For testing the "Partial success" warning.
-	logger, observed := observer.New(zap.DebugLevel)
-	set.TelemetrySettings.Logger = zap.New(logger)
-
 	exp, err := factory.CreateLogs(context.Background(), set, cfg)
 	require.NoError(t, err)
 	require.NotNil(t, exp)
@@ -791,7 +798,8 @@ func TestSendLogData(t *testing.T) {
 	ld = testdata.GenerateLogs(2)
 
 	err = exp.ConsumeLogs(context.Background(), ld)
-	require.NoError(t, err)
-	assert.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
-	assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+	rejected, ok := consumererror.RejectedItems(err)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, rejected)
+	assert.True(t, consumererror.IsPermanent(err))
 }
@@ -47,7 +47,10 @@ func (e *EncodingType) UnmarshalText(text []byte) error {
 type Config struct {
 	confighttp.ClientConfig    `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 	exporterhelper.QueueConfig `mapstructure:"sending_queue"`
-	RetryConfig                configretry.BackOffConfig `mapstructure:"retry_on_failure"`
+	RetryConfig                configretry.BackOffConfig           `mapstructure:"retry_on_failure"`
+	DeadLetterConfig           exporterhelper.DeadLetterConfig     `mapstructure:"dead_letter"`
+	RateLimitConfig            exporterhelper.RateLimitConfig      `mapstructure:"rate_limit"`
+	CircuitBreakerConfig       exporterhelper.CircuitBreakerConfig `mapstructure:"circuit_breaker"`
 
 	// The URL to send traces to. If omitted the Endpoint + "/v1/traces" will be used.
 	TracesEndpoint string `mapstructure:"traces_endpoint"`
@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
 )
 
 func TestUnmarshalDefaultConfig(t *testing.T) {
@@ -37,6 +38,7 @@ func TestUnmarshalConfig(t *testing.T) {
 	defaultMaxConnsPerHost := http.DefaultTransport.(*http.Transport).MaxConnsPerHost
 	defaultIdleConnTimeout := http.DefaultTransport.(*http.Transport).IdleConnTimeout
 
+	fileStorageID := component.MustNewID("file_storage")
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
 	factory := NewFactory()
@@ -52,10 +54,28 @@ func TestUnmarshalConfig(t *testing.T) {
 				MaxInterval:         1 * time.Minute,
 				MaxElapsedTime:      10 * time.Minute,
 			},
+			DeadLetterConfig: exporterhelper.DeadLetterConfig{
+				Storage: &fileStorageID,
+			},
+			RateLimitConfig: exporterhelper.RateLimitConfig{
+				Enabled:        true,
+				ItemsPerSecond: 10000,
+				Burst:          2 * time.Second,
+			},
+			CircuitBreakerConfig: exporterhelper.CircuitBreakerConfig{
+				Enabled:          true,
+				FailureRatio:     0.8,
+				MinRequests:      10,
+				Interval:         time.Minute,
+				CoolDown:         time.Minute,
+				HalfOpenRequests: 1,
+			},
 			QueueConfig: exporterhelper.QueueConfig{
-				Enabled:      true,
-				NumConsumers: 2,
-				QueueSize:    10,
+				Enabled:             true,
+				NumConsumers:        2,
+				QueueSize:           10,
+				Sizer:               exporterqueue.SizerTypeBytes,
+				AdaptiveConcurrency: exporterqueue.NewDefaultAdaptiveConcurrencyConfig(),
 			},
 			Encoding: EncodingProto,
 			ClientConfig: confighttp.ClientConfig{
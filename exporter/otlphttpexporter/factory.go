@@ -43,10 +43,13 @@ func createDefaultConfig() component.Config {
 	clientConfig.WriteBufferSize = 512 * 1024
 
 	return &Config{
-		RetryConfig:  configretry.NewDefaultBackOffConfig(),
-		QueueConfig:  exporterhelper.NewDefaultQueueConfig(),
-		Encoding:     EncodingProto,
-		ClientConfig: clientConfig,
+		RetryConfig:          configretry.NewDefaultBackOffConfig(),
+		QueueConfig:          exporterhelper.NewDefaultQueueConfig(),
+		DeadLetterConfig:     exporterhelper.NewDefaultDeadLetterConfig(),
+		RateLimitConfig:      exporterhelper.NewDefaultRateLimitConfig(),
+		CircuitBreakerConfig: exporterhelper.NewDefaultCircuitBreakerConfig(),
+		Encoding:             EncodingProto,
+		ClientConfig:         clientConfig,
 	}
 }
 
@@ -92,10 +95,14 @@ func createTraces(
 	return exporterhelper.NewTraces(ctx, set, cfg,
 		oce.pushTraces,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig))
 }
 
@@ -118,10 +125,14 @@ func createMetrics(
 	return exporterhelper.NewMetrics(ctx, set, cfg,
 		oce.pushMetrics,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig))
 }
 
@@ -143,10 +154,14 @@ func createLogs(
 	return exporterhelper.NewLogs(ctx, set, cfg,
 		oce.pushLogs,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig))
 }
 
@@ -169,9 +184,13 @@ func createProfiles(
 	return exporterhelperprofiles.NewProfilesExporter(ctx, set, cfg,
 		oce.pushProfiles,
 		exporterhelper.WithStart(oce.start),
+		exporterhelper.WithShutdown(oce.shutdown),
 		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
 		// explicitly disable since we rely on http.Client timeout logic.
 		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
 		exporterhelper.WithRetry(oCfg.RetryConfig),
+		exporterhelper.WithDeadLetter(oCfg.DeadLetterConfig),
+		exporterhelper.WithCircuitBreaker(oCfg.CircuitBreakerConfig),
+		exporterhelper.WithRateLimit(oCfg.RateLimitConfig),
 		exporterhelper.WithQueue(oCfg.QueueConfig))
 }
@@ -12,17 +12,18 @@ import (
 	"net/http"
 	"net/url"
 	"runtime"
-	"strconv"
-	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/rpc/status"
 	"google.golang.org/protobuf/proto"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/internal/partialsuccess"
 	"go.opentelemetry.io/collector/internal/httphelper"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
@@ -46,10 +47,11 @@ type baseExporter struct {
 	settings    component.TelemetrySettings
 	// Default user-agent header.
 	userAgent string
+	// cancel stops watching the TLS files of the client.
+	cancel context.CancelFunc
 }
 
 const (
-	headerRetryAfter         = "Retry-After"
 	maxHTTPResponseReadBytes = 64 * 1024
 
 	jsonContentType     = "application/json"
@@ -81,12 +83,26 @@ func newExporter(cfg component.Config, set exporter.Settings) (*baseExporter, er
 
 // start actually creates the HTTP client. The client construction is deferred till this point as this
 // is the only place we get hold of Extensions which are required to construct auth round tripper.
-func (e *baseExporter) start(ctx context.Context, host component.Host) error {
-	client, err := e.config.ClientConfig.ToClient(ctx, host, e.settings)
+func (e *baseExporter) start(_ context.Context, host component.Host) error {
+	// The start context is not used: the TLS files of the client are watched until shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
+	client, err := e.config.ClientConfig.ToClient(ctx, host, e.settings, confighttp.WithThrottleErrors())
 	if err != nil {
+		cancel()
 		return err
 	}
 	e.client = client
+	e.cancel = cancel
+	return nil
+}
+
+func (e *baseExporter) shutdown(context.Context) error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	if e.client != nil {
+		e.client.CloseIdleConnections()
+	}
 	return nil
 }
 
@@ -192,7 +208,12 @@ func (e *baseExporter) export(ctx context.Context, url string, request []byte, p
 	req.Header.Set("User-Agent", e.userAgent)
 
 	resp, err := e.client.Do(req)
-	if err != nil {
+	// The client turns the responses requesting a delay before retrying into a throttle error, which keeps the
+	// response to format the error.
+	var throttledErr *confighttp.ThrottledResponseError
+	if errors.As(err, &throttledErr) {
+		resp = throttledErr.Response
+	} else if err != nil {
 		return fmt.Errorf("failed to make an HTTP request: %w", err)
 	}
 
@@ -224,19 +245,10 @@ func (e *baseExporter) export(ctx context.Context, url string, request []byte, p
 
 	if isRetryableStatusCode(resp.StatusCode) {
 		// A retry duration of 0 seconds will trigger the default backoff policy
-		// of our caller (retry handler).
-		retryAfter := 0
-
-		// Check if the server is overwhelmed.
-		// See spec https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/protocol/otlp.md#otlphttp-throttling
-		isThrottleError := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
-		if val := resp.Header.Get(headerRetryAfter); isThrottleError && val != "" {
-			if seconds, err2 := strconv.Atoi(val); err2 == nil {
-				retryAfter = seconds
-			}
-		}
-
-		return exporterhelper.NewThrottleRetry(formattedErr, time.Duration(retryAfter)*time.Second)
+		// of our caller (retry handler). Otherwise, the server is overwhelmed and
+		// asked to wait before retrying.
+		retryAfter, _ := configretry.ThrottleDelay(err)
+		return exporterhelper.NewThrottleRetry(formattedErr, retryAfter)
 	}
 
 	return consumererror.NewPermanent(formattedErr)
@@ -348,13 +360,7 @@ func (e *baseExporter) tracesPartialSuccessHandler(protoBytes []byte, contentTyp
 	}
 
 	partialSuccess := exportResponse.PartialSuccess()
-	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedSpans() == 0) {
-		e.logger.Warn("Partial success response",
-			zap.String("message", exportResponse.PartialSuccess().ErrorMessage()),
-			zap.Int64("dropped_spans", exportResponse.PartialSuccess().RejectedSpans()),
-		)
-	}
-	return nil
+	return partialsuccess.ToError(e.logger, partialSuccess.ErrorMessage(), partialSuccess.RejectedSpans())
 }
 
 func (e *baseExporter) metricsPartialSuccessHandler(protoBytes []byte, contentType string) error {
@@ -378,13 +384,7 @@ func (e *baseExporter) metricsPartialSuccessHandler(protoBytes []byte, contentTy
 	}
 
 	partialSuccess := exportResponse.PartialSuccess()
-	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedDataPoints() == 0) {
-		e.logger.Warn("Partial success response",
-			zap.String("message", exportResponse.PartialSuccess().ErrorMessage()),
-			zap.Int64("dropped_data_points", exportResponse.PartialSuccess().RejectedDataPoints()),
-		)
-	}
-	return nil
+	return partialsuccess.ToError(e.logger, partialSuccess.ErrorMessage(), partialSuccess.RejectedDataPoints())
 }
 
 func (e *baseExporter) logsPartialSuccessHandler(protoBytes []byte, contentType string) error {
@@ -408,13 +408,7 @@ func (e *baseExporter) logsPartialSuccessHandler(protoBytes []byte, contentType
 	}
 
 	partialSuccess := exportResponse.PartialSuccess()
-	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedLogRecords() == 0) {
-		e.logger.Warn("Partial success response",
-			zap.String("message", exportResponse.PartialSuccess().ErrorMessage()),
-			zap.Int64("dropped_log_records", exportResponse.PartialSuccess().RejectedLogRecords()),
-		)
-	}
-	return nil
+	return partialsuccess.ToError(e.logger, partialSuccess.ErrorMessage(), partialSuccess.RejectedLogRecords())
 }
 
 func (e *baseExporter) profilesPartialSuccessHandler(protoBytes []byte, contentType string) error {
@@ -438,11 +432,5 @@ func (e *baseExporter) profilesPartialSuccessHandler(protoBytes []byte, contentT
 	}
 
 	partialSuccess := exportResponse.PartialSuccess()
-	if !(partialSuccess.ErrorMessage() == "" && partialSuccess.RejectedProfiles() == 0) {
-		e.logger.Warn("Partial success response",
-			zap.String("message", exportResponse.PartialSuccess().ErrorMessage()),
-			zap.Int64("dropped_samples", exportResponse.PartialSuccess().RejectedProfiles()),
-		)
-	}
-	return nil
+	return partialsuccess.ToError(e.logger, partialSuccess.ErrorMessage(), partialSuccess.RejectedProfiles())
 }
@@ -526,9 +526,6 @@ func TestPartialSuccess_logs(t *testing.T) {
 	}
 	set := exportertest.NewNopSettings()
 
-	logger, observed := observer.New(zap.DebugLevel)
-	set.TelemetrySettings.Logger = zap.New(logger)
-
 	exp, err := createLogs(context.Background(), set, cfg)
 	require.NoError(t, err)
 
@@ -542,9 +539,28 @@ func TestPartialSuccess_logs(t *testing.T) {
 	// generate data
 	logs := plog.NewLogs()
 	err = exp.ConsumeLogs(context.Background(), logs)
+	rejected, ok := consumererror.RejectedItems(err)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, rejected)
+	assert.True(t, consumererror.IsPermanent(err))
+}
+
+func TestPartialSuccess_warning(t *testing.T) {
+	cfg := createDefaultConfig()
+	set := exportertest.NewNopSettings()
+	logger, observed := observer.New(zap.DebugLevel)
+	set.TelemetrySettings.Logger = zap.New(logger)
+	exp, err := newExporter(cfg, set)
 	require.NoError(t, err)
+
+	// A partial success without rejected items is only a warning.
+	response := ptraceotlp.NewExportResponse()
+	response.PartialSuccess().SetErrorMessage("hello")
+	data, err := response.MarshalProto()
+	require.NoError(t, err)
+	require.NoError(t, exp.tracesPartialSuccessHandler(data, protobufContentType))
 	require.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
-	require.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+	assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
 }
 
 func TestPartialResponse_missingHeaderButHasBody(t *testing.T) {
@@ -614,7 +630,9 @@ func TestPartialResponse_missingHeaderButHasBody(t *testing.T) {
 					},
 				}
 				err = handlePartialSuccessResponse(resp, tt.handler)
-				assert.NoError(t, err)
+				rejected, ok := consumererror.RejectedItems(err)
+				assert.True(t, ok)
+				assert.EqualValues(t, 1, rejected)
 			})
 		}
 	}
@@ -796,8 +814,6 @@ func TestPartialSuccess_longContentLengthHeader(t *testing.T) {
 			t.Run(tt.telemetryType+" "+ct.contentType, func(t *testing.T) {
 				cfg := createDefaultConfig()
 				set := exportertest.NewNopSettings()
-				logger, observed := observer.New(zap.DebugLevel)
-				set.TelemetrySettings.Logger = zap.New(logger)
 				exp, err := newExporter(cfg, set)
 				require.NoError(t, err)
 
@@ -837,12 +853,12 @@ func TestPartialSuccess_longContentLengthHeader(t *testing.T) {
 						"Content-Type": {ct.contentType},
 					},
 				}
-				// No real error happens for long content length, so the partial
-				// success is handled as success with a warning.
+				// No real error happens for long content length, so the rejected
+				// items of the partial success are reported.
 				err = handlePartialSuccessResponse(resp, handler)
-				require.NoError(t, err)
-				assert.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
-				assert.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+				rejected, ok := consumererror.RejectedItems(err)
+				assert.True(t, ok)
+				assert.EqualValues(t, 1, rejected)
 			})
 		}
 	}
@@ -885,8 +901,6 @@ func TestPartialSuccess_traces(t *testing.T) {
 		ClientConfig:   confighttp.ClientConfig{},
 	}
 	set := exportertest.NewNopSettings()
-	logger, observed := observer.New(zap.DebugLevel)
-	set.TelemetrySettings.Logger = zap.New(logger)
 	exp, err := createTraces(context.Background(), set, cfg)
 	require.NoError(t, err)
 
@@ -900,9 +914,10 @@ func TestPartialSuccess_traces(t *testing.T) {
 	// generate data
 	traces := ptrace.NewTraces()
 	err = exp.ConsumeTraces(context.Background(), traces)
-	require.NoError(t, err)
-	require.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
-	require.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+	rejected, ok := consumererror.RejectedItems(err)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, rejected)
+	assert.True(t, consumererror.IsPermanent(err))
 }
 
 func TestPartialSuccess_metrics(t *testing.T) {
@@ -925,8 +940,6 @@ func TestPartialSuccess_metrics(t *testing.T) {
 		ClientConfig:    confighttp.ClientConfig{},
 	}
 	set := exportertest.NewNopSettings()
-	logger, observed := observer.New(zap.DebugLevel)
-	set.TelemetrySettings.Logger = zap.New(logger)
 	exp, err := createMetrics(context.Background(), set, cfg)
 	require.NoError(t, err)
 
@@ -940,9 +953,10 @@ func TestPartialSuccess_metrics(t *testing.T) {
 	// generate data
 	metrics := pmetric.NewMetrics()
 	err = exp.ConsumeMetrics(context.Background(), metrics)
-	require.NoError(t, err)
-	require.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
-	require.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+	rejected, ok := consumererror.RejectedItems(err)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, rejected)
+	assert.True(t, consumererror.IsPermanent(err))
 }
 
 func TestPartialSuccess_profiles(t *testing.T) {
@@ -966,8 +980,6 @@ func TestPartialSuccess_profiles(t *testing.T) {
 		},
 	}
 	set := exportertest.NewNopSettings()
-	logger, observed := observer.New(zap.DebugLevel)
-	set.TelemetrySettings.Logger = zap.New(logger)
 	exp, err := createProfiles(context.Background(), set, cfg)
 	require.NoError(t, err)
 
@@ -981,9 +993,10 @@ func TestPartialSuccess_profiles(t *testing.T) {
 	// generate data
 	profiles := pprofile.NewProfiles()
 	err = exp.ConsumeProfiles(context.Background(), profiles)
-	require.NoError(t, err)
-	require.Len(t, observed.FilterLevelExact(zap.WarnLevel).All(), 1)
-	require.Contains(t, observed.FilterLevelExact(zap.WarnLevel).All()[0].Message, "Partial success")
+	rejected, ok := consumererror.RejectedItems(err)
+	require.True(t, ok)
+	assert.EqualValues(t, 1, rejected)
+	assert.True(t, consumererror.IsPermanent(err))
 }
 
 func TestEncoding(t *testing.T) {
@@ -0,0 +1,538 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// The storage file is an append-only log of records. Every record holds all the write operations
+// of a single Set, Delete or Batch call, so a batch is either fully applied or not applied at all:
+//
+//	┌──────────────┬───────────────┬─────────────────────────────────────────┐
+//	│ crc32c (4 B) │ length (4 B)  │ payload (length B)                      │
+//	└──────────────┴───────────────┴─────────────────────────────────────────┘
+//
+// The payload is a sequence of operations:
+//
+//	┌─────────┬─────────────────┬─────┬───────────────────────────┬───────┐
+//	│ op (1B) │ key len uvarint │ key │ value len uvarint (set)   │ value │
+//	└─────────┴─────────────────┴─────┴───────────────────────────┴───────┘
+//
+// The values are not kept in memory, only their position in the file. When the file is opened,
+// records are replayed to rebuild the index. A torn or corrupted record at the end of the file,
+// left by a crash in the middle of a write, is truncated.
+const (
+	recordHeaderSize = 8
+
+	opSet    byte = 1
+	opDelete byte = 2
+
+	// maxRecordSize guards against allocating huge buffers when reading a corrupted length.
+	maxRecordSize = 1 << 30
+)
+
+var (
+	errClientClosed    = errors.New("storage client is closed")
+	errCorruptedRecord = errors.New("corrupted record")
+
+	crcTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// valueLocation describes where a value is stored in the file.
+type valueLocation struct {
+	offset int64
+	size   int
+}
+
+type fileClient struct {
+	path       string
+	logger     *zap.Logger
+	fsync      FsyncPolicy
+	compaction CompactionConfig
+	// onClose is called once the client is closed, it is used by the extension to release the file.
+	onClose func()
+
+	// mu guards everything declared below.
+	mu    sync.Mutex
+	file  *os.File
+	index map[string]valueLocation
+	// size is the size of the file, new records are written at this offset.
+	size int64
+	// liveSize is the number of bytes in the file used by the current values.
+	liveSize int64
+	dirty    bool
+	closed   bool
+
+	stopFsync chan struct{}
+	fsyncWG   sync.WaitGroup
+}
+
+var _ storage.Client = (*fileClient)(nil)
+
+func newFileClient(path string, cfg *Config, logger *zap.Logger, onClose func()) (*fileClient, error) {
+	c := &fileClient{
+		path:       path,
+		logger:     logger.With(zap.String("path", path)),
+		fsync:      cfg.Fsync,
+		compaction: cfg.Compaction,
+		onClose:    onClose,
+	}
+	if err := c.open(); err != nil {
+		return nil, err
+	}
+	if cfg.Compaction.OnStart {
+		if err := c.compact(); err != nil {
+			return nil, multierr.Append(fmt.Errorf("failed to compact %q: %w", path, err), c.file.Close())
+		}
+	}
+	if cfg.Fsync == FsyncInterval {
+		c.stopFsync = make(chan struct{})
+		c.fsyncWG.Add(1)
+		go c.fsyncPeriodically(cfg.FsyncInterval)
+	}
+	return c, nil
+}
+
+// open opens the storage file, rebuilds the index and truncates any incomplete trailing record.
+func (c *fileClient) open() error {
+	file, err := os.OpenFile(c.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	index, validSize, liveSize, err := replay(file)
+	if err != nil {
+		return multierr.Append(fmt.Errorf("failed to read %q: %w", c.path, err), file.Close())
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return multierr.Append(err, file.Close())
+	}
+	if info.Size() != validSize {
+		c.logger.Warn("Truncating incomplete or corrupted data at the end of the storage file",
+			zap.Int64("valid_size", validSize), zap.Int64("file_size", info.Size()))
+		if err = file.Truncate(validSize); err != nil {
+			return multierr.Append(err, file.Close())
+		}
+		if err = file.Sync(); err != nil {
+			return multierr.Append(err, file.Close())
+		}
+	}
+	c.file = file
+	c.index = index
+	c.size = validSize
+	c.liveSize = liveSize
+	return nil
+}
+
+// replay reads all the valid records from the file and returns the resulting index, the size of the valid
+// part of the file and the number of bytes used by the current values.
+func replay(file *os.File) (map[string]valueLocation, int64, int64, error) {
+	index := map[string]valueLocation{}
+	liveSize := int64(0)
+	offset := int64(0)
+	reader := bufio.NewReader(io.NewSectionReader(file, 0, 1<<63-1))
+	header := make([]byte, recordHeaderSize)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return index, offset, liveSize, nil
+			}
+			return nil, 0, 0, err
+		}
+		checksum := binary.LittleEndian.Uint32(header[0:4])
+		length := binary.LittleEndian.Uint32(header[4:8])
+		if length > maxRecordSize {
+			return index, offset, liveSize, nil
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return index, offset, liveSize, nil
+			}
+			return nil, 0, 0, err
+		}
+		if crc32.Checksum(payload, crcTable) != checksum {
+			return index, offset, liveSize, nil
+		}
+		ops, err := decodePayload(payload)
+		if err != nil {
+			return index, offset, liveSize, nil
+		}
+		payloadOffset := offset + recordHeaderSize
+		for _, op := range ops {
+			if old, ok := index[op.key]; ok {
+				liveSize -= entrySize(op.key, old.size)
+				delete(index, op.key)
+			}
+			if op.typ == opSet {
+				index[op.key] = valueLocation{offset: payloadOffset + int64(op.valueOffset), size: op.valueSize}
+				liveSize += entrySize(op.key, op.valueSize)
+			}
+		}
+		offset = payloadOffset + int64(length)
+	}
+}
+
+type decodedOp struct {
+	typ         byte
+	key         string
+	valueOffset int
+	valueSize   int
+}
+
+func decodePayload(payload []byte) ([]decodedOp, error) {
+	var ops []decodedOp
+	pos := 0
+	for pos < len(payload) {
+		typ := payload[pos]
+		pos++
+		keyLen, n := binary.Uvarint(payload[pos:])
+		if n <= 0 || keyLen > uint64(len(payload)-pos-n) {
+			return nil, errCorruptedRecord
+		}
+		pos += n
+		op := decodedOp{typ: typ, key: string(payload[pos : pos+int(keyLen)])}
+		pos += int(keyLen)
+		switch typ {
+		case opSet:
+			valueLen, m := binary.Uvarint(payload[pos:])
+			if m <= 0 || valueLen > uint64(len(payload)-pos-m) {
+				return nil, errCorruptedRecord
+			}
+			pos += m
+			op.valueOffset = pos
+			op.valueSize = int(valueLen)
+			pos += int(valueLen)
+		case opDelete:
+		default:
+			return nil, errCorruptedRecord
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// entrySize returns the number of bytes used by a set operation in the file, including the header
+// of a record dedicated to it, as written by compaction.
+func entrySize(key string, valueSize int) int64 {
+	return int64(recordHeaderSize + 1 + uvarintSize(uint64(len(key))) + len(key) + uvarintSize(uint64(valueSize)) + valueSize)
+}
+
+func uvarintSize(v uint64) int {
+	n := 1
+	for v >= 0x80 {
+		v >>= 7
+		n++
+	}
+	return n
+}
+
+// Get will retrieve data from storage that corresponds to the specified key.
+func (c *fileClient) Get(ctx context.Context, key string) ([]byte, error) {
+	op := storage.GetOperation(key)
+	if err := c.Batch(ctx, op); err != nil {
+		return nil, err
+	}
+	return op.Value, nil
+}
+
+// Set will store data. The data can be retrieved using the same key.
+func (c *fileClient) Set(ctx context.Context, key string, value []byte) error {
+	return c.Batch(ctx, storage.SetOperation(key, value))
+}
+
+// Delete will delete data associated with the specified key.
+func (c *fileClient) Delete(ctx context.Context, key string) error {
+	return c.Batch(ctx, storage.DeleteOperation(key))
+}
+
+// Batch executes the specified operations in order. All the write operations are persisted
+// in a single record, so either all or none of them survive a crash.
+func (c *fileClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errClientClosed
+	}
+
+	var payload []byte
+	// pending holds values written by the preceding operations of this batch, so that subsequent
+	// get operations observe them. A nil value represents a deleted key.
+	pending := map[string][]byte{}
+	type write struct {
+		key         string
+		deleted     bool
+		valueOffset int
+		valueSize   int
+	}
+	var writes []write
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			if val, ok := pending[op.Key]; ok {
+				op.Value = val
+				continue
+			}
+			val, err := c.read(op.Key)
+			if err != nil {
+				return err
+			}
+			op.Value = val
+		case storage.Set:
+			payload = append(payload, opSet)
+			payload = binary.AppendUvarint(payload, uint64(len(op.Key)))
+			payload = append(payload, op.Key...)
+			payload = binary.AppendUvarint(payload, uint64(len(op.Value)))
+			writes = append(writes, write{key: op.Key, valueOffset: len(payload), valueSize: len(op.Value)})
+			payload = append(payload, op.Value...)
+			// Make a copy so that the batch is not affected if the caller modifies the value afterward.
+			pending[op.Key] = append([]byte{}, op.Value...)
+		case storage.Delete:
+			payload = append(payload, opDelete)
+			payload = binary.AppendUvarint(payload, uint64(len(op.Key)))
+			payload = append(payload, op.Key...)
+			writes = append(writes, write{key: op.Key, deleted: true})
+			pending[op.Key] = nil
+		default:
+			return errors.New("wrong operation type")
+		}
+	}
+
+	if len(writes) == 0 {
+		return nil
+	}
+
+	payloadOffset, err := c.appendRecord(payload)
+	if err != nil {
+		return err
+	}
+	for _, w := range writes {
+		if old, ok := c.index[w.key]; ok {
+			c.liveSize -= entrySize(w.key, old.size)
+			delete(c.index, w.key)
+		}
+		if !w.deleted {
+			c.index[w.key] = valueLocation{offset: payloadOffset + int64(w.valueOffset), size: w.valueSize}
+			c.liveSize += entrySize(w.key, w.valueSize)
+		}
+	}
+
+	if c.shouldCompact() {
+		if err = c.compact(); err != nil {
+			// The data is already persisted, compaction will be attempted again on the next write.
+			c.logger.Warn("Failed to compact the storage file", zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// read returns the current value of the key. Callers MUST hold the mutex.
+func (c *fileClient) read(key string) ([]byte, error) {
+	loc, ok := c.index[key]
+	if !ok {
+		return nil, nil
+	}
+	val := make([]byte, loc.size)
+	if _, err := c.file.ReadAt(val, loc.offset); err != nil {
+		return nil, fmt.Errorf("failed to read value of key %q: %w", key, err)
+	}
+	return val, nil
+}
+
+// appendRecord writes the payload as a new record at the end of the file and returns the offset of the payload.
+// Callers MUST hold the mutex.
+func (c *fileClient) appendRecord(payload []byte) (int64, error) {
+	buf := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], crc32.Checksum(payload, crcTable))
+	// nolint: gosec
+	binary.LittleEndian.PutUint32(buf[4:8], uint32(len(payload)))
+	buf = append(buf, payload...)
+	if _, err := c.file.WriteAt(buf, c.size); err != nil {
+		// Drop whatever part of the record may have been written, so the next record starts at a known offset.
+		if truncErr := c.file.Truncate(c.size); truncErr != nil {
+			c.logger.Error("Failed to truncate the storage file after a failed write", zap.Error(truncErr))
+		}
+		return 0, err
+	}
+	payloadOffset := c.size + recordHeaderSize
+	c.size += int64(len(buf))
+
+	if c.fsync == FsyncAlways {
+		return payloadOffset, c.file.Sync()
+	}
+	c.dirty = true
+	return payloadOffset, nil
+}
+
+// shouldCompact reports whether the file has reached the online compaction thresholds.
+// Callers MUST hold the mutex.
+func (c *fileClient) shouldCompact() bool {
+	if !c.compaction.Online || c.size < c.compaction.MinFileSizeMiB*1024*1024 || c.size == 0 {
+		return false
+	}
+	garbage := c.size - c.liveSize
+	return float64(garbage)/float64(c.size) >= c.compaction.GarbageRatio
+}
+
+// compact rewrites the storage file keeping only the current values. The new file is written next to the
+// current one and atomically renamed over it, so a crash during compaction leaves one of the two complete files.
+// Callers MUST hold the mutex, or have exclusive access to the client.
+func (c *fileClient) compact() error {
+	tmpPath := c.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	cleanup := func(err error) error {
+		return multierr.Combine(err, tmp.Close(), os.Remove(tmpPath))
+	}
+
+	newIndex := make(map[string]valueLocation, len(c.index))
+	offset := int64(0)
+	writer := bufio.NewWriter(tmp)
+	header := make([]byte, recordHeaderSize)
+	for key := range c.index {
+		val, readErr := c.read(key)
+		if readErr != nil {
+			return cleanup(readErr)
+		}
+		payload := make([]byte, 0, entrySize(key, len(val))-recordHeaderSize)
+		payload = append(payload, opSet)
+		payload = binary.AppendUvarint(payload, uint64(len(key)))
+		payload = append(payload, key...)
+		payload = binary.AppendUvarint(payload, uint64(len(val)))
+		valueOffset := len(payload)
+		payload = append(payload, val...)
+
+		binary.LittleEndian.PutUint32(header[0:4], crc32.Checksum(payload, crcTable))
+		// nolint: gosec
+		binary.LittleEndian.PutUint32(header[4:8], uint32(len(payload)))
+		if _, err = writer.Write(header); err != nil {
+			return cleanup(err)
+		}
+		if _, err = writer.Write(payload); err != nil {
+			return cleanup(err)
+		}
+		newIndex[key] = valueLocation{offset: offset + recordHeaderSize + int64(valueOffset), size: len(val)}
+		offset += recordHeaderSize + int64(len(payload))
+	}
+	if err = writer.Flush(); err != nil {
+		return cleanup(err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return cleanup(err)
+	}
+	// Windows does not rename the files that are open, both files are closed before the rename and the storage
+	// file is opened again after it.
+	if err = tmp.Close(); err != nil {
+		return multierr.Append(err, os.Remove(tmpPath))
+	}
+	if err = c.file.Close(); err != nil {
+		c.logger.Warn("Failed to close the storage file replaced by compaction", zap.Error(err))
+	}
+	if err = os.Rename(tmpPath, c.path); err != nil {
+		// The storage file is unchanged, it's used with its current index.
+		return multierr.Combine(err, os.Remove(tmpPath), c.reopen())
+	}
+	syncDir(filepath.Dir(c.path))
+	if err = c.reopen(); err != nil {
+		return err
+	}
+
+	c.logger.Debug("Compacted the storage file", zap.Int64("old_size", c.size), zap.Int64("new_size", offset))
+	c.index = newIndex
+	c.size = offset
+	c.liveSize = offset
+	c.dirty = false
+	return nil
+}
+
+// reopen opens the storage file again after compact closed it, without reading it.
+func (c *fileClient) reopen() error {
+	file, err := os.OpenFile(c.path, os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen %q: %w", c.path, err)
+	}
+	c.file = file
+	return nil
+}
+
+// syncDir flushes the directory entries, so that a rename survives a host crash.
+// It's a best effort operation, not all the platforms support it.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
+}
+
+func (c *fileClient) fsyncPeriodically(interval time.Duration) {
+	defer c.fsyncWG.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.sync(); err != nil {
+				c.logger.Warn("Failed to sync the storage file", zap.Error(err))
+			}
+		case <-c.stopFsync:
+			return
+		}
+	}
+}
+
+// sync flushes the file to the disk if there are writes since the last flush.
+func (c *fileClient) sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || !c.dirty {
+		return nil
+	}
+	c.dirty = false
+	return c.file.Sync()
+}
+
+// Close flushes any pending writes and closes the storage file.
+func (c *fileClient) Close(context.Context) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	var err error
+	if c.dirty && c.fsync != FsyncNever {
+		err = c.file.Sync()
+	}
+	err = multierr.Append(err, c.file.Close())
+	c.mu.Unlock()
+
+	if c.stopFsync != nil {
+		close(c.stopFsync)
+		c.fsyncWG.Wait()
+	}
+	if c.onClose != nil {
+		c.onClose()
+	}
+	return err
+}
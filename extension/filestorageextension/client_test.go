@@ -0,0 +1,237 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+func newTestClient(t *testing.T, path string, modify func(*Config)) *fileClient {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Directory = filepath.Dir(path)
+	if modify != nil {
+		modify(cfg)
+	}
+	c, err := newFileClient(path, cfg, zap.NewNop(), nil)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClientOperations(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, filepath.Join(t.TempDir(), "client"), nil)
+
+	val, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	require.NoError(t, c.Set(ctx, "key", []byte("value")))
+	val, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+
+	require.NoError(t, c.Set(ctx, "key", []byte("new value")))
+	val, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new value"), val)
+
+	require.NoError(t, c.Set(ctx, "empty", []byte{}))
+	val, err = c.Get(ctx, "empty")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{}, val)
+
+	require.NoError(t, c.Delete(ctx, "key"))
+	require.NoError(t, c.Delete(ctx, "missing"))
+	val, err = c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	require.NoError(t, c.Close(ctx))
+	assert.ErrorIs(t, c.Set(ctx, "key", []byte("value")), errClientClosed)
+	assert.NoError(t, c.Close(ctx))
+}
+
+func TestClientBatch(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t, filepath.Join(t.TempDir(), "client"), nil)
+	defer func() { require.NoError(t, c.Close(ctx)) }()
+
+	require.NoError(t, c.Set(ctx, "existing", []byte("old")))
+
+	getBefore := storage.GetOperation("existing")
+	getAfterSet := storage.GetOperation("existing")
+	getAfterDelete := storage.GetOperation("other")
+	require.NoError(t, c.Batch(ctx,
+		getBefore,
+		storage.SetOperation("existing", []byte("new")),
+		getAfterSet,
+		storage.SetOperation("other", []byte("value")),
+		storage.DeleteOperation("other"),
+		getAfterDelete,
+	))
+	assert.Equal(t, []byte("old"), getBefore.Value)
+	assert.Equal(t, []byte("new"), getAfterSet.Value)
+	assert.Nil(t, getAfterDelete.Value)
+}
+
+func TestClientReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	c := newTestClient(t, path, nil)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, c.Set(ctx, fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i))))
+	}
+	for i := 0; i < 100; i += 2 {
+		require.NoError(t, c.Delete(ctx, fmt.Sprintf("key%d", i)))
+	}
+	require.NoError(t, c.Close(ctx))
+
+	c = newTestClient(t, path, nil)
+	defer func() { require.NoError(t, c.Close(ctx)) }()
+	for i := 0; i < 100; i++ {
+		val, err := c.Get(ctx, fmt.Sprintf("key%d", i))
+		require.NoError(t, err)
+		if i%2 == 0 {
+			assert.Nil(t, val)
+		} else {
+			assert.Equal(t, []byte(fmt.Sprintf("value%d", i)), val)
+		}
+	}
+}
+
+func TestClientTruncatesTornRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	c := newTestClient(t, path, nil)
+	require.NoError(t, c.Set(ctx, "first", []byte("value")))
+	validSize := c.size
+	require.NoError(t, c.Batch(ctx, storage.SetOperation("second", []byte("value")), storage.DeleteOperation("first")))
+	require.NoError(t, c.Close(ctx))
+
+	// Simulate a crash in the middle of writing the last record.
+	require.NoError(t, os.Truncate(path, validSize+recordHeaderSize+3))
+
+	c = newTestClient(t, path, nil)
+	defer func() { require.NoError(t, c.Close(ctx)) }()
+	val, err := c.Get(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+	val, err = c.Get(ctx, "second")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, validSize, info.Size())
+
+	// New records are appended after the last valid one.
+	require.NoError(t, c.Set(ctx, "third", []byte("value")))
+	val, err = c.Get(ctx, "third")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+}
+
+func TestClientIgnoresCorruptedRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	c := newTestClient(t, path, nil)
+	require.NoError(t, c.Set(ctx, "first", []byte("value")))
+	validSize := c.size
+	require.NoError(t, c.Set(ctx, "second", []byte("value")))
+	require.NoError(t, c.Close(ctx))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	c = newTestClient(t, path, nil)
+	defer func() { require.NoError(t, c.Close(ctx)) }()
+	assert.Equal(t, validSize, c.size)
+	val, err := c.Get(ctx, "second")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+}
+
+func TestClientOnlineCompaction(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	c := newTestClient(t, path, func(cfg *Config) {
+		cfg.Compaction.MinFileSizeMiB = 0
+		cfg.Compaction.GarbageRatio = 0.9
+	})
+	defer func() { require.NoError(t, c.Close(ctx)) }()
+
+	require.NoError(t, c.Set(ctx, "live", []byte("value")))
+	liveSize := c.size
+	for i := 0; i < 100; i++ {
+		require.NoError(t, c.Set(ctx, "overwritten", []byte(fmt.Sprintf("value%d", i))))
+		require.NoError(t, c.Delete(ctx, "overwritten"))
+		// The file never grows beyond the threshold, it's compacted down to the live values.
+		assert.Less(t, c.size, 20*liveSize)
+	}
+
+	val, err := c.Get(ctx, "live")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), val)
+	_, err = os.Stat(path + ".compact")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestClientCompactionOnStart(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "client")
+	c := newTestClient(t, path, nil)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, c.Set(ctx, "key", []byte(fmt.Sprintf("value%d", i))))
+	}
+	require.NoError(t, c.Set(ctx, "deleted", []byte("value")))
+	require.NoError(t, c.Delete(ctx, "deleted"))
+	sizeBefore := c.size
+	require.NoError(t, c.Close(ctx))
+
+	c = newTestClient(t, path, func(cfg *Config) { cfg.Compaction.OnStart = true })
+	defer func() { require.NoError(t, c.Close(ctx)) }()
+	assert.Equal(t, entrySize("key", len("value9")), c.size)
+	assert.Less(t, c.size, sizeBefore)
+	assert.Equal(t, c.size, c.liveSize)
+
+	val, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value9"), val)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, c.size, info.Size())
+}
+
+func TestClientFsyncPolicies(t *testing.T) {
+	for _, policy := range []FsyncPolicy{FsyncAlways, FsyncInterval, FsyncNever} {
+		t.Run(string(policy), func(t *testing.T) {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "client")
+			c := newTestClient(t, path, func(cfg *Config) { cfg.Fsync = policy })
+			require.NoError(t, c.Set(ctx, "key", []byte("value")))
+			assert.Equal(t, policy != FsyncAlways, c.dirty)
+			require.NoError(t, c.sync())
+			require.NoError(t, c.Close(ctx))
+
+			c = newTestClient(t, path, nil)
+			defer func() { require.NoError(t, c.Close(ctx)) }()
+			val, err := c.Get(ctx, "key")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("value"), val)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// FsyncPolicy defines when the data written to the storage files is flushed to the disk.
+type FsyncPolicy string
+
+const (
+	// FsyncAlways flushes the data to the disk after every write operation. This is the safest
+	// but the slowest option.
+	FsyncAlways FsyncPolicy = "always"
+	// FsyncInterval flushes the data to the disk periodically, see Config.FsyncInterval.
+	// Data written since the last flush can be lost if the host crashes, but not if the collector process crashes.
+	FsyncInterval FsyncPolicy = "interval"
+	// FsyncNever leaves flushing the data to the operating system.
+	FsyncNever FsyncPolicy = "never"
+)
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *FsyncPolicy) UnmarshalText(text []byte) error {
+	switch pol := FsyncPolicy(text); pol {
+	case FsyncAlways, FsyncInterval, FsyncNever:
+		*p = pol
+		return nil
+	default:
+		return fmt.Errorf("unsupported fsync policy %q, must be one of %q, %q or %q", pol, FsyncAlways, FsyncInterval, FsyncNever)
+	}
+}
+
+// Config defines configuration for the file storage extension.
+type Config struct {
+	// Directory is the directory where the storage files are stored. Every client gets its own file in the directory.
+	Directory string `mapstructure:"directory"`
+
+	// CreateDirectory creates the Directory (including missing parents) if it does not exist.
+	CreateDirectory bool `mapstructure:"create_directory"`
+
+	// Fsync defines when the written data is flushed to the disk. Default is "interval".
+	Fsync FsyncPolicy `mapstructure:"fsync"`
+
+	// FsyncInterval is the flush interval used by the "interval" fsync policy. Default is 1s.
+	FsyncInterval time.Duration `mapstructure:"fsync_interval"`
+
+	// Compaction configures how the space used by deleted and overwritten values is reclaimed.
+	Compaction CompactionConfig `mapstructure:"compaction"`
+}
+
+// CompactionConfig defines when the storage files are compacted.
+// Compaction rewrites a storage file keeping only the current values.
+type CompactionConfig struct {
+	// OnStart compacts every storage file when its client is created.
+	OnStart bool `mapstructure:"on_start"`
+
+	// Online compacts storage files while the collector is running, once they reach
+	// MinFileSizeMiB and GarbageRatio.
+	Online bool `mapstructure:"online"`
+
+	// GarbageRatio is the minimum ratio of the file occupied by deleted or overwritten values
+	// that triggers an online compaction. Must be in the (0, 1] range.
+	GarbageRatio float64 `mapstructure:"garbage_ratio"`
+
+	// MinFileSizeMiB is the minimum size of the file in MiB that triggers an online compaction.
+	MinFileSizeMiB int64 `mapstructure:"min_file_size_mib"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the extension configuration is valid.
+func (cfg *Config) Validate() error {
+	if cfg.Directory == "" {
+		return errors.New("\"directory\" must be specified")
+	}
+	if cfg.Fsync == FsyncInterval && cfg.FsyncInterval <= 0 {
+		return errors.New("\"fsync_interval\" must be positive when the \"interval\" fsync policy is used")
+	}
+	if cfg.Compaction.GarbageRatio <= 0 || cfg.Compaction.GarbageRatio > 1 {
+		return errors.New("\"compaction::garbage_ratio\" must be in the (0, 1] range")
+	}
+	if cfg.Compaction.MinFileSizeMiB < 0 {
+		return errors.New("\"compaction::min_file_size_mib\" must not be negative")
+	}
+	return nil
+}
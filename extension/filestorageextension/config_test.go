@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+	"go.opentelemetry.io/collector/extension/filestorageextension/internal/metadata"
+)
+
+func TestUnmarshalDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	require.NoError(t, confmap.New().Unmarshal(&cfg))
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg)
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
+
+func TestUnmarshalConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		id       component.ID
+		expected component.Config
+	}{
+		{
+			id:       component.NewID(metadata.Type),
+			expected: NewFactory().CreateDefaultConfig(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "all_settings"),
+			expected: &Config{
+				Directory:       "/var/lib/otelcol/custom",
+				CreateDirectory: true,
+				Fsync:           FsyncAlways,
+				FsyncInterval:   time.Second,
+				Compaction: CompactionConfig{
+					OnStart:        true,
+					Online:         false,
+					GarbageRatio:   0.75,
+					MinFileSizeMiB: 8,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig()
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
+
+func TestUnmarshalInvalidFsyncPolicy(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig()
+	err := confmap.NewFromStringMap(map[string]any{"fsync": "sometimes"}).Unmarshal(&cfg)
+	assert.ErrorContains(t, err, `unsupported fsync policy "sometimes"`)
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*Config)
+		expected string
+	}{
+		{
+			name:     "empty directory",
+			modify:   func(cfg *Config) { cfg.Directory = "" },
+			expected: `"directory" must be specified`,
+		},
+		{
+			name:     "invalid fsync interval",
+			modify:   func(cfg *Config) { cfg.FsyncInterval = 0 },
+			expected: `"fsync_interval" must be positive`,
+		},
+		{
+			name:     "zero garbage ratio",
+			modify:   func(cfg *Config) { cfg.Compaction.GarbageRatio = 0 },
+			expected: `"compaction::garbage_ratio" must be in the (0, 1] range`,
+		},
+		{
+			name:     "garbage ratio above one",
+			modify:   func(cfg *Config) { cfg.Compaction.GarbageRatio = 1.5 },
+			expected: `"compaction::garbage_ratio" must be in the (0, 1] range`,
+		},
+		{
+			name:     "negative min file size",
+			modify:   func(cfg *Config) { cfg.Compaction.MinFileSizeMiB = -1 },
+			expected: `"compaction::min_file_size_mib" must not be negative`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			tt.modify(cfg)
+			assert.ErrorContains(t, cfg.Validate(), tt.expected)
+		})
+	}
+}
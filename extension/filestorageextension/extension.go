@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+// escapedCharacters matches the characters that are escaped in the parts of file names. It includes the separator
+// of the parts, '_', and the escape character, '~', so that two different components never share a file.
+var escapedCharacters = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+
+type fileStorage struct {
+	cfg    *Config
+	logger *zap.Logger
+
+	// mu guards clients.
+	mu sync.Mutex
+	// clients holds the open clients by the name of their storage file.
+	clients map[string]*fileClient
+}
+
+var _ storage.Extension = (*fileStorage)(nil)
+
+func newFileStorage(cfg *Config, logger *zap.Logger) *fileStorage {
+	return &fileStorage{
+		cfg:     cfg,
+		logger:  logger,
+		clients: map[string]*fileClient{},
+	}
+}
+
+// Start ensures the storage directory exists.
+func (fs *fileStorage) Start(context.Context, component.Host) error {
+	if fs.cfg.CreateDirectory {
+		if err := os.MkdirAll(fs.cfg.Directory, 0o750); err != nil {
+			return fmt.Errorf("failed to create directory %q: %w", fs.cfg.Directory, err)
+		}
+	}
+	info, err := os.Stat(fs.cfg.Directory)
+	if err != nil {
+		return fmt.Errorf("directory %q is not accessible: %w", fs.cfg.Directory, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%q is not a directory", fs.cfg.Directory)
+	}
+	return nil
+}
+
+// Shutdown closes the clients that were not closed by the components that requested them.
+func (fs *fileStorage) Shutdown(ctx context.Context) error {
+	fs.mu.Lock()
+	clients := make([]*fileClient, 0, len(fs.clients))
+	for _, c := range fs.clients {
+		clients = append(clients, c)
+	}
+	fs.mu.Unlock()
+
+	var errs error
+	for _, c := range clients {
+		errs = multierr.Append(errs, c.Close(ctx))
+	}
+	return errs
+}
+
+// GetClient returns a client backed by a file dedicated to the given component and storage name.
+// Only one client can be open for the same component and storage name at a time.
+func (fs *fileStorage) GetClient(_ context.Context, kind component.Kind, id component.ID, storageName string) (storage.Client, error) {
+	name := fileName(kind, id, storageName)
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, ok := fs.clients[name]; ok {
+		return nil, fmt.Errorf("storage client %q is already in use", name)
+	}
+	c, err := newFileClient(filepath.Join(fs.cfg.Directory, name), fs.cfg, fs.logger, func() {
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		delete(fs.clients, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	fs.clients[name] = c
+	return c, nil
+}
+
+// fileName returns the name of the storage file, e.g. "exporter_otlp_backend_traces". The parts are joined with '_',
+// the characters of the parts that are not safe in a file name or that are the separator are escaped as "~XX",
+// XX being the hexadecimal value of each of their bytes.
+func fileName(kind component.Kind, id component.ID, storageName string) string {
+	parts := []string{strings.ToLower(kind.String()), id.Type().String(), id.Name()}
+	if storageName != "" {
+		parts = append(parts, storageName)
+	}
+	for i, part := range parts {
+		parts[i] = escapedCharacters.ReplaceAllStringFunc(part, func(s string) string {
+			var sb strings.Builder
+			for _, b := range []byte(s) {
+				fmt.Fprintf(&sb, "~%02X", b)
+			}
+			return sb.String()
+		})
+	}
+	return strings.Join(parts, "_")
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+func newTestExtension(t *testing.T, dir string) *fileStorage {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Directory = dir
+	fs := newFileStorage(cfg, zap.NewNop())
+	require.NoError(t, fs.Start(context.Background(), componenttest.NewNopHost()))
+	return fs
+}
+
+func TestStartDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "storage")
+
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Directory = dir
+	assert.ErrorContains(t, newFileStorage(cfg, zap.NewNop()).Start(context.Background(), componenttest.NewNopHost()), "is not accessible")
+
+	cfg.CreateDirectory = true
+	require.NoError(t, newFileStorage(cfg, zap.NewNop()).Start(context.Background(), componenttest.NewNopHost()))
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+
+	cfg.CreateDirectory = false
+	cfg.Directory = filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(cfg.Directory, nil, 0o600))
+	assert.ErrorContains(t, newFileStorage(cfg, zap.NewNop()).Start(context.Background(), componenttest.NewNopHost()), "is not a directory")
+}
+
+func TestGetClientNamespaces(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fs := newTestExtension(t, dir)
+
+	otlpID := component.MustNewIDWithName("otlp", "backend")
+	tracesClient, err := fs.GetClient(ctx, component.KindExporter, otlpID, "traces")
+	require.NoError(t, err)
+	logsClient, err := fs.GetClient(ctx, component.KindExporter, otlpID, "logs")
+	require.NoError(t, err)
+	receiverClient, err := fs.GetClient(ctx, component.KindReceiver, component.MustNewID("otlp"), "")
+	require.NoError(t, err)
+
+	_, err = fs.GetClient(ctx, component.KindExporter, otlpID, "traces")
+	require.ErrorContains(t, err, "already in use")
+
+	require.NoError(t, tracesClient.Set(ctx, "key", []byte("traces")))
+	require.NoError(t, logsClient.Set(ctx, "key", []byte("logs")))
+	val, err := tracesClient.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("traces"), val)
+	val, err = receiverClient.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	for _, name := range []string{"exporter_otlp_backend_traces", "exporter_otlp_backend_logs", "receiver_otlp_"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, name)
+	}
+
+	// The client can be requested again once closed.
+	require.NoError(t, tracesClient.Close(ctx))
+	tracesClient, err = fs.GetClient(ctx, component.KindExporter, otlpID, "traces")
+	require.NoError(t, err)
+	val, err = tracesClient.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("traces"), val)
+
+	// Shutdown closes the clients left open by the components.
+	require.NoError(t, fs.Shutdown(ctx))
+	assert.ErrorIs(t, tracesClient.Set(ctx, "key", nil), errClientClosed)
+	assert.ErrorIs(t, logsClient.Set(ctx, "key", nil), errClientClosed)
+	assert.ErrorIs(t, receiverClient.Batch(ctx, storage.GetOperation("key")), errClientClosed)
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "exporter_otlp_backend_traces",
+		fileName(component.KindExporter, component.MustNewIDWithName("otlp", "backend"), "traces"))
+	assert.Equal(t, "processor_batch_a~2Fb~C3~A9",
+		fileName(component.KindProcessor, component.MustNewIDWithName("batch", "a/bé"), ""))
+	assert.Equal(t, "exporter_file~5Fstorage_",
+		fileName(component.KindExporter, component.MustNewID("file_storage"), ""))
+
+	// The separator is escaped in the parts, the names of different components never collide.
+	assert.NotEqual(t,
+		fileName(component.KindExporter, component.MustNewIDWithName("otlp", "a_b"), ""),
+		fileName(component.KindExporter, component.MustNewIDWithName("otlp", "a"), "b"))
+	assert.NotEqual(t,
+		fileName(component.KindExporter, component.MustNewID("otlp"), "a~2Fb"),
+		fileName(component.KindExporter, component.MustNewID("otlp"), "a/b"))
+}
@@ -0,0 +1,42 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/filestorageextension/internal/metadata"
+)
+
+const (
+	defaultFsyncInterval  = time.Second
+	defaultGarbageRatio   = 0.5
+	defaultMinFileSizeMiB = 1
+)
+
+// NewFactory creates a factory for the file storage extension.
+func NewFactory() extension.Factory {
+	return extension.NewFactory(metadata.Type, createDefaultConfig, create, metadata.ExtensionStability)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		Directory:     defaultDirectory(),
+		Fsync:         FsyncInterval,
+		FsyncInterval: defaultFsyncInterval,
+		Compaction: CompactionConfig{
+			Online:         true,
+			GarbageRatio:   defaultGarbageRatio,
+			MinFileSizeMiB: defaultMinFileSizeMiB,
+		},
+	}
+}
+
+// create creates the extension based on this config.
+func create(_ context.Context, set extension.Settings, cfg component.Config) (extension.Extension, error) {
+	return newFileStorage(cfg.(*Config), set.TelemetrySettings.Logger), nil
+}
@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+func defaultDirectory() string {
+	return "/var/lib/otelcol/local_file_storage"
+}
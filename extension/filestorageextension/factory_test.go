@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorageextension
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/extension/extensiontest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		Directory:     defaultDirectory(),
+		Fsync:         FsyncInterval,
+		FsyncInterval: defaultFsyncInterval,
+		Compaction: CompactionConfig{
+			Online:         true,
+			GarbageRatio:   defaultGarbageRatio,
+			MinFileSizeMiB: defaultMinFileSizeMiB,
+		},
+	}, cfg)
+	assert.NoError(t, componenttest.CheckConfigStruct(cfg))
+}
+
+func TestCreate(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+
+	ext, err := NewFactory().Create(context.Background(), extensiontest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+	assert.Implements(t, (*storage.Extension)(nil), ext)
+}
@@ -0,0 +1,15 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package filestorageextension // import "go.opentelemetry.io/collector/extension/filestorageextension"
+
+import (
+	"os"
+	"path/filepath"
+)
+
+func defaultDirectory() string {
+	return filepath.Join(os.Getenv("ProgramData"), "Otelcol", "LocalFileStorage")
+}
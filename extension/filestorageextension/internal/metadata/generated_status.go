@@ -0,0 +1,16 @@
+// Code generated by mdatagen. DO NOT EDIT.
+
+package metadata
+
+import (
+	"go.opentelemetry.io/collector/component"
+)
+
+var (
+	Type      = component.MustNewType("local_file_storage")
+	ScopeName = "go.opentelemetry.io/collector/extension/filestorageextension"
+)
+
+const (
+	ExtensionStability = component.StabilityLevelAlpha
+)
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"reflect"
 	"sync/atomic"
 	"syscall"
 
@@ -84,6 +85,8 @@ type CollectorSettings struct {
 //   Collector can be shutdown if parser gets a shutdown error.
 // - Run runs runAndWaitForShutdownEvent and waits for a shutdown event.
 //   SIGINT and SIGTERM, errors, and (*Collector).Shutdown can trigger the shutdown events.
+// - On SIGHUP or a config change, reloadConfiguration applies the updated config. If it fails,
+//   the collector rolls back to the last config applied successfully, kept in col.config.
 // - Upon shutdown, pipelines are notified, then pipelines and extensions are shut down.
 // - Users can call (*Collector).Shutdown anytime to shut down the collector.
 
@@ -93,6 +96,7 @@ type Collector struct {
 
 	configProvider ConfigProvider
 
+	config        *Config
 	serviceConfig *service.Config
 	service       *service.Service
 	state         *atomic.Int64
@@ -159,28 +163,76 @@ func (col *Collector) Shutdown() {
 func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 	col.setCollectorState(StateStarting)
 
+	factories, cfg, err := col.loadConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+	return col.setupService(ctx, factories, cfg)
+}
+
+// loadConfiguration initializes the factories, and gets and validates the config.
+func (col *Collector) loadConfiguration(ctx context.Context) (Factories, *Config, error) {
 	factories, err := col.set.Factories()
 	if err != nil {
-		return fmt.Errorf("failed to initialize factories: %w", err)
+		return Factories{}, nil, fmt.Errorf("failed to initialize factories: %w", err)
 	}
 	cfg, err := col.configProvider.Get(ctx, factories)
 	if err != nil {
-		return fmt.Errorf("failed to get config: %w", err)
+		return Factories{}, nil, fmt.Errorf("failed to get config: %w", err)
 	}
 
 	if err = cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+		return Factories{}, nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	return factories, cfg, nil
+}
 
+// setupService creates the service of the config and starts it.
+func (col *Collector) setupService(ctx context.Context, factories Factories, cfg *Config) error {
 	col.serviceConfig = &cfg.Service
 
+	set, err := col.serviceSettings(factories, cfg)
+	if err != nil {
+		return err
+	}
+	col.service, err = service.New(ctx, set, cfg.Service)
+	if err != nil {
+		return err
+	}
+	if col.updateConfigProviderLogger != nil {
+		col.updateConfigProviderLogger(col.service.Logger().Core())
+	}
+	if col.bc != nil {
+		x := col.bc.TakeLogs()
+		for _, log := range x {
+			ce := col.service.Logger().Core().Check(log.Entry, nil)
+			if ce != nil {
+				ce.Write(log.Context...)
+			}
+		}
+	}
+
+	if !col.set.SkipSettingGRPCLogger {
+		grpclog.SetLogger(col.service.Logger(), cfg.Service.Telemetry.Logs.Level)
+	}
+
+	if err = col.service.Start(ctx); err != nil {
+		return multierr.Combine(err, col.service.Shutdown(ctx))
+	}
+	col.config = cfg
+	col.setCollectorState(StateRunning)
+
+	return nil
+}
+
+func (col *Collector) serviceSettings(factories Factories, cfg *Config) (service.Settings, error) {
 	conf := confmap.New()
 
-	if err = conf.Marshal(cfg); err != nil {
-		return fmt.Errorf("could not marshal configuration: %w", err)
+	if err := conf.Marshal(cfg); err != nil {
+		return service.Settings{}, fmt.Errorf("could not marshal configuration: %w", err)
 	}
 
-	col.service, err = service.New(ctx, service.Settings{
+	return service.Settings{
 		BuildInfo:     col.set.BuildInfo,
 		CollectorConf: conf,
 
@@ -204,50 +256,89 @@ func (col *Collector) setupConfigurationComponents(ctx context.Context) error {
 		},
 		AsyncErrorChannel: col.asyncErrorChannel,
 		LoggingOptions:    col.set.LoggingOptions,
-	}, cfg.Service)
+	}, nil
+}
+
+// reloadConfiguration applies the updated config. If only the pipelines and the configuration of their components
+// changed, the changed components are replaced while the others keep running. Otherwise, the service is restarted.
+// If the updated config cannot be loaded, or its components cannot be built or started, the collector rolls back to
+// the last config applied successfully. An error is returned only if the rollback fails.
+func (col *Collector) reloadConfiguration(ctx context.Context) error {
+	factories, cfg, err := col.loadConfiguration(ctx)
 	if err != nil {
-		return err
-	}
-	if col.updateConfigProviderLogger != nil {
-		col.updateConfigProviderLogger(col.service.Logger().Core())
+		// The running service is not changed.
+		col.service.ReportConfigRollback(ctx, err)
+		return nil
 	}
-	if col.bc != nil {
-		x := col.bc.TakeLogs()
-		for _, log := range x {
-			ce := col.service.Logger().Core().Check(log.Entry, nil)
-			if ce != nil {
-				ce.Write(log.Context...)
+
+	if col.config != nil && onlyPipelinesChanged(col.config, cfg) {
+		col.service.Logger().Info("Config updated, reload pipelines")
+		if err = col.reloadPipelines(ctx, factories, cfg); err != nil {
+			if errors.Is(err, service.ErrReloadAborted) {
+				// The running service still runs the pipelines of the last config applied successfully.
+				col.service.ReportConfigRollback(ctx, err)
+				return nil
 			}
+			return col.rollbackConfiguration(ctx, factories, fmt.Errorf("failed to reload pipelines: %w", err))
 		}
+		return nil
 	}
 
-	if !col.set.SkipSettingGRPCLogger {
-		grpclog.SetLogger(col.service.Logger(), cfg.Service.Telemetry.Logs.Level)
+	col.service.Logger().Warn("Config updated, restart service")
+	col.setCollectorState(StateClosing)
+
+	if shutdownErr := col.service.Shutdown(ctx); shutdownErr != nil {
+		return fmt.Errorf("failed to shutdown the retiring config: %w", shutdownErr)
 	}
 
-	if err = col.service.Start(ctx); err != nil {
-		return multierr.Combine(err, col.service.Shutdown(ctx))
+	col.setCollectorState(StateStarting)
+	if err = col.setupService(ctx, factories, cfg); err != nil {
+		return col.rollbackConfiguration(ctx, factories, fmt.Errorf("failed to setup configuration components: %w", err))
 	}
-	col.setCollectorState(StateRunning)
 
 	return nil
 }
 
-func (col *Collector) reloadConfiguration(ctx context.Context) error {
-	col.service.Logger().Warn("Config updated, restart service")
-	col.setCollectorState(StateClosing)
-
-	if err := col.service.Shutdown(ctx); err != nil {
-		return fmt.Errorf("failed to shutdown the retiring config: %w", err)
+// rollbackConfiguration starts a service with the last config applied successfully, once the service of the
+// updated config failed and was shut down.
+func (col *Collector) rollbackConfiguration(ctx context.Context, factories Factories, err error) error {
+	if col.config == nil {
+		return err
 	}
-
-	if err := col.setupConfigurationComponents(ctx); err != nil {
-		return fmt.Errorf("failed to setup configuration components: %w", err)
+	col.setCollectorState(StateStarting)
+	if rollbackErr := col.setupService(ctx, factories, col.config); rollbackErr != nil {
+		return multierr.Combine(err, fmt.Errorf("failed to roll back to the last applied config: %w", rollbackErr))
 	}
+	col.service.ReportConfigRollback(ctx, err)
+	return nil
+}
 
+// reloadPipelines replaces the pipelines of the running service with the ones of the config. If they cannot be
+// replaced, the service is shut down unless it still runs the previous pipelines, see service.ErrReloadAborted.
+func (col *Collector) reloadPipelines(ctx context.Context, factories Factories, cfg *Config) error {
+	set, err := col.serviceSettings(factories, cfg)
+	if err != nil {
+		return err
+	}
+	if err = col.service.Reload(ctx, set, cfg.Service); err != nil {
+		if errors.Is(err, service.ErrReloadAborted) {
+			return err
+		}
+		return multierr.Combine(err, col.service.Shutdown(ctx))
+	}
+	col.config = cfg
+	col.serviceConfig = &cfg.Service
 	return nil
 }
 
+// onlyPipelinesChanged returns true if the configs differ only by their pipelines, and the receivers,
+// processors, exporters and connectors used by them.
+func onlyPipelinesChanged(oldCfg, newCfg *Config) bool {
+	oldService, newService := oldCfg.Service, newCfg.Service
+	oldService.Pipelines, newService.Pipelines = nil, nil
+	return reflect.DeepEqual(oldCfg.Extensions, newCfg.Extensions) && reflect.DeepEqual(oldService, newService)
+}
+
 func (col *Collector) DryRun(ctx context.Context) error {
 	factories, err := col.set.Factories()
 	if err != nil {
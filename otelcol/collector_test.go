@@ -7,6 +7,7 @@ package otelcol
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -17,6 +18,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 	"gopkg.in/yaml.v3"
 
 	"go.opentelemetry.io/collector/component"
@@ -116,6 +119,171 @@ func TestCollectorStateAfterConfigChange(t *testing.T) {
 	assert.Equal(t, StateClosed, col.GetState())
 }
 
+func TestCollectorReloadConfiguration(t *testing.T) {
+	const cfgTemplate = `
+receivers:
+  nop:
+processors:
+  nop:
+exporters:
+  nop:
+service:
+  telemetry:
+    metrics:
+      level: none
+    logs:
+      level: %s
+  pipelines:
+    metrics:
+      receivers: [nop]
+      processors: [%s]
+      exporters: [nop]
+`
+	tests := []struct {
+		name           string
+		newConfig      string
+		serviceRestart bool
+	}{
+		{
+			name:           "pipelines_changed",
+			newConfig:      fmt.Sprintf(cfgTemplate, "info", ""),
+			serviceRestart: false,
+		},
+		{
+			name:           "telemetry_changed",
+			newConfig:      fmt.Sprintf(cfgTemplate, "debug", "nop"),
+			serviceRestart: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(cfgFile, []byte(fmt.Sprintf(cfgTemplate, "info", "nop")), 0o600))
+
+			watcher := make(chan error)
+			col, err := NewCollector(CollectorSettings{
+				BuildInfo:              component.NewDefaultBuildInfo(),
+				Factories:              nopFactories,
+				ConfigProviderSettings: newDefaultConfigProviderSettings(t, []string{cfgFile}),
+			})
+			require.NoError(t, err)
+			provider, err := NewConfigProvider(newDefaultConfigProviderSettings(t, []string{cfgFile}))
+			require.NoError(t, err)
+			col.configProvider = &mockCfgProvider{ConfigProvider: provider, watcher: watcher}
+
+			wg := startCollector(context.Background(), t, col)
+			assert.Eventually(t, func() bool {
+				return StateRunning == col.GetState()
+			}, 2*time.Second, 200*time.Millisecond)
+			srv := col.service
+
+			require.NoError(t, os.WriteFile(cfgFile, []byte(tt.newConfig), 0o600))
+			watcher <- nil
+			assert.Eventually(t, func() bool {
+				return StateRunning == col.GetState()
+			}, 2*time.Second, 200*time.Millisecond)
+
+			col.Shutdown()
+			wg.Wait()
+			assert.Equal(t, StateClosed, col.GetState())
+			assert.Equal(t, tt.serviceRestart, srv != col.service)
+		})
+	}
+}
+
+func TestCollectorReloadConfigurationRollback(t *testing.T) {
+	const cfgTemplate = `
+receivers:
+  nop:
+  nop_logs:
+processors:
+  nop:
+exporters:
+  nop:
+service:
+  telemetry:
+    metrics:
+      level: none
+    logs:
+      level: %s
+  pipelines:
+    metrics:
+      receivers: [%s]
+      processors: [%s]
+      exporters: [nop]
+`
+	tests := []struct {
+		name           string
+		newConfig      string
+		serviceRestart bool
+	}{
+		{
+			name:           "invalid_config",
+			newConfig:      fmt.Sprintf(cfgTemplate, "info", "nop", "unknown"),
+			serviceRestart: false,
+		},
+		{
+			// The nop_logs receiver does not support metrics, the pipelines cannot be built and the running
+			// pipelines are kept.
+			name:           "pipelines_reload_failed",
+			newConfig:      fmt.Sprintf(cfgTemplate, "info", "nop_logs", "nop"),
+			serviceRestart: false,
+		},
+		{
+			name:           "service_restart_failed",
+			newConfig:      fmt.Sprintf(cfgTemplate, "debug", "nop_logs", "nop"),
+			serviceRestart: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(cfgFile, []byte(fmt.Sprintf(cfgTemplate, "info", "nop", "nop")), 0o600))
+
+			watcher := make(chan error)
+			core, observed := observer.New(zapcore.ErrorLevel)
+			col, err := NewCollector(CollectorSettings{
+				BuildInfo:              component.NewDefaultBuildInfo(),
+				Factories:              nopFactories,
+				ConfigProviderSettings: newDefaultConfigProviderSettings(t, []string{cfgFile}),
+				LoggingOptions: []zap.Option{zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+					return zapcore.NewTee(c, core)
+				})},
+			})
+			require.NoError(t, err)
+			provider, err := NewConfigProvider(newDefaultConfigProviderSettings(t, []string{cfgFile}))
+			require.NoError(t, err)
+			col.configProvider = &mockCfgProvider{ConfigProvider: provider, watcher: watcher}
+
+			wg := startCollector(context.Background(), t, col)
+			assert.Eventually(t, func() bool {
+				return StateRunning == col.GetState()
+			}, 2*time.Second, 200*time.Millisecond)
+			srv := col.service
+			cfg := col.config
+			rollbacks := func() int {
+				return observed.FilterMessage("Failed to apply the updated config, rolled back to the last applied config").Len()
+			}
+
+			require.NoError(t, os.WriteFile(cfgFile, []byte(tt.newConfig), 0o600))
+			// The collector keeps running the last applied config, and handles the next update.
+			watcher <- nil
+			assert.Eventually(t, func() bool { return rollbacks() == 1 }, 2*time.Second, 10*time.Millisecond)
+			assert.Equal(t, StateRunning, col.GetState())
+			assert.Same(t, cfg, col.config)
+			assert.Equal(t, tt.serviceRestart, srv != col.service)
+
+			watcher <- nil
+			assert.Eventually(t, func() bool { return rollbacks() == 2 }, 2*time.Second, 10*time.Millisecond)
+			assert.Equal(t, StateRunning, col.GetState())
+
+			col.Shutdown()
+			wg.Wait()
+			assert.Equal(t, StateClosed, col.GetState())
+		})
+	}
+}
+
 func TestCollectorReportError(t *testing.T) {
 	col, err := NewCollector(CollectorSettings{
 		BuildInfo:              component.NewDefaultBuildInfo(),
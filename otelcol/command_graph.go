@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol // import "go.opentelemetry.io/collector/otelcol"
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"go.opentelemetry.io/collector/service"
+)
+
+// newGraphSubCommand constructs a new graph sub command using the given CollectorSettings.
+func newGraphSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.Command {
+	var format string
+	graphCmd := &cobra.Command{
+		Use:   "graph",
+		Short: "Writes the graph of the pipelines of the config to the standard output",
+		Long: "Writes the graph of the pipelines of the config to the standard output, with the kind, the component ID, " +
+			"the pipelines and the capabilities of each node. The components are created but not started. " +
+			"The output format is not stable and can change between releases.",
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := updateSettingsUsingFlags(&set, flagSet); err != nil {
+				return err
+			}
+			col, err := NewCollector(set)
+			if err != nil {
+				return err
+			}
+			return col.writePipelineGraph(cmd.Context(), cmd.OutOrStdout(), format)
+		},
+	}
+	graphCmd.Flags().AddGoFlagSet(flagSet)
+	graphCmd.Flags().StringVar(&format, "format", "dot", "Output format, `dot` for Graphviz or json")
+	return graphCmd
+}
+
+// writePipelineGraph validates the config and writes the graph of its pipelines to w in the given format.
+func (col *Collector) writePipelineGraph(ctx context.Context, w io.Writer, format string) error {
+	factories, err := col.set.Factories()
+	if err != nil {
+		return fmt.Errorf("failed to initialize factories: %w", err)
+	}
+	cfg, err := col.configProvider.Get(ctx, factories)
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+	if err = cfg.Validate(); err != nil {
+		return err
+	}
+	set, err := col.serviceSettings(factories, cfg)
+	if err != nil {
+		return err
+	}
+	return service.WritePipelineGraph(ctx, w, format, set, cfg.Service)
+}
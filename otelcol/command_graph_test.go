@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/featuregate"
+)
+
+func TestGraphSubCommandNoConfig(t *testing.T) {
+	cmd := newGraphSubCommand(CollectorSettings{Factories: nopFactories}, flags(featuregate.GlobalRegistry()))
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "at least one config flag must be provided")
+}
+
+func TestGraphSubCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "dot",
+			expected: `"receiver|traces|nop" -> "capabilities|traces";`,
+		},
+		{
+			name:     "json",
+			args:     []string{"--format", "json"},
+			expected: `"id": "processor|traces|nop"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newGraphSubCommand(newGraphTestSettings(t, "otelcol-nop.yaml"), flags(featuregate.GlobalRegistry()))
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetArgs(tt.args)
+			require.NoError(t, cmd.Execute())
+			assert.Contains(t, out.String(), tt.expected)
+		})
+	}
+}
+
+func TestGraphSubCommandInvalidConfig(t *testing.T) {
+	cmd := newGraphSubCommand(newGraphTestSettings(t, "otelcol-invalid-components.yaml"), flags(featuregate.GlobalRegistry()))
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "unknown type: \"nosuchprocessor\"")
+}
+
+func TestGraphSubCommandInvalidFormat(t *testing.T) {
+	cmd := newGraphSubCommand(newGraphTestSettings(t, "otelcol-nop.yaml"), flags(featuregate.GlobalRegistry()))
+	cmd.SetArgs([]string{"--format", "svg"})
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "unsupported graph format \"svg\"")
+}
+
+func newGraphTestSettings(t *testing.T, file string) CollectorSettings {
+	filePath := filepath.Join("testdata", file)
+	fileProvider := newFakeProvider("file", func(_ context.Context, _ string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+		return confmap.NewRetrieved(newConfFromFile(t, filePath))
+	})
+	return CollectorSettings{Factories: nopFactories, ConfigProviderSettings: ConfigProviderSettings{
+		ResolverSettings: confmap.ResolverSettings{
+			URIs:              []string{filePath},
+			ProviderFactories: []confmap.ProviderFactory{fileProvider},
+			DefaultScheme:     "file",
+		},
+	}}
+}
@@ -0,0 +1,362 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol // import "go.opentelemetry.io/collector/otelcol"
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/metric"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/pipeline/pipelineprofiles"
+)
+
+// queueStorageKey is the key of the storage extension ID in the config of the exporters using exporterhelper.
+const queueStorageKey = "sending_queue::storage"
+
+type queueFlags struct {
+	exporter string
+	signal   string
+	storage  string
+}
+
+// newQueueSubCommand constructs a new queue sub command using the given CollectorSettings.
+func newQueueSubCommand(set CollectorSettings, flagSet *flag.FlagSet) *cobra.Command {
+	qf := &queueFlags{}
+	queueCmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Inspects the persistent sending queue of an exporter",
+		Long: "Inspects the persistent sending queue of an exporter. The collector must be stopped while the queue is inspected. " +
+			"The storage extension is created from the config, the requests are expected to be encoded as OTLP protobuf. " +
+			"The output format is not stable and can change between releases.",
+	}
+	queueCmd.PersistentFlags().AddGoFlagSet(flagSet)
+	queueCmd.PersistentFlags().StringVar(&qf.exporter, "exporter", "", "ID of the exporter owning the queue, e.g. `otlp/backend`")
+	queueCmd.PersistentFlags().StringVar(&qf.signal, "signal", "", "Signal of the queue, all the signals of the pipelines using the exporter by default")
+	queueCmd.PersistentFlags().StringVar(&qf.storage, "storage", "", "ID of the storage extension, the exporter `sending_queue::storage` setting by default")
+
+	queueCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Lists the requests in the queue",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "SIGNAL\tINDEX\tSTATE\tBYTES\tITEMS")
+			err := withPersistentQueues(cmd.Context(), set, flagSet, qf, func(signal pipeline.Signal, pc *exporterqueue.PersistentQueueContents) error {
+				items, err := pc.Items(cmd.Context())
+				if err != nil {
+					return err
+				}
+				for _, item := range items {
+					state := "pending"
+					if item.Dispatched {
+						state = "dispatched"
+					}
+					count := "-"
+					if n, _, err := decodeQueueItem(signal, item.Value); err == nil {
+						count = strconv.Itoa(n)
+					}
+					fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\n", signal, item.Index, state, len(item.Value), count)
+				}
+				return nil
+			})
+			return multierr.Append(err, w.Flush())
+		},
+	})
+
+	queueCmd.AddCommand(&cobra.Command{
+		Use:   "dump",
+		Short: "Writes the requests in the queue to the standard output as OTLP JSON, one request per line",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withPersistentQueues(cmd.Context(), set, flagSet, qf, func(signal pipeline.Signal, pc *exporterqueue.PersistentQueueContents) error {
+				_, err := dumpQueueItems(cmd.Context(), cmd.OutOrStdout(), signal, pc, pc.Len())
+				return err
+			})
+		},
+	})
+
+	var drainCount int
+	drainCmd := &cobra.Command{
+		Use:   "drain",
+		Short: "Removes the first requests from the queue and writes them to the standard output as OTLP JSON",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withPersistentQueues(cmd.Context(), set, flagSet, qf, func(signal pipeline.Signal, pc *exporterqueue.PersistentQueueContents) error {
+				n := pc.Len()
+				if drainCount >= 0 {
+					n = min(n, drainCount)
+				}
+				// Only the requests written to the output are removed.
+				dumped, err := dumpQueueItems(cmd.Context(), cmd.OutOrStdout(), signal, pc, n)
+				return multierr.Append(err, pc.Remove(cmd.Context(), dumped))
+			})
+		},
+	}
+	drainCmd.Flags().IntVar(&drainCount, "count", -1, "Number of requests to drain, all the requests by default")
+	queueCmd.AddCommand(drainCmd)
+
+	var truncateKeep int
+	truncateCmd := &cobra.Command{
+		Use:   "truncate",
+		Short: "Removes the last requests from the queue",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return withPersistentQueues(cmd.Context(), set, flagSet, qf, func(signal pipeline.Signal, pc *exporterqueue.PersistentQueueContents) error {
+				removed := max(pc.Len()-truncateKeep, 0)
+				if err := pc.Truncate(cmd.Context(), truncateKeep); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Removed %d requests from the %s queue\n", removed, signal)
+				return nil
+			})
+		},
+	}
+	truncateCmd.Flags().IntVar(&truncateKeep, "keep", 0, "Number of requests to keep at the front of the queue")
+	queueCmd.AddCommand(truncateCmd)
+
+	return queueCmd
+}
+
+// withPersistentQueues calls fn with the persistent queue of the exporter for each of the selected signals.
+func withPersistentQueues(ctx context.Context, set CollectorSettings, flagSet *flag.FlagSet, qf *queueFlags,
+	fn func(signal pipeline.Signal, pc *exporterqueue.PersistentQueueContents) error) error {
+	if err := updateSettingsUsingFlags(&set, flagSet); err != nil {
+		return err
+	}
+	factories, err := set.Factories()
+	if err != nil {
+		return fmt.Errorf("failed to initialize factories: %w", err)
+	}
+	configProvider, err := NewConfigProvider(set.ConfigProviderSettings)
+	if err != nil {
+		return err
+	}
+	cfg, err := configProvider.Get(ctx, factories)
+	if err != nil {
+		return fmt.Errorf("failed to get config: %w", err)
+	}
+
+	if qf.exporter == "" {
+		return errors.New("the exporter must be provided")
+	}
+	var exporterID component.ID
+	if err = exporterID.UnmarshalText([]byte(qf.exporter)); err != nil {
+		return fmt.Errorf("invalid exporter ID: %w", err)
+	}
+	exporterCfg, found := cfg.Exporters[exporterID]
+	if !found {
+		return fmt.Errorf("exporter %q is not configured", exporterID)
+	}
+	storageID, err := queueStorageID(qf.storage, exporterCfg)
+	if err != nil {
+		return err
+	}
+	signals, err := queueSignals(qf.signal, exporterID, cfg)
+	if err != nil {
+		return err
+	}
+
+	ext, err := createQueueStorage(ctx, set, factories, cfg, storageID)
+	if err != nil {
+		return err
+	}
+	host := &queueHost{extensions: map[component.ID]component.Component{storageID: ext}}
+	if err = ext.Start(ctx, host); err != nil {
+		return fmt.Errorf("failed to start storage extension %q: %w", storageID, err)
+	}
+	for _, signal := range signals {
+		if err = withPersistentQueue(ctx, ext, exporterID, signal, fn); err != nil {
+			break
+		}
+	}
+	return multierr.Append(err, ext.Shutdown(ctx))
+}
+
+func withPersistentQueue(ctx context.Context, ext storage.Extension, exporterID component.ID, signal pipeline.Signal,
+	fn func(signal pipeline.Signal, pc *exporterqueue.PersistentQueueContents) error) error {
+	pc, err := exporterqueue.NewPersistentQueueContents(ctx, ext, exporterID, signal)
+	if err != nil {
+		return fmt.Errorf("failed to read the %s queue: %w", signal, err)
+	}
+	return multierr.Append(fn(signal, pc), pc.Close(ctx))
+}
+
+// queueStorageID returns the ID of the storage extension of the queue,
+// from the flag or from the `sending_queue::storage` exporter setting.
+func queueStorageID(flagValue string, exporterCfg component.Config) (component.ID, error) {
+	var storageID component.ID
+	if flagValue != "" {
+		if err := storageID.UnmarshalText([]byte(flagValue)); err != nil {
+			return storageID, fmt.Errorf("invalid storage ID: %w", err)
+		}
+		return storageID, nil
+	}
+	conf := confmap.New()
+	if err := conf.Marshal(exporterCfg); err != nil {
+		return storageID, err
+	}
+	value, ok := conf.Get(queueStorageKey).(string)
+	if !ok || value == "" {
+		return storageID, errors.New("the exporter has no persistent queue, the storage must be provided")
+	}
+	if err := storageID.UnmarshalText([]byte(value)); err != nil {
+		return storageID, fmt.Errorf("invalid storage ID: %w", err)
+	}
+	return storageID, nil
+}
+
+// queueSignals returns the signal from the flag, or the signals of the pipelines using the exporter.
+func queueSignals(flagValue string, exporterID component.ID, cfg *Config) ([]pipeline.Signal, error) {
+	if flagValue != "" {
+		for _, signal := range []pipeline.Signal{pipeline.SignalTraces, pipeline.SignalMetrics, pipeline.SignalLogs, pipelineprofiles.SignalProfiles} {
+			if signal.String() == flagValue {
+				return []pipeline.Signal{signal}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signal %q", flagValue)
+	}
+	var signals []pipeline.Signal
+	for pipelineID, pipelineCfg := range cfg.Service.Pipelines {
+		if slices.Contains(pipelineCfg.Exporters, exporterID) && !slices.Contains(signals, pipelineID.Signal()) {
+			signals = append(signals, pipelineID.Signal())
+		}
+	}
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("exporter %q is not used by any pipeline", exporterID)
+	}
+	slices.SortFunc(signals, func(a, b pipeline.Signal) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	return signals, nil
+}
+
+// createQueueStorage creates the storage extension with the given ID from the config.
+func createQueueStorage(ctx context.Context, set CollectorSettings, factories Factories, cfg *Config,
+	storageID component.ID) (storage.Extension, error) {
+	extCfg, found := cfg.Extensions[storageID]
+	if !found {
+		return nil, fmt.Errorf("storage extension %q is not configured", storageID)
+	}
+	factory, found := factories.Extensions[storageID.Type()]
+	if !found {
+		return nil, fmt.Errorf("storage extension factory %q is not available", storageID.Type())
+	}
+	ext, err := factory.CreateExtension(ctx, extension.Settings{
+		ID:                storageID,
+		TelemetrySettings: nopTelemetrySettings(),
+		BuildInfo:         set.BuildInfo,
+	}, extCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage extension %q: %w", storageID, err)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("extension %q is not a storage extension", storageID)
+	}
+	return storageExt, nil
+}
+
+// dumpQueueItems writes the first n items of the queue to w as OTLP JSON and returns the indexes of the items written.
+func dumpQueueItems(ctx context.Context, w io.Writer, signal pipeline.Signal, pc *exporterqueue.PersistentQueueContents, n int) ([]uint64, error) {
+	items, err := pc.Items(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var dumped []uint64
+	for _, item := range items[:min(n, len(items))] {
+		_, buf, err := decodeQueueItem(signal, item.Value)
+		if err != nil {
+			return dumped, fmt.Errorf("failed to decode the %s request %d: %w", signal, item.Index, err)
+		}
+		if _, err = w.Write(append(buf, '\n')); err != nil {
+			return dumped, err
+		}
+		dumped = append(dumped, item.Index)
+	}
+	return dumped, nil
+}
+
+// decodeQueueItem decodes a request encoded as OTLP protobuf,
+// it returns the number of items in the request and the request encoded as OTLP JSON.
+func decodeQueueItem(signal pipeline.Signal, buf []byte) (int, []byte, error) {
+	switch signal {
+	case pipeline.SignalTraces:
+		td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(buf)
+		if err != nil {
+			return 0, nil, err
+		}
+		out, err := (&ptrace.JSONMarshaler{}).MarshalTraces(td)
+		return td.SpanCount(), out, err
+	case pipeline.SignalMetrics:
+		md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(buf)
+		if err != nil {
+			return 0, nil, err
+		}
+		out, err := (&pmetric.JSONMarshaler{}).MarshalMetrics(md)
+		return md.DataPointCount(), out, err
+	case pipeline.SignalLogs:
+		ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(buf)
+		if err != nil {
+			return 0, nil, err
+		}
+		out, err := (&plog.JSONMarshaler{}).MarshalLogs(ld)
+		return ld.LogRecordCount(), out, err
+	case pipelineprofiles.SignalProfiles:
+		pd, err := (&pprofile.ProtoUnmarshaler{}).UnmarshalProfiles(buf)
+		if err != nil {
+			return 0, nil, err
+		}
+		out, err := (&pprofile.JSONMarshaler{}).MarshalProfiles(pd)
+		return pd.SampleCount(), out, err
+	default:
+		return 0, nil, fmt.Errorf("unsupported signal %q", signal)
+	}
+}
+
+func nopTelemetrySettings() component.TelemetrySettings {
+	return component.TelemetrySettings{
+		Logger:         zap.NewNop(),
+		TracerProvider: nooptrace.NewTracerProvider(),
+		MeterProvider:  noopmetric.NewMeterProvider(),
+		LeveledMeterProvider: func(configtelemetry.Level) metric.MeterProvider {
+			return noopmetric.NewMeterProvider()
+		},
+		MetricsLevel: configtelemetry.LevelNone,
+		Resource:     pcommon.NewResource(),
+	}
+}
+
+// queueHost is the host of the storage extension created to inspect the queues.
+type queueHost struct {
+	extensions map[component.ID]component.Component
+}
+
+func (h *queueHost) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
@@ -0,0 +1,322 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcol
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/extension"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+var (
+	queueExporterID = component.MustNewID("queue")
+	fakeStorageID   = component.MustNewID("fake_storage")
+)
+
+type queueExporterConfig struct {
+	QueueConfig exporterhelper.QueueConfig `mapstructure:"sending_queue"`
+}
+
+// fakeStorage is a storage extension keeping the data in memory, shared by all the clients.
+type fakeStorage struct {
+	component.StartFunc
+	component.ShutdownFunc
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (fs *fakeStorage) GetClient(_ context.Context, kind component.Kind, id component.ID, name string) (storage.Client, error) {
+	return &fakeStorageClient{storage: fs, prefix: kind.String() + "/" + id.String() + "/" + name + "/"}, nil
+}
+
+type fakeStorageClient struct {
+	storage *fakeStorage
+	prefix  string
+}
+
+func (c *fakeStorageClient) Get(ctx context.Context, key string) ([]byte, error) {
+	op := storage.GetOperation(key)
+	err := c.Batch(ctx, op)
+	return op.Value, err
+}
+
+func (c *fakeStorageClient) Set(ctx context.Context, key string, value []byte) error {
+	return c.Batch(ctx, storage.SetOperation(key, value))
+}
+
+func (c *fakeStorageClient) Delete(ctx context.Context, key string) error {
+	return c.Batch(ctx, storage.DeleteOperation(key))
+}
+
+func (c *fakeStorageClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	c.storage.mu.Lock()
+	defer c.storage.mu.Unlock()
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			op.Value = c.storage.data[c.prefix+op.Key]
+		case storage.Set:
+			c.storage.data[c.prefix+op.Key] = op.Value
+		case storage.Delete:
+			delete(c.storage.data, c.prefix+op.Key)
+		}
+	}
+	return nil
+}
+
+func (c *fakeStorageClient) Close(context.Context) error {
+	return nil
+}
+
+// queueFactories returns the nop factories, a storage extension factory returning the given storage
+// and an exporter factory with a sending queue config.
+func queueFactories(fs *fakeStorage) func() (Factories, error) {
+	return func() (Factories, error) {
+		factories, err := nopFactories()
+		if err != nil {
+			return Factories{}, err
+		}
+		factories.Extensions[fakeStorageID.Type()] = extension.NewFactory(fakeStorageID.Type(),
+			func() component.Config { return &struct{}{} },
+			func(context.Context, extension.Settings, component.Config) (extension.Extension, error) {
+				return fs, nil
+			},
+			component.StabilityLevelDevelopment)
+		factories.Exporters[queueExporterID.Type()] = exporter.NewFactory(queueExporterID.Type(),
+			func() component.Config {
+				return &queueExporterConfig{QueueConfig: exporterhelper.NewDefaultQueueConfig()}
+			})
+		return factories, nil
+	}
+}
+
+// fillQueue writes the requests to the persistent queue of the queue exporter for the given signal.
+func fillQueue[T any](t *testing.T, fs *fakeStorage, signal pipeline.Signal, marshaler exporterqueue.Marshaler[T], reqs ...T) {
+	qf := exporterqueue.NewPersistentQueueFactory[T](&fakeStorageID, exporterqueue.PersistentQueueSettings[T]{
+		Marshaler:   marshaler,
+		Unmarshaler: func([]byte) (T, error) { var req T; return req, nil },
+	})
+	set := exportertest.NewNopSettings()
+	set.ID = queueExporterID
+	q := qf(context.Background(), exporterqueue.Settings{Signal: signal, ExporterSettings: set}, exporterqueue.NewDefaultConfig())
+	require.NoError(t, q.Start(context.Background(), &queueHost{extensions: map[component.ID]component.Component{fakeStorageID: fs}}))
+	for _, req := range reqs {
+		require.NoError(t, q.Offer(context.Background(), req))
+	}
+	require.NoError(t, q.Shutdown(context.Background()))
+}
+
+func newTestTraces(spans int) ptrace.Traces {
+	td := ptrace.NewTraces()
+	ss := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty()
+	for i := 0; i < spans; i++ {
+		ss.Spans().AppendEmpty().SetName("span")
+	}
+	return td
+}
+
+func newTestLogs(records int) plog.Logs {
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	for i := 0; i < records; i++ {
+		sl.LogRecords().AppendEmpty().Body().SetStr("log")
+	}
+	return ld
+}
+
+func newTestFakeStorage(t *testing.T) *fakeStorage {
+	fs := &fakeStorage{data: map[string][]byte{}}
+	fillQueue(t, fs, pipeline.SignalTraces, (&ptrace.ProtoMarshaler{}).MarshalTraces, newTestTraces(1), newTestTraces(2))
+	fillQueue(t, fs, pipeline.SignalLogs, (&plog.ProtoMarshaler{}).MarshalLogs, newTestLogs(3))
+	return fs
+}
+
+// runQueueSubCommand runs the queue sub command with the given arguments and returns its output.
+func runQueueSubCommand(t *testing.T, fs *fakeStorage, args ...string) (string, error) {
+	filePath := filepath.Join("testdata", "otelcol-queue.yaml")
+	fileProvider := newFakeProvider("file", func(_ context.Context, _ string, _ confmap.WatcherFunc) (*confmap.Retrieved, error) {
+		return confmap.NewRetrieved(newConfFromFile(t, filePath))
+	})
+	cmd := newQueueSubCommand(CollectorSettings{Factories: queueFactories(fs), ConfigProviderSettings: ConfigProviderSettings{
+		ResolverSettings: confmap.ResolverSettings{
+			URIs:              []string{filePath},
+			ProviderFactories: []confmap.ProviderFactory{fileProvider},
+			DefaultScheme:     "file",
+		},
+	}}, flags(featuregate.NewRegistry()))
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestQueueSubCommandList(t *testing.T) {
+	fs := newTestFakeStorage(t)
+	out, err := runQueueSubCommand(t, fs, "list", "--exporter", "queue")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"SIGNAL", "INDEX", "STATE", "BYTES", "ITEMS"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"logs", "0", "pending"}, strings.Fields(lines[1])[:3])
+	assert.Equal(t, "3", strings.Fields(lines[1])[4])
+	assert.Equal(t, []string{"traces", "0", "pending"}, strings.Fields(lines[2])[:3])
+	assert.Equal(t, "1", strings.Fields(lines[2])[4])
+	assert.Equal(t, []string{"traces", "1", "pending"}, strings.Fields(lines[3])[:3])
+	assert.Equal(t, "2", strings.Fields(lines[3])[4])
+
+	out, err = runQueueSubCommand(t, fs, "list", "--exporter", "queue", "--signal", "logs")
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(out), "\n"), 2)
+}
+
+func TestQueueSubCommandDump(t *testing.T) {
+	fs := newTestFakeStorage(t)
+	out, err := runQueueSubCommand(t, fs, "dump", "--exporter", "queue", "--signal", "traces")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(line))
+		require.NoError(t, err)
+		assert.Equal(t, i+1, td.SpanCount())
+	}
+
+	// The queue is not modified.
+	out, err = runQueueSubCommand(t, fs, "dump", "--exporter", "queue", "--signal", "traces")
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(out), "\n"), 2)
+}
+
+func TestQueueSubCommandDrain(t *testing.T) {
+	fs := newTestFakeStorage(t)
+	out, err := runQueueSubCommand(t, fs, "drain", "--exporter", "queue", "--signal", "traces", "--count", "1")
+	require.NoError(t, err)
+	td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(strings.TrimSpace(out)))
+	require.NoError(t, err)
+	assert.Equal(t, 1, td.SpanCount())
+
+	out, err = runQueueSubCommand(t, fs, "drain", "--exporter", "queue")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	ld, err := (&plog.JSONUnmarshaler{}).UnmarshalLogs([]byte(lines[0]))
+	require.NoError(t, err)
+	assert.Equal(t, 3, ld.LogRecordCount())
+	td, err = (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(lines[1]))
+	require.NoError(t, err)
+	assert.Equal(t, 2, td.SpanCount())
+
+	out, err = runQueueSubCommand(t, fs, "dump", "--exporter", "queue")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestQueueSubCommandDrainMissingItem(t *testing.T) {
+	fs := &fakeStorage{data: map[string][]byte{}}
+	fillQueue(t, fs, pipeline.SignalTraces, (&ptrace.ProtoMarshaler{}).MarshalTraces, newTestTraces(1), newTestTraces(2), newTestTraces(3))
+	client, err := fs.GetClient(context.Background(), component.KindExporter, queueExporterID, pipeline.SignalTraces.String())
+	require.NoError(t, err)
+	require.NoError(t, client.Delete(context.Background(), "0"))
+
+	// The missing item is skipped, the drained item is the one written to the output.
+	out, err := runQueueSubCommand(t, fs, "drain", "--exporter", "queue", "--signal", "traces", "--count", "1")
+	require.NoError(t, err)
+	td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(strings.TrimSpace(out)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, td.SpanCount())
+
+	out, err = runQueueSubCommand(t, fs, "dump", "--exporter", "queue", "--signal", "traces")
+	require.NoError(t, err)
+	td, err = (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(strings.TrimSpace(out)))
+	require.NoError(t, err)
+	assert.Equal(t, 3, td.SpanCount())
+}
+
+func TestQueueSubCommandTruncate(t *testing.T) {
+	fs := newTestFakeStorage(t)
+	_, err := runQueueSubCommand(t, fs, "truncate", "--exporter", "queue", "--signal", "traces", "--keep", "1")
+	require.NoError(t, err)
+	out, err := runQueueSubCommand(t, fs, "dump", "--exporter", "queue", "--signal", "traces")
+	require.NoError(t, err)
+	td, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces([]byte(strings.TrimSpace(out)))
+	require.NoError(t, err)
+	assert.Equal(t, 1, td.SpanCount())
+
+	_, err = runQueueSubCommand(t, fs, "truncate", "--exporter", "queue")
+	require.NoError(t, err)
+	out, err = runQueueSubCommand(t, fs, "list", "--exporter", "queue")
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(out), "\n"), 1)
+}
+
+func TestQueueSubCommandErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "no_exporter",
+			args:    []string{"list"},
+			wantErr: "the exporter must be provided",
+		},
+		{
+			name:    "unknown_exporter",
+			args:    []string{"list", "--exporter", "otlp"},
+			wantErr: `exporter "otlp" is not configured`,
+		},
+		{
+			name:    "no_persistent_queue",
+			args:    []string{"list", "--exporter", "queue/memory"},
+			wantErr: "the exporter has no persistent queue, the storage must be provided",
+		},
+		{
+			name:    "unknown_storage",
+			args:    []string{"list", "--exporter", "queue/memory", "--storage", "file_storage"},
+			wantErr: `storage extension "file_storage" is not configured`,
+		},
+		{
+			name:    "unknown_signal",
+			args:    []string{"list", "--exporter", "queue", "--signal", "unknown"},
+			wantErr: `unknown signal "unknown"`,
+		},
+		{
+			name:    "not_storage",
+			args:    []string{"list", "--exporter", "queue", "--storage", "nop"},
+			wantErr: `extension "nop" is not a storage extension`,
+		},
+		{
+			name:    "unused_exporter",
+			args:    []string{"list", "--exporter", "queue/unused"},
+			wantErr: `exporter "queue/unused" is not used by any pipeline`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runQueueSubCommand(t, newTestFakeStorage(t), tt.args...)
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
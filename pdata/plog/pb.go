@@ -22,6 +22,24 @@ func (e *ProtoMarshaler) LogsSize(ld Logs) int {
 	return pb.Size()
 }
 
+// ResourceLogsSize returns the size in bytes of the ResourceLogs serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in Logs.
+func (e *ProtoMarshaler) ResourceLogsSize(rl ResourceLogs) int {
+	return rl.orig.Size()
+}
+
+// ScopeLogsSize returns the size in bytes of the ScopeLogs serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ResourceLogs.
+func (e *ProtoMarshaler) ScopeLogsSize(sl ScopeLogs) int {
+	return sl.orig.Size()
+}
+
+// LogRecordSize returns the size in bytes of the LogRecord serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ScopeLogs.
+func (e *ProtoMarshaler) LogRecordSize(lr LogRecord) int {
+	return lr.orig.Size()
+}
+
 var _ Unmarshaler = (*ProtoUnmarshaler)(nil)
 
 type ProtoUnmarshaler struct{}
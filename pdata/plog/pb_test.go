@@ -37,6 +37,29 @@ func TestProtoSizerEmptyLogs(t *testing.T) {
 	assert.Equal(t, 0, sizer.LogsSize(NewLogs()))
 }
 
+func TestProtoSizerComponents(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	ld := NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("host.name", "localhost")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope")
+	lr := sl.LogRecords().AppendEmpty()
+	lr.SetSeverityText("error")
+
+	bytes, err := rl.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ResourceLogsSize(rl))
+
+	bytes, err = sl.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ScopeLogsSize(sl))
+
+	bytes, err = lr.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.LogRecordSize(lr))
+}
+
 func BenchmarkLogsToProto(b *testing.B) {
 	marshaler := &ProtoMarshaler{}
 	logs := generateBenchmarkLogs(128)
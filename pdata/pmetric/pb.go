@@ -22,6 +22,48 @@ func (e *ProtoMarshaler) MetricsSize(md Metrics) int {
 	return pb.Size()
 }
 
+// ResourceMetricsSize returns the size in bytes of the ResourceMetrics serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in Metrics.
+func (e *ProtoMarshaler) ResourceMetricsSize(rm ResourceMetrics) int {
+	return rm.orig.Size()
+}
+
+// ScopeMetricsSize returns the size in bytes of the ScopeMetrics serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ResourceMetrics.
+func (e *ProtoMarshaler) ScopeMetricsSize(sm ScopeMetrics) int {
+	return sm.orig.Size()
+}
+
+// MetricSize returns the size in bytes of the Metric serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ScopeMetrics.
+func (e *ProtoMarshaler) MetricSize(m Metric) int {
+	return m.orig.Size()
+}
+
+// NumberDataPointSize returns the size in bytes of the NumberDataPoint serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in a Gauge or a Sum.
+func (e *ProtoMarshaler) NumberDataPointSize(ndp NumberDataPoint) int {
+	return ndp.orig.Size()
+}
+
+// HistogramDataPointSize returns the size in bytes of the HistogramDataPoint serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in a Histogram.
+func (e *ProtoMarshaler) HistogramDataPointSize(hdp HistogramDataPoint) int {
+	return hdp.orig.Size()
+}
+
+// ExponentialHistogramDataPointSize returns the size in bytes of the ExponentialHistogramDataPoint serialized as
+// protobuf, not including the field tag and length prefix used when it is embedded in an ExponentialHistogram.
+func (e *ProtoMarshaler) ExponentialHistogramDataPointSize(ehdp ExponentialHistogramDataPoint) int {
+	return ehdp.orig.Size()
+}
+
+// SummaryDataPointSize returns the size in bytes of the SummaryDataPoint serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in a Summary.
+func (e *ProtoMarshaler) SummaryDataPointSize(sdp SummaryDataPoint) int {
+	return sdp.orig.Size()
+}
+
 type ProtoUnmarshaler struct{}
 
 func (d *ProtoUnmarshaler) UnmarshalMetrics(buf []byte) (Metrics, error) {
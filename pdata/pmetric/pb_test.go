@@ -36,6 +36,53 @@ func TestProtoSizerEmptyMetrics(t *testing.T) {
 	assert.Equal(t, 0, sizer.MetricsSize(NewMetrics()))
 }
 
+func TestProtoSizerComponents(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	md := NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("host.name", "localhost")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("scope")
+	m := sm.Metrics().AppendEmpty()
+	m.SetName("foo")
+	ndp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	ndp.SetIntValue(1)
+	hdp := NewHistogramDataPoint()
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+	ehdp := NewExponentialHistogramDataPoint()
+	ehdp.SetScale(2)
+	sdp := NewSummaryDataPoint()
+	sdp.SetCount(3)
+
+	bytes, err := rm.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ResourceMetricsSize(rm))
+
+	bytes, err = sm.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ScopeMetricsSize(sm))
+
+	bytes, err = m.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.MetricSize(m))
+
+	bytes, err = ndp.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.NumberDataPointSize(ndp))
+
+	bytes, err = hdp.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.HistogramDataPointSize(hdp))
+
+	bytes, err = ehdp.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ExponentialHistogramDataPointSize(ehdp))
+
+	bytes, err = sdp.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.SummaryDataPointSize(sdp))
+}
+
 func BenchmarkMetricsToProto(b *testing.B) {
 	marshaler := &ProtoMarshaler{}
 	metrics := generateBenchmarkMetrics(128)
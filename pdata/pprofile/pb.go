@@ -22,6 +22,24 @@ func (e *ProtoMarshaler) ProfilesSize(td Profiles) int {
 	return pb.Size()
 }
 
+// ResourceProfilesSize returns the size in bytes of the ResourceProfiles serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in Profiles.
+func (e *ProtoMarshaler) ResourceProfilesSize(rp ResourceProfiles) int {
+	return rp.orig.Size()
+}
+
+// ScopeProfilesSize returns the size in bytes of the ScopeProfiles serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ResourceProfiles.
+func (e *ProtoMarshaler) ScopeProfilesSize(sp ScopeProfiles) int {
+	return sp.orig.Size()
+}
+
+// ProfileContainerSize returns the size in bytes of the ProfileContainer serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ScopeProfiles.
+func (e *ProtoMarshaler) ProfileContainerSize(pc ProfileContainer) int {
+	return pc.orig.Size()
+}
+
 type ProtoUnmarshaler struct{}
 
 func (d *ProtoUnmarshaler) UnmarshalProfiles(buf []byte) (Profiles, error) {
@@ -76,3 +76,26 @@ func generateBenchmarkProfiles(samplesCount int) Profiles {
 	}
 	return md
 }
+
+func TestProtoSizerComponents(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	td := NewProfiles()
+	rp := td.ResourceProfiles().AppendEmpty()
+	rp.Resource().Attributes().PutStr("host.name", "localhost")
+	sp := rp.ScopeProfiles().AppendEmpty()
+	sp.Scope().SetName("scope")
+	pc := sp.Profiles().AppendEmpty()
+	pc.Profile().StringTable().Append("foobar")
+
+	bytes, err := rp.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ResourceProfilesSize(rp))
+
+	bytes, err = sp.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ScopeProfilesSize(sp))
+
+	bytes, err = pc.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ProfileContainerSize(pc))
+}
@@ -22,6 +22,24 @@ func (e *ProtoMarshaler) TracesSize(td Traces) int {
 	return pb.Size()
 }
 
+// ResourceSpansSize returns the size in bytes of the ResourceSpans serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in Traces.
+func (e *ProtoMarshaler) ResourceSpansSize(rs ResourceSpans) int {
+	return rs.orig.Size()
+}
+
+// ScopeSpansSize returns the size in bytes of the ScopeSpans serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ResourceSpans.
+func (e *ProtoMarshaler) ScopeSpansSize(ss ScopeSpans) int {
+	return ss.orig.Size()
+}
+
+// SpanSize returns the size in bytes of the Span serialized as protobuf,
+// not including the field tag and length prefix used when it is embedded in ScopeSpans.
+func (e *ProtoMarshaler) SpanSize(span Span) int {
+	return span.orig.Size()
+}
+
 type ProtoUnmarshaler struct{}
 
 func (d *ProtoUnmarshaler) UnmarshalTraces(buf []byte) (Traces, error) {
@@ -37,6 +37,29 @@ func TestProtoSizerEmptyTraces(t *testing.T) {
 	assert.Equal(t, 0, sizer.TracesSize(NewTraces()))
 }
 
+func TestProtoSizerComponents(t *testing.T) {
+	marshaler := &ProtoMarshaler{}
+	td := NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("host.name", "localhost")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("scope")
+	span := ss.Spans().AppendEmpty()
+	span.SetName("operation")
+
+	bytes, err := rs.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ResourceSpansSize(rs))
+
+	bytes, err = ss.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.ScopeSpansSize(ss))
+
+	bytes, err = span.orig.Marshal()
+	require.NoError(t, err)
+	assert.Len(t, bytes, marshaler.SpanSize(span))
+}
+
 func BenchmarkTracesToProto(b *testing.B) {
 	marshaler := &ProtoMarshaler{}
 	traces := generateBenchmarkTraces(128)
@@ -40,10 +40,41 @@ type Protocols struct {
 	HTTP *HTTPConfig              `mapstructure:"http"`
 }
 
+// AdmissionConfig limits the requests processed concurrently by the receiver, over all the protocols.
+// The requests exceeding the limits are rejected with a retryable error before their data is buffered.
+type AdmissionConfig struct {
+	// MaxConcurrentRequests is the maximum number of requests processed concurrently, 0 means no limit.
+	MaxConcurrentRequests int64 `mapstructure:"max_concurrent_requests"`
+
+	// MaxInFlightBytes is the maximum number of bytes of the requests processed concurrently, 0 means no limit.
+	// The bytes of the HTTP requests are the bytes of their body once decompressed. The gRPC requests admit the
+	// largest message accepted by the server until their message is decoded, and then the size of the message.
+	MaxInFlightBytes int64 `mapstructure:"max_in_flight_bytes"`
+}
+
+// MaxItemsPerRequestConfig limits the number of items of each request, per signal, over all the protocols.
+// The requests exceeding the limits are refused with a non-retryable error.
+type MaxItemsPerRequestConfig struct {
+	// Traces is the maximum number of spans per request, 0 means no limit.
+	Traces int `mapstructure:"traces"`
+
+	// Metrics is the maximum number of metric data points per request, 0 means no limit.
+	Metrics int `mapstructure:"metrics"`
+
+	// Logs is the maximum number of log records per request, 0 means no limit.
+	Logs int `mapstructure:"logs"`
+}
+
 // Config defines configuration for OTLP receiver.
 type Config struct {
 	// Protocols is the configuration for the supported protocols, currently gRPC and HTTP (Proto and JSON).
 	Protocols `mapstructure:"protocols"`
+
+	// Admission limits the requests processed concurrently by the receiver.
+	Admission AdmissionConfig `mapstructure:"admission"`
+
+	// MaxItemsPerRequest limits the number of items of each request, per signal.
+	MaxItemsPerRequest MaxItemsPerRequestConfig `mapstructure:"max_items_per_request"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -54,6 +85,12 @@ func (cfg *Config) Validate() error {
 	if cfg.GRPC == nil && cfg.HTTP == nil {
 		return errors.New("must specify at least one protocol when using the OTLP receiver")
 	}
+	if cfg.Admission.MaxConcurrentRequests < 0 || cfg.Admission.MaxInFlightBytes < 0 {
+		return errors.New("admission limits must be non-negative")
+	}
+	if cfg.MaxItemsPerRequest.Traces < 0 || cfg.MaxItemsPerRequest.Metrics < 0 || cfg.MaxItemsPerRequest.Logs < 0 {
+		return errors.New("max_items_per_request limits must be non-negative")
+	}
 	return nil
 }
 
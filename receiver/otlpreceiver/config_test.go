@@ -140,6 +140,15 @@ func TestUnmarshalConfig(t *testing.T) {
 					LogsURLPath:    "/log/ingest",
 				},
 			},
+			Admission: AdmissionConfig{
+				MaxConcurrentRequests: 100,
+				MaxInFlightBytes:      64 * 1024 * 1024,
+			},
+			MaxItemsPerRequest: MaxItemsPerRequestConfig{
+				Traces:  10000,
+				Metrics: 20000,
+				Logs:    10000,
+			},
 		}, cfg)
 
 }
@@ -197,6 +206,18 @@ func TestUnmarshalConfigEmptyProtocols(t *testing.T) {
 	assert.EqualError(t, component.ValidateConfig(cfg), "must specify at least one protocol when using the OTLP receiver")
 }
 
+func TestValidateConfigAdmission(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Admission.MaxInFlightBytes = -1
+	assert.EqualError(t, component.ValidateConfig(cfg), "admission limits must be non-negative")
+}
+
+func TestValidateConfigMaxItemsPerRequest(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxItemsPerRequest.Logs = -1
+	assert.EqualError(t, component.ValidateConfig(cfg), "max_items_per_request limits must be non-negative")
+}
+
 func TestUnmarshalConfigInvalidSignalPath(t *testing.T) {
 	tests := []struct {
 		name       string
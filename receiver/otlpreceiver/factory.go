@@ -23,6 +23,9 @@ const (
 	defaultMetricsURLPath  = "/v1/metrics"
 	defaultLogsURLPath     = "/v1/logs"
 	defaultProfilesURLPath = "/v1development/profiles"
+
+	// defaultGRPCMaxRecvMsgSize is the largest message accepted by the gRPC servers by default.
+	defaultGRPCMaxRecvMsgSize = 4 * 1024 * 1024
 )
 
 // NewFactory creates a new OTLP receiver factory.
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package admission // import "go.opentelemetry.io/collector/receiver/otlpreceiver/internal/admission"
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+)
+
+// ErrTooManyRequests is returned when a request is rejected because the receiver is processing
+// too many requests or too much data.
+var ErrTooManyRequests = errors.New("too many requests or too much data in flight, retry later")
+
+// Controller limits the number of requests and the number of bytes processed concurrently.
+// The requests exceeding the limits are rejected without waiting.
+type Controller struct {
+	maxRequests int64
+	maxBytes    int64
+
+	mu       sync.Mutex
+	requests int64
+	bytes    int64
+}
+
+// New returns a Controller admitting up to maxRequests requests and maxBytes bytes in flight.
+// A limit lower than or equal to 0 means no limit.
+func New(maxRequests, maxBytes int64) *Controller {
+	return &Controller{maxRequests: maxRequests, maxBytes: maxBytes}
+}
+
+// TryAcquire admits the given number of requests and bytes if they fit in the limits.
+func (c *Controller) TryAcquire(requests, bytes int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxRequests > 0 && requests > 0 && c.requests+requests > c.maxRequests {
+		return false
+	}
+	if c.maxBytes > 0 && bytes > 0 && c.bytes+bytes > c.maxBytes {
+		return false
+	}
+	c.requests += requests
+	c.bytes += bytes
+	return true
+}
+
+// Release gives back the requests and bytes admitted by TryAcquire.
+func (c *Controller) Release(requests, bytes int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests -= requests
+	c.bytes -= bytes
+}
+
+// Full returns true if no other request can be admitted.
+func (c *Controller) Full() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return (c.maxRequests > 0 && c.requests >= c.maxRequests) || (c.maxBytes > 0 && c.bytes >= c.maxBytes)
+}
+
+// Body is a request body admitting the bytes as they are read. The reads fail with ErrTooManyRequests
+// once the bytes in flight exceed the limit, before the data is buffered by the handler.
+type Body struct {
+	io.ReadCloser
+	ctl      *Controller
+	read     int64
+	admitted int64
+}
+
+// NewBody returns a Body reading from body, where admitted is the number of bytes already admitted
+// for it, if the size of the body is known.
+func NewBody(ctl *Controller, body io.ReadCloser, admitted int64) *Body {
+	return &Body{ReadCloser: body, ctl: ctl, admitted: admitted}
+}
+
+func (b *Body) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.admitted {
+		if !b.ctl.TryAcquire(0, b.read-b.admitted) {
+			return 0, ErrTooManyRequests
+		}
+		b.admitted = b.read
+	}
+	return n, err
+}
+
+// Release gives back the bytes admitted for the body. It must be called once the data
+// read from the body is not used anymore.
+func (b *Body) Release() {
+	b.ctl.Release(0, b.admitted)
+	b.admitted = 0
+}
+
+// reservation is the request and the bytes admitted for a gRPC stream by TapHandle.
+type reservation struct {
+	mu       sync.Mutex
+	bytes    int64
+	released bool
+}
+
+type reservationKey struct{}
+
+// TapHandle returns the gRPC tap handle admitting the requests before their message is read. As the size of the
+// message is not known yet, maxMessageSize bytes, at most the bytes limit, are admitted for each request until
+// the message is decoded. The request and its bytes are released once the stream is done.
+func (c *Controller) TapHandle(maxMessageSize int64) tap.ServerInHandle {
+	if c.maxBytes <= 0 {
+		maxMessageSize = 0
+	} else if maxMessageSize <= 0 || maxMessageSize > c.maxBytes {
+		maxMessageSize = c.maxBytes
+	}
+	return func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+		if !c.TryAcquire(1, maxMessageSize) {
+			return ctx, status.Error(codes.ResourceExhausted, ErrTooManyRequests.Error())
+		}
+		r := &reservation{bytes: maxMessageSize}
+		// The context of the stream is canceled once the stream is done, including when it fails before
+		// reaching the handler.
+		context.AfterFunc(ctx, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			c.Release(1, r.bytes)
+			r.released = true
+		})
+		return context.WithValue(ctx, reservationKey{}, r), nil
+	}
+}
+
+// UnaryServerInterceptor adjusts the bytes admitted by TapHandle to the size of the decoded message, rejecting
+// the requests whose message is larger than the bytes available.
+func (c *Controller) UnaryServerInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	r, ok := ctx.Value(reservationKey{}).(*reservation)
+	if !ok || c.maxBytes <= 0 {
+		return handler(ctx, req)
+	}
+	var size int64
+	if s, ok := req.(interface{ Size() int }); ok {
+		size = int64(s.Size())
+	}
+	r.mu.Lock()
+	if !r.released {
+		if size > r.bytes {
+			if !c.TryAcquire(0, size-r.bytes) {
+				r.mu.Unlock()
+				return nil, status.Error(codes.ResourceExhausted, ErrTooManyRequests.Error())
+			}
+		} else {
+			c.Release(0, r.bytes-size)
+		}
+		r.bytes = size
+	}
+	r.mu.Unlock()
+	return handler(ctx, req)
+}
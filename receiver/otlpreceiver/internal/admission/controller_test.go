@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package admission
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestController(t *testing.T) {
+	ctl := New(2, 100)
+	assert.True(t, ctl.TryAcquire(1, 60))
+	assert.False(t, ctl.TryAcquire(1, 60))
+	assert.True(t, ctl.TryAcquire(1, 40))
+	assert.True(t, ctl.Full())
+	assert.False(t, ctl.TryAcquire(1, 0))
+	ctl.Release(1, 60)
+	assert.False(t, ctl.Full())
+	assert.True(t, ctl.TryAcquire(1, 0))
+
+	// No limits.
+	ctl = New(0, 0)
+	assert.True(t, ctl.TryAcquire(1000, 1<<40))
+	assert.False(t, ctl.Full())
+}
+
+func TestBody(t *testing.T) {
+	ctl := New(0, 10)
+	// The bytes of a body with an unknown size are admitted as they are read.
+	body := NewBody(ctl, io.NopCloser(strings.NewReader(strings.Repeat("a", 8))), 0)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Len(t, data, 8)
+
+	other := NewBody(ctl, io.NopCloser(strings.NewReader(strings.Repeat("a", 8))), 0)
+	_, err = io.ReadAll(other)
+	require.ErrorIs(t, err, ErrTooManyRequests)
+	other.Release()
+
+	body.Release()
+	assert.False(t, ctl.Full())
+
+	// The bytes of a body with a known size are admitted before reading.
+	require.True(t, ctl.TryAcquire(0, 4))
+	body = NewBody(ctl, io.NopCloser(strings.NewReader("abcd")), 4)
+	_, err = io.ReadAll(body)
+	require.NoError(t, err)
+	body.Release()
+	assert.True(t, ctl.TryAcquire(0, 10))
+}
+
+type sizedRequest int
+
+func (r sizedRequest) Size() int {
+	return int(r)
+}
+
+func TestGRPC(t *testing.T) {
+	ctl := New(0, 100)
+	tapHandle := ctl.TapHandle(60)
+
+	// The largest message is admitted before the message is read.
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, err := tapHandle(ctx, nil)
+	require.NoError(t, err)
+	otherCtx, otherCancel := context.WithCancel(context.Background())
+	defer otherCancel()
+	_, err = tapHandle(otherCtx, nil)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// The admitted bytes are adjusted to the size of the decoded message.
+	handler := func(context.Context, any) (any, error) {
+		otherCtx, err = tapHandle(otherCtx, nil)
+		assert.NoError(t, err)
+		return "resp", nil
+	}
+	resp, err := ctl.UnaryServerInterceptor(ctx, sizedRequest(10), nil, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+
+	// The messages larger than the bytes available are rejected.
+	_, err = ctl.UnaryServerInterceptor(otherCtx, sizedRequest(200), nil, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// The bytes are released once the streams are done.
+	cancel()
+	otherCancel()
+	assert.Eventually(t, func() bool {
+		return ctl.TryAcquire(0, 100)
+	}, time.Second, time.Millisecond)
+}
+
+func TestGRPCRequests(t *testing.T) {
+	ctl := New(1, 0)
+	tapHandle := ctl.TapHandle(1 << 20)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := tapHandle(ctx, nil)
+	require.NoError(t, err)
+	_, err = tapHandle(context.Background(), nil)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	cancel()
+	assert.Eventually(t, func() bool {
+		return !ctl.Full()
+	}, time.Second, time.Millisecond)
+}
@@ -27,6 +27,30 @@ func GetStatusFromError(err error) error {
 	return s.Err()
 }
 
+// RejectedItems returns the number of items rejected by the pipeline if err reports that only some of the
+// numItems items of the request were rejected, and the request can be answered with a partial success.
+// The rejected items are counted by a consumererror.PartialSuccess, or else by failedItems, which returns
+// the size of the failed subset carried by a signal error such as consumererror.Traces.
+func RejectedItems(err error, numItems int, failedItems func(error) (int, bool)) (int64, bool) {
+	rejected, ok := consumererror.RejectedItems(err)
+	if !ok {
+		var failed int
+		if failed, ok = failedItems(err); !ok {
+			return 0, false
+		}
+		rejected = int64(failed)
+	}
+	if rejected <= 0 || rejected >= int64(numItems) {
+		return 0, false
+	}
+	return rejected, true
+}
+
+// NewTooManyItems returns the InvalidArgument status refusing a request containing more items than the limit.
+func NewTooManyItems(numItems int, maxItems int, itemsName string) error {
+	return status.Errorf(codes.InvalidArgument, "the request contains %d %s, more than the limit of %d %s per request", numItems, itemsName, maxItems, itemsName)
+}
+
 func GetHTTPStatusCodeFromStatus(s *status.Status) int {
 	// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/docs/specification.md#failures
 	// to see if a code is retryable.
@@ -94,3 +94,48 @@ func Test_GetHTTPStatusCodeFromStatus(t *testing.T) {
 		})
 	}
 }
+
+func Test_RejectedItems(t *testing.T) {
+	failedItems := func(err error) (int, bool) {
+		return 3, err.Error() == "signal error"
+	}
+	tests := []struct {
+		name     string
+		input    error
+		numItems int
+		rejected int64
+		partial  bool
+	}{
+		{
+			name:     "Partial success",
+			input:    consumererror.NewPartialSuccess(fmt.Errorf("test"), 2),
+			numItems: 10,
+			rejected: 2,
+			partial:  true,
+		},
+		{
+			name:     "Failed subset",
+			input:    fmt.Errorf("signal error"),
+			numItems: 10,
+			rejected: 3,
+			partial:  true,
+		},
+		{
+			name:     "All items failed",
+			input:    fmt.Errorf("signal error"),
+			numItems: 3,
+		},
+		{
+			name:     "Other error",
+			input:    fmt.Errorf("test"),
+			numItems: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejected, ok := RejectedItems(tt.input, tt.numItems, failedItems)
+			assert.Equal(t, tt.partial, ok)
+			assert.Equal(t, tt.rejected, rejected)
+		})
+	}
+}
@@ -5,8 +5,10 @@ package logs // import "go.opentelemetry.io/collector/receiver/otlpreceiver/inte
 
 import (
 	"context"
+	stderrors "errors"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/errors"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
@@ -17,15 +19,18 @@ const dataFormatProtobuf = "protobuf"
 // Receiver is the type used to handle logs from OpenTelemetry exporters.
 type Receiver struct {
 	plogotlp.UnimplementedGRPCServer
-	nextConsumer consumer.Logs
-	obsreport    *receiverhelper.ObsReport
+	nextConsumer  consumer.Logs
+	obsreport     *receiverhelper.ObsReport
+	maxLogRecords int
 }
 
-// New creates a new Receiver reference.
-func New(nextConsumer consumer.Logs, obsreport *receiverhelper.ObsReport) *Receiver {
+// New creates a new Receiver reference. The requests containing more than maxLogRecords log records are refused,
+// 0 means no limit.
+func New(nextConsumer consumer.Logs, obsreport *receiverhelper.ObsReport, maxLogRecords int) *Receiver {
 	return &Receiver{
-		nextConsumer: nextConsumer,
-		obsreport:    obsreport,
+		nextConsumer:  nextConsumer,
+		obsreport:     obsreport,
+		maxLogRecords: maxLogRecords,
 	}
 }
 
@@ -38,7 +43,21 @@ func (r *Receiver) Export(ctx context.Context, req plogotlp.ExportRequest) (plog
 	}
 
 	ctx = r.obsreport.StartLogsOp(ctx)
+	if r.maxLogRecords > 0 && numSpans > r.maxLogRecords {
+		err := errors.NewTooManyItems(numSpans, r.maxLogRecords, "log records")
+		r.obsreport.EndLogsOp(ctx, dataFormatProtobuf, numSpans, err)
+		return plogotlp.NewExportResponse(), err
+	}
 	err := r.nextConsumer.ConsumeLogs(ctx, ld)
+
+	// Only a subset of the data failed: the client is told how many items are rejected, and must not retry.
+	if rejected, ok := errors.RejectedItems(err, numSpans, failedLogs); ok {
+		r.obsreport.EndLogsOp(ctx, dataFormatProtobuf, numSpans, consumererror.NewPartialSuccess(err, rejected))
+		resp := plogotlp.NewExportResponse()
+		resp.PartialSuccess().SetRejectedLogRecords(rejected)
+		resp.PartialSuccess().SetErrorMessage(err.Error())
+		return resp, nil
+	}
 	r.obsreport.EndLogsOp(ctx, dataFormatProtobuf, numSpans, err)
 
 	// Use appropriate status codes for permanent/non-permanent errors
@@ -53,3 +72,12 @@ func (r *Receiver) Export(ctx context.Context, req plogotlp.ExportRequest) (plog
 
 	return plogotlp.NewExportResponse(), nil
 }
+
+// failedLogs returns the number of log records carried by a consumererror.Logs error.
+func failedLogs(err error) (int, bool) {
+	var lErr consumererror.Logs
+	if !stderrors.As(err, &lErr) {
+		return 0, false
+	}
+	return lErr.Data().LogRecordCount(), true
+}
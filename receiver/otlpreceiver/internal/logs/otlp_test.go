@@ -6,6 +6,7 @@ package logs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"testing"
 
@@ -31,7 +32,7 @@ func TestExport(t *testing.T) {
 	req := plogotlp.NewExportRequestFromLogs(ld)
 
 	logSink := new(consumertest.LogsSink)
-	logClient := makeLogsServiceClient(t, logSink)
+	logClient := makeLogsServiceClient(t, logSink, 0)
 	resp, err := logClient.Export(context.Background(), req)
 	require.NoError(t, err, "Failed to export trace: %v", err)
 	require.NotNil(t, resp, "The response is missing")
@@ -44,7 +45,7 @@ func TestExport(t *testing.T) {
 func TestExport_EmptyRequest(t *testing.T) {
 	logSink := new(consumertest.LogsSink)
 
-	logClient := makeLogsServiceClient(t, logSink)
+	logClient := makeLogsServiceClient(t, logSink, 0)
 	resp, err := logClient.Export(context.Background(), plogotlp.NewExportRequest())
 	require.NoError(t, err, "Failed to export trace: %v", err)
 	assert.NotNil(t, resp, "The response is missing")
@@ -54,7 +55,7 @@ func TestExport_NonPermanentErrorConsumer(t *testing.T) {
 	ld := testdata.GenerateLogs(1)
 	req := plogotlp.NewExportRequestFromLogs(ld)
 
-	logClient := makeLogsServiceClient(t, consumertest.NewErr(errors.New("my error")))
+	logClient := makeLogsServiceClient(t, consumertest.NewErr(errors.New("my error")), 0)
 	resp, err := logClient.Export(context.Background(), req)
 	require.EqualError(t, err, "rpc error: code = Unavailable desc = my error")
 	assert.IsType(t, status.Error(codes.Unknown, ""), err)
@@ -65,15 +66,46 @@ func TestExport_PermanentErrorConsumer(t *testing.T) {
 	ld := testdata.GenerateLogs(1)
 	req := plogotlp.NewExportRequestFromLogs(ld)
 
-	logClient := makeLogsServiceClient(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("my error"))))
+	logClient := makeLogsServiceClient(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("my error"))), 0)
 	resp, err := logClient.Export(context.Background(), req)
 	require.EqualError(t, err, "rpc error: code = Internal desc = Permanent error: my error")
 	assert.IsType(t, status.Error(codes.Unknown, ""), err)
 	assert.Equal(t, plogotlp.ExportResponse{}, resp)
 }
 
-func makeLogsServiceClient(t *testing.T, lc consumer.Logs) plogotlp.GRPCClient {
-	addr := otlpReceiverOnGRPCServer(t, lc)
+func TestExport_PartialSuccess(t *testing.T) {
+	req := plogotlp.NewExportRequestFromLogs(testdata.GenerateLogs(2))
+
+	client := makeLogsServiceClient(t, consumertest.NewErr(consumererror.NewLogs(errors.New("my error"), testdata.GenerateLogs(1))), 0)
+	resp, err := client.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(testdata.GenerateLogs(1).LogRecordCount()), resp.PartialSuccess().RejectedLogRecords())
+	assert.Equal(t, "my error", resp.PartialSuccess().ErrorMessage())
+
+	// The request fails as a whole when all the items failed.
+	client = makeLogsServiceClient(t, consumertest.NewErr(consumererror.NewLogs(errors.New("my error"), testdata.GenerateLogs(2))), 0)
+	_, err = client.Export(context.Background(), req)
+	require.EqualError(t, err, "rpc error: code = Unavailable desc = my error")
+}
+
+func TestExport_MaxItemsPerRequest(t *testing.T) {
+	req := plogotlp.NewExportRequestFromLogs(testdata.GenerateLogs(2))
+	count := testdata.GenerateLogs(2).LogRecordCount()
+
+	sink := new(consumertest.LogsSink)
+	client := makeLogsServiceClient(t, sink, count-1)
+	_, err := client.Export(context.Background(), req)
+	require.EqualError(t, err, fmt.Sprintf("rpc error: code = InvalidArgument desc = the request contains %d log records, more than the limit of %d log records per request", count, count-1))
+	assert.Zero(t, sink.LogRecordCount())
+
+	client = makeLogsServiceClient(t, sink, count)
+	_, err = client.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, count, sink.LogRecordCount())
+}
+
+func makeLogsServiceClient(t *testing.T, lc consumer.Logs, maxItems int) plogotlp.GRPCClient {
+	addr := otlpReceiverOnGRPCServer(t, lc, maxItems)
 	cc, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	require.NoError(t, err, "Failed to create the TraceServiceClient: %v", err)
 	t.Cleanup(func() {
@@ -83,7 +115,7 @@ func makeLogsServiceClient(t *testing.T, lc consumer.Logs) plogotlp.GRPCClient {
 	return plogotlp.NewGRPCClient(cc)
 }
 
-func otlpReceiverOnGRPCServer(t *testing.T, lc consumer.Logs) net.Addr {
+func otlpReceiverOnGRPCServer(t *testing.T, lc consumer.Logs, maxItems int) net.Addr {
 	ln, err := net.Listen("tcp", "localhost:")
 	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
 
@@ -99,7 +131,7 @@ func otlpReceiverOnGRPCServer(t *testing.T, lc consumer.Logs) net.Addr {
 		ReceiverCreateSettings: set,
 	})
 	require.NoError(t, err)
-	r := New(lc, obsreport)
+	r := New(lc, obsreport, maxItems)
 	// Now run it as a gRPC server
 	srv := grpc.NewServer()
 	plogotlp.RegisterGRPCServer(srv, r)
@@ -5,8 +5,10 @@ package metrics // import "go.opentelemetry.io/collector/receiver/otlpreceiver/i
 
 import (
 	"context"
+	stderrors "errors"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/errors"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
@@ -17,15 +19,18 @@ const dataFormatProtobuf = "protobuf"
 // Receiver is the type used to handle metrics from OpenTelemetry exporters.
 type Receiver struct {
 	pmetricotlp.UnimplementedGRPCServer
-	nextConsumer consumer.Metrics
-	obsreport    *receiverhelper.ObsReport
+	nextConsumer  consumer.Metrics
+	obsreport     *receiverhelper.ObsReport
+	maxDataPoints int
 }
 
-// New creates a new Receiver reference.
-func New(nextConsumer consumer.Metrics, obsreport *receiverhelper.ObsReport) *Receiver {
+// New creates a new Receiver reference. The requests containing more than maxDataPoints metric data points are refused,
+// 0 means no limit.
+func New(nextConsumer consumer.Metrics, obsreport *receiverhelper.ObsReport, maxDataPoints int) *Receiver {
 	return &Receiver{
-		nextConsumer: nextConsumer,
-		obsreport:    obsreport,
+		nextConsumer:  nextConsumer,
+		obsreport:     obsreport,
+		maxDataPoints: maxDataPoints,
 	}
 }
 
@@ -38,7 +43,21 @@ func (r *Receiver) Export(ctx context.Context, req pmetricotlp.ExportRequest) (p
 	}
 
 	ctx = r.obsreport.StartMetricsOp(ctx)
+	if r.maxDataPoints > 0 && dataPointCount > r.maxDataPoints {
+		err := errors.NewTooManyItems(dataPointCount, r.maxDataPoints, "data points")
+		r.obsreport.EndMetricsOp(ctx, dataFormatProtobuf, dataPointCount, err)
+		return pmetricotlp.NewExportResponse(), err
+	}
 	err := r.nextConsumer.ConsumeMetrics(ctx, md)
+
+	// Only a subset of the data failed: the client is told how many items are rejected, and must not retry.
+	if rejected, ok := errors.RejectedItems(err, dataPointCount, failedMetrics); ok {
+		r.obsreport.EndMetricsOp(ctx, dataFormatProtobuf, dataPointCount, consumererror.NewPartialSuccess(err, rejected))
+		resp := pmetricotlp.NewExportResponse()
+		resp.PartialSuccess().SetRejectedDataPoints(rejected)
+		resp.PartialSuccess().SetErrorMessage(err.Error())
+		return resp, nil
+	}
 	r.obsreport.EndMetricsOp(ctx, dataFormatProtobuf, dataPointCount, err)
 
 	// Use appropriate status codes for permanent/non-permanent errors
@@ -53,3 +72,12 @@ func (r *Receiver) Export(ctx context.Context, req pmetricotlp.ExportRequest) (p
 
 	return pmetricotlp.NewExportResponse(), nil
 }
+
+// failedMetrics returns the number of metric data points carried by a consumererror.Metrics error.
+func failedMetrics(err error) (int, bool) {
+	var mErr consumererror.Metrics
+	if !stderrors.As(err, &mErr) {
+		return 0, false
+	}
+	return mErr.Data().DataPointCount(), true
+}
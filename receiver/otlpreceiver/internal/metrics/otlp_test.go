@@ -6,6 +6,7 @@ package metrics
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"testing"
 
@@ -31,7 +32,7 @@ func TestExport(t *testing.T) {
 	req := pmetricotlp.NewExportRequestFromMetrics(md)
 
 	metricSink := new(consumertest.MetricsSink)
-	metricsClient := makeMetricsServiceClient(t, metricSink)
+	metricsClient := makeMetricsServiceClient(t, metricSink, 0)
 	resp, err := metricsClient.Export(context.Background(), req)
 
 	require.NoError(t, err, "Failed to export metrics: %v", err)
@@ -44,7 +45,7 @@ func TestExport(t *testing.T) {
 
 func TestExport_EmptyRequest(t *testing.T) {
 	metricSink := new(consumertest.MetricsSink)
-	metricsClient := makeMetricsServiceClient(t, metricSink)
+	metricsClient := makeMetricsServiceClient(t, metricSink, 0)
 	resp, err := metricsClient.Export(context.Background(), pmetricotlp.NewExportRequest())
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -54,7 +55,7 @@ func TestExport_NonPermanentErrorConsumer(t *testing.T) {
 	md := testdata.GenerateMetrics(1)
 	req := pmetricotlp.NewExportRequestFromMetrics(md)
 
-	metricsClient := makeMetricsServiceClient(t, consumertest.NewErr(errors.New("my error")))
+	metricsClient := makeMetricsServiceClient(t, consumertest.NewErr(errors.New("my error")), 0)
 	resp, err := metricsClient.Export(context.Background(), req)
 	require.EqualError(t, err, "rpc error: code = Unavailable desc = my error")
 	assert.IsType(t, status.Error(codes.Unknown, ""), err)
@@ -65,15 +66,46 @@ func TestExport_PermanentErrorConsumer(t *testing.T) {
 	ld := testdata.GenerateMetrics(1)
 	req := pmetricotlp.NewExportRequestFromMetrics(ld)
 
-	metricsClient := makeMetricsServiceClient(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("my error"))))
+	metricsClient := makeMetricsServiceClient(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("my error"))), 0)
 	resp, err := metricsClient.Export(context.Background(), req)
 	require.EqualError(t, err, "rpc error: code = Internal desc = Permanent error: my error")
 	assert.IsType(t, status.Error(codes.Unknown, ""), err)
 	assert.Equal(t, pmetricotlp.ExportResponse{}, resp)
 }
 
-func makeMetricsServiceClient(t *testing.T, mc consumer.Metrics) pmetricotlp.GRPCClient {
-	addr := otlpReceiverOnGRPCServer(t, mc)
+func TestExport_PartialSuccess(t *testing.T) {
+	req := pmetricotlp.NewExportRequestFromMetrics(testdata.GenerateMetrics(2))
+
+	client := makeMetricsServiceClient(t, consumertest.NewErr(consumererror.NewMetrics(errors.New("my error"), testdata.GenerateMetrics(1))), 0)
+	resp, err := client.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(testdata.GenerateMetrics(1).DataPointCount()), resp.PartialSuccess().RejectedDataPoints())
+	assert.Equal(t, "my error", resp.PartialSuccess().ErrorMessage())
+
+	// The request fails as a whole when all the items failed.
+	client = makeMetricsServiceClient(t, consumertest.NewErr(consumererror.NewMetrics(errors.New("my error"), testdata.GenerateMetrics(2))), 0)
+	_, err = client.Export(context.Background(), req)
+	require.EqualError(t, err, "rpc error: code = Unavailable desc = my error")
+}
+
+func TestExport_MaxItemsPerRequest(t *testing.T) {
+	req := pmetricotlp.NewExportRequestFromMetrics(testdata.GenerateMetrics(2))
+	count := testdata.GenerateMetrics(2).DataPointCount()
+
+	sink := new(consumertest.MetricsSink)
+	client := makeMetricsServiceClient(t, sink, count-1)
+	_, err := client.Export(context.Background(), req)
+	require.EqualError(t, err, fmt.Sprintf("rpc error: code = InvalidArgument desc = the request contains %d data points, more than the limit of %d data points per request", count, count-1))
+	assert.Zero(t, sink.DataPointCount())
+
+	client = makeMetricsServiceClient(t, sink, count)
+	_, err = client.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, count, sink.DataPointCount())
+}
+
+func makeMetricsServiceClient(t *testing.T, mc consumer.Metrics, maxItems int) pmetricotlp.GRPCClient {
+	addr := otlpReceiverOnGRPCServer(t, mc, maxItems)
 
 	cc, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	require.NoError(t, err, "Failed to create the MetricsServiceClient: %v", err)
@@ -84,7 +116,7 @@ func makeMetricsServiceClient(t *testing.T, mc consumer.Metrics) pmetricotlp.GRP
 	return pmetricotlp.NewGRPCClient(cc)
 }
 
-func otlpReceiverOnGRPCServer(t *testing.T, mc consumer.Metrics) net.Addr {
+func otlpReceiverOnGRPCServer(t *testing.T, mc consumer.Metrics, maxItems int) net.Addr {
 	ln, err := net.Listen("tcp", "localhost:")
 	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
 
@@ -100,7 +132,7 @@ func otlpReceiverOnGRPCServer(t *testing.T, mc consumer.Metrics) net.Addr {
 		ReceiverCreateSettings: set,
 	})
 	require.NoError(t, err)
-	r := New(mc, obsreport)
+	r := New(mc, obsreport, maxItems)
 	// Now run it as a gRPC server
 	srv := grpc.NewServer()
 	pmetricotlp.RegisterGRPCServer(srv, r)
@@ -5,8 +5,10 @@ package trace // import "go.opentelemetry.io/collector/receiver/otlpreceiver/int
 
 import (
 	"context"
+	stderrors "errors"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/errors"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
@@ -19,13 +21,16 @@ type Receiver struct {
 	ptraceotlp.UnimplementedGRPCServer
 	nextConsumer consumer.Traces
 	obsreport    *receiverhelper.ObsReport
+	maxSpans     int
 }
 
-// New creates a new Receiver reference.
-func New(nextConsumer consumer.Traces, obsreport *receiverhelper.ObsReport) *Receiver {
+// New creates a new Receiver reference. The requests containing more than maxSpans spans are refused,
+// 0 means no limit.
+func New(nextConsumer consumer.Traces, obsreport *receiverhelper.ObsReport, maxSpans int) *Receiver {
 	return &Receiver{
 		nextConsumer: nextConsumer,
 		obsreport:    obsreport,
+		maxSpans:     maxSpans,
 	}
 }
 
@@ -39,7 +44,21 @@ func (r *Receiver) Export(ctx context.Context, req ptraceotlp.ExportRequest) (pt
 	}
 
 	ctx = r.obsreport.StartTracesOp(ctx)
+	if r.maxSpans > 0 && numSpans > r.maxSpans {
+		err := errors.NewTooManyItems(numSpans, r.maxSpans, "spans")
+		r.obsreport.EndTracesOp(ctx, dataFormatProtobuf, numSpans, err)
+		return ptraceotlp.NewExportResponse(), err
+	}
 	err := r.nextConsumer.ConsumeTraces(ctx, td)
+
+	// Only a subset of the data failed: the client is told how many items are rejected, and must not retry.
+	if rejected, ok := errors.RejectedItems(err, numSpans, failedTraces); ok {
+		r.obsreport.EndTracesOp(ctx, dataFormatProtobuf, numSpans, consumererror.NewPartialSuccess(err, rejected))
+		resp := ptraceotlp.NewExportResponse()
+		resp.PartialSuccess().SetRejectedSpans(rejected)
+		resp.PartialSuccess().SetErrorMessage(err.Error())
+		return resp, nil
+	}
 	r.obsreport.EndTracesOp(ctx, dataFormatProtobuf, numSpans, err)
 
 	// Use appropriate status codes for permanent/non-permanent errors
@@ -54,3 +73,12 @@ func (r *Receiver) Export(ctx context.Context, req ptraceotlp.ExportRequest) (pt
 
 	return ptraceotlp.NewExportResponse(), nil
 }
+
+// failedTraces returns the number of spans carried by a consumererror.Traces error.
+func failedTraces(err error) (int, bool) {
+	var tErr consumererror.Traces
+	if !stderrors.As(err, &tErr) {
+		return 0, false
+	}
+	return tErr.Data().SpanCount(), true
+}
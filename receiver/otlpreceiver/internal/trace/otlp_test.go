@@ -6,6 +6,7 @@ package trace
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"testing"
 
@@ -31,7 +32,7 @@ func TestExport(t *testing.T) {
 	req := ptraceotlp.NewExportRequestFromTraces(td)
 
 	traceSink := new(consumertest.TracesSink)
-	traceClient := makeTraceServiceClient(t, traceSink)
+	traceClient := makeTraceServiceClient(t, traceSink, 0)
 	resp, err := traceClient.Export(context.Background(), req)
 	require.NoError(t, err, "Failed to export trace: %v", err)
 	require.NotNil(t, resp, "The response is missing")
@@ -42,7 +43,7 @@ func TestExport(t *testing.T) {
 
 func TestExport_EmptyRequest(t *testing.T) {
 	traceSink := new(consumertest.TracesSink)
-	traceClient := makeTraceServiceClient(t, traceSink)
+	traceClient := makeTraceServiceClient(t, traceSink, 0)
 	resp, err := traceClient.Export(context.Background(), ptraceotlp.NewExportRequest())
 	require.NoError(t, err, "Failed to export trace: %v", err)
 	assert.NotNil(t, resp, "The response is missing")
@@ -52,7 +53,7 @@ func TestExport_NonPermanentErrorConsumer(t *testing.T) {
 	td := testdata.GenerateTraces(1)
 	req := ptraceotlp.NewExportRequestFromTraces(td)
 
-	traceClient := makeTraceServiceClient(t, consumertest.NewErr(errors.New("my error")))
+	traceClient := makeTraceServiceClient(t, consumertest.NewErr(errors.New("my error")), 0)
 	resp, err := traceClient.Export(context.Background(), req)
 	require.EqualError(t, err, "rpc error: code = Unavailable desc = my error")
 	assert.IsType(t, status.Error(codes.Unknown, ""), err)
@@ -62,15 +63,46 @@ func TestExport_PermanentErrorConsumer(t *testing.T) {
 	ld := testdata.GenerateTraces(1)
 	req := ptraceotlp.NewExportRequestFromTraces(ld)
 
-	traceClient := makeTraceServiceClient(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("my error"))))
+	traceClient := makeTraceServiceClient(t, consumertest.NewErr(consumererror.NewPermanent(errors.New("my error"))), 0)
 	resp, err := traceClient.Export(context.Background(), req)
 	require.EqualError(t, err, "rpc error: code = Internal desc = Permanent error: my error")
 	assert.IsType(t, status.Error(codes.Unknown, ""), err)
 	assert.Equal(t, ptraceotlp.ExportResponse{}, resp)
 }
 
-func makeTraceServiceClient(t *testing.T, tc consumer.Traces) ptraceotlp.GRPCClient {
-	addr := otlpReceiverOnGRPCServer(t, tc)
+func TestExport_PartialSuccess(t *testing.T) {
+	req := ptraceotlp.NewExportRequestFromTraces(testdata.GenerateTraces(2))
+
+	client := makeTraceServiceClient(t, consumertest.NewErr(consumererror.NewTraces(errors.New("my error"), testdata.GenerateTraces(1))), 0)
+	resp, err := client.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(testdata.GenerateTraces(1).SpanCount()), resp.PartialSuccess().RejectedSpans())
+	assert.Equal(t, "my error", resp.PartialSuccess().ErrorMessage())
+
+	// The request fails as a whole when all the items failed.
+	client = makeTraceServiceClient(t, consumertest.NewErr(consumererror.NewTraces(errors.New("my error"), testdata.GenerateTraces(2))), 0)
+	_, err = client.Export(context.Background(), req)
+	require.EqualError(t, err, "rpc error: code = Unavailable desc = my error")
+}
+
+func TestExport_MaxItemsPerRequest(t *testing.T) {
+	req := ptraceotlp.NewExportRequestFromTraces(testdata.GenerateTraces(2))
+	count := testdata.GenerateTraces(2).SpanCount()
+
+	sink := new(consumertest.TracesSink)
+	client := makeTraceServiceClient(t, sink, count-1)
+	_, err := client.Export(context.Background(), req)
+	require.EqualError(t, err, fmt.Sprintf("rpc error: code = InvalidArgument desc = the request contains %d spans, more than the limit of %d spans per request", count, count-1))
+	assert.Zero(t, sink.SpanCount())
+
+	client = makeTraceServiceClient(t, sink, count)
+	_, err = client.Export(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, count, sink.SpanCount())
+}
+
+func makeTraceServiceClient(t *testing.T, tc consumer.Traces, maxItems int) ptraceotlp.GRPCClient {
+	addr := otlpReceiverOnGRPCServer(t, tc, maxItems)
 	cc, err := grpc.NewClient(addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	require.NoError(t, err, "Failed to create the TraceServiceClient: %v", err)
 	t.Cleanup(func() {
@@ -80,7 +112,7 @@ func makeTraceServiceClient(t *testing.T, tc consumer.Traces) ptraceotlp.GRPCCli
 	return ptraceotlp.NewGRPCClient(cc)
 }
 
-func otlpReceiverOnGRPCServer(t *testing.T, tc consumer.Traces) net.Addr {
+func otlpReceiverOnGRPCServer(t *testing.T, tc consumer.Traces, maxItems int) net.Addr {
 	ln, err := net.Listen("tcp", "localhost:")
 	require.NoError(t, err, "Failed to find an available address to run the gRPC server: %v", err)
 
@@ -96,7 +128,7 @@ func otlpReceiverOnGRPCServer(t *testing.T, tc consumer.Traces) net.Addr {
 		ReceiverCreateSettings: set,
 	})
 	require.NoError(t, err)
-	r := New(tc, obsreport)
+	r := New(tc, obsreport, maxItems)
 	// Now run it as a gRPC server
 	srv := grpc.NewServer()
 	ptraceotlp.RegisterGRPCServer(srv, r)
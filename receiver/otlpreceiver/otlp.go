@@ -15,6 +15,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerprofiles"
@@ -23,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pprofile/pprofileotlp"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/admission"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metrics"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/profiles"
@@ -35,6 +37,10 @@ type otlpReceiver struct {
 	cfg        *Config
 	serverGRPC *grpc.Server
 	serverHTTP *http.Server
+	// serverHTTP3 is nil if the experimental HTTP/3 server is not enabled.
+	serverHTTP3 *confighttp.HTTP3Server
+	// cancelGRPC stops watching the TLS files of the gRPC server.
+	cancelGRPC context.CancelFunc
 
 	nextTraces   consumer.Traces
 	nextMetrics  consumer.Metrics
@@ -45,6 +51,9 @@ type otlpReceiver struct {
 	obsrepGRPC *receiverhelper.ObsReport
 	obsrepHTTP *receiverhelper.ObsReport
 
+	// admission is nil if the requests processed concurrently are not limited.
+	admission *admission.Controller
+
 	settings *receiver.Settings
 }
 
@@ -79,6 +88,10 @@ func newOtlpReceiver(cfg *Config, set *receiver.Settings) (*otlpReceiver, error)
 		return nil, err
 	}
 
+	if cfg.Admission.MaxConcurrentRequests > 0 || cfg.Admission.MaxInFlightBytes > 0 {
+		r.admission = admission.New(cfg.Admission.MaxConcurrentRequests, cfg.Admission.MaxInFlightBytes)
+	}
+
 	return r, nil
 }
 
@@ -88,21 +101,38 @@ func (r *otlpReceiver) startGRPCServer(host component.Host) error {
 		return nil
 	}
 
+	var opts []configgrpc.ToServerOption
+	if r.admission != nil {
+		// The size of the messages is only known once they are read, the largest message accepted is admitted
+		// for each request until then.
+		maxMessageSize := int64(defaultGRPCMaxRecvMsgSize)
+		if r.cfg.GRPC.MaxRecvMsgSizeMiB > 0 {
+			maxMessageSize = int64(r.cfg.GRPC.MaxRecvMsgSizeMiB) * 1024 * 1024
+		}
+		opts = append(opts,
+			configgrpc.WithTapHandle(r.admission.TapHandle(maxMessageSize)),
+			configgrpc.WithGrpcServerOption(grpc.ChainUnaryInterceptor(r.admission.UnaryServerInterceptor)))
+	}
+
+	// The TLS files of the gRPC server are watched until shutdown.
+	ctx, cancel := context.WithCancel(context.Background())
 	var err error
-	if r.serverGRPC, err = r.cfg.GRPC.ToServer(context.Background(), host, r.settings.TelemetrySettings); err != nil {
+	if r.serverGRPC, err = r.cfg.GRPC.ToServer(ctx, host, r.settings.TelemetrySettings, opts...); err != nil {
+		cancel()
 		return err
 	}
+	r.cancelGRPC = cancel
 
 	if r.nextTraces != nil {
-		ptraceotlp.RegisterGRPCServer(r.serverGRPC, trace.New(r.nextTraces, r.obsrepGRPC))
+		ptraceotlp.RegisterGRPCServer(r.serverGRPC, trace.New(r.nextTraces, r.obsrepGRPC, r.cfg.MaxItemsPerRequest.Traces))
 	}
 
 	if r.nextMetrics != nil {
-		pmetricotlp.RegisterGRPCServer(r.serverGRPC, metrics.New(r.nextMetrics, r.obsrepGRPC))
+		pmetricotlp.RegisterGRPCServer(r.serverGRPC, metrics.New(r.nextMetrics, r.obsrepGRPC, r.cfg.MaxItemsPerRequest.Metrics))
 	}
 
 	if r.nextLogs != nil {
-		plogotlp.RegisterGRPCServer(r.serverGRPC, logs.New(r.nextLogs, r.obsrepGRPC))
+		plogotlp.RegisterGRPCServer(r.serverGRPC, logs.New(r.nextLogs, r.obsrepGRPC, r.cfg.MaxItemsPerRequest.Logs))
 	}
 
 	if r.nextProfiles != nil {
@@ -134,21 +164,21 @@ func (r *otlpReceiver) startHTTPServer(ctx context.Context, host component.Host)
 
 	httpMux := http.NewServeMux()
 	if r.nextTraces != nil {
-		httpTracesReceiver := trace.New(r.nextTraces, r.obsrepHTTP)
+		httpTracesReceiver := trace.New(r.nextTraces, r.obsrepHTTP, r.cfg.MaxItemsPerRequest.Traces)
 		httpMux.HandleFunc(r.cfg.HTTP.TracesURLPath, func(resp http.ResponseWriter, req *http.Request) {
 			handleTraces(resp, req, httpTracesReceiver)
 		})
 	}
 
 	if r.nextMetrics != nil {
-		httpMetricsReceiver := metrics.New(r.nextMetrics, r.obsrepHTTP)
+		httpMetricsReceiver := metrics.New(r.nextMetrics, r.obsrepHTTP, r.cfg.MaxItemsPerRequest.Metrics)
 		httpMux.HandleFunc(r.cfg.HTTP.MetricsURLPath, func(resp http.ResponseWriter, req *http.Request) {
 			handleMetrics(resp, req, httpMetricsReceiver)
 		})
 	}
 
 	if r.nextLogs != nil {
-		httpLogsReceiver := logs.New(r.nextLogs, r.obsrepHTTP)
+		httpLogsReceiver := logs.New(r.nextLogs, r.obsrepHTTP, r.cfg.MaxItemsPerRequest.Logs)
 		httpMux.HandleFunc(r.cfg.HTTP.LogsURLPath, func(resp http.ResponseWriter, req *http.Request) {
 			handleLogs(resp, req, httpLogsReceiver)
 		})
@@ -161,17 +191,38 @@ func (r *otlpReceiver) startHTTPServer(ctx context.Context, host component.Host)
 		})
 	}
 
+	var handler http.Handler = httpMux
+	if r.admission != nil {
+		handler = admissionHandler(r.admission, httpMux)
+	}
+
 	var err error
-	if r.serverHTTP, err = r.cfg.HTTP.ToServer(ctx, host, r.settings.TelemetrySettings, httpMux, confighttp.WithErrorHandler(errorHandler)); err != nil {
+	if r.serverHTTP, err = r.cfg.HTTP.ToServer(ctx, host, r.settings.TelemetrySettings, handler, confighttp.WithErrorHandler(errorHandler)); err != nil {
 		return err
 	}
 
 	r.settings.Logger.Info("Starting HTTP server", zap.String("endpoint", r.cfg.HTTP.ServerConfig.Endpoint))
 	var hln net.Listener
-	if hln, err = r.cfg.HTTP.ServerConfig.ToListener(ctx); err != nil {
+	if hln, err = r.cfg.HTTP.ServerConfig.ToListener(ctx, confighttp.WithListenerTelemetry(host, r.settings.TelemetrySettings)); err != nil {
 		return err
 	}
 
+	if http3 := r.cfg.HTTP.ServerConfig.HTTP3; http3 != nil && http3.Enabled {
+		r.settings.Logger.Info("Starting HTTP/3 server", zap.String("endpoint", r.cfg.HTTP.ServerConfig.Endpoint))
+		if r.serverHTTP3, err = r.cfg.HTTP.ServerConfig.ToHTTP3Server(ctx, r.serverHTTP, confighttp.WithListenerTelemetry(host, r.settings.TelemetrySettings)); err != nil {
+			return errors.Join(err, hln.Close())
+		}
+
+		r.shutdownWG.Add(1)
+		go func() {
+			defer r.shutdownWG.Done()
+
+			if errHTTP3 := r.serverHTTP3.Serve(); errHTTP3 != nil && !errors.Is(errHTTP3, http.ErrServerClosed) {
+				componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(errHTTP3))
+			}
+		}()
+	}
+
 	r.shutdownWG.Add(1)
 	go func() {
 		defer r.shutdownWG.Done()
@@ -207,9 +258,16 @@ func (r *otlpReceiver) Shutdown(ctx context.Context) error {
 		err = r.serverHTTP.Shutdown(ctx)
 	}
 
+	if r.serverHTTP3 != nil {
+		err = errors.Join(err, r.serverHTTP3.Shutdown(ctx))
+	}
+
 	if r.serverGRPC != nil {
 		r.serverGRPC.GracefulStop()
 	}
+	if r.cancelGRPC != nil {
+		r.cancelGRPC()
+	}
 
 	r.shutdownWG.Wait()
 	return err
@@ -781,6 +781,36 @@ func TestHTTPInvalidTLSCredentials(t *testing.T) {
 		`failed to load TLS config: failed to load TLS cert and key: for auth via TLS, provide both certificate and key, or neither`)
 }
 
+func TestHTTP3WithoutTLS(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := &Config{
+		Protocols: Protocols{
+			HTTP: &HTTPConfig{
+				ServerConfig: &confighttp.ServerConfig{
+					Endpoint: addr,
+					HTTP3:    &confighttp.HTTP3Config{Enabled: true},
+				},
+				TracesURLPath:  defaultTracesURLPath,
+				MetricsURLPath: defaultMetricsURLPath,
+				LogsURLPath:    defaultLogsURLPath,
+			},
+		},
+	}
+
+	r, err := NewFactory().CreateTraces(
+		context.Background(),
+		receivertest.NewNopSettings(),
+		cfg,
+		consumertest.NewNop())
+	require.NoError(t, err)
+	require.EqualError(t, r.Start(context.Background(), componenttest.NewNopHost()), "http3 requires tls settings with a certificate")
+
+	// The TCP listener is closed when the HTTP/3 server cannot be created.
+	ln, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+}
+
 func testHTTPMaxRequestBodySize(t *testing.T, path string, contentType string, payload []byte, size int, expectedStatusCode int) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	url := "http://" + addr + path
@@ -823,6 +853,151 @@ func TestHTTPMaxRequestBodySize(t *testing.T) {
 	}
 }
 
+func TestHTTPAdmission(t *testing.T) {
+	dr := generateTracesRequest(t)
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.Admission.MaxInFlightBytes = int64(len(dr.protoBytes) - 1)
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, consumertest.NewNop())
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	// The size of uncompressed requests is known before reading their body, the size of
+	// compressed requests is admitted as they are decompressed.
+	for _, encoding := range []string{"", "gzip"} {
+		req := createHTTPRequest(t, "http://"+addr+dr.path, encoding, "application/x-protobuf", dr.protoBytes)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_, err = io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	}
+}
+
+func TestGRPCAdmission(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := newErrOrSinkConsumer()
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	cfg.HTTP = nil
+	cfg.Admission.MaxInFlightBytes = 1024
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, sink)
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	cc, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	err = exportTraces(cc, testdata.GenerateTraces(100))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+	require.Len(t, sink.AllTraces(), 1)
+}
+
+type memoryLimiterExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	refuse bool
+}
+
+func (ml *memoryLimiterExtension) MustRefuse() bool {
+	return ml.refuse
+}
+
+type memoryLimiterHost struct {
+	component.Host
+	ml *memoryLimiterExtension
+}
+
+func (h memoryLimiterHost) GetExtensions() map[component.ID]component.Component {
+	return map[component.ID]component.Component{component.MustNewID("memory_limiter"): h.ml}
+}
+
+func TestGRPCAdmissionWithMemoryLimiter(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	sink := newErrOrSinkConsumer()
+	cfg := createDefaultConfig().(*Config)
+	cfg.GRPC.NetAddr.Endpoint = addr
+	mlID := component.MustNewID("memory_limiter")
+	cfg.GRPC.MemoryLimiter = &mlID
+	cfg.HTTP = nil
+	cfg.Admission.MaxInFlightBytes = 1024
+	ml := &memoryLimiterExtension{}
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, sink)
+	require.NoError(t, recv.Start(context.Background(), memoryLimiterHost{Host: componenttest.NewNopHost(), ml: ml}))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	cc, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cc.Close())
+	}()
+
+	err = exportTraces(cc, testdata.GenerateTraces(100))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.NoError(t, exportTraces(cc, testdata.GenerateTraces(1)))
+	require.Len(t, sink.AllTraces(), 1)
+
+	ml.refuse = true
+	err = exportTraces(cc, testdata.GenerateTraces(1))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	require.Len(t, sink.AllTraces(), 1)
+}
+
+func TestHTTPPartialSuccess(t *testing.T) {
+	dr := generateTracesRequest(t)
+	addr := testutil.GetAvailableLocalAddress(t)
+	failed := testdata.GenerateTraces(1)
+	recv := newHTTPReceiver(t, componenttest.NewNopTelemetrySettings(), addr, consumertest.NewErr(consumererror.NewTraces(errors.New("my error"), failed)))
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	req := createHTTPRequest(t, "http://"+addr+dr.path, "", "application/x-protobuf", dr.protoBytes)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	respBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	tr := ptraceotlp.NewExportResponse()
+	require.NoError(t, tr.UnmarshalProto(respBytes))
+	assert.Equal(t, int64(failed.SpanCount()), tr.PartialSuccess().RejectedSpans())
+	assert.Equal(t, "my error", tr.PartialSuccess().ErrorMessage())
+}
+
+func TestHTTPMaxItemsPerRequest(t *testing.T) {
+	dr := generateTracesRequest(t)
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.HTTP.Endpoint = addr
+	cfg.GRPC = nil
+	cfg.MaxItemsPerRequest.Traces = 1
+	sink := newErrOrSinkConsumer()
+	recv := newReceiver(t, componenttest.NewNopTelemetrySettings(), cfg, otlpReceiverID, sink)
+	require.NoError(t, recv.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { require.NoError(t, recv.Shutdown(context.Background())) })
+
+	req := createHTTPRequest(t, "http://"+addr+dr.path, "", "application/x-protobuf", dr.protoBytes)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	respBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	errStatus := &spb.Status{}
+	require.NoError(t, proto.Unmarshal(respBytes, errStatus))
+	assert.Equal(t, "the request contains 2 spans, more than the limit of 1 spans per request", errStatus.GetMessage())
+	assert.Empty(t, sink.AllTraces())
+}
+
 func newGRPCReceiver(t *testing.T, settings component.TelemetrySettings, endpoint string, c consumertest.Consumer) component.Component {
 	cfg := createDefaultConfig().(*Config)
 	cfg.GRPC.NetAddr.Endpoint = endpoint
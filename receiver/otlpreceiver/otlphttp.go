@@ -4,6 +4,7 @@
 package otlpreceiver // import "go.opentelemetry.io/collector/receiver/otlpreceiver"
 
 import (
+	stderrors "errors"
 	"fmt"
 	"io"
 	"mime"
@@ -13,6 +14,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"go.opentelemetry.io/collector/internal/httphelper"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/admission"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/errors"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/logs"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver/internal/metrics"
@@ -169,7 +171,11 @@ func readContentType(resp http.ResponseWriter, req *http.Request) (encoder, bool
 func readAndCloseBody(resp http.ResponseWriter, req *http.Request, enc encoder) ([]byte, bool) {
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
-		writeError(resp, enc, err, http.StatusBadRequest)
+		statusCode := http.StatusBadRequest
+		if stderrors.Is(err, admission.ErrTooManyRequests) {
+			statusCode = http.StatusTooManyRequests
+		}
+		writeError(resp, enc, err, statusCode)
 		return nil, false
 	}
 	if err = req.Body.Close(); err != nil {
@@ -179,6 +185,25 @@ func readAndCloseBody(resp http.ResponseWriter, req *http.Request, enc encoder)
 	return body, true
 }
 
+// admissionHandler rejects the requests with a 429 status code, before reading their body, when the receiver
+// is processing too many requests or too much data. The body of the admitted requests is admitted as it is read.
+func admissionHandler(ctl *admission.Controller, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := max(r.ContentLength, 0)
+		if !ctl.TryAcquire(1, size) {
+			errorHandler(w, r, admission.ErrTooManyRequests.Error(), http.StatusTooManyRequests)
+			return
+		}
+		body := admission.NewBody(ctl, r.Body, size)
+		defer func() {
+			body.Release()
+			ctl.Release(1, 0)
+		}()
+		r.Body = body
+		next.ServeHTTP(w, r)
+	})
+}
+
 // writeError encodes the HTTP error inside a rpc.Status message as required by the OTLP protocol.
 func writeError(w http.ResponseWriter, encoder encoder, err error, statusCode int) {
 	s, ok := status.FromError(err)
@@ -14,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pipeline"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/internal"
@@ -157,7 +158,11 @@ func (rec *ObsReport) endOp(
 ) {
 	numAccepted := numReceivedItems
 	numRefused := 0
-	if err != nil {
+	if rejected, ok := consumererror.RejectedItems(err); ok {
+		// Only the rejected items of a partial success are refused.
+		numRefused = min(int(rejected), numReceivedItems)
+		numAccepted = numReceivedItems - numRefused
+	} else if err != nil {
 		numAccepted = 0
 		numRefused = numReceivedItems
 	}
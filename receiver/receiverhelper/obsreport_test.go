@@ -15,6 +15,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/internal"
 )
@@ -43,6 +44,7 @@ func TestReceiveTraceDataOp(t *testing.T) {
 		params := []testParams{
 			{items: 13, err: errFake},
 			{items: 42, err: nil},
+			{items: 10, err: consumererror.NewPartialSuccess(errFake, 3)},
 		}
 		for i, param := range params {
 			rec, err := newReceiver(ObsReportSettings{
@@ -68,6 +70,13 @@ func TestReceiveTraceDataOp(t *testing.T) {
 				require.Contains(t, span.Attributes(), attribute.KeyValue{Key: internal.AcceptedSpansKey, Value: attribute.Int64Value(int64(params[i].items))})
 				require.Contains(t, span.Attributes(), attribute.KeyValue{Key: internal.RefusedSpansKey, Value: attribute.Int64Value(0)})
 				assert.Equal(t, codes.Unset, span.Status().Code)
+			case errors.As(params[i].err, new(consumererror.PartialSuccess)):
+				// Only the rejected spans are refused.
+				acceptedSpans += 7
+				refusedSpans += 3
+				require.Contains(t, span.Attributes(), attribute.KeyValue{Key: internal.AcceptedSpansKey, Value: attribute.Int64Value(7)})
+				require.Contains(t, span.Attributes(), attribute.KeyValue{Key: internal.RefusedSpansKey, Value: attribute.Int64Value(3)})
+				assert.Equal(t, codes.Error, span.Status().Code)
 			case errors.Is(params[i].err, errFake):
 				refusedSpans += params[i].items
 				require.Contains(t, span.Attributes(), attribute.KeyValue{Key: internal.AcceptedSpansKey, Value: attribute.Int64Value(0)})
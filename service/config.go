@@ -4,8 +4,12 @@
 package service // import "go.opentelemetry.io/collector/service"
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/service/extensions"
 	"go.opentelemetry.io/collector/service/pipelines"
 	"go.opentelemetry.io/collector/service/telemetry"
@@ -21,6 +25,25 @@ type Config struct {
 
 	// Pipelines are the set of data pipelines configured for the service.
 	Pipelines pipelines.Config `mapstructure:"pipelines"`
+
+	// Shutdown defines how the pipelines are drained when the service is shut down.
+	Shutdown ShutdownConfig `mapstructure:"shutdown"`
+}
+
+// ShutdownConfig defines how the pipelines are drained when the service is shut down. The receivers are stopped
+// first, then the processors and the exporters are shut down in topological order so that they flush their data.
+type ShutdownConfig struct {
+	// DrainTimeout is the maximum time given to the processors and the exporters to flush their data.
+	// Once it is exceeded, the exporters stop draining their queue. Zero means no limit.
+	DrainTimeout time.Duration `mapstructure:"drain_timeout"`
+
+	// PersistUnsent if true, the requests left in the memory queue of the exporters once DrainTimeout is exceeded
+	// are persisted in the Storage extension and sent after the restart, instead of being dropped.
+	// It is supported by the exporters built with exporterhelper.
+	PersistUnsent bool `mapstructure:"persist_unsent"`
+
+	// Storage is the ID of the storage extension used by PersistUnsent. It must be one of the service extensions.
+	Storage *component.ID `mapstructure:"storage"`
 }
 
 func (cfg *Config) Validate() error {
@@ -28,9 +51,26 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("service::pipelines config validation failed: %w", err)
 	}
 
+	if err := cfg.Shutdown.validate(cfg.Extensions); err != nil {
+		return fmt.Errorf("service::shutdown config validation failed: %w", err)
+	}
+
 	if err := cfg.Telemetry.Validate(); err != nil {
 		fmt.Printf("service::telemetry config validation failed: %v\n", err)
 	}
 
 	return nil
 }
+
+func (cfg *ShutdownConfig) validate(exts extensions.Config) error {
+	if cfg.DrainTimeout < 0 {
+		return errors.New("drain_timeout must not be negative")
+	}
+	if cfg.PersistUnsent && cfg.Storage == nil {
+		return errors.New("persist_unsent requires a storage extension")
+	}
+	if cfg.Storage != nil && !slices.Contains(exts, *cfg.Storage) {
+		return fmt.Errorf("storage extension %q is not one of the service extensions", cfg.Storage)
+	}
+	return nil
+}
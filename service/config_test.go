@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/contrib/config"
@@ -73,6 +74,46 @@ func TestConfigValidate(t *testing.T) {
 			},
 			expected: nil,
 		},
+		{
+			name: "valid-shutdown",
+			cfgFn: func() *Config {
+				cfg := generateConfig()
+				cfg.Shutdown.DrainTimeout = 30 * time.Second
+				cfg.Shutdown.PersistUnsent = true
+				cfg.Shutdown.Storage = &cfg.Extensions[0]
+				return cfg
+			},
+			expected: nil,
+		},
+		{
+			name: "negative-drain-timeout",
+			cfgFn: func() *Config {
+				cfg := generateConfig()
+				cfg.Shutdown.DrainTimeout = -time.Second
+				return cfg
+			},
+			expected: fmt.Errorf(`service::shutdown config validation failed: %w`, errors.New(`drain_timeout must not be negative`)),
+		},
+		{
+			name: "persist-unsent-without-storage",
+			cfgFn: func() *Config {
+				cfg := generateConfig()
+				cfg.Shutdown.PersistUnsent = true
+				return cfg
+			},
+			expected: fmt.Errorf(`service::shutdown config validation failed: %w`, errors.New(`persist_unsent requires a storage extension`)),
+		},
+		{
+			name: "unknown-shutdown-storage",
+			cfgFn: func() *Config {
+				cfg := generateConfig()
+				storageID := component.MustNewID("file_storage")
+				cfg.Shutdown.PersistUnsent = true
+				cfg.Shutdown.Storage = &storageID
+				return cfg
+			},
+			expected: fmt.Errorf(`service::shutdown config validation failed: %w`, errors.New(`storage extension "file_storage" is not one of the service extensions`)),
+		},
 	}
 
 	for _, tt := range testCases {
@@ -380,6 +380,11 @@ func (b *ConnectorBuilder) Factory(componentType component.Type) component.Facto
 	return b.factories[componentType]
 }
 
+// Config returns the configuration of the connector with the given ID, nil if it is not configured.
+func (b *ConnectorBuilder) Config(componentID component.ID) component.Config {
+	return b.cfgs[componentID]
+}
+
 // NewNopConnectorConfigsAndFactories returns a configuration and factories that allows building a new nop connector.
 func NewNopConnectorConfigsAndFactories() (map[component.ID]component.Config, map[component.Type]connector.Factory) {
 	nopFactory := connectortest.NewNopFactory()
@@ -98,6 +98,11 @@ func (b *ExporterBuilder) Factory(componentType component.Type) component.Factor
 	return b.factories[componentType]
 }
 
+// Config returns the configuration of the exporter with the given ID, nil if it is not configured.
+func (b *ExporterBuilder) Config(componentID component.ID) component.Config {
+	return b.cfgs[componentID]
+}
+
 // NewNopExporterConfigsAndFactories returns a configuration and factories that allows building a new nop exporter.
 func NewNopExporterConfigsAndFactories() (map[component.ID]component.Config, map[component.Type]exporter.Factory) {
 	nopFactory := exportertest.NewNopFactory()
@@ -114,6 +114,11 @@ func (b *ReceiverBuilder) Factory(componentType component.Type) component.Factor
 	return b.factories[componentType]
 }
 
+// Config returns the configuration of the receiver with the given ID, nil if it is not configured.
+func (b *ReceiverBuilder) Config(componentID component.ID) component.Config {
+	return b.cfgs[componentID]
+}
+
 // NewNopReceiverConfigsAndFactories returns a configuration and factories that allows building a new nop receiver.
 func NewNopReceiverConfigsAndFactories() (map[component.ID]component.Config, map[component.Type]receiver.Factory) {
 	nopFactory := receivertest.NewNopFactory()
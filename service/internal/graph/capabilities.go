@@ -4,8 +4,15 @@
 package graph // import "go.opentelemetry.io/collector/service/internal/graph"
 
 import (
+	"context"
+	"sync/atomic"
+
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pipeline"
 )
 
@@ -18,23 +25,52 @@ var _ consumerNode = (*capabilitiesNode)(nil)
 // 1. Present aggregated capabilities to receivers, such as whether the pipeline mutates data.
 // 2. Present a consistent "first consumer" for each pipeline.
 // The nodeID is derived from "pipeline ID".
+//
+// The next consumer is shared with the capabilities node of the same pipeline in the graph built by
+// Graph.Reload, so that the receivers of the pipeline keep running while its processors and exporters are replaced.
 type capabilitiesNode struct {
 	nodeID
 	pipelineID pipeline.ID
+	next       *atomic.Pointer[capabilitiesNext]
+}
+
+type capabilitiesNext struct {
 	baseConsumer
-	consumer.ConsumeTracesFunc
-	consumer.ConsumeMetricsFunc
-	consumer.ConsumeLogsFunc
-	consumerprofiles.ConsumeProfilesFunc
 }
 
 func newCapabilitiesNode(pipelineID pipeline.ID) *capabilitiesNode {
 	return &capabilitiesNode{
 		nodeID:     newNodeID(capabilitiesSeed, pipelineID.String()),
 		pipelineID: pipelineID,
+		next:       &atomic.Pointer[capabilitiesNext]{},
 	}
 }
 
 func (n *capabilitiesNode) getConsumer() baseConsumer {
 	return n
 }
+
+// setNext sets the consumer of the pipeline, the capability consumer in front of the first processor or the fan-out node.
+func (n *capabilitiesNode) setNext(next baseConsumer) {
+	n.next.Store(&capabilitiesNext{baseConsumer: next})
+}
+
+func (n *capabilitiesNode) Capabilities() consumer.Capabilities {
+	return n.next.Load().Capabilities()
+}
+
+func (n *capabilitiesNode) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	return n.next.Load().baseConsumer.(consumer.Traces).ConsumeTraces(ctx, td)
+}
+
+func (n *capabilitiesNode) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	return n.next.Load().baseConsumer.(consumer.Metrics).ConsumeMetrics(ctx, md)
+}
+
+func (n *capabilitiesNode) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	return n.next.Load().baseConsumer.(consumer.Logs).ConsumeLogs(ctx, ld)
+}
+
+func (n *capabilitiesNode) ConsumeProfiles(ctx context.Context, pd pprofile.Profiles) error {
+	return n.next.Load().baseConsumer.(consumerprofiles.Profiles).ConsumeProfiles(ctx, pd)
+}
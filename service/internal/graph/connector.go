@@ -75,7 +75,7 @@ func (n *connectorNode) buildTraces(
 ) error {
 	consumers := make(map[pipeline.ID]consumer.Traces, len(nexts))
 	for _, next := range nexts {
-		consumers[next.(*capabilitiesNode).pipelineID] = next.(consumer.Traces)
+		consumers[nextPipelineID(next)] = next.(consumer.Traces)
 	}
 	next := connector.NewTracesRouter(consumers)
 
@@ -110,7 +110,7 @@ func (n *connectorNode) buildMetrics(
 ) error {
 	consumers := make(map[pipeline.ID]consumer.Metrics, len(nexts))
 	for _, next := range nexts {
-		consumers[next.(*capabilitiesNode).pipelineID] = next.(consumer.Metrics)
+		consumers[nextPipelineID(next)] = next.(consumer.Metrics)
 	}
 	next := connector.NewMetricsRouter(consumers)
 
@@ -145,7 +145,7 @@ func (n *connectorNode) buildLogs(
 ) error {
 	consumers := make(map[pipeline.ID]consumer.Logs, len(nexts))
 	for _, next := range nexts {
-		consumers[next.(*capabilitiesNode).pipelineID] = next.(consumer.Logs)
+		consumers[nextPipelineID(next)] = next.(consumer.Logs)
 	}
 	next := connector.NewLogsRouter(consumers)
 
@@ -180,7 +180,7 @@ func (n *connectorNode) buildProfiles(
 ) error {
 	consumers := make(map[pipeline.ID]consumerprofiles.Profiles, len(nexts))
 	for _, next := range nexts {
-		consumers[next.(*capabilitiesNode).pipelineID] = next.(consumerprofiles.Profiles)
+		consumers[nextPipelineID(next)] = next.(consumerprofiles.Profiles)
 	}
 	next := connectorprofiles.NewProfilesRouter(consumers)
 
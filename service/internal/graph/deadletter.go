@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph // import "go.opentelemetry.io/collector/service/internal/graph"
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+// deadLetterConfig is implemented by the dead-letter configuration of the exporters using the exporterhelper.
+type deadLetterConfig interface {
+	DeadLetterExporterID() (component.ID, bool)
+}
+
+// deadLetterExporter returns the ID of the dead-letter exporter set in the fields of the given exporter
+// configuration, if any.
+func deadLetterExporter(cfg component.Config) (component.ID, bool) {
+	return findDeadLetterExporter(reflect.ValueOf(cfg))
+}
+
+func findDeadLetterExporter(v reflect.Value) (component.ID, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return component.ID{}, false
+		}
+	case reflect.Invalid:
+		return component.ID{}, false
+	}
+	if dl, ok := v.Interface().(deadLetterConfig); ok {
+		return dl.DeadLetterExporterID()
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return findDeadLetterExporter(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if id, ok := findDeadLetterExporter(v.Field(i)); ok {
+				return id, true
+			}
+		}
+	}
+	return component.ID{}, false
+}
+
+// linkDeadLetters records the dead-letter exporter of the exporters sending their failed data to another exporter
+// of the graph. The exporters are ordered before their dead-letter exporter, see sortedNodes: the dead-letter
+// exporter is started before and shut down after them. A dead-letter exporter closing a cycle of dead-letter
+// exporters is not recorded.
+func (g *Graph) linkDeadLetters(set Settings) {
+	var exporters []*exporterNode
+	for _, pg := range g.pipelines {
+		for _, node := range pg.exporters {
+			if expNode, ok := node.(*exporterNode); ok && !slices.Contains(exporters, expNode) {
+				exporters = append(exporters, expNode)
+			}
+		}
+	}
+	// The cycles are broken the same way for the same configuration.
+	slices.SortFunc(exporters, func(a, b *exporterNode) int {
+		return cmp.Or(cmp.Compare(a.pipelineType.String(), b.pipelineType.String()),
+			cmp.Compare(a.componentID.String(), b.componentID.String()))
+	})
+
+	order := simple.NewDirectedGraph()
+	graph.Copy(order, g.componentGraph)
+	for _, expNode := range exporters {
+		id, ok := deadLetterExporter(set.ExporterBuilder.Config(expNode.componentID))
+		if !ok {
+			continue
+		}
+		to, ok := g.componentGraph.Node(newExporterNode(expNode.pipelineType, id).ID()).(*exporterNode)
+		if !ok || to == expNode || topo.PathExistsIn(order, to, expNode) {
+			continue
+		}
+		order.SetEdge(order.NewEdge(expNode, to))
+		g.deadLetters[expNode.ID()] = to.ID()
+	}
+}
+
+// sortedNodes returns the nodes of the graph in topological order, with the exporters before their dead-letter
+// exporter.
+func (g *Graph) sortedNodes() ([]graph.Node, error) {
+	if len(g.deadLetters) == 0 {
+		return topo.Sort(g.componentGraph)
+	}
+	order := simple.NewDirectedGraph()
+	graph.Copy(order, g.componentGraph)
+	for from, to := range g.deadLetters {
+		order.SetEdge(order.NewEdge(order.Node(from), order.Node(to)))
+	}
+	return topo.Sort(order)
+}
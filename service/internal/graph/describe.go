@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph // import "go.opentelemetry.io/collector/service/internal/graph"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// The formats the graph can be written in, see Graph.Write.
+const (
+	FormatDOT  = "dot"
+	FormatJSON = "json"
+)
+
+// The kinds of the nodes of the graph.
+const (
+	nodeKindReceiver     = "receiver"
+	nodeKindProcessor    = "processor"
+	nodeKindExporter     = "exporter"
+	nodeKindConnector    = "connector"
+	nodeKindCapabilities = "capabilities"
+	nodeKindFanOut       = "fanout"
+)
+
+// description is the representation of the graph written by Graph.Write.
+// The format is not stable and can change between releases.
+type description struct {
+	Nodes []nodeDescription `json:"nodes"`
+	Edges []edgeDescription `json:"edges"`
+}
+
+type nodeDescription struct {
+	// ID identifies the node in the edges. It is derived from the same parts as the nodeID.
+	ID          string   `json:"id"`
+	Kind        string   `json:"kind"`
+	ComponentID string   `json:"component_id,omitempty"`
+	Pipelines   []string `json:"pipelines"`
+	// MutatesData is the capability of the consumer of the node, receivers do not consume data.
+	MutatesData bool `json:"mutates_data"`
+}
+
+type edgeDescription struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Write writes the nodes and the edges of the graph to w in the given format, FormatDOT or FormatJSON.
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatDOT:
+		return writeDOT(w, g.describe())
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g.describe())
+	default:
+		return fmt.Errorf("unsupported graph format %q, must be %q or %q", format, FormatDOT, FormatJSON)
+	}
+}
+
+func (g *Graph) describe() description {
+	pipelineIDs := g.nodePipelines()
+	nodes := make(map[int64]nodeDescription)
+	for it := g.componentGraph.Nodes(); it.Next(); {
+		n := it.Node()
+		desc := describeNode(n)
+		for _, pipelineID := range pipelineIDs[n.ID()] {
+			desc.Pipelines = append(desc.Pipelines, pipelineID.String())
+		}
+		slices.Sort(desc.Pipelines)
+		nodes[n.ID()] = desc
+	}
+
+	var d description
+	for _, n := range nodes {
+		d.Nodes = append(d.Nodes, n)
+	}
+	slices.SortFunc(d.Nodes, func(a, b nodeDescription) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	for it := g.componentGraph.Edges(); it.Next(); {
+		e := it.Edge()
+		d.Edges = append(d.Edges, edgeDescription{From: nodes[e.From().ID()].ID, To: nodes[e.To().ID()].ID})
+	}
+	slices.SortFunc(d.Edges, func(a, b edgeDescription) int {
+		if c := strings.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		return strings.Compare(a.To, b.To)
+	})
+	return d
+}
+
+// nodePipelines returns the pipelines each node is part of, a connector being part of the pipelines it exports from
+// and receives to.
+func (g *Graph) nodePipelines() map[int64][]pipeline.ID {
+	pipelineIDs := make(map[int64][]pipeline.ID)
+	for pipelineID, pg := range g.pipelines {
+		for nodeID := range pg.receivers {
+			pipelineIDs[nodeID] = append(pipelineIDs[nodeID], pipelineID)
+		}
+		pipelineIDs[pg.capabilitiesNode.ID()] = append(pipelineIDs[pg.capabilitiesNode.ID()], pipelineID)
+		for _, n := range pg.processors {
+			pipelineIDs[n.ID()] = append(pipelineIDs[n.ID()], pipelineID)
+		}
+		pipelineIDs[pg.fanOutNode.ID()] = append(pipelineIDs[pg.fanOutNode.ID()], pipelineID)
+		for nodeID := range pg.exporters {
+			pipelineIDs[nodeID] = append(pipelineIDs[nodeID], pipelineID)
+		}
+	}
+	return pipelineIDs
+}
+
+func describeNode(n graph.Node) nodeDescription {
+	desc := nodeDescription{ID: nodeName(n)}
+	switch n := n.(type) {
+	case *receiverNode:
+		desc.Kind, desc.ComponentID = nodeKindReceiver, n.componentID.String()
+		// Receivers do not consume data.
+		return desc
+	case *processorNode:
+		desc.Kind, desc.ComponentID = nodeKindProcessor, n.componentID.String()
+	case *exporterNode:
+		desc.Kind, desc.ComponentID = nodeKindExporter, n.componentID.String()
+	case *connectorNode:
+		desc.Kind, desc.ComponentID = nodeKindConnector, n.componentID.String()
+	case *capabilitiesNode:
+		desc.Kind = nodeKindCapabilities
+	case *fanOutNode:
+		desc.Kind = nodeKindFanOut
+	}
+	if cn, ok := n.(consumerNode); ok {
+		desc.MutatesData = cn.getConsumer().Capabilities().MutatesData
+	}
+	return desc
+}
+
+// nodeName returns the name of a node, made of the same parts as its nodeID. Unlike the nodeID, it is readable,
+// it identifies the node in the graph written by Graph.Write and in the node telemetry.
+func nodeName(n graph.Node) string {
+	var parts []string
+	switch n := n.(type) {
+	case *receiverNode:
+		parts = []string{receiverSeed, n.pipelineType.String(), n.componentID.String()}
+	case *processorNode:
+		parts = []string{processorSeed, n.pipelineID.String(), n.componentID.String()}
+	case *exporterNode:
+		parts = []string{exporterSeed, n.pipelineType.String(), n.componentID.String()}
+	case *connectorNode:
+		parts = []string{connectorSeed, n.componentID.String(), n.exprPipelineType.String(), n.rcvrPipelineType.String()}
+	case *capabilitiesNode:
+		parts = []string{capabilitiesSeed, n.pipelineID.String()}
+	case *fanOutNode:
+		parts = []string{fanOutToExporters, n.pipelineID.String()}
+	default:
+		return strconv.FormatInt(n.ID(), 10)
+	}
+	return strings.Join(parts, "|")
+}
+
+// writeDOT writes the graph in the Graphviz DOT language, the label of a node being its kind, its component ID,
+// and its pipelines.
+func writeDOT(w io.Writer, d description) error {
+	var sb strings.Builder
+	sb.WriteString("digraph pipelines {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	for _, n := range d.Nodes {
+		label := []string{n.Kind}
+		if n.ComponentID != "" {
+			label = append(label, n.ComponentID)
+		}
+		label = append(label, strings.Join(n.Pipelines, ", "))
+		if n.MutatesData {
+			label = append(label, "mutates data")
+		}
+		shape := "box"
+		switch n.Kind {
+		case nodeKindCapabilities, nodeKindFanOut:
+			shape = "ellipse"
+		case nodeKindConnector:
+			shape = "diamond"
+		}
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(strings.Join(label, "\n")), shape)
+	}
+	for _, e := range d.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s;\n", strconv.Quote(e.From), strconv.Quote(e.To))
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/service/internal/builders"
+	"go.opentelemetry.io/collector/service/internal/testcomponents"
+	"go.opentelemetry.io/collector/service/pipelines"
+)
+
+func newDescribeTestGraph(t *testing.T, tel component.TelemetrySettings) *Graph {
+	recvID := component.MustNewID("examplereceiver")
+	procID := component.MustNewIDWithName("exampleprocessor", "mutate")
+	connID := component.MustNewID("exampleconnector")
+	expID := component.MustNewID("exampleexporter")
+	g, err := Build(context.Background(), Settings{
+		Telemetry: tel,
+		BuildInfo: component.NewDefaultBuildInfo(),
+		ReceiverBuilder: builders.NewReceiver(
+			map[component.ID]component.Config{recvID: testcomponents.ExampleReceiverFactory.CreateDefaultConfig()},
+			map[component.Type]receiver.Factory{testcomponents.ExampleReceiverFactory.Type(): testcomponents.ExampleReceiverFactory},
+		),
+		ProcessorBuilder: builders.NewProcessor(
+			map[component.ID]component.Config{procID: testcomponents.ExampleProcessorFactory.CreateDefaultConfig()},
+			map[component.Type]processor.Factory{testcomponents.ExampleProcessorFactory.Type(): testcomponents.ExampleProcessorFactory},
+		),
+		ExporterBuilder: builders.NewExporter(
+			map[component.ID]component.Config{expID: testcomponents.ExampleExporterFactory.CreateDefaultConfig()},
+			map[component.Type]exporter.Factory{testcomponents.ExampleExporterFactory.Type(): testcomponents.ExampleExporterFactory},
+		),
+		ConnectorBuilder: builders.NewConnector(
+			map[component.ID]component.Config{connID: testcomponents.ExampleConnectorFactory.CreateDefaultConfig()},
+			map[component.Type]connector.Factory{testcomponents.ExampleConnectorFactory.Type(): testcomponents.ExampleConnectorFactory},
+		),
+		PipelineConfigs: pipelines.Config{
+			pipeline.NewID(pipeline.SignalTraces): {
+				Receivers:  []component.ID{recvID},
+				Processors: []component.ID{procID},
+				Exporters:  []component.ID{connID},
+			},
+			pipeline.NewID(pipeline.SignalLogs): {
+				Receivers: []component.ID{connID},
+				Exporters: []component.ID{expID},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return g
+}
+
+func TestGraphWriteJSON(t *testing.T) {
+	g := newDescribeTestGraph(t, componenttest.NewNopTelemetrySettings())
+
+	var buf bytes.Buffer
+	require.NoError(t, g.Write(&buf, FormatJSON))
+	var d description
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &d))
+
+	assert.Equal(t, []nodeDescription{
+		{ID: "capabilities|logs", Kind: nodeKindCapabilities, Pipelines: []string{"logs"}},
+		{ID: "capabilities|traces", Kind: nodeKindCapabilities, Pipelines: []string{"traces"}, MutatesData: true},
+		{ID: "connector|exampleconnector|traces|logs", Kind: nodeKindConnector, ComponentID: "exampleconnector", Pipelines: []string{"logs", "traces"}},
+		{ID: "exporter|logs|exampleexporter", Kind: nodeKindExporter, ComponentID: "exampleexporter", Pipelines: []string{"logs"}},
+		{ID: "fanout_to_exporters|logs", Kind: nodeKindFanOut, Pipelines: []string{"logs"}},
+		{ID: "fanout_to_exporters|traces", Kind: nodeKindFanOut, Pipelines: []string{"traces"}},
+		{ID: "processor|traces|exampleprocessor/mutate", Kind: nodeKindProcessor, ComponentID: "exampleprocessor/mutate", Pipelines: []string{"traces"}, MutatesData: true},
+		{ID: "receiver|traces|examplereceiver", Kind: nodeKindReceiver, ComponentID: "examplereceiver", Pipelines: []string{"traces"}},
+	}, d.Nodes)
+	assert.Equal(t, []edgeDescription{
+		{From: "capabilities|logs", To: "fanout_to_exporters|logs"},
+		{From: "capabilities|traces", To: "processor|traces|exampleprocessor/mutate"},
+		{From: "connector|exampleconnector|traces|logs", To: "capabilities|logs"},
+		{From: "fanout_to_exporters|logs", To: "exporter|logs|exampleexporter"},
+		{From: "fanout_to_exporters|traces", To: "connector|exampleconnector|traces|logs"},
+		{From: "processor|traces|exampleprocessor/mutate", To: "fanout_to_exporters|traces"},
+		{From: "receiver|traces|examplereceiver", To: "capabilities|traces"},
+	}, d.Edges)
+}
+
+func TestGraphWriteDOT(t *testing.T) {
+	g := newDescribeTestGraph(t, componenttest.NewNopTelemetrySettings())
+
+	var buf bytes.Buffer
+	require.NoError(t, g.Write(&buf, FormatDOT))
+	out := buf.String()
+	assert.Contains(t, out, "digraph pipelines {\n")
+	assert.Contains(t, out, `  "processor|traces|exampleprocessor/mutate" [label="processor\nexampleprocessor/mutate\ntraces\nmutates data", shape=box];`)
+	assert.Contains(t, out, `  "connector|exampleconnector|traces|logs" [label="connector\nexampleconnector\nlogs, traces", shape=diamond];`)
+	assert.Contains(t, out, `  "receiver|traces|examplereceiver" -> "capabilities|traces";`)
+
+	require.ErrorContains(t, g.Write(&buf, "svg"), `unsupported graph format "svg"`)
+}
+
+func TestGraphHandleZPagesFormat(t *testing.T) {
+	g := newDescribeTestGraph(t, componenttest.NewNopTelemetrySettings())
+
+	tests := []struct {
+		format      string
+		code        int
+		contentType string
+	}{
+		{format: "", code: http.StatusOK, contentType: "text/html; charset=utf-8"},
+		{format: FormatDOT, code: http.StatusOK, contentType: "text/vnd.graphviz; charset=utf-8"},
+		{format: FormatJSON, code: http.StatusOK, contentType: "application/json"},
+		{format: "svg", code: http.StatusBadRequest, contentType: "text/plain; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			g.HandleZPages(rr, httptest.NewRequest(http.MethodGet, "/debug/pipelinez?format="+tt.format, nil))
+			assert.Equal(t, tt.code, rr.Code)
+			assert.Equal(t, tt.contentType, rr.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestHostZPagesRunningGraph(t *testing.T) {
+	host := &Host{}
+	host.SetPipelines(newDescribeTestGraph(t, componenttest.NewNopTelemetrySettings()))
+	mux := http.NewServeMux()
+	host.RegisterZPages(mux, "/debug")
+
+	getGraph := func() description {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/debug/pipelinez?format="+FormatJSON, nil))
+		require.Equal(t, http.StatusOK, rr.Code)
+		var d description
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &d))
+		return d
+	}
+	assert.NotEmpty(t, getGraph().Nodes)
+
+	// The pages describe the graph replacing the registered one.
+	empty, err := Build(context.Background(), Settings{Telemetry: componenttest.NewNopTelemetrySettings()})
+	require.NoError(t, err)
+	host.SetPipelines(empty)
+	assert.Empty(t, getGraph().Nodes)
+}
@@ -10,6 +10,8 @@
 // [Graph.StartAll] starts all components in each pipeline.
 //
 // [Graph.ShutdownAll] stops all components in each pipeline.
+//
+// [Graph.Reload] replaces the graph with the one of a new configuration, only restarting the components that changed.
 package graph // import "go.opentelemetry.io/collector/service/internal/graph"
 
 import (
@@ -17,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -65,17 +68,43 @@ type Graph struct {
 	// Keep track of status source per node
 	instanceIDs map[int64]*componentstatus.InstanceID
 
+	// The dead-letter exporter of the exporter nodes sending their failed data to another exporter of the graph.
+	deadLetters map[int64]int64
+
 	telemetry component.TelemetrySettings
+
+	// The settings the graph is built with, to compare the configurations of the components on reload.
+	settings Settings
+
+	// Instruments the data sent between the nodes, nil unless the service.pipelineNodeTelemetry gate is enabled.
+	nodeTelemetry *nodeTelemetry
 }
 
 // Build builds a full pipeline graph.
 // Build also validates the configuration of the pipelines and does the actual initialization of each Component in the Graph.
 func Build(ctx context.Context, set Settings) (*Graph, error) {
+	pipelines, err := newGraph(set)
+	if err != nil {
+		return nil, err
+	}
+	return pipelines, pipelines.buildComponents(ctx, set, newReuse(nil))
+}
+
+// newGraph creates the nodes and the edges of the graph, without building the components.
+func newGraph(set Settings) (*Graph, error) {
 	pipelines := &Graph{
 		componentGraph: simple.NewDirectedGraph(),
 		pipelines:      make(map[pipeline.ID]*pipelineNodes, len(set.PipelineConfigs)),
 		instanceIDs:    make(map[int64]*componentstatus.InstanceID),
+		deadLetters:    make(map[int64]int64),
 		telemetry:      set.Telemetry,
+		settings:       set,
+	}
+	if nodeTelemetryGate.IsEnabled() {
+		var err error
+		if pipelines.nodeTelemetry, err = newNodeTelemetry(set.Telemetry); err != nil {
+			return nil, err
+		}
 	}
 	for pipelineID := range set.PipelineConfigs {
 		pipelines.pipelines[pipelineID] = &pipelineNodes{
@@ -87,7 +116,8 @@ func Build(ctx context.Context, set Settings) (*Graph, error) {
 		return nil, err
 	}
 	pipelines.createEdges()
-	return pipelines, pipelines.buildComponents(ctx, set)
+	pipelines.linkDeadLetters(set)
+	return pipelines, nil
 }
 
 // Creates a node for each instance of a component and adds it to the graph.
@@ -277,8 +307,10 @@ func (g *Graph) createEdges() {
 // Uses the already built graph g to instantiate the actual components for each component of each pipeline.
 // Handles calling the factories for each component - and hooking up each component to the next.
 // Also calculates whether each pipeline mutates data so the receiver can know whether it needs to clone the data.
-func (g *Graph) buildComponents(ctx context.Context, set Settings) error {
-	nodes, err := topo.Sort(g.componentGraph)
+// The components kept by r are reused instead of being instantiated.
+func (g *Graph) buildComponents(ctx context.Context, set Settings, r *reuse) error {
+	// The dead-letter exporters are handled before the exporters using them, see reuse.keep.
+	nodes, err := g.sortedNodes()
 	if err != nil {
 		return cycleErr(err, topo.DirectedCyclesIn(g.componentGraph))
 	}
@@ -288,13 +320,29 @@ func (g *Graph) buildComponents(ctx context.Context, set Settings) error {
 
 		switch n := node.(type) {
 		case *receiverNode:
+			if prev, ok := r.keep(g, n).(*receiverNode); ok {
+				n.Component = prev.Component
+				continue
+			}
 			err = n.buildComponent(ctx, set.Telemetry, set.BuildInfo, set.ReceiverBuilder, g.nextConsumers(n.ID()))
 		case *processorNode:
+			if prev, ok := r.keep(g, n).(*processorNode); ok {
+				n.Component = prev.Component
+				continue
+			}
 			// nextConsumers is guaranteed to be length 1.  Either it is the next processor or it is the fanout node for the exporters.
 			err = n.buildComponent(ctx, set.Telemetry, set.BuildInfo, set.ProcessorBuilder, g.nextConsumers(n.ID())[0])
 		case *exporterNode:
+			if prev, ok := r.keep(g, n).(*exporterNode); ok {
+				n.Component = prev.Component
+				continue
+			}
 			err = n.buildComponent(ctx, set.Telemetry, set.BuildInfo, set.ExporterBuilder)
 		case *connectorNode:
+			if prev, ok := r.keep(g, n).(*connectorNode); ok {
+				n.Component = prev.Component
+				continue
+			}
 			err = n.buildComponent(ctx, set.Telemetry, set.BuildInfo, set.ConnectorBuilder, g.nextConsumers(n.ID()))
 		case *capabilitiesNode:
 			capability := consumer.Capabilities{
@@ -305,25 +353,23 @@ func (g *Graph) buildComponents(ctx context.Context, set Settings) error {
 				capability.MutatesData = capability.MutatesData || proc.getConsumer().Capabilities().MutatesData
 			}
 			next := g.nextConsumers(n.ID())[0]
+			var cc baseConsumer
 			switch n.pipelineID.Signal() {
 			case pipeline.SignalTraces:
-				cc := capabilityconsumer.NewTraces(next.(consumer.Traces), capability)
-				n.baseConsumer = cc
-				n.ConsumeTracesFunc = cc.ConsumeTraces
+				cc = capabilityconsumer.NewTraces(next.(consumer.Traces), capability)
 			case pipeline.SignalMetrics:
-				cc := capabilityconsumer.NewMetrics(next.(consumer.Metrics), capability)
-				n.baseConsumer = cc
-				n.ConsumeMetricsFunc = cc.ConsumeMetrics
+				cc = capabilityconsumer.NewMetrics(next.(consumer.Metrics), capability)
 			case pipeline.SignalLogs:
-				cc := capabilityconsumer.NewLogs(next.(consumer.Logs), capability)
-				n.baseConsumer = cc
-				n.ConsumeLogsFunc = cc.ConsumeLogs
+				cc = capabilityconsumer.NewLogs(next.(consumer.Logs), capability)
 			case pipelineprofiles.SignalProfiles:
-				cc := capabilityconsumer.NewProfiles(next.(consumerprofiles.Profiles), capability)
-				n.baseConsumer = cc
-				n.ConsumeProfilesFunc = cc.ConsumeProfiles
+				cc = capabilityconsumer.NewProfiles(next.(consumerprofiles.Profiles), capability)
 			}
+			r.setCapabilitiesNext(g, n, cc)
 		case *fanOutNode:
+			if prev, ok := r.keep(g, n).(*fanOutNode); ok {
+				n.baseConsumer = prev.baseConsumer
+				continue
+			}
 			nexts := g.nextConsumers(n.ID())
 			switch n.pipelineID.Signal() {
 			case pipeline.SignalTraces:
@@ -364,7 +410,11 @@ func (g *Graph) nextConsumers(nodeID int64) []baseConsumer {
 	nextNodes := g.componentGraph.From(nodeID)
 	nexts := make([]baseConsumer, 0, nextNodes.Len())
 	for nextNodes.Next() {
-		nexts = append(nexts, nextNodes.Node().(consumerNode).getConsumer())
+		next := nextNodes.Node().(consumerNode).getConsumer()
+		if g.nodeTelemetry != nil {
+			next = g.nodeTelemetry.wrap(g.componentGraph.Node(nodeID), nextNodes.Node(), next)
+		}
+		nexts = append(nexts, next)
 	}
 	return nexts
 }
@@ -392,20 +442,24 @@ func (g *Graph) StartAll(ctx context.Context, host *Host) error {
 	if host == nil {
 		return errors.New("host cannot be nil")
 	}
+	return g.startNodes(ctx, host, func(graph.Node) bool { return true })
+}
 
-	nodes, err := topo.Sort(g.componentGraph)
+// startNodes starts the components of the nodes selected by the filter.
+func (g *Graph) startNodes(ctx context.Context, host *Host, filter func(graph.Node) bool) error {
+	nodes, err := g.sortedNodes()
 	if err != nil {
 		return err
 	}
 
 	// Start in reverse topological order so that downstream components
 	// are started before upstream components. This ensures that each
-	// component's consumer is ready to consume.
+	// component's consumer, and dead-letter exporter, is ready to consume.
 	for i := len(nodes) - 1; i >= 0; i-- {
 		node := nodes[i]
 		comp, ok := node.(component.Component)
 
-		if !ok {
+		if !ok || !filter(node) {
 			// Skip capabilities/fanout nodes
 			continue
 		}
@@ -437,21 +491,26 @@ func (g *Graph) StartAll(ctx context.Context, host *Host) error {
 }
 
 func (g *Graph) ShutdownAll(ctx context.Context, reporter status.Reporter) error {
-	nodes, err := topo.Sort(g.componentGraph)
+	return g.shutdownNodes(ctx, reporter, func(graph.Node) bool { return true })
+}
+
+// shutdownNodes shuts down the components of the nodes selected by the filter.
+func (g *Graph) shutdownNodes(ctx context.Context, reporter status.Reporter, filter func(graph.Node) bool) error {
+	nodes, err := g.sortedNodes()
 	if err != nil {
 		return err
 	}
 
 	// Stop in topological order so that upstream components
 	// are stopped before downstream components.  This ensures
-	// that each component has a chance to drain to its consumer
-	// before the consumer is stopped.
+	// that each component has a chance to drain to its consumer,
+	// and to its dead-letter exporter, before the consumer is stopped.
 	var errs error
 	for i := 0; i < len(nodes); i++ {
 		node := nodes[i]
 		comp, ok := node.(component.Component)
 
-		if !ok {
+		if !ok || !filter(node) {
 			// Skip capabilities/fanout nodes
 			continue
 		}
@@ -462,7 +521,15 @@ func (g *Graph) ShutdownAll(ctx context.Context, reporter status.Reporter) error
 			componentstatus.NewEvent(componentstatus.StatusStopping),
 		)
 
-		if compErr := comp.Shutdown(ctx); compErr != nil {
+		start := time.Now()
+		compErr := comp.Shutdown(ctx)
+		// Report the progress of the drain of the pipelines, the components are flushed one after the other.
+		g.telemetry.Logger.Debug("Component shut down",
+			zap.String("type", instanceID.Kind().String()),
+			zap.String("id", instanceID.ComponentID().String()),
+			zap.Duration("duration", time.Since(start)),
+		)
+		if compErr != nil {
 			errs = multierr.Append(errs, compErr)
 			reporter.ReportStatus(
 				instanceID,
@@ -497,6 +564,15 @@ func (g *Graph) GetExporters() map[pipeline.Signal]map[component.ID]component.Co
 	return exportersMap
 }
 
+// GetExporter returns the exporter with the given ID if it is part of a pipeline of the given signal.
+func (g *Graph) GetExporter(signal pipeline.Signal, id component.ID) (component.Component, bool) {
+	expNode, ok := g.componentGraph.Node(newNodeID(exporterSeed, signal.String(), id.String()).ID()).(*exporterNode)
+	if !ok {
+		return nil, false
+	}
+	return expNode.Component, true
+}
+
 func cycleErr(err error, cycles [][]graph.Node) error {
 	var topoErr topo.Unorderable
 	if !errors.As(err, &topoErr) || len(cycles) == 0 || len(cycles[0]) == 0 {
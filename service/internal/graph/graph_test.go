@@ -1237,6 +1237,21 @@ func TestConnectorRouter(t *testing.T) {
 
 }
 
+func TestGraphGetExporter(t *testing.T) {
+	g := newDescribeTestGraph(t, componenttest.NewNopTelemetrySettings())
+
+	exp, ok := g.GetExporter(pipeline.SignalLogs, component.MustNewID("exampleexporter"))
+	require.True(t, ok)
+	assert.IsType(t, &testcomponents.ExampleExporter{}, exp)
+
+	// The exporter is not part of a traces pipeline.
+	_, ok = g.GetExporter(pipeline.SignalTraces, component.MustNewID("exampleexporter"))
+	assert.False(t, ok)
+	// Connectors are not exporters.
+	_, ok = g.GetExporter(pipeline.SignalTraces, component.MustNewID("exampleconnector"))
+	assert.False(t, ok)
+}
+
 func TestGraphBuildErrors(t *testing.T) {
 	nopReceiverFactory := receivertest.NewNopFactory()
 	nopProcessorFactory := processortest.NewNopFactory()
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"path"
 	"runtime"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -41,7 +42,8 @@ type Host struct {
 	ModuleInfo extension.ModuleInfo
 	BuildInfo  component.BuildInfo
 
-	Pipelines         *Graph
+	// pipelines is the running graph, it's replaced by Graph.Reload while the extensions may access it.
+	pipelines         atomic.Pointer[Graph]
 	ServiceExtensions *extensions.Extensions
 
 	Reporter status.Reporter
@@ -63,6 +65,16 @@ func (host *Host) GetFactory(kind component.Kind, componentType component.Type)
 	return nil
 }
 
+// Pipelines returns the running graph of the pipelines.
+func (host *Host) Pipelines() *Graph {
+	return host.pipelines.Load()
+}
+
+// SetPipelines sets the running graph of the pipelines.
+func (host *Host) SetPipelines(g *Graph) {
+	host.pipelines.Store(g)
+}
+
 func (host *Host) GetExtensions() map[component.ID]component.Component {
 	return host.ServiceExtensions.GetExtensions()
 }
@@ -74,7 +86,14 @@ func (host *Host) GetExtensions() map[component.ID]component.Component {
 // https://github.com/open-telemetry/opentelemetry-collector/pull/7390#issuecomment-1483710184
 // for additional information.
 func (host *Host) GetExporters() map[pipeline.Signal]map[component.ID]component.Component {
-	return host.Pipelines.GetExporters()
+	return host.Pipelines().GetExporters()
+}
+
+// GetExporter returns the exporter with the given ID if it is part of a pipeline of the given signal.
+// Unlike GetExporters, it only gives access to an exporter explicitly referenced in the configuration of a component,
+// e.g. the dead-letter exporter of the exporters using the exporterhelper.
+func (host *Host) GetExporter(signal pipeline.Signal, id component.ID) (component.Component, bool) {
+	return host.Pipelines().GetExporter(signal, id)
 }
 
 func (host *Host) NotifyComponentStatusChange(source *componentstatus.InstanceID, event *componentstatus.Event) {
@@ -109,7 +128,10 @@ func init() {
 
 func (host *Host) RegisterZPages(mux *http.ServeMux, pathPrefix string) {
 	mux.HandleFunc(path.Join(pathPrefix, zServicePath), host.zPagesRequest)
-	mux.HandleFunc(path.Join(pathPrefix, zPipelinePath), host.Pipelines.HandleZPages)
+	// The pages describe the graph running when they are requested, which changes when the pipelines are reloaded.
+	mux.HandleFunc(path.Join(pathPrefix, zPipelinePath), func(w http.ResponseWriter, r *http.Request) {
+		host.Pipelines().HandleZPages(w, r)
+	})
 	mux.HandleFunc(path.Join(pathPrefix, zExtensionPath), host.ServiceExtensions.HandleZPages)
 	mux.HandleFunc(path.Join(pathPrefix, zFeaturePath), handleFeaturezRequest)
 }
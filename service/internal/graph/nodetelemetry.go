@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph // import "go.opentelemetry.io/collector/service/internal/graph"
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/multierr"
+	"gonum.org/v1/gonum/graph"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerprofiles"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/pipeline/pipelineprofiles"
+)
+
+var nodeTelemetryGate = featuregate.GlobalRegistry().MustRegister(
+	"service.pipelineNodeTelemetry",
+	featuregate.StageAlpha,
+	featuregate.WithRegisterFromVersion("v0.112.0"),
+	featuregate.WithRegisterDescription("Controls whether the data sent from one node of the pipelines to the next one "+
+		"is instrumented, reporting the items, errors and latency of each node"),
+)
+
+const (
+	// nodeTelemetryScopeName is the instrumentation scope of the metrics of the nodes, the ones of the service.
+	nodeTelemetryScopeName = "go.opentelemetry.io/collector/service"
+
+	pipelineKey = "pipeline"
+	nodeKey     = "node"
+)
+
+// nodeTelemetry instruments the edges of the graph: the consumer of a node is wrapped for each of the nodes sending
+// data to it. It is shared by the graphs replacing each other on reload, since the components that are kept still
+// send their data through the edges of the graph they were built with.
+type nodeTelemetry struct {
+	consumedItems metric.Int64Counter
+	producedItems metric.Int64Counter
+	errors        metric.Int64Counter
+	duration      metric.Float64Histogram
+
+	mu    sync.Mutex
+	edges map[edgeKey]*edgeStats
+}
+
+type edgeKey struct {
+	from int64
+	to   int64
+}
+
+// edgeStats are the totals of an edge since it was created, shown by the pipelinez zPage.
+type edgeStats struct {
+	calls  atomic.Int64
+	items  atomic.Int64
+	errors atomic.Int64
+	// duration is the total duration of the calls, in nanoseconds.
+	duration atomic.Int64
+}
+
+func newNodeTelemetry(tel component.TelemetrySettings) (*nodeTelemetry, error) {
+	meter := tel.MeterProvider.Meter(nodeTelemetryScopeName)
+	nt := &nodeTelemetry{edges: make(map[edgeKey]*edgeStats)}
+	var err, errs error
+	nt.consumedItems, err = meter.Int64Counter(
+		"otelcol_pipeline_node_consumed_items",
+		metric.WithDescription("Number of items passed to the consumer of the node by the previous nodes."),
+		metric.WithUnit("{items}"))
+	errs = multierr.Append(errs, err)
+	nt.producedItems, err = meter.Int64Counter(
+		"otelcol_pipeline_node_produced_items",
+		metric.WithDescription("Number of items passed by the node to the consumers of the next nodes, counting each copy sent by a fan-out."),
+		metric.WithUnit("{items}"))
+	errs = multierr.Append(errs, err)
+	nt.errors, err = meter.Int64Counter(
+		"otelcol_pipeline_node_errors",
+		metric.WithDescription("Number of calls to the consumer of the node that returned an error."),
+		metric.WithUnit("{errors}"))
+	errs = multierr.Append(errs, err)
+	nt.duration, err = meter.Float64Histogram(
+		"otelcol_pipeline_node_consume_duration",
+		metric.WithDescription("Duration of the calls to the consumer of the node, including the time spent in the next nodes "+
+			"called synchronously."),
+		metric.WithUnit("s"))
+	errs = multierr.Append(errs, err)
+	if errs != nil {
+		return nil, fmt.Errorf("failed to create the pipeline node metrics: %w", errs)
+	}
+	return nt, nil
+}
+
+// stats returns the totals of the edge, created on first use.
+func (nt *nodeTelemetry) stats(key edgeKey) *edgeStats {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	s, ok := nt.edges[key]
+	if !ok {
+		s = &edgeStats{}
+		nt.edges[key] = s
+	}
+	return s
+}
+
+// lookup returns the totals of the edge, nil if no consumer was wrapped for it.
+func (nt *nodeTelemetry) lookup(key edgeKey) *edgeStats {
+	nt.mu.Lock()
+	defer nt.mu.Unlock()
+	return nt.edges[key]
+}
+
+// wrap returns the consumer of the node to, instrumented for the data sent to it by the node from.
+func (nt *nodeTelemetry) wrap(from, to graph.Node, next baseConsumer) baseConsumer {
+	pipelineID := edgePipelineID(from, to)
+	e := &edge{
+		nt:         nt,
+		stats:      nt.stats(edgeKey{from: from.ID(), to: to.ID()}),
+		pipelineID: pipelineID,
+		consumedAttrs: metric.WithAttributeSet(attribute.NewSet(
+			attribute.String(pipelineKey, pipelineID.String()), attribute.String(nodeKey, nodeName(to)))),
+		producedAttrs: metric.WithAttributeSet(attribute.NewSet(
+			attribute.String(pipelineKey, pipelineID.String()), attribute.String(nodeKey, nodeName(from)))),
+	}
+	switch pipelineID.Signal() {
+	case pipeline.SignalTraces:
+		return &tracesEdge{edge: e, next: next.(consumer.Traces)}
+	case pipeline.SignalMetrics:
+		return &metricsEdge{edge: e, next: next.(consumer.Metrics)}
+	case pipeline.SignalLogs:
+		return &logsEdge{edge: e, next: next.(consumer.Logs)}
+	case pipelineprofiles.SignalProfiles:
+		return &profilesEdge{edge: e, next: next.(consumerprofiles.Profiles)}
+	}
+	return next
+}
+
+// edgePipelineID returns the pipeline of an edge. The edges to an exporter or a connector are the ones from the
+// fan-out node of the pipeline, the other nodes consuming data belong to a single pipeline.
+func edgePipelineID(from, to graph.Node) pipeline.ID {
+	switch n := to.(type) {
+	case *capabilitiesNode:
+		return n.pipelineID
+	case *processorNode:
+		return n.pipelineID
+	case *fanOutNode:
+		return n.pipelineID
+	}
+	return from.(*fanOutNode).pipelineID
+}
+
+// nextPipelineID returns the pipeline of a consumer of a connector, the capabilities node of the pipeline.
+func nextPipelineID(next baseConsumer) pipeline.ID {
+	if e, ok := next.(interface{ getPipelineID() pipeline.ID }); ok {
+		return e.getPipelineID()
+	}
+	return next.(*capabilitiesNode).pipelineID
+}
+
+type edge struct {
+	nt            *nodeTelemetry
+	stats         *edgeStats
+	pipelineID    pipeline.ID
+	consumedAttrs metric.MeasurementOption
+	producedAttrs metric.MeasurementOption
+}
+
+func (e *edge) getPipelineID() pipeline.ID {
+	return e.pipelineID
+}
+
+func (e *edge) record(ctx context.Context, items int, start time.Time, err error) {
+	duration := time.Since(start)
+	e.stats.calls.Add(1)
+	e.stats.items.Add(int64(items))
+	e.stats.duration.Add(int64(duration))
+	e.nt.producedItems.Add(ctx, int64(items), e.producedAttrs)
+	e.nt.consumedItems.Add(ctx, int64(items), e.consumedAttrs)
+	e.nt.duration.Record(ctx, duration.Seconds(), e.consumedAttrs)
+	if err != nil {
+		e.stats.errors.Add(1)
+		e.nt.errors.Add(ctx, 1, e.consumedAttrs)
+	}
+}
+
+type tracesEdge struct {
+	*edge
+	next consumer.Traces
+}
+
+func (e *tracesEdge) Capabilities() consumer.Capabilities {
+	return e.next.Capabilities()
+}
+
+func (e *tracesEdge) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	// The items are counted first, the next consumer can modify the data.
+	items, start := td.SpanCount(), time.Now()
+	err := e.next.ConsumeTraces(ctx, td)
+	e.record(ctx, items, start, err)
+	return err
+}
+
+type metricsEdge struct {
+	*edge
+	next consumer.Metrics
+}
+
+func (e *metricsEdge) Capabilities() consumer.Capabilities {
+	return e.next.Capabilities()
+}
+
+func (e *metricsEdge) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	items, start := md.DataPointCount(), time.Now()
+	err := e.next.ConsumeMetrics(ctx, md)
+	e.record(ctx, items, start, err)
+	return err
+}
+
+type logsEdge struct {
+	*edge
+	next consumer.Logs
+}
+
+func (e *logsEdge) Capabilities() consumer.Capabilities {
+	return e.next.Capabilities()
+}
+
+func (e *logsEdge) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	items, start := ld.LogRecordCount(), time.Now()
+	err := e.next.ConsumeLogs(ctx, ld)
+	e.record(ctx, items, start, err)
+	return err
+}
+
+type profilesEdge struct {
+	*edge
+	next consumerprofiles.Profiles
+}
+
+func (e *profilesEdge) Capabilities() consumer.Capabilities {
+	return e.next.Capabilities()
+}
+
+func (e *profilesEdge) ConsumeProfiles(ctx context.Context, pd pprofile.Profiles) error {
+	items, start := pd.SampleCount(), time.Now()
+	err := e.next.ConsumeProfiles(ctx, pd)
+	e.record(ctx, items, start, err)
+	return err
+}
@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/featuregate"
+	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/service/internal/testcomponents"
+	"go.opentelemetry.io/collector/service/internal/zpages"
+)
+
+func setNodeTelemetryGate(t *testing.T, enabled bool) {
+	prev := nodeTelemetryGate.IsEnabled()
+	require.NoError(t, featuregate.GlobalRegistry().Set(nodeTelemetryGate.ID(), enabled))
+	t.Cleanup(func() {
+		require.NoError(t, featuregate.GlobalRegistry().Set(nodeTelemetryGate.ID(), prev))
+	})
+}
+
+func TestNodeTelemetryDisabled(t *testing.T) {
+	setNodeTelemetryGate(t, false)
+	g := newDescribeTestGraph(t, componenttest.NewNopTelemetrySettings())
+	assert.Nil(t, g.nodeTelemetry)
+	for _, next := range g.nextConsumers(g.pipelines[pipeline.NewID(pipeline.SignalLogs)].fanOutNode.ID()) {
+		assert.IsType(t, &testcomponents.ExampleExporter{}, next)
+	}
+}
+
+func TestNodeTelemetry(t *testing.T) {
+	setNodeTelemetryGate(t, true)
+	reader := sdkmetric.NewManualReader()
+	tel := componenttest.NewNopTelemetrySettings()
+	tel.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	g := newDescribeTestGraph(t, tel)
+	require.NotNil(t, g.nodeTelemetry)
+
+	for _, c := range g.getReceivers()[pipeline.SignalTraces] {
+		require.NoError(t, c.(*testcomponents.ExampleReceiver).ConsumeTraces(context.Background(), testdata.GenerateTraces(2)))
+	}
+
+	rows := g.edgesTableData().Rows
+	require.Len(t, rows, 7)
+	for _, row := range rows {
+		assert.Equal(t, int64(1), row.Calls, "%s -> %s", row.From, row.To)
+		assert.Equal(t, int64(2), row.Items, "%s -> %s", row.From, row.To)
+		assert.Equal(t, int64(0), row.Errors, "%s -> %s", row.From, row.To)
+		assert.NotEmpty(t, row.AverageLatency)
+	}
+	assert.Equal(t, zpages.EdgesTableRowData{
+		Pipeline: "logs",
+		From:     "capabilities|logs",
+		To:       "fanout_to_exporters|logs",
+		Calls:    1,
+		Items:    2,
+	}, withoutLatency(rows[0]))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, nodeTelemetryScopeName, rm.ScopeMetrics[0].Scope.Name)
+	metrics := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	consumed := metrics["otelcol_pipeline_node_consumed_items"].Data.(metricdata.Sum[int64])
+	exporterAttrs := attribute.NewSet(attribute.String(pipelineKey, "logs"), attribute.String(nodeKey, "exporter|logs|exampleexporter"))
+	var exporterConsumed int64
+	for _, dp := range consumed.DataPoints {
+		if dp.Attributes.Equals(&exporterAttrs) {
+			exporterConsumed = dp.Value
+		}
+	}
+	assert.Equal(t, int64(2), exporterConsumed)
+	produced := metrics["otelcol_pipeline_node_produced_items"].Data.(metricdata.Sum[int64])
+	assert.Len(t, produced.DataPoints, 7)
+	duration := metrics["otelcol_pipeline_node_consume_duration"].Data.(metricdata.Histogram[float64])
+	assert.Len(t, duration.DataPoints, 7)
+	assert.NotContains(t, metrics, "otelcol_pipeline_node_errors")
+
+	rr := httptest.NewRecorder()
+	g.HandleZPages(rr, httptest.NewRequest(http.MethodGet, "/debug/pipelinez", nil))
+	assert.Contains(t, rr.Body.String(), "Pipeline nodes")
+	assert.Contains(t, rr.Body.String(), "connector|exampleconnector|traces|logs")
+}
+
+func TestNodeTelemetryErrors(t *testing.T) {
+	nt, err := newNodeTelemetry(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	from := newFanOutNode(pipeline.NewID(pipeline.SignalTraces))
+	to := newExporterNode(pipeline.SignalTraces, component.MustNewID("exampleexporter"))
+	next := nt.wrap(from, to, consumertest.NewErr(errors.New("failed"))).(*tracesEdge)
+	require.Error(t, next.ConsumeTraces(context.Background(), testdata.GenerateTraces(3)))
+
+	stats := nt.lookup(edgeKey{from: from.ID(), to: to.ID()})
+	require.NotNil(t, stats)
+	assert.Equal(t, int64(1), stats.errors.Load())
+	assert.Equal(t, int64(3), stats.items.Load())
+	assert.Equal(t, pipeline.NewID(pipeline.SignalTraces), nextPipelineID(next))
+}
+
+func withoutLatency(row zpages.EdgesTableRowData) zpages.EdgesTableRowData {
+	row.AverageLatency = ""
+	return row
+}
@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph // import "go.opentelemetry.io/collector/service/internal/graph"
+
+import (
+	"context"
+	"reflect"
+
+	"go.uber.org/multierr"
+	"gonum.org/v1/gonum/graph"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// reuse keeps track of the nodes of a running graph reused by the graph built to replace it.
+type reuse struct {
+	prev *Graph
+
+	// Nodes whose consumer, as seen by the upstream nodes, is the one of the running graph.
+	unchanged map[int64]bool
+
+	// Consumers of the capabilities nodes shared with the running graph, set once the new components are started.
+	nexts map[*capabilitiesNode]baseConsumer
+}
+
+func newReuse(prev *Graph) *reuse {
+	return &reuse{
+		prev:      prev,
+		unchanged: make(map[int64]bool),
+		nexts:     make(map[*capabilitiesNode]baseConsumer),
+	}
+}
+
+// keep returns the node of the running graph to reuse in place of n, nil if the component must be built.
+// A component is reused if its configuration and pipelines did not change, and if its next consumers are
+// the ones of the running graph. An exporter is also rebuilt if its dead-letter exporter changed, the exporters
+// resolve their dead-letter exporter when they start. A fan-out node is reused if its next consumers are the ones
+// of the running graph.
+func (r *reuse) keep(g *Graph, n graph.Node) graph.Node {
+	if r.prev == nil {
+		return nil
+	}
+	prev := r.prev.componentGraph.Node(n.ID())
+	if prev == nil || !r.sameNextConsumers(g, n.ID()) || !r.sameDeadLetter(g, n.ID()) {
+		return nil
+	}
+	if _, ok := n.(*fanOutNode); !ok {
+		if !reflect.DeepEqual(componentConfig(r.prev.settings, prev), componentConfig(g.settings, n)) ||
+			!samePipelines(r.prev.instanceIDs[n.ID()], g.instanceIDs[n.ID()]) {
+			return nil
+		}
+		g.instanceIDs[n.ID()] = r.prev.instanceIDs[n.ID()]
+	}
+	r.unchanged[n.ID()] = true
+	return prev
+}
+
+// setCapabilitiesNext sets the consumer of a capabilities node. The consumer of a pipeline of the running graph
+// is shared with the new node, and is replaced once the new components are started if the pipeline changed.
+// The node is unchanged for the upstream nodes if the pipeline existed and still mutates the data the same way.
+func (r *reuse) setCapabilitiesNext(g *Graph, n *capabilitiesNode, next baseConsumer) {
+	var prev *capabilitiesNode
+	if r.prev != nil {
+		prev, _ = r.prev.componentGraph.Node(n.ID()).(*capabilitiesNode)
+	}
+	if prev == nil {
+		n.setNext(next)
+		return
+	}
+	n.next = prev.next
+	if !r.sameNextConsumers(g, n.ID()) {
+		r.nexts[n] = next
+	}
+	if prev.Capabilities() == next.Capabilities() {
+		r.unchanged[n.ID()] = true
+	}
+}
+
+// sameNextConsumers returns true if the node has the same next nodes in both graphs, all unchanged.
+func (r *reuse) sameNextConsumers(g *Graph, nodeID int64) bool {
+	nexts := g.componentGraph.From(nodeID)
+	if nexts.Len() != r.prev.componentGraph.From(nodeID).Len() {
+		return false
+	}
+	for nexts.Next() {
+		next := nexts.Node().ID()
+		if !r.unchanged[next] || !r.prev.componentGraph.HasEdgeFromTo(nodeID, next) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameDeadLetter returns true if the node has the same dead-letter exporter in both graphs, unchanged, or none.
+func (r *reuse) sameDeadLetter(g *Graph, nodeID int64) bool {
+	next, ok := g.deadLetters[nodeID]
+	prevNext, prevOK := r.prev.deadLetters[nodeID]
+	return ok == prevOK && next == prevNext && (!ok || r.unchanged[next])
+}
+
+func componentConfig(set Settings, n graph.Node) component.Config {
+	switch n := n.(type) {
+	case *receiverNode:
+		return set.ReceiverBuilder.Config(n.componentID)
+	case *processorNode:
+		return set.ProcessorBuilder.Config(n.componentID)
+	case *exporterNode:
+		return set.ExporterBuilder.Config(n.componentID)
+	case *connectorNode:
+		return set.ConnectorBuilder.Config(n.componentID)
+	}
+	return nil
+}
+
+func samePipelines(a, b *componentstatus.InstanceID) bool {
+	pipelines := make(map[pipeline.ID]bool)
+	a.AllPipelineIDs(func(id pipeline.ID) bool {
+		pipelines[id] = true
+		return true
+	})
+	same := true
+	var count int
+	b.AllPipelineIDs(func(id pipeline.ID) bool {
+		count++
+		same = pipelines[id]
+		return same
+	})
+	return same && count == len(pipelines)
+}
+
+// Reload builds the graph of the given settings in place of g, reusing the running components of g whose
+// configuration, pipelines and next consumers did not change. Only the other components are stopped and
+// started: the receivers that are kept continue to receive data while the rest of their pipelines is replaced.
+//
+// The new components are started and the components of g that are not reused are stopped in the following order:
+//  1. The new graph becomes the running graph of the host, so that the new components looking up other components
+//     when they start, e.g. their dead-letter exporter, find the ones of the new graph. Then the new components
+//     other than receivers are started, downstream components first.
+//  2. The receivers of g that are replaced are stopped, before their replacements are started.
+//  3. The new receivers are started.
+//  4. The pipelines of the kept receivers are switched to the new components.
+//  5. The remaining components of g are stopped, upstream components first so that they drain to their consumers.
+//
+// Reload returns the graph that is running. It is g, still running and not changed, if the new graph cannot be
+// built or if one of the new components other than receivers fails to start: the new components are shut down and
+// g is the running graph of the host again.
+// If a new receiver fails to start, the receivers of g it replaces are already stopped: Reload completes the
+// steps above without it and returns the new graph, which must be shut down.
+func (g *Graph) Reload(ctx context.Context, set Settings, host *Host) (*Graph, error) {
+	newG, err := newGraph(set)
+	if err != nil {
+		return g, err
+	}
+	if g.nodeTelemetry != nil {
+		// The kept components send their data through the edges they were built with.
+		newG.nodeTelemetry = g.nodeTelemetry
+	}
+	r := newReuse(g)
+	if err = newG.buildComponents(ctx, set, r); err != nil {
+		return g, err
+	}
+
+	isReceiver := func(n graph.Node) bool {
+		_, ok := n.(*receiverNode)
+		return ok
+	}
+	notReused := func(n graph.Node) bool { return !r.unchanged[n.ID()] }
+
+	host.SetPipelines(newG)
+	if err = newG.startNodes(ctx, host, func(n graph.Node) bool { return notReused(n) && !isReceiver(n) }); err != nil {
+		// Nothing of g was stopped or switched yet, g keeps running.
+		host.SetPipelines(g)
+		return g, multierr.Append(err, newG.shutdownNodes(ctx, host.Reporter, func(n graph.Node) bool {
+			return notReused(n) && !isReceiver(n)
+		}))
+	}
+
+	errs := g.shutdownNodes(ctx, host.Reporter, func(n graph.Node) bool { return notReused(n) && isReceiver(n) })
+	errs = multierr.Append(errs, newG.startNodes(ctx, host, func(n graph.Node) bool { return notReused(n) && isReceiver(n) }))
+
+	for n, next := range r.nexts {
+		n.setNext(next)
+	}
+
+	return newG, multierr.Append(errs, g.shutdownNodes(ctx, host.Reporter, func(n graph.Node) bool {
+		return notReused(n) && !isReceiver(n)
+	}))
+}
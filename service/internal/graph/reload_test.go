@@ -0,0 +1,474 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/connector"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/pipeline"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/service/internal/builders"
+	"go.opentelemetry.io/collector/service/internal/status"
+	"go.opentelemetry.io/collector/service/internal/testcomponents"
+	"go.opentelemetry.io/collector/service/pipelines"
+)
+
+type reloadTestConfig struct {
+	Value string
+}
+
+func TestGraphReload(t *testing.T) {
+	recvID := component.MustNewID("examplereceiver")
+	procID := component.MustNewID("exampleprocessor")
+	expID := component.MustNewID("exampleexporter")
+	exp1ID := component.MustNewIDWithName("exampleexporter", "1")
+	tracesID := pipeline.NewID(pipeline.SignalTraces)
+	logsID := pipeline.NewID(pipeline.SignalLogs)
+
+	// The example receivers are shared per configuration, each test case uses its own.
+	newSettings := func(recvCfg component.Config, procValue, expValue string, pipelineCfgs pipelines.Config) Settings {
+		return Settings{
+			Telemetry: componenttest.NewNopTelemetrySettings(),
+			BuildInfo: component.NewDefaultBuildInfo(),
+			ReceiverBuilder: builders.NewReceiver(
+				map[component.ID]component.Config{recvID: recvCfg},
+				map[component.Type]receiver.Factory{testcomponents.ExampleReceiverFactory.Type(): testcomponents.ExampleReceiverFactory},
+			),
+			ProcessorBuilder: builders.NewProcessor(
+				map[component.ID]component.Config{procID: &reloadTestConfig{Value: procValue}},
+				map[component.Type]processor.Factory{testcomponents.ExampleProcessorFactory.Type(): testcomponents.ExampleProcessorFactory},
+			),
+			ExporterBuilder: builders.NewExporter(
+				map[component.ID]component.Config{
+					expID:  &reloadTestConfig{Value: expValue},
+					exp1ID: &reloadTestConfig{},
+				},
+				map[component.Type]exporter.Factory{testcomponents.ExampleExporterFactory.Type(): testcomponents.ExampleExporterFactory},
+			),
+			ConnectorBuilder: builders.NewConnector(
+				map[component.ID]component.Config{},
+				map[component.Type]connector.Factory{},
+			),
+			PipelineConfigs: pipelineCfgs,
+		}
+	}
+	tracesPipeline := pipelines.Config{
+		tracesID: {Receivers: []component.ID{recvID}, Processors: []component.ID{procID}, Exporters: []component.ID{expID}},
+	}
+
+	tests := []struct {
+		name            string
+		receiverChanged bool
+		procValue       string
+		expValue        string
+		pipelineCfgs    pipelines.Config
+		receiverKept    bool
+		processorKept   bool
+		exporterKept    bool
+	}{
+		{
+			name:          "unchanged",
+			pipelineCfgs:  tracesPipeline,
+			receiverKept:  true,
+			processorKept: true,
+			exporterKept:  true,
+		},
+		{
+			name:          "processor_changed",
+			procValue:     "changed",
+			pipelineCfgs:  tracesPipeline,
+			receiverKept:  true,
+			processorKept: false,
+			exporterKept:  true,
+		},
+		{
+			name:          "exporter_changed",
+			expValue:      "changed",
+			pipelineCfgs:  tracesPipeline,
+			receiverKept:  true,
+			processorKept: false,
+			exporterKept:  false,
+		},
+		{
+			name: "exporter_added",
+			pipelineCfgs: pipelines.Config{
+				tracesID: {Receivers: []component.ID{recvID}, Processors: []component.ID{procID}, Exporters: []component.ID{expID, exp1ID}},
+			},
+			receiverKept:  true,
+			processorKept: false,
+			exporterKept:  true,
+		},
+		{
+			name:            "receiver_changed",
+			receiverChanged: true,
+			pipelineCfgs:    tracesPipeline,
+			receiverKept:    false,
+			processorKept:   true,
+			exporterKept:    true,
+		},
+		{
+			name: "pipeline_added",
+			pipelineCfgs: pipelines.Config{
+				tracesID: {Receivers: []component.ID{recvID}, Processors: []component.ID{procID}, Exporters: []component.ID{expID}},
+				logsID:   {Receivers: []component.ID{recvID}, Exporters: []component.ID{exp1ID}},
+			},
+			receiverKept:  true,
+			processorKept: true,
+			exporterKept:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			host := &Host{Reporter: status.NewReporter(func(*componentstatus.InstanceID, *componentstatus.Event) {}, func(error) {})}
+			recvCfg := &reloadTestConfig{Value: tt.name}
+
+			pg, err := Build(ctx, newSettings(recvCfg, "", "", tracesPipeline))
+			require.NoError(t, err)
+			require.NoError(t, pg.StartAll(ctx, host))
+
+			rcvr := pg.getReceivers()[pipeline.SignalTraces][recvID].(*testcomponents.ExampleReceiver)
+			proc := pg.pipelines[tracesID].processors[0].Component.(*testcomponents.ExampleProcessor)
+			exp := pg.GetExporters()[pipeline.SignalTraces][expID].(*testcomponents.ExampleExporter)
+
+			newRecvCfg := recvCfg
+			if tt.receiverChanged {
+				newRecvCfg = &reloadTestConfig{Value: tt.name + "/changed"}
+			}
+			newPG, err := pg.Reload(ctx, newSettings(newRecvCfg, tt.procValue, tt.expValue, tt.pipelineCfgs), host)
+			require.NoError(t, err)
+
+			newProc := newPG.pipelines[tracesID].processors[0].Component.(*testcomponents.ExampleProcessor)
+			newExp := newPG.GetExporters()[pipeline.SignalTraces][expID].(*testcomponents.ExampleExporter)
+			assert.Equal(t, tt.processorKept, proc == newProc)
+			assert.Equal(t, tt.processorKept, !proc.Stopped())
+			assert.True(t, newProc.Started())
+			assert.Equal(t, tt.exporterKept, exp == newExp)
+			assert.Equal(t, tt.exporterKept, !exp.Stopped())
+			assert.True(t, newExp.Started())
+			newRcvr := newPG.getReceivers()[pipeline.SignalTraces][recvID].(*testcomponents.ExampleReceiver)
+			assert.Equal(t, tt.receiverKept, rcvr == newRcvr)
+			assert.Equal(t, tt.receiverKept, !rcvr.Stopped())
+			assert.True(t, newRcvr.Started())
+
+			// The data received goes through the new pipeline.
+			require.NoError(t, newRcvr.ConsumeTraces(ctx, testdata.GenerateTraces(1)))
+			assert.Len(t, newExp.Traces, 1)
+
+			require.NoError(t, newPG.ShutdownAll(ctx, host.Reporter))
+			assert.True(t, newProc.Stopped())
+			assert.True(t, newExp.Stopped())
+		})
+	}
+}
+
+func TestGraphReloadBuildError(t *testing.T) {
+	ctx := context.Background()
+	host := &Host{Reporter: status.NewReporter(func(*componentstatus.InstanceID, *componentstatus.Event) {}, func(error) {})}
+	recvID := component.MustNewID("examplereceiver")
+	expID := component.MustNewID("exampleexporter")
+	set := Settings{
+		Telemetry: componenttest.NewNopTelemetrySettings(),
+		BuildInfo: component.NewDefaultBuildInfo(),
+		ReceiverBuilder: builders.NewReceiver(
+			map[component.ID]component.Config{recvID: &reloadTestConfig{Value: "build_error"}},
+			map[component.Type]receiver.Factory{testcomponents.ExampleReceiverFactory.Type(): testcomponents.ExampleReceiverFactory},
+		),
+		ProcessorBuilder: builders.NewProcessor(map[component.ID]component.Config{}, map[component.Type]processor.Factory{}),
+		ExporterBuilder: builders.NewExporter(
+			map[component.ID]component.Config{expID: &reloadTestConfig{}},
+			map[component.Type]exporter.Factory{testcomponents.ExampleExporterFactory.Type(): testcomponents.ExampleExporterFactory},
+		),
+		ConnectorBuilder: builders.NewConnector(map[component.ID]component.Config{}, map[component.Type]connector.Factory{}),
+		PipelineConfigs: pipelines.Config{
+			pipeline.NewID(pipeline.SignalTraces): {Receivers: []component.ID{recvID}, Exporters: []component.ID{expID}},
+		},
+	}
+	pg, err := Build(ctx, set)
+	require.NoError(t, err)
+	require.NoError(t, pg.StartAll(ctx, host))
+	exp := pg.GetExporters()[pipeline.SignalTraces][expID].(*testcomponents.ExampleExporter)
+
+	set.PipelineConfigs = pipelines.Config{
+		pipeline.NewID(pipeline.SignalTraces): {
+			Receivers:  []component.ID{recvID},
+			Processors: []component.ID{component.MustNewID("missing")},
+			Exporters:  []component.ID{expID},
+		},
+	}
+	newPG, err := pg.Reload(ctx, set, host)
+	require.Error(t, err)
+	// The running graph is not changed.
+	assert.Same(t, pg, newPG)
+	assert.False(t, exp.Stopped())
+	require.NoError(t, pg.ShutdownAll(ctx, host.Reporter))
+}
+
+func TestGraphReloadStartError(t *testing.T) {
+	recvID := component.MustNewID("examplereceiver")
+	expID := component.MustNewID("exampleexporter")
+	errID := component.MustNewID("err")
+	tracesID := pipeline.NewID(pipeline.SignalTraces)
+	errReceiverFactory := newErrReceiverFactory()
+	errExporterFactory := newErrExporterFactory()
+
+	newSettings := func(recvCfg component.Config, pipelineCfg *pipelines.PipelineConfig) Settings {
+		return Settings{
+			Telemetry: componenttest.NewNopTelemetrySettings(),
+			BuildInfo: component.NewDefaultBuildInfo(),
+			ReceiverBuilder: builders.NewReceiver(
+				map[component.ID]component.Config{recvID: recvCfg, errID: errReceiverFactory.CreateDefaultConfig()},
+				map[component.Type]receiver.Factory{
+					testcomponents.ExampleReceiverFactory.Type(): testcomponents.ExampleReceiverFactory,
+					errReceiverFactory.Type():                    errReceiverFactory,
+				},
+			),
+			ProcessorBuilder: builders.NewProcessor(map[component.ID]component.Config{}, map[component.Type]processor.Factory{}),
+			ExporterBuilder: builders.NewExporter(
+				map[component.ID]component.Config{expID: &reloadTestConfig{}, errID: errExporterFactory.CreateDefaultConfig()},
+				map[component.Type]exporter.Factory{
+					testcomponents.ExampleExporterFactory.Type(): testcomponents.ExampleExporterFactory,
+					errExporterFactory.Type():                    errExporterFactory,
+				},
+			),
+			ConnectorBuilder: builders.NewConnector(map[component.ID]component.Config{}, map[component.Type]connector.Factory{}),
+			PipelineConfigs:  pipelines.Config{tracesID: pipelineCfg},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		pipelineCfg *pipelines.PipelineConfig
+		graphKept   bool
+	}{
+		{
+			name:        "exporter_start_error",
+			pipelineCfg: &pipelines.PipelineConfig{Receivers: []component.ID{recvID}, Exporters: []component.ID{expID, errID}},
+			graphKept:   true,
+		},
+		{
+			name:        "receiver_start_error",
+			pipelineCfg: &pipelines.PipelineConfig{Receivers: []component.ID{recvID, errID}, Exporters: []component.ID{expID}},
+			graphKept:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			host := &Host{Reporter: status.NewReporter(func(*componentstatus.InstanceID, *componentstatus.Event) {}, func(error) {})}
+			recvCfg := &reloadTestConfig{Value: tt.name}
+
+			pg, err := Build(ctx, newSettings(recvCfg, &pipelines.PipelineConfig{Receivers: []component.ID{recvID}, Exporters: []component.ID{expID}}))
+			require.NoError(t, err)
+			require.NoError(t, pg.StartAll(ctx, host))
+			rcvr := pg.getReceivers()[pipeline.SignalTraces][recvID].(*testcomponents.ExampleReceiver)
+			exp := pg.GetExporters()[pipeline.SignalTraces][expID].(*testcomponents.ExampleExporter)
+
+			newPG, err := pg.Reload(ctx, newSettings(recvCfg, tt.pipelineCfg), host)
+			require.Error(t, err)
+			if !tt.graphKept {
+				// The new graph runs without the receiver that failed to start.
+				assert.NotSame(t, pg, newPG)
+				newExp := newPG.GetExporters()[pipeline.SignalTraces][expID].(*testcomponents.ExampleExporter)
+				assert.True(t, newExp.Started())
+				_ = newPG.ShutdownAll(ctx, host.Reporter)
+				assert.True(t, newExp.Stopped())
+				return
+			}
+
+			// The running graph is not changed, and still receives the data.
+			assert.Same(t, pg, newPG)
+			assert.False(t, rcvr.Stopped())
+			assert.False(t, exp.Stopped())
+			require.NoError(t, rcvr.ConsumeTraces(ctx, testdata.GenerateTraces(1)))
+			assert.Len(t, exp.Traces, 1)
+			require.NoError(t, pg.ShutdownAll(ctx, host.Reporter))
+		})
+	}
+}
+
+type deadLetterTestTarget struct {
+	Exporter *component.ID
+}
+
+func (cfg deadLetterTestTarget) DeadLetterExporterID() (component.ID, bool) {
+	if cfg.Exporter == nil {
+		return component.ID{}, false
+	}
+	return *cfg.Exporter, true
+}
+
+type deadLetterTestConfig struct {
+	DeadLetter deadLetterTestTarget
+}
+
+// deadLetterTestExporter looks up its dead-letter exporter when it starts, like the exporterhelper.
+type deadLetterTestExporter struct {
+	component.ShutdownFunc
+	consumer.Traces
+	cfg        *deadLetterTestConfig
+	deadLetter component.Component
+}
+
+func (e *deadLetterTestExporter) Start(_ context.Context, host component.Host) error {
+	id, ok := e.cfg.DeadLetter.DeadLetterExporterID()
+	if !ok {
+		return nil
+	}
+	exp, ok := host.(interface {
+		GetExporter(signal pipeline.Signal, id component.ID) (component.Component, bool)
+	}).GetExporter(pipeline.SignalTraces, id)
+	if !ok {
+		return errors.New("dead-letter exporter not found")
+	}
+	e.deadLetter = exp
+	return nil
+}
+
+func TestGraphReloadDeadLetter(t *testing.T) {
+	recvID := component.MustNewID("examplereceiver")
+	dlID := component.MustNewID("deadletter")
+	expID := component.MustNewID("exampleexporter")
+	tracesID := pipeline.NewID(pipeline.SignalTraces)
+	dlFactory := exporter.NewFactory(dlID.Type(),
+		func() component.Config { return &deadLetterTestConfig{} },
+		exporter.WithTraces(func(_ context.Context, _ exporter.Settings, cfg component.Config) (exporter.Traces, error) {
+			return &deadLetterTestExporter{Traces: consumertest.NewNop(), cfg: cfg.(*deadLetterTestConfig)}, nil
+		}, component.StabilityLevelDevelopment))
+
+	recvCfg := &reloadTestConfig{}
+	newSettings := func(expValue string) Settings {
+		return Settings{
+			Telemetry: componenttest.NewNopTelemetrySettings(),
+			BuildInfo: component.NewDefaultBuildInfo(),
+			ReceiverBuilder: builders.NewReceiver(
+				map[component.ID]component.Config{recvID: recvCfg},
+				map[component.Type]receiver.Factory{testcomponents.ExampleReceiverFactory.Type(): testcomponents.ExampleReceiverFactory},
+			),
+			ProcessorBuilder: builders.NewProcessor(map[component.ID]component.Config{}, map[component.Type]processor.Factory{}),
+			ExporterBuilder: builders.NewExporter(
+				map[component.ID]component.Config{
+					dlID:  &deadLetterTestConfig{DeadLetter: deadLetterTestTarget{Exporter: &expID}},
+					expID: &reloadTestConfig{Value: expValue},
+				},
+				map[component.Type]exporter.Factory{
+					testcomponents.ExampleExporterFactory.Type(): testcomponents.ExampleExporterFactory,
+					dlFactory.Type(): dlFactory,
+				},
+			),
+			ConnectorBuilder: builders.NewConnector(map[component.ID]component.Config{}, map[component.Type]connector.Factory{}),
+			PipelineConfigs: pipelines.Config{
+				tracesID: {Receivers: []component.ID{recvID}, Exporters: []component.ID{dlID, expID}},
+			},
+		}
+	}
+
+	ctx := context.Background()
+	host := &Host{Reporter: status.NewReporter(func(*componentstatus.InstanceID, *componentstatus.Event) {}, func(error) {})}
+	pg, err := Build(ctx, newSettings(""))
+	require.NoError(t, err)
+	host.SetPipelines(pg)
+	require.NoError(t, pg.StartAll(ctx, host))
+	dl := pg.GetExporters()[pipeline.SignalTraces][dlID].(*deadLetterTestExporter)
+	exp := pg.GetExporters()[pipeline.SignalTraces][expID].(*testcomponents.ExampleExporter)
+	assert.Same(t, exp, dl.deadLetter)
+
+	// The exporter is kept while its dead-letter exporter is.
+	newPG, err := pg.Reload(ctx, newSettings(""), host)
+	require.NoError(t, err)
+	assert.Same(t, newPG, host.Pipelines())
+	assert.Same(t, dl, newPG.GetExporters()[pipeline.SignalTraces][dlID])
+	pg = newPG
+
+	// The exporter is replaced along with its dead-letter exporter, and finds the new one when it starts.
+	newPG, err = pg.Reload(ctx, newSettings("changed"), host)
+	require.NoError(t, err)
+	assert.Same(t, newPG, host.Pipelines())
+	newDL := newPG.GetExporters()[pipeline.SignalTraces][dlID].(*deadLetterTestExporter)
+	newExp := newPG.GetExporters()[pipeline.SignalTraces][expID].(*testcomponents.ExampleExporter)
+	assert.NotSame(t, dl, newDL)
+	assert.NotSame(t, exp, newExp)
+	assert.Same(t, newExp, newDL.deadLetter)
+	assert.True(t, exp.Stopped())
+	require.NoError(t, newPG.ShutdownAll(ctx, host.Reporter))
+}
+
+func TestGraphDeadLetterOrder(t *testing.T) {
+	recvID := component.MustNewID("examplereceiver")
+	dl1ID := component.MustNewIDWithName("deadletter", "1")
+	dl2ID := component.MustNewIDWithName("deadletter", "2")
+	dlFactory := exporter.NewFactory(dl1ID.Type(),
+		func() component.Config { return &deadLetterTestConfig{} },
+		exporter.WithTraces(func(_ context.Context, _ exporter.Settings, cfg component.Config) (exporter.Traces, error) {
+			return &deadLetterTestExporter{Traces: consumertest.NewNop(), cfg: cfg.(*deadLetterTestConfig)}, nil
+		}, component.StabilityLevelDevelopment))
+
+	tests := []struct {
+		name       string
+		from       component.ID
+		deadLetter component.ID
+	}{
+		{name: "first_to_second", from: dl1ID, deadLetter: dl2ID},
+		{name: "second_to_first", from: dl2ID, deadLetter: dl1ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var started, stopped []component.ID
+			host := &Host{Reporter: status.NewReporter(func(id *componentstatus.InstanceID, ev *componentstatus.Event) {
+				if id.Kind() != component.KindExporter {
+					return
+				}
+				switch ev.Status() {
+				case componentstatus.StatusStarting:
+					started = append(started, id.ComponentID())
+				case componentstatus.StatusStopping:
+					stopped = append(stopped, id.ComponentID())
+				}
+			}, func(error) {})}
+			deadLetter := tt.deadLetter
+			pg, err := Build(context.Background(), Settings{
+				Telemetry: componenttest.NewNopTelemetrySettings(),
+				BuildInfo: component.NewDefaultBuildInfo(),
+				ReceiverBuilder: builders.NewReceiver(
+					map[component.ID]component.Config{recvID: &reloadTestConfig{Value: tt.name}},
+					map[component.Type]receiver.Factory{testcomponents.ExampleReceiverFactory.Type(): testcomponents.ExampleReceiverFactory},
+				),
+				ProcessorBuilder: builders.NewProcessor(map[component.ID]component.Config{}, map[component.Type]processor.Factory{}),
+				ExporterBuilder: builders.NewExporter(
+					map[component.ID]component.Config{
+						tt.from:       &deadLetterTestConfig{DeadLetter: deadLetterTestTarget{Exporter: &deadLetter}},
+						tt.deadLetter: &deadLetterTestConfig{},
+					},
+					map[component.Type]exporter.Factory{dlFactory.Type(): dlFactory},
+				),
+				ConnectorBuilder: builders.NewConnector(map[component.ID]component.Config{}, map[component.Type]connector.Factory{}),
+				PipelineConfigs: pipelines.Config{
+					pipeline.NewID(pipeline.SignalTraces): {Receivers: []component.ID{recvID}, Exporters: []component.ID{dl1ID, dl2ID}},
+				},
+			})
+			require.NoError(t, err)
+			host.SetPipelines(pg)
+			require.NoError(t, pg.StartAll(context.Background(), host))
+			require.NoError(t, pg.ShutdownAll(context.Background(), host.Reporter))
+
+			// The dead-letter exporter is started before, and shut down after, the exporter using it.
+			assert.Equal(t, []component.ID{tt.deadLetter, tt.from}, started)
+			assert.Equal(t, []component.ID{tt.from, tt.deadLetter}, stopped)
+		})
+	}
+}
@@ -4,8 +4,11 @@
 package graph // import "go.opentelemetry.io/collector/service/internal/graph"
 
 import (
+	"bytes"
 	"net/http"
 	"sort"
+	"strconv"
+	"time"
 
 	"go.opentelemetry.io/collector/service/internal/zpages"
 )
@@ -16,6 +19,7 @@ const (
 	zPipelineName  = "pipelinenamez"
 	zComponentName = "componentnamez"
 	zComponentKind = "componentkindz"
+	zFormat        = "format"
 )
 
 func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
@@ -24,6 +28,16 @@ func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
 	componentName := qValues.Get(zComponentName)
 	componentKind := qValues.Get(zComponentKind)
 
+	switch format := qValues.Get(zFormat); format {
+	case "":
+	case FormatDOT, FormatJSON:
+		g.handleGraphRequest(w, format)
+		return
+	default:
+		http.Error(w, "unsupported format "+strconv.Quote(format), http.StatusBadRequest)
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	zpages.WriteHTMLPageHeader(w, zpages.HeaderData{Title: "builtPipelines"})
 
@@ -66,6 +80,10 @@ func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
 		return sumData.Rows[i].FullName < sumData.Rows[j].FullName
 	})
 	zpages.WriteHTMLPipelinesSummaryTable(w, sumData)
+	if g.nodeTelemetry != nil {
+		zpages.WriteHTMLComponentHeader(w, zpages.ComponentHeaderData{Name: "Pipeline nodes"})
+		zpages.WriteHTMLEdgesTable(w, g.edgesTableData())
+	}
 
 	if pipelineName != "" && componentName != "" && componentKind != "" {
 		fullName := componentName
@@ -79,3 +97,52 @@ func (g *Graph) HandleZPages(w http.ResponseWriter, r *http.Request) {
 	}
 	zpages.WriteHTMLPageFooter(w)
 }
+
+// handleGraphRequest writes the graph in the given format, to be rendered or reviewed by other tools.
+func (g *Graph) handleGraphRequest(w http.ResponseWriter, format string) {
+	var buf bytes.Buffer
+	if err := g.Write(&buf, format); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if format == FormatDOT {
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+// edgesTableData returns the totals of the data sent along each edge of the graph since it was created.
+func (g *Graph) edgesTableData() zpages.EdgesTableData {
+	data := zpages.EdgesTableData{}
+	for it := g.componentGraph.Edges(); it.Next(); {
+		e := it.Edge()
+		stats := g.nodeTelemetry.lookup(edgeKey{from: e.From().ID(), to: e.To().ID()})
+		if stats == nil {
+			continue
+		}
+		row := zpages.EdgesTableRowData{
+			Pipeline: edgePipelineID(e.From(), e.To()).String(),
+			From:     nodeName(e.From()),
+			To:       nodeName(e.To()),
+			Calls:    stats.calls.Load(),
+			Items:    stats.items.Load(),
+			Errors:   stats.errors.Load(),
+		}
+		if row.Calls > 0 {
+			row.AverageLatency = (time.Duration(stats.duration.Load()) / time.Duration(row.Calls)).String()
+		}
+		data.Rows = append(data.Rows, row)
+	}
+	sort.Slice(data.Rows, func(i, j int) bool {
+		if data.Rows[i].Pipeline != data.Rows[j].Pipeline {
+			return data.Rows[i].Pipeline < data.Rows[j].Pipeline
+		}
+		if data.Rows[i].From != data.Rows[j].From {
+			return data.Rows[i].From < data.Rows[j].From
+		}
+		return data.Rows[i].To < data.Rows[j].To
+	})
+	return data
+}
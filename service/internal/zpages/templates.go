@@ -21,6 +21,10 @@ var (
 	componentHeaderBytes    []byte
 	componentHeaderTemplate = parseTemplate("component_header", componentHeaderBytes)
 
+	//go:embed templates/edges_table.html
+	edgesTableBytes    []byte
+	edgesTableTemplate = parseTemplate("edges_table", edgesTableBytes)
+
 	//go:embed templates/extensions_table.html
 	extensionsTableBytes    []byte
 	extensionsTableTemplate = parseTemplate("extensions_table", extensionsTableBytes)
@@ -104,6 +108,30 @@ func WriteHTMLPipelinesSummaryTable(w io.Writer, spd SummaryPipelinesTableData)
 	}
 }
 
+// EdgesTableData contains data for the table of the edges between the nodes of the pipelines.
+type EdgesTableData struct {
+	Rows []EdgesTableRowData
+}
+
+// EdgesTableRowData contains data for one edge in the edges table template.
+type EdgesTableRowData struct {
+	Pipeline       string
+	From           string
+	To             string
+	Calls          int64
+	Items          int64
+	Errors         int64
+	AverageLatency string
+}
+
+// WriteHTMLEdgesTable writes the table of the data sent from one node of the pipelines to the next one.
+// It does not write the header or footer.
+func WriteHTMLEdgesTable(w io.Writer, etd EdgesTableData) {
+	if err := edgesTableTemplate.Execute(w, etd); err != nil {
+		log.Printf("zpages: executing template: %v", err)
+	}
+}
+
 // ComponentHeaderData contains data for component header template.
 type ComponentHeaderData struct {
 	Name              string
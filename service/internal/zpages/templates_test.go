@@ -48,6 +48,18 @@ func TestNoCrash(t *testing.T) {
 	assert.NotPanics(t, func() {
 		WriteHTMLComponentHeader(buf, ComponentHeaderData{Name: "Bar", ComponentEndpoint: "pagez", Link: true})
 	})
+	assert.NotPanics(t, func() {
+		WriteHTMLEdgesTable(buf, EdgesTableData{
+			Rows: []EdgesTableRowData{{
+				Pipeline:       "traces",
+				From:           "receiver|traces|otlp",
+				To:             "capabilities|traces",
+				Calls:          2,
+				Items:          10,
+				AverageLatency: "1ms",
+			}},
+		})
+	})
 	assert.NotPanics(t, func() {
 		WriteHTMLPipelinesSummaryTable(buf, SummaryPipelinesTableData{
 			Rows: []SummaryPipelinesTableRowData{{
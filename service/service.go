@@ -9,19 +9,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"runtime"
+	"time"
 
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/confmap"
 	"go.opentelemetry.io/collector/connector"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -36,6 +41,13 @@ import (
 	"go.opentelemetry.io/collector/service/telemetry"
 )
 
+// serviceScopeName is the instrumentation scope of the metrics reported by the service itself.
+const serviceScopeName = "go.opentelemetry.io/collector/service"
+
+// ConfigInstanceID is the instance for which the status of the configuration of the collector is reported to the
+// extensions watching the component status, see ReportConfigRollback. It is not the instance of a component.
+var ConfigInstanceID = componentstatus.NewInstanceID(component.MustNewID("config"), component.KindExtension)
+
 // useOtelWithSDKConfigurationForInternalTelemetryFeatureGate is the feature gate that controls whether the collector
 // supports configuring the OpenTelemetry SDK via configuration
 var _ = featuregate.GlobalRegistry().MustRegister(
@@ -92,6 +104,11 @@ type Service struct {
 	telemetrySettings component.TelemetrySettings
 	host              *graph.Host
 	collectorConf     *confmap.Conf
+	shutdownCfg       ShutdownConfig
+
+	// configRollbacks counts the configurations rolled back, see ReportConfigRollback.
+	configRollbacks metric.Int64Counter
+	rolledBack      bool
 }
 
 // New creates a new Service, its telemetry, and Components.
@@ -110,6 +127,7 @@ func New(ctx context.Context, set Settings, cfg Config) (*Service, error) {
 			AsyncErrorChannel: set.AsyncErrorChannel,
 		},
 		collectorConf: set.CollectorConf,
+		shutdownCfg:   cfg.Shutdown,
 	}
 
 	// Fetch data for internal telemetry like instance id and sdk version to provide for internal telemetry.
@@ -176,6 +194,14 @@ func New(ctx context.Context, set Settings, cfg Config) (*Service, error) {
 		return nil, fmt.Errorf("failed to register process metrics: %w", err)
 	}
 
+	srv.configRollbacks, err = srv.telemetrySettings.MeterProvider.Meter(serviceScopeName).Int64Counter(
+		"otelcol_config_rollbacks",
+		metric.WithDescription("Number of updated configurations that failed to be applied and were rolled back."),
+		metric.WithUnit("{rollbacks}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config rollbacks metric: %w", err)
+	}
+
 	return srv, nil
 }
 
@@ -219,7 +245,7 @@ func (srv *Service) Start(ctx context.Context) error {
 		}
 	}
 
-	if err := srv.host.Pipelines.StartAll(ctx, srv.host); err != nil {
+	if err := srv.host.Pipelines().StartAll(srv.pipelinesContext(ctx), srv.host); err != nil {
 		return fmt.Errorf("cannot start pipelines: %w", err)
 	}
 
@@ -231,6 +257,66 @@ func (srv *Service) Start(ctx context.Context) error {
 	return nil
 }
 
+// ErrReloadAborted is wrapped by the errors of Reload when the pipelines cannot be replaced, and the service still
+// runs the previous pipelines.
+var ErrReloadAborted = errors.New("the previous pipelines are still running")
+
+// Reload replaces the pipelines of the service with the ones of the given settings and configuration. Only the
+// components whose configuration or next consumers changed are replaced, see graph.Graph.Reload. The telemetry
+// and the extensions of the service are not changed, the extensions are notified about the new configuration.
+// If Reload fails with an error wrapping ErrReloadAborted the service is not changed, otherwise Shutdown should
+// be called to ensure a clean state.
+func (srv *Service) Reload(ctx context.Context, set Settings, cfg Config) error {
+	prevReceivers, prevProcessors, prevExporters, prevConnectors := srv.host.Receivers, srv.host.Processors, srv.host.Exporters, srv.host.Connectors
+	prevPipelines := srv.host.Pipelines()
+	srv.host.Receivers = builders.NewReceiver(set.ReceiversConfigs, set.ReceiversFactories)
+	srv.host.Processors = builders.NewProcessor(set.ProcessorsConfigs, set.ProcessorsFactories)
+	srv.host.Exporters = builders.NewExporter(set.ExportersConfigs, set.ExportersFactories)
+	srv.host.Connectors = builders.NewConnector(set.ConnectorsConfigs, set.ConnectorsFactories)
+
+	// The replaced exporters persist the data left in their queue, and the new ones restore it, like at shutdown
+	// and start. The running graph of the host is set by Reload.
+	pipelines, err := prevPipelines.Reload(srv.pipelinesContext(ctx), srv.graphSettings(cfg), srv.host)
+	if err != nil && pipelines == prevPipelines {
+		srv.host.Receivers = prevReceivers
+		srv.host.Processors = prevProcessors
+		srv.host.Exporters = prevExporters
+		srv.host.Connectors = prevConnectors
+		return fmt.Errorf("failed to reload pipelines, %w: %w", ErrReloadAborted, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reload pipelines: %w", err)
+	}
+
+	srv.collectorConf = set.CollectorConf
+	if srv.collectorConf != nil {
+		if err = srv.host.ServiceExtensions.NotifyConfig(ctx, srv.collectorConf); err != nil {
+			return err
+		}
+	}
+	if srv.rolledBack {
+		srv.rolledBack = false
+		srv.host.Reporter.ReportStatus(ConfigInstanceID, componentstatus.NewEvent(componentstatus.StatusOK))
+	}
+	srv.telemetrySettings.Logger.Info("Pipelines reloaded.")
+	return nil
+}
+
+// ReportConfigRollback reports that an updated configuration failed to be applied, and that the service runs
+// the last configuration applied successfully instead. The error is logged, counted by the otelcol_config_rollbacks
+// metric, and reported as a recoverable error of ConfigInstanceID to the extensions watching the component status.
+// The status of ConfigInstanceID is OK again once an updated configuration is applied by Reload.
+func (srv *Service) ReportConfigRollback(ctx context.Context, err error) {
+	srv.telemetrySettings.Logger.Error("Failed to apply the updated config, rolled back to the last applied config",
+		zap.Error(err))
+	srv.configRollbacks.Add(ctx, 1)
+	if !srv.rolledBack {
+		srv.rolledBack = true
+		srv.host.Reporter.ReportStatus(ConfigInstanceID, componentstatus.NewEvent(componentstatus.StatusStarting))
+	}
+	srv.host.Reporter.ReportStatus(ConfigInstanceID, componentstatus.NewRecoverableErrorEvent(err))
+}
+
 func (srv *Service) shutdownTelemetry(ctx context.Context) error {
 	// The metric.MeterProvider and trace.TracerProvider interfaces do not have a Shutdown method.
 	// To shutdown the providers we try to cast to this interface, which matches the type signature used in the SDK.
@@ -269,9 +355,7 @@ func (srv *Service) Shutdown(ctx context.Context) error {
 		errs = multierr.Append(errs, fmt.Errorf("failed to notify that pipeline is not ready: %w", err))
 	}
 
-	if err := srv.host.Pipelines.ShutdownAll(ctx, srv.host.Reporter); err != nil {
-		errs = multierr.Append(errs, fmt.Errorf("failed to shutdown pipelines: %w", err))
-	}
+	errs = multierr.Append(errs, srv.drainPipelines(ctx))
 
 	if err := srv.host.ServiceExtensions.Shutdown(ctx); err != nil {
 		errs = multierr.Append(errs, fmt.Errorf("failed to shutdown extensions: %w", err))
@@ -284,6 +368,47 @@ func (srv *Service) Shutdown(ctx context.Context) error {
 	return errs
 }
 
+// pipelinesContext returns the context of the start and the shutdown of the pipelines, telling the exporters
+// where to persist the data left in their queue at the end of the drain and to restore it from.
+func (srv *Service) pipelinesContext(ctx context.Context) context.Context {
+	if srv.shutdownCfg.PersistUnsent {
+		return exporterqueue.ContextWithUnsentStorage(ctx, *srv.shutdownCfg.Storage)
+	}
+	return ctx
+}
+
+// drainPipelines shuts down the pipelines within the drain timeout. The receivers are stopped first, then the
+// processors and the exporters flush their data in topological order.
+func (srv *Service) drainPipelines(ctx context.Context) error {
+	logger := srv.telemetrySettings.Logger
+	drainCtx := srv.pipelinesContext(ctx)
+	if srv.shutdownCfg.DrainTimeout > 0 {
+		var cancel context.CancelFunc
+		drainCtx, cancel = context.WithTimeout(drainCtx, srv.shutdownCfg.DrainTimeout)
+		defer cancel()
+		logger.Info("Draining pipelines...", zap.Duration("drain_timeout", srv.shutdownCfg.DrainTimeout))
+	}
+
+	start := time.Now()
+	if err := srv.host.Pipelines().ShutdownAll(drainCtx, srv.host.Reporter); err != nil {
+		return fmt.Errorf("failed to shutdown pipelines: %w", err)
+	}
+	if srv.shutdownCfg.DrainTimeout <= 0 {
+		return nil
+	}
+	if errors.Is(drainCtx.Err(), context.DeadlineExceeded) {
+		unsent := "dropped"
+		if srv.shutdownCfg.PersistUnsent {
+			unsent = "persisted"
+		}
+		logger.Warn("Drain timeout exceeded, the data left in the exporter queues is "+unsent+".",
+			zap.Duration("duration", time.Since(start)))
+		return nil
+	}
+	logger.Info("Pipelines drained.", zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
 // Creates extensions.
 func (srv *Service) initExtensions(ctx context.Context, cfg extensions.Config) error {
 	var err error
@@ -301,8 +426,16 @@ func (srv *Service) initExtensions(ctx context.Context, cfg extensions.Config) e
 
 // Creates the pipeline graph.
 func (srv *Service) initGraph(ctx context.Context, cfg Config) error {
-	var err error
-	if srv.host.Pipelines, err = graph.Build(ctx, graph.Settings{
+	pipelines, err := graph.Build(ctx, srv.graphSettings(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to build pipelines: %w", err)
+	}
+	srv.host.SetPipelines(pipelines)
+	return nil
+}
+
+func (srv *Service) graphSettings(cfg Config) graph.Settings {
+	return graph.Settings{
 		Telemetry:        srv.telemetrySettings,
 		BuildInfo:        srv.buildInfo,
 		ReceiverBuilder:  srv.host.Receivers,
@@ -311,10 +444,40 @@ func (srv *Service) initGraph(ctx context.Context, cfg Config) error {
 		ConnectorBuilder: srv.host.Connectors,
 		PipelineConfigs:  cfg.Pipelines,
 		ReportStatus:     srv.host.Reporter.ReportStatus,
-	}); err != nil {
+	}
+}
+
+// WritePipelineGraph builds the components of the pipelines of cfg without starting them, and writes the graph of
+// the pipelines to w in the given format, "dot" or "json". The components are shut down once the graph is written.
+// The output format is not stable and can change between releases.
+func WritePipelineGraph(ctx context.Context, w io.Writer, format string, set Settings, cfg Config) error {
+	if format != graph.FormatDOT && format != graph.FormatJSON {
+		return fmt.Errorf("unsupported graph format %q, must be %q or %q", format, graph.FormatDOT, graph.FormatJSON)
+	}
+	reporter := status.NewReporter(func(*componentstatus.InstanceID, *componentstatus.Event) {}, func(error) {})
+	g, err := graph.Build(ctx, graph.Settings{
+		Telemetry: component.TelemetrySettings{
+			Logger:         zap.NewNop(),
+			TracerProvider: nooptrace.NewTracerProvider(),
+			MeterProvider:  noop.NewMeterProvider(),
+			LeveledMeterProvider: func(configtelemetry.Level) metric.MeterProvider {
+				return noop.NewMeterProvider()
+			},
+			MetricsLevel: configtelemetry.LevelNone,
+			Resource:     pcommon.NewResource(),
+		},
+		BuildInfo:        set.BuildInfo,
+		ReceiverBuilder:  builders.NewReceiver(set.ReceiversConfigs, set.ReceiversFactories),
+		ProcessorBuilder: builders.NewProcessor(set.ProcessorsConfigs, set.ProcessorsFactories),
+		ExporterBuilder:  builders.NewExporter(set.ExportersConfigs, set.ExportersFactories),
+		ConnectorBuilder: builders.NewConnector(set.ConnectorsConfigs, set.ConnectorsFactories),
+		PipelineConfigs:  cfg.Pipelines,
+		ReportStatus:     reporter.ReportStatus,
+	})
+	if err != nil {
 		return fmt.Errorf("failed to build pipelines: %w", err)
 	}
-	return nil
+	return multierr.Append(g.Write(w, format), g.ShutdownAll(ctx, reporter))
 }
 
 // Logger returns the logger created for this service.
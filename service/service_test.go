@@ -5,6 +5,7 @@ package service
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -20,12 +21,17 @@ import (
 	"go.opentelemetry.io/contrib/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componentstatus"
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterqueue"
 	"go.opentelemetry.io/collector/extension"
 	"go.opentelemetry.io/collector/extension/zpagesextension"
 	"go.opentelemetry.io/collector/internal/testutil"
@@ -35,6 +41,7 @@ import (
 	"go.opentelemetry.io/collector/service/extensions"
 	"go.opentelemetry.io/collector/service/internal/builders"
 	"go.opentelemetry.io/collector/service/internal/promtest"
+	"go.opentelemetry.io/collector/service/internal/status"
 	"go.opentelemetry.io/collector/service/pipelines"
 	"go.opentelemetry.io/collector/service/telemetry"
 )
@@ -447,6 +454,121 @@ func TestServiceTelemetryLogger(t *testing.T) {
 	assert.NotNil(t, srv.telemetrySettings.Logger)
 }
 
+func TestServiceShutdownDrainTimeout(t *testing.T) {
+	core, observed := observer.New(zapcore.InfoLevel)
+	set := newNopSettings()
+	set.LoggingOptions = []zap.Option{zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })}
+	cfg := newNopConfig()
+	cfg.Shutdown.DrainTimeout = 10 * time.Second
+
+	srv, err := New(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+	require.NoError(t, srv.Shutdown(context.Background()))
+
+	assert.Equal(t, 1, observed.FilterMessage("Draining pipelines...").Len())
+	assert.Equal(t, 1, observed.FilterMessage("Pipelines drained.").Len())
+}
+
+type unsentStorageTestConfig struct {
+	Value string
+}
+
+func TestServiceReloadUnsentStorage(t *testing.T) {
+	expID := component.MustNewID("unsent")
+	var started, stopped []bool
+	expFactory := exporter.NewFactory(expID.Type(), func() component.Config { return &unsentStorageTestConfig{} },
+		exporter.WithTraces(func(context.Context, exporter.Settings, component.Config) (exporter.Traces, error) {
+			return struct {
+				component.StartFunc
+				component.ShutdownFunc
+				consumer.Traces
+			}{
+				StartFunc: func(ctx context.Context, _ component.Host) error {
+					_, ok := exporterqueue.UnsentStorageFromContext(ctx)
+					started = append(started, ok)
+					return nil
+				},
+				ShutdownFunc: func(ctx context.Context) error {
+					_, ok := exporterqueue.UnsentStorageFromContext(ctx)
+					stopped = append(stopped, ok)
+					return nil
+				},
+				Traces: consumertest.NewNop(),
+			}, nil
+		}, component.StabilityLevelDevelopment))
+	newSettings := func(value string) Settings {
+		set := newNopSettings()
+		set.ExportersConfigs[expID] = &unsentStorageTestConfig{Value: value}
+		set.ExportersFactories[expID.Type()] = expFactory
+		return set
+	}
+	storageID := component.NewID(nopType)
+	cfg := newNopConfigPipelineConfigs(pipelines.Config{
+		pipeline.NewID(pipeline.SignalTraces): {
+			Receivers: []component.ID{component.NewID(nopType)},
+			Exporters: []component.ID{expID},
+		},
+	})
+	cfg.Shutdown = ShutdownConfig{PersistUnsent: true, Storage: &storageID}
+
+	srv, err := New(context.Background(), newSettings("before"), cfg)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	// The replaced exporter persists its queue, and the new one restores it.
+	require.NoError(t, srv.Reload(context.Background(), newSettings("after"), cfg))
+	assert.Equal(t, []bool{true, true}, started)
+	assert.Equal(t, []bool{true}, stopped)
+}
+
+func TestServiceReportConfigRollback(t *testing.T) {
+	core, observed := observer.New(zapcore.InfoLevel)
+	set := newNopSettings()
+	set.LoggingOptions = []zap.Option{zap.WrapCore(func(zapcore.Core) zapcore.Core { return core })}
+	srv, err := New(context.Background(), set, newNopConfig())
+	require.NoError(t, err)
+	var events []componentstatus.Status
+	srv.host.Reporter = status.NewReporter(func(id *componentstatus.InstanceID, ev *componentstatus.Event) {
+		if id == ConfigInstanceID {
+			events = append(events, ev.Status())
+		}
+	}, func(error) {})
+
+	require.NoError(t, srv.Start(context.Background()))
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	srv.ReportConfigRollback(context.Background(), assert.AnError)
+	logs := observed.FilterMessage("Failed to apply the updated config, rolled back to the last applied config")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, assert.AnError.Error(), logs.All()[0].ContextMap()["error"])
+	// The next config applied clears the error.
+	require.NoError(t, srv.Reload(context.Background(), newNopSettings(), newNopConfig()))
+	assert.Equal(t, []componentstatus.Status{
+		componentstatus.StatusStarting,
+		componentstatus.StatusRecoverableError,
+		componentstatus.StatusOK,
+	}, events)
+}
+
+func TestWritePipelineGraph(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePipelineGraph(context.Background(), &buf, "json", newNopSettings(), newNopConfig()))
+	assert.Contains(t, buf.String(), `"id": "receiver|traces|nop"`)
+
+	buf.Reset()
+	require.NoError(t, WritePipelineGraph(context.Background(), &buf, "dot", newNopSettings(), newNopConfig()))
+	assert.Contains(t, buf.String(), `"processor|logs|nop" -> "fanout_to_exporters|logs";`)
+
+	require.ErrorContains(t, WritePipelineGraph(context.Background(), &buf, "svg", newNopSettings(), newNopConfig()),
+		`unsupported graph format "svg"`)
+}
+
 func TestServiceFatalError(t *testing.T) {
 	set := newNopSettings()
 	set.AsyncErrorChannel = make(chan error)